@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 邀请用户
+// @Description 预先设置部门、岗位和角色，向被邀请人的邮箱发送激活链接，被邀请人设置密码后账号启用；接受邀请前用户为待激活状态
+// @Tags 用户管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.InviteAdminDto true "邀请用户请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/adminService/inviteAdmin [post]
+func InviteAdmin(c *gin.Context) {
+	var dto entity.InviteAdminDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// 获取数据权限时已校验登录状态
+	loggedUser, _ := getLoggedUser(c)
+	if err := AdminInvitationService.InviteAdmin(scope, loggedUser.ID, &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 重新发送邀请
+// @Description 向待激活用户重新发送邀请邮件，之前发送的链接失效，有效期重新计算
+// @Tags 用户管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.AdminInvitationDto true "重新发送邀请请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/adminService/resendInvitation [post]
+func ResendInvitation(c *gin.Context) {
+	var dto entity.AdminInvitationDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := AdminInvitationService.ResendInvitation(scope, dto.ID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 撤销邀请
+// @Description 撤销尚未接受的邀请，同时删除待激活的用户
+// @Tags 用户管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.AdminInvitationDto true "撤销邀请请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/adminService/revokeInvitation [post]
+func RevokeInvitation(c *gin.Context) {
+	var dto entity.AdminInvitationDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := AdminInvitationService.RevokeInvitation(scope, dto.ID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 查询邀请信息
+// @Description 接受邀请页面使用邀请链接中的令牌查询被邀请的账号
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.InvitationTokenDto true "查询邀请信息请求结构体"
+// @Success 200 {object} response.Response{data=entity.InvitationInfoVo}
+// @Failure 400 {object} response.Response
+// @Router /api/invitation/info [post]
+func GetInvitationInfo(c *gin.Context) {
+	var dto entity.InvitationTokenDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	info, err := AdminInvitationService.GetInvitationInfo(dto.Token)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, info)
+}
+
+// @Summary 接受邀请
+// @Description 设置密码并激活账号，新密码需符合密码策略；成功后使用用户名和新密码登录
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.AcceptInvitationDto true "接受邀请请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/invitation/accept [post]
+func AcceptInvitation(c *gin.Context) {
+	var dto entity.AcceptInvitationDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	if err := AdminInvitationService.AcceptInvitation(&dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 创建服务账号的API令牌
+// @Description 为数据权限范围内的服务账号创建API令牌，令牌明文只在响应中返回一次。
+// @Description 服务账号拥有超级管理员权限或操作人没有的权限时不能创建。
+// @Description 调用接口时放在 X-API-Key 请求头或 Authorization: Bearer 中，scopes 为空时与服务账号的权限一致
+// @Tags API令牌管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.CreateApiTokenDto true "创建API令牌请求"
+// @Success 200 {object} response.Response{data=entity.ApiTokenCreatedVo}
+// @Failure 400 {object} response.Response
+// @Router /api/apiTokenService/createApiToken [post]
+func CreateApiToken(c *gin.Context) {
+	var dto entity.CreateApiTokenDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// 获取数据权限时已校验登录状态
+	loggedUser, _ := getLoggedUser(c)
+	created, err := ApiTokenService.CreateApiToken(scope, loggedUser.ID, loggedUser.Username, &dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, created)
+}
+
+// @Summary 查询API令牌列表
+// @Description 查询数据权限范围内用户的API令牌，不返回令牌明文
+// @Tags API令牌管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "页大小"
+// @Param adminId query int false "所属用户id"
+// @Param name query string false "令牌名称"
+// @Success 200 {object} response.Response{data=entity.ApiTokenListVo}
+// @Failure 400 {object} response.Response
+// @Router /api/apiTokenService/getApiTokenList [get]
+func GetApiTokenList(c *gin.Context) {
+	pageNum, _ := strconv.Atoi(c.Query("pageNum"))
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
+	adminId, _ := strconv.ParseUint(c.Query("adminId"), 10, 64)
+	name := c.Query("name")
+
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	tokenList, err := ApiTokenService.GetApiTokenList(scope, pageNum, pageSize, uint(adminId), name)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, tokenList)
+}
+
+// @Summary 吊销API令牌
+// @Description 吊销数据权限范围内用户的API令牌，吊销后立即失效
+// @Tags API令牌管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.RevokeApiTokenDto true "吊销API令牌请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/apiTokenService/revokeApiToken [post]
+func RevokeApiToken(c *gin.Context) {
+	var dto entity.RevokeApiTokenDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := ApiTokenService.RevokeApiToken(scope, dto.ID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 创建个人API令牌
+// @Description 为当前登录用户创建API令牌，令牌明文只在响应中返回一次，scopes 为空时与本人的权限一致
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.CreateApiTokenDto true "创建API令牌请求，adminId 不需要传"
+// @Success 200 {object} response.Response{data=entity.ApiTokenCreatedVo}
+// @Failure 400 {object} response.Response
+// @Router /api/me/createApiToken [post]
+func CreateMyApiToken(c *gin.Context) {
+	var dto entity.CreateApiTokenDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	created, err := ApiTokenService.CreateMyApiToken(loggedUser.ID, &dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, created)
+}
+
+// @Summary 查询我的API令牌
+// @Description 查询当前登录用户的个人API令牌，不返回令牌明文
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "页大小"
+// @Success 200 {object} response.Response{data=entity.ApiTokenListVo}
+// @Failure 401 {object} response.Response
+// @Router /api/me/apiTokens [get]
+func GetMyApiTokenList(c *gin.Context) {
+	pageNum, _ := strconv.Atoi(c.Query("pageNum"))
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	tokenList, err := ApiTokenService.GetMyApiTokenList(loggedUser.ID, pageNum, pageSize)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, tokenList)
+}
+
+// @Summary 吊销我的API令牌
+// @Description 吊销当前登录用户的个人API令牌
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.RevokeApiTokenDto true "吊销API令牌请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/me/revokeApiToken [post]
+func RevokeMyApiToken(c *gin.Context) {
+	var dto entity.RevokeApiTokenDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	if err := ApiTokenService.RevokeMyApiToken(loggedUser.ID, dto.ID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 模拟登录
+// @Description 以指定用户的身份登录，用于按该用户看到的内容排查问题。返回的令牌同时携带发起人和被模拟用户的身份，
+// @Description 操作日志会记录双方；模拟期间不能修改密码、管理两步验证或再次模拟登录，不能模拟自己或超级管理员
+// @Tags 用户管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.ImpersonateDto true "模拟登录请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/adminService/impersonate [post]
+func Impersonate(c *gin.Context) {
+	var dto entity.ImpersonateDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	claims, ok := getLoggedClaims(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	ip := c.ClientIP()
+	browser := utils.GetBrowser(c)
+	Os := utils.GetOS(c)
+	device := utils.GetDevice(c)
+
+	user, tokenPair, err := SysAdminService.Impersonate(scope, claims, ip, browser, Os, device, dto.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	data, err := loginData(user, tokenPair)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	data["impersonator"] = claims.JwtAdmin
+	response.SuccessWithData(c, data)
+}
+
+// @Summary 结束模拟登录
+// @Description 删除模拟登录会话，回到发起人原来的登录会话，返回发起人的新令牌；原会话已失效时需要重新登录
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/endImpersonation [post]
+func EndImpersonation(c *gin.Context) {
+	claims, ok := getLoggedClaims(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	user, tokenPair, err := SysAdminService.EndImpersonation(claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	data, err := loginData(user, tokenPair)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, data)
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 同步LDAP目录
+// @Description 按目录创建、更新和停用用户，并按 OU 和用户组映射部门和角色；dryRun 为 true 时只返回将要执行的变更
+// @Tags 目录同步
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.LdapSyncDto true "目录同步请求"
+// @Success 200 {object} response.Response{data=entity.LdapSyncReport}
+// @Failure 400 {object} response.Response
+// @Router /api/ldapService/syncDirectory [post]
+func SyncLdapDirectory(c *gin.Context) {
+	var dto entity.LdapSyncDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	report, err := LdapService.SyncDirectory(dto.DryRun)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, report)
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 从上下文中获取当前登录用户
+func getLoggedUser(c *gin.Context) (entity.JwtAdmin, bool) {
+	userInfo, exists := c.Get(global.LoggedUser)
+	if !exists {
+		return entity.JwtAdmin{}, false
+	}
+	loggedUser, ok := userInfo.(entity.JwtAdmin)
+	return loggedUser, ok
+}
+
+// 从上下文中获取当前请求的令牌声明
+func getLoggedClaims(c *gin.Context) (*jwt.CustomClaims, bool) {
+	claimsInfo, exists := c.Get(global.LoggedClaims)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := claimsInfo.(*jwt.CustomClaims)
+	return claims, ok
+}
+
+// 获取当前登录用户的数据权限
+func getDataScope(c *gin.Context) (*entity.DataScope, error) {
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		return nil, response.ErrAdminUnauthorized
+	}
+	return DataScopeService.GetDataScope(loggedUser.ID)
+}
+
+// @Summary 查询个人资料
+// @Description 查询当前登录用户的个人资料，返回数据附带版本号
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=entity.MyProfileVo}
+// @Failure 401 {object} response.Response
+// @Router /api/me/profile [get]
+func GetMyProfile(c *gin.Context) {
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	profile, err := SysAdminService.GetMyProfile(loggedUser.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, profile)
+}
+
+// @Summary 查询左侧菜单
+// @Description 查询当前登录用户的左侧菜单列表，返回数据附带版本号
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=entity.MyMenusVo}
+// @Failure 401 {object} response.Response
+// @Router /api/me/menus [get]
+func GetMyMenus(c *gin.Context) {
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	menus, err := SysMenuService.GetMyMenus(loggedUser.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, menus)
+}
+
+// @Summary 查询权限列表
+// @Description 查询当前登录用户的权限列表，返回数据附带版本号
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=entity.MyPermissionsVo}
+// @Failure 401 {object} response.Response
+// @Router /api/me/permissions [get]
+func GetMyPermissions(c *gin.Context) {
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	permissions, err := SysMenuService.GetMyPermissions(loggedUser.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, permissions)
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 查询单点登录配置
+// @Description 登录页据此决定是否显示单点登录入口
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=entity.OidcConfigVo}
+// @Router /api/oidc/config [get]
+func GetOidcConfig(c *gin.Context) {
+	response.SuccessWithData(c, OidcService.GetOidcConfig())
+}
+
+// @Summary 发起单点登录
+// @Description 返回身份提供方的授权地址，前端保存 state 后跳转到 authUrl；
+// @Description 身份提供方重定向回前端回调页面后，比对 state 并调用 /api/oidc/login 完成登录
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=entity.OidcAuthorizeVo}
+// @Failure 400 {object} response.Response
+// @Router /api/oidc/authorize [get]
+func OidcAuthorize(c *gin.Context) {
+	authorize, err := OidcService.Authorize()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, authorize)
+}
+
+// @Summary 单点登录
+// @Description 使用身份提供方回调携带的 code 和 state 完成登录，响应与 /api/login 相同，需要两步验证时返回挑战令牌
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.OidcLoginDto true "单点登录请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/oidc/login [post]
+func OidcLogin(c *gin.Context) {
+	var dto entity.OidcLoginDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	// 获取当前登录用户的ip、浏览器、操作系统
+	ip := c.ClientIP()
+	browser := utils.GetBrowser(c)
+	Os := utils.GetOS(c)
+	device := utils.GetDevice(c)
+
+	user, tokenPair, challenge, err := OidcService.Login(ip, browser, Os, device, &dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// 需要两步验证
+	if challenge != nil {
+		response.SuccessWithData(c, challenge)
+		return
+	}
+	data, err := loginData(user, tokenPair)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, data)
+}
@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 发起通行密钥登录
+// @Description 返回传给 navigator.credentials.get() 的参数，由认证器选择通行密钥，需要启用免密码登录
+// @Tags 无需认证接口
+// @Produce json
+// @Success 200 {object} response.Response{data=entity.PasskeyOptionsVo}
+// @Failure 400 {object} response.Response
+// @Router /api/login/passkeyOptions [post]
+func BeginPasskeyLogin(c *gin.Context) {
+	options, err := PasskeyService.BeginLogin()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, options)
+}
+
+// @Summary 通行密钥登录
+// @Description 提交 navigator.credentials.get() 返回的凭据完成免密码登录，不需要再进行两步验证
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.PasskeyLoginDto true "通行密钥登录请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/login/passkey [post]
+func PasskeyLogin(c *gin.Context) {
+	var dto entity.PasskeyLoginDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	ip := c.ClientIP()
+	browser := utils.GetBrowser(c)
+	Os := utils.GetOS(c)
+	device := utils.GetDevice(c)
+
+	user, tokenPair, err := PasskeyService.Login(ip, browser, Os, device, &dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	data, err := loginData(user, tokenPair)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, data)
+}
+
+// @Summary 发起通行密钥两步验证
+// @Description 登录返回 passkeyAvailable=true 时，使用挑战令牌获取传给 navigator.credentials.get() 的参数
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.TwoFactorPasskeyOptionsDto true "发起通行密钥两步验证请求结构体"
+// @Success 200 {object} response.Response{data=entity.PasskeyOptionsVo}
+// @Failure 400 {object} response.Response
+// @Router /api/login/twoFactorPasskeyOptions [post]
+func BeginTwoFactorPasskey(c *gin.Context) {
+	var dto entity.TwoFactorPasskeyOptionsDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	options, err := PasskeyService.BeginTwoFactor(&dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, options)
+}
+
+// @Summary 通行密钥两步验证登录
+// @Description 使用通行密钥代替验证码完成两步验证
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.TwoFactorPasskeyLoginDto true "通行密钥两步验证登录请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/login/twoFactorPasskey [post]
+func LoginTwoFactorPasskey(c *gin.Context) {
+	var dto entity.TwoFactorPasskeyLoginDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	user, tokenPair, err := PasskeyService.LoginTwoFactor(&dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	data, err := loginData(user, tokenPair)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, data)
+}
+
+// @Summary 查询个人通行密钥
+// @Tags 当前用户
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]entity.SysAdminCredential}
+// @Failure 400 {object} response.Response
+// @Router /api/me/passkeys [get]
+func GetMyPasskeys(c *gin.Context) {
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	passkeys, err := PasskeyService.GetMyPasskeys(loggedUser.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, passkeys)
+}
+
+// @Summary 发起注册通行密钥
+// @Description 返回传给 navigator.credentials.create() 的参数
+// @Tags 当前用户
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=entity.PasskeyOptionsVo}
+// @Failure 400 {object} response.Response
+// @Router /api/me/beginPasskeyRegistration [post]
+func BeginPasskeyRegistration(c *gin.Context) {
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	options, err := PasskeyService.BeginRegistration(loggedUser.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, options)
+}
+
+// @Summary 完成注册通行密钥
+// @Description 提交 navigator.credentials.create() 返回的凭据和通行密钥名称
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.FinishPasskeyRegistrationDto true "完成注册通行密钥请求结构体"
+// @Success 200 {object} response.Response{data=entity.SysAdminCredential}
+// @Failure 400 {object} response.Response
+// @Router /api/me/finishPasskeyRegistration [post]
+func FinishPasskeyRegistration(c *gin.Context) {
+	var dto entity.FinishPasskeyRegistrationDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	created, err := PasskeyService.FinishRegistration(loggedUser.ID, &dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, created)
+}
+
+// @Summary 修改通行密钥名称
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.RenamePasskeyDto true "修改通行密钥名称请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/me/renamePasskey [post]
+func RenamePasskey(c *gin.Context) {
+	var dto entity.RenamePasskeyDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	if err := PasskeyService.RenamePasskey(loggedUser.ID, &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 删除通行密钥
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.DeletePasskeyDto true "删除通行密钥请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/me/deletePasskey [post]
+func DeletePasskey(c *gin.Context) {
+	var dto entity.DeletePasskeyDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	if err := PasskeyService.DeletePasskey(loggedUser.ID, &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
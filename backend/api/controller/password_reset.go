@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 找回密码
+// @Description 按用户名或邮箱申请重置密码，账号存在且可以重置时向其邮箱发送一次性的重置链接；无论账号是否存在都返回成功
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.ForgotPasswordDto true "找回密码请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/password/forgot [post]
+func ForgotPassword(c *gin.Context) {
+	var dto entity.ForgotPasswordDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	if err := PasswordResetService.ForgotPassword(c.ClientIP(), &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 重置密码
+// @Description 使用重置邮件中的令牌设置新密码，新密码需符合密码策略；成功后链接失效，已登录的会话全部退出
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.PasswordResetDto true "重置密码请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/password/reset [post]
+func ResetPasswordByMail(c *gin.Context) {
+	var dto entity.PasswordResetDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	if err := PasswordResetService.ResetPassword(c.ClientIP(), &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求参数格式错误
+func scimInvalidSyntax(c *gin.Context, err error) {
+	response.ScimFail(c, response.NewScimError(http.StatusBadRequest, response.ScimTypeInvalidSyntax, err.Error()))
+}
+
+// @Summary 查询SCIM用户列表
+// @Description 支持 filter、startIndex、count 分页和排除 groups 属性
+// @Tags SCIM
+// @Security BearerAuth
+// @Produce json
+// @Param filter query string false "过滤条件，如 userName eq \"alice\""
+// @Param startIndex query int false "起始位置，从1开始"
+// @Param count query int false "每页数量"
+// @Param excludedAttributes query string false "排除的属性"
+// @Success 200 {object} entity.ScimListResponse{Resources=[]entity.ScimUser}
+// @Failure 400 {object} response.Response
+// @Router /scim/v2/Users [get]
+func ScimListUsers(c *gin.Context) {
+	var query entity.ScimListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		scimInvalidSyntax(c, err)
+		return
+	}
+	list, err := ScimService.ListUsers(&query)
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusOK, list)
+}
+
+// @Summary 查询SCIM用户
+// @Tags SCIM
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "用户id"
+// @Success 200 {object} entity.ScimUser
+// @Failure 404 {object} response.Response
+// @Router /scim/v2/Users/{id} [get]
+func ScimGetUser(c *gin.Context) {
+	user, err := ScimService.GetUser(c.Param("id"))
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusOK, user)
+}
+
+// @Summary 创建SCIM用户
+// @Description 使用配置的默认部门和岗位创建用户，密码随机生成
+// @Tags SCIM
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.ScimUser true "用户"
+// @Success 201 {object} entity.ScimUser
+// @Failure 409 {object} response.Response
+// @Router /scim/v2/Users [post]
+func ScimCreateUser(c *gin.Context) {
+	var dto entity.ScimUser
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		scimInvalidSyntax(c, err)
+		return
+	}
+	user, err := ScimService.CreateUser(&dto)
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusCreated, user)
+}
+
+// @Summary 替换SCIM用户
+// @Description active 为 false 时停用用户并吊销会话
+// @Tags SCIM
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "用户id"
+// @Param data body entity.ScimUser true "用户"
+// @Success 200 {object} entity.ScimUser
+// @Failure 400 {object} response.Response
+// @Router /scim/v2/Users/{id} [put]
+func ScimReplaceUser(c *gin.Context) {
+	var dto entity.ScimUser
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		scimInvalidSyntax(c, err)
+		return
+	}
+	user, err := ScimService.ReplaceUser(c.Param("id"), &dto)
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusOK, user)
+}
+
+// @Summary 修改SCIM用户
+// @Description 支持 add、replace、remove 操作，active 为 false 时停用用户并吊销会话
+// @Tags SCIM
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "用户id"
+// @Param data body entity.ScimPatchRequest true "修改操作"
+// @Success 200 {object} entity.ScimUser
+// @Failure 400 {object} response.Response
+// @Router /scim/v2/Users/{id} [patch]
+func ScimPatchUser(c *gin.Context) {
+	var dto entity.ScimPatchRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		scimInvalidSyntax(c, err)
+		return
+	}
+	user, err := ScimService.PatchUser(c.Param("id"), &dto)
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusOK, user)
+}
+
+// @Summary 删除SCIM用户
+// @Tags SCIM
+// @Security BearerAuth
+// @Param id path string true "用户id"
+// @Success 204
+// @Failure 404 {object} response.Response
+// @Router /scim/v2/Users/{id} [delete]
+func ScimDeleteUser(c *gin.Context) {
+	if err := ScimService.DeleteUser(c.Param("id")); err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusNoContent, nil)
+}
+
+// @Summary 查询SCIM组列表
+// @Description 支持 filter、startIndex、count 分页和排除 members 属性
+// @Tags SCIM
+// @Security BearerAuth
+// @Produce json
+// @Param filter query string false "过滤条件，如 displayName eq \"运维\""
+// @Param startIndex query int false "起始位置，从1开始"
+// @Param count query int false "每页数量"
+// @Param excludedAttributes query string false "排除的属性"
+// @Success 200 {object} entity.ScimListResponse{Resources=[]entity.ScimGroup}
+// @Failure 400 {object} response.Response
+// @Router /scim/v2/Groups [get]
+func ScimListGroups(c *gin.Context) {
+	var query entity.ScimListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		scimInvalidSyntax(c, err)
+		return
+	}
+	list, err := ScimService.ListGroups(&query)
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusOK, list)
+}
+
+// @Summary 查询SCIM组
+// @Tags SCIM
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "角色id"
+// @Success 200 {object} entity.ScimGroup
+// @Failure 404 {object} response.Response
+// @Router /scim/v2/Groups/{id} [get]
+func ScimGetGroup(c *gin.Context) {
+	group, err := ScimService.GetGroup(c.Param("id"))
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusOK, group)
+}
+
+// @Summary 创建SCIM组
+// @Description 创建角色，菜单权限需要在角色管理中分配
+// @Tags SCIM
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.ScimGroup true "组"
+// @Success 201 {object} entity.ScimGroup
+// @Failure 409 {object} response.Response
+// @Router /scim/v2/Groups [post]
+func ScimCreateGroup(c *gin.Context) {
+	var dto entity.ScimGroup
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		scimInvalidSyntax(c, err)
+		return
+	}
+	group, err := ScimService.CreateGroup(&dto)
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusCreated, group)
+}
+
+// @Summary 替换SCIM组
+// @Tags SCIM
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "角色id"
+// @Param data body entity.ScimGroup true "组"
+// @Success 200 {object} entity.ScimGroup
+// @Failure 400 {object} response.Response
+// @Router /scim/v2/Groups/{id} [put]
+func ScimReplaceGroup(c *gin.Context) {
+	var dto entity.ScimGroup
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		scimInvalidSyntax(c, err)
+		return
+	}
+	group, err := ScimService.ReplaceGroup(c.Param("id"), &dto)
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusOK, group)
+}
+
+// @Summary 修改SCIM组
+// @Description 支持修改名称和增减成员
+// @Tags SCIM
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "角色id"
+// @Param data body entity.ScimPatchRequest true "修改操作"
+// @Success 200 {object} entity.ScimGroup
+// @Failure 400 {object} response.Response
+// @Router /scim/v2/Groups/{id} [patch]
+func ScimPatchGroup(c *gin.Context) {
+	var dto entity.ScimPatchRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		scimInvalidSyntax(c, err)
+		return
+	}
+	group, err := ScimService.PatchGroup(c.Param("id"), &dto)
+	if err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusOK, group)
+}
+
+// @Summary 删除SCIM组
+// @Tags SCIM
+// @Security BearerAuth
+// @Param id path string true "角色id"
+// @Success 204
+// @Failure 404 {object} response.Response
+// @Router /scim/v2/Groups/{id} [delete]
+func ScimDeleteGroup(c *gin.Context) {
+	if err := ScimService.DeleteGroup(c.Param("id")); err != nil {
+		response.ScimFail(c, err)
+		return
+	}
+	response.ScimSuccess(c, http.StatusNoContent, nil)
+}
+
+// @Summary 查询SCIM服务提供方配置
+// @Tags SCIM
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]any
+// @Router /scim/v2/ServiceProviderConfig [get]
+func ScimServiceProviderConfig(c *gin.Context) {
+	response.ScimSuccess(c, http.StatusOK, ScimService.GetServiceProviderConfig())
+}
+
+// @Summary 查询SCIM资源类型
+// @Tags SCIM
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} entity.ScimListResponse
+// @Router /scim/v2/ResourceTypes [get]
+func ScimResourceTypes(c *gin.Context) {
+	response.ScimSuccess(c, http.StatusOK, ScimService.GetResourceTypes())
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 退出登录
+// @Description 退出登录，删除当前登录会话，会话下签发的令牌立即失效
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/logout [post]
+func Logout(c *gin.Context) {
+	claims, ok := getLoggedClaims(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	if err := SessionService.Logout(claims.JwtAdmin.ID, claims.SessionID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 查询我的登录会话
+// @Description 查询当前用户所有的登录会话(登录设备)，当前会话的current为true
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]entity.SysSession}
+// @Failure 401 {object} response.Response
+// @Router /api/me/sessions [get]
+func GetMySessions(c *gin.Context) {
+	claims, ok := getLoggedClaims(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	sessions, err := SessionService.GetMySessions(claims.JwtAdmin.ID, claims.SessionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, sessions)
+}
+
+// @Summary 吊销登录会话
+// @Description 吊销当前用户的某个登录会话，该设备需要重新登录
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.RevokeSessionDto true "吊销会话请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/me/revokeSession [post]
+func RevokeSession(c *gin.Context) {
+	claims, ok := getLoggedClaims(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	var dto entity.RevokeSessionDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	if err := SessionService.RevokeSession(claims.JwtAdmin.ID, dto.SessionID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 吊销其他登录会话
+// @Description 吊销当前用户除当前会话以外的所有登录会话
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/me/revokeOtherSessions [post]
+func RevokeOtherSessions(c *gin.Context) {
+	claims, ok := getLoggedClaims(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	if err := SessionService.RevokeOtherSessions(claims.JwtAdmin.ID, claims.SessionID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 强制用户下线
+// @Description 删除指定用户的所有登录会话，用户需要重新登录
+// @Tags 用户管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.ForceLogoutDto true "强制下线请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/adminService/forceLogout [post]
+func ForceLogout(c *gin.Context) {
+	var dto entity.ForceLogoutDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loggedUser, _ := getLoggedUser(c)
+	if err := SessionService.ForceLogout(scope, loggedUser.ID, dto.ID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
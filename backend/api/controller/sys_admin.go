@@ -4,14 +4,15 @@ import (
 	"go-admin-server/api/entity"
 	"go-admin-server/common/response"
 	"go-admin-server/common/utils"
-	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // @Summary 用户登录
-// @Description 用户登录
+// @Description 用户登录，需要两步验证时返回挑战令牌，使用挑战令牌和验证码调用 /api/login/twoFactor 完成登录；
+// @Description 密码已过期时返回 passwordExpired=true，此时令牌只能用于修改密码和退出登录
 // @Tags 无需认证接口
 // @Accept json
 // @Produce json
@@ -29,31 +30,72 @@ func Login(c *gin.Context) {
 	ip := c.ClientIP()
 	browser := utils.GetBrowser(c)
 	Os := utils.GetOS(c)
+	device := utils.GetDevice(c)
 
 	// 用户登录
-	user, token, err := SysAdminService.Login(ip, browser, Os, &dto)
+	user, tokenPair, challenge, err := SysAdminService.Login(ip, browser, Os, device, &dto)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	// 用于前端展示的左侧菜单列表
-	leftMenuList, err := SysMenuService.GetLeftMenuList(user.ID)
+	// 需要两步验证
+	if challenge != nil {
+		response.SuccessWithData(c, challenge)
+		return
+	}
+	data, err := loginData(user, tokenPair)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
+	response.SuccessWithData(c, data)
+}
+
+// 登录成功的响应数据
+func loginData(user *entity.SysAdmin, tokenPair *jwt.TokenPair) (map[string]any, error) {
+	// 用于前端展示的左侧菜单列表
+	leftMenuList, err := SysMenuService.GetLeftMenuList(user.ID)
+	if err != nil {
+		return nil, err
+	}
 	// 当前登录用户的权限列表
 	permissionList, err := SysMenuService.GetPermissionList(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"sysAdmin":         user,
+		"token":            tokenPair.AccessToken,
+		"refreshToken":     tokenPair.RefreshToken,
+		"accessExpiresAt":  tokenPair.AccessExpiresAt,
+		"refreshExpiresAt": tokenPair.RefreshExpiresAt,
+		"passwordExpired":  tokenPair.Scope == jwt.ScopePasswordChange, // 密码已过期，需要先修改密码
+		"leftMenuList":     leftMenuList,
+		"permissionList":   permissionList,
+	}, nil
+}
+
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌随即失效
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.RefreshTokenDto true "刷新令牌请求结构体"
+// @Success 200 {object} response.Response{data=jwt.TokenPair}
+// @Failure 401 {object} response.Response
+// @Router /api/token/refresh [post]
+func RefreshToken(c *gin.Context) {
+	var dto entity.RefreshTokenDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	tokenPair, err := SysAdminService.RefreshToken(dto.RefreshToken)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	response.SuccessWithData(c, map[string]any{
-		"sysAdmin":       user,
-		"token":          token,
-		"leftMenuList":   leftMenuList,
-		"permissionList": permissionList,
-	})
+	response.SuccessWithData(c, tokenPair)
 }
 
 // @Summary 创建用户
@@ -72,7 +114,13 @@ func CreateAdmin(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := SysAdminService.CreateAdmin(&dto); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysAdminService.CreateAdmin(scope, loggedUser.ID, &dto); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -88,7 +136,7 @@ func CreateAdmin(c *gin.Context) {
 // @Param pageNum query int false "页码"
 // @Param pageSize query int false "页大小"
 // @Param username query string false "用户名"
-// @Param status query int false "状态"
+// @Param status query int false "状态：1->启用,2->禁用,3->待激活"
 // @Param beginTime query string false "开始时间"
 // @Param endTime query string false "结束时间"
 // @Success 200 {object} response.Response
@@ -103,7 +151,12 @@ func GetAdminList(c *gin.Context) {
 	beginTime := c.Query("beginTime")
 	endTime := c.Query("endTime")
 
-	adminListVo, err := SysAdminService.JointGetAdminList(pageNum, pageSize, status, username, beginTime, endTime)
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	adminListVo, err := SysAdminService.JointGetAdminList(scope, pageNum, pageSize, status, username, beginTime, endTime)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -127,7 +180,12 @@ func GetAdminById(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	sysAdmin, err := SysAdminService.JointGetAdminById(dto.ID)
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	sysAdmin, err := SysAdminService.JointGetAdminById(scope, dto.ID)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -151,7 +209,14 @@ func UpdateAdmin(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := SysAdminService.UpdateSysAdmin(&dto); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// 获取数据权限时已校验登录状态
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysAdminService.UpdateSysAdmin(scope, loggedUser.ID, &dto); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -174,7 +239,14 @@ func DeleteAdmin(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := SysAdminService.DeleteAdmin(dto.ID); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// 获取数据权限时已校验登录状态
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysAdminService.DeleteAdmin(scope, loggedUser.ID, dto.ID); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -198,7 +270,14 @@ func UpdateAdminStatus(c *gin.Context) {
 		return
 	}
 
-	if err := SysAdminService.UpdateAdminStatus(&dto); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// 获取数据权限时已校验登录状态
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysAdminService.UpdateAdminStatus(scope, loggedUser.ID, &dto); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -222,7 +301,43 @@ func ResetPassword(c *gin.Context) {
 		return
 	}
 
-	if err := SysAdminService.ResetPassword(&dto); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysAdminService.ResetPassword(scope, loggedUser.ID, &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 解除用户登录锁定
+// @Description 清除用户因登录失败次数过多产生的锁定状态和失败次数
+// @Tags 用户管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.UnlockAdminDto true "解除登录锁定请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/adminService/unlockAdmin [post]
+func UnlockAdmin(c *gin.Context) {
+	var dto entity.UnlockAdminDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysAdminService.UnlockAdmin(scope, loggedUser.ID, dto.ID); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -241,13 +356,17 @@ func ResetPassword(c *gin.Context) {
 // @Router /api/adminService/updatePersonal [post]
 func UpdatePersonal(c *gin.Context) {
 	// id要通过jwt获取(当前登录用户)
-	id, _ := c.Get(global.LoggedUser)
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
 	var dto entity.UpdatePersonalDto
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := SysAdminService.UpdatePersonal(id.(uint), &dto); err != nil {
+	if err := SysAdminService.UpdatePersonal(loggedUser.ID, &dto); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -266,13 +385,17 @@ func UpdatePersonal(c *gin.Context) {
 // @Router /api/adminService/updatePassword [post]
 func UpdatePassword(c *gin.Context) {
 	// id要通过jwt获取(当前登录用户)
-	id, _ := c.Get(global.LoggedUser)
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
 	var dto entity.UpdatePasswordDto
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := SysAdminService.UpdatePassword(id.(uint), &dto); err != nil {
+	if err := SysAdminService.UpdatePassword(loggedUser.ID, &dto); err != nil {
 		response.Error(c, err)
 		return
 	}
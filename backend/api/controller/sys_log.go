@@ -20,6 +20,10 @@ import (
 // @Param loginStatus query int false "登录状态"
 // @Param beginTime query string false "开始时间"
 // @Param endTime query string false "结束时间"
+// @Param country query string false "国家"
+// @Param region query string false "省份/地区"
+// @Param city query string false "城市"
+// @Param isp query string false "运营商"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Router /api/logService/getLoginLogList [get]
@@ -30,8 +34,19 @@ func GetLoginLogList(c *gin.Context) {
 	loginStaus, _ := strconv.ParseUint(c.Query("loginStatus"), 10, 64)
 	beginTime := c.Query("beginTime")
 	endTime := c.Query("endTime")
+	location := entity.LoginLocationQuery{
+		Country: c.Query("country"),
+		Region:  c.Query("region"),
+		City:    c.Query("city"),
+		Isp:     c.Query("isp"),
+	}
 
-	logListVo, err := LogService.GetLoginLogList(pageNum, pageSize, username, beginTime, endTime, uint(loginStaus))
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	logListVo, err := LogService.GetLoginLogList(scope, pageNum, pageSize, username, beginTime, endTime, uint(loginStaus), location)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -55,7 +70,12 @@ func DeleteLoginLog(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := LogService.DeleteLoginLog(dto.ID); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := LogService.DeleteLoginLog(scope, dto.ID); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -78,7 +98,12 @@ func BatchDeleteLoginLog(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := LogService.BatchDeleteLoginLog(dto.Ids); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := LogService.BatchDeleteLoginLog(scope, dto.Ids); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -106,7 +131,12 @@ func GetOpLogList(c *gin.Context) {
 	beginTime := c.Query("beginTime")
 	endTime := c.Query("endTime")
 
-	logListVo, err := LogService.GetOperationLogList(pageNum, pageSize, username, beginTime, endTime)
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	logListVo, err := LogService.GetOperationLogList(scope, pageNum, pageSize, username, beginTime, endTime)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -130,7 +160,12 @@ func DeleteOpLog(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := LogService.DeleteOpLog(dto.ID); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := LogService.DeleteOpLog(scope, dto.ID); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -153,9 +188,27 @@ func BatchDeleteOpLog(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := LogService.BatchDeleteOpLog(dto.Ids); err != nil {
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := LogService.BatchDeleteOpLog(scope, dto.Ids); err != nil {
 		response.Error(c, err)
 		return
 	}
 	response.Success(c)
 }
+
+// @Summary 查询日志写入指标
+// @Description 查询登录日志、操作日志异步写入的运行指标，包括已写入、已丢弃、写入失败和等待写入的日志数
+// @Tags 日志管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=logwriter.Stats}
+// @Failure 400 {object} response.Response
+// @Router /api/logService/getLogWriterStats [get]
+func GetLogWriterStats(c *gin.Context) {
+	response.SuccessWithData(c, LogService.GetLogWriterStats())
+}
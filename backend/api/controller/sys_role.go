@@ -24,7 +24,8 @@ func CreateRole(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := SysRoleService.CreateRole(&dto); err != nil {
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysRoleService.CreateRole(loggedUser.ID, &dto); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -103,7 +104,8 @@ func UpdateRole(c *gin.Context) {
 		return
 	}
 
-	if err := SysRoleService.UpdateRole(&dto); err != nil {
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysRoleService.UpdateRole(loggedUser.ID, &dto); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -126,7 +128,9 @@ func DeleteRole(c *gin.Context) {
 		response.ValidationError(c, err)
 		return
 	}
-	if err := SysRoleService.DeleteRole(dto.ID); err != nil {
+
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysRoleService.DeleteRole(loggedUser.ID, dto.ID); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -150,7 +154,8 @@ func UpdateRoleStatus(c *gin.Context) {
 		return
 	}
 
-	if err := SysRoleService.UpdateRoleStatus(&dto); err != nil {
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysRoleService.UpdateRoleStatus(loggedUser.ID, &dto); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -199,13 +204,13 @@ func AssignRoleMenus(c *gin.Context) {
 }
 
 // @Summary 查询角色权限列表
-// @Description 查询角色权限列表
+// @Description 查询角色权限列表，分别返回直接分配的权限和从父角色继承的权限
 // @Tags 角色管理
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param data body entity.GetRoleMenusDto true "查询角色权限"
-// @Success 200 {object} response.Response
+// @Success 200 {object} response.Response{data=entity.RoleMenusVo}
 // @Failure 400 {object} response.Response
 // @Router /api/roleService/getRoleMenus [post]
 func GetRoleMenus(c *gin.Context) {
@@ -221,3 +226,140 @@ func GetRoleMenus(c *gin.Context) {
 	}
 	response.SuccessWithData(c, roleMenus)
 }
+
+// @Summary 查询角色数据权限
+// @Description 查询角色的数据权限范围，以及自定义数据权限时可访问的部门
+// @Tags 角色管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.GetRoleDataScopeDto true "查询角色数据权限"
+// @Success 200 {object} response.Response{data=entity.RoleDataScopeVo}
+// @Failure 400 {object} response.Response
+// @Router /api/roleService/getRoleDataScope [post]
+func GetRoleDataScope(c *gin.Context) {
+	var dto entity.GetRoleDataScopeDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	dataScope, err := SysRoleService.GetRoleDataScope(dto.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, dataScope)
+}
+
+// @Summary 分配角色数据权限
+// @Description 设置角色的数据权限范围：1->全部数据,2->自定义部门,3->本部门,4->本部门及以下,5->仅本人
+// @Tags 角色管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.AssignRoleDataScopeDto true "分配角色数据权限"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/roleService/assignRoleDataScope [post]
+func AssignRoleDataScope(c *gin.Context) {
+	var dto entity.AssignRoleDataScopeDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	if err := SysRoleService.AssignRoleDataScope(&dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 查询拥有角色的用户列表
+// @Description 查询拥有该角色的用户列表，只返回数据权限范围内的用户
+// @Tags 角色管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param roleId query int true "角色id"
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "页大小"
+// @Param username query string false "用户名"
+// @Success 200 {object} response.Response{data=entity.RoleAdminListVo}
+// @Failure 400 {object} response.Response
+// @Router /api/roleService/getRoleAdminList [get]
+func GetRoleAdminList(c *gin.Context) {
+	roleId, _ := strconv.ParseUint(c.Query("roleId"), 10, 64)
+	pageNum, _ := strconv.Atoi(c.Query("pageNum"))
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
+	username := c.Query("username")
+
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	adminList, err := SysRoleService.GetRoleAdminList(scope, uint(roleId), pageNum, pageSize, username)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, adminList)
+}
+
+// @Summary 批量为角色添加用户
+// @Description 批量为角色添加用户，可设置授权有效期用于临时授权，已拥有该角色的用户更新有效期和原因
+// @Tags 角色管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.AddRoleAdminsDto true "添加角色用户请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/roleService/addRoleAdmins [post]
+func AddRoleAdmins(c *gin.Context) {
+	var dto entity.AddRoleAdminsDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysRoleService.AddRoleAdmins(scope, loggedUser.ID, &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 批量从角色中移除用户
+// @Description 批量从角色中移除用户
+// @Tags 角色管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.RoleAdminsDto true "角色用户请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/roleService/removeRoleAdmins [post]
+func RemoveRoleAdmins(c *gin.Context) {
+	var dto entity.RoleAdminsDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loggedUser, _ := getLoggedUser(c)
+	if err := SysRoleService.RemoveRoleAdmins(scope, loggedUser.ID, &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
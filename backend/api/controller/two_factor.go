@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 两步验证登录
+// @Description 使用登录返回的挑战令牌和验证码(或恢复码)完成登录；首次绑定验证器时同时返回恢复码
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.TwoFactorLoginDto true "两步验证登录请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/login/twoFactor [post]
+func LoginTwoFactor(c *gin.Context) {
+	var dto entity.TwoFactorLoginDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	user, tokenPair, recoveryCodes, err := TwoFactorService.LoginTwoFactor(&dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	data, err := loginData(user, tokenPair)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if recoveryCodes != nil {
+		data["recoveryCodes"] = recoveryCodes.RecoveryCodes
+	}
+	response.SuccessWithData(c, data)
+}
+
+// @Summary 登录时绑定验证器
+// @Description 所属角色要求两步验证但尚未绑定验证器时，使用挑战令牌获取密钥和二维码
+// @Tags 无需认证接口
+// @Accept json
+// @Produce json
+// @Param data body entity.TwoFactorLoginSetupDto true "登录时绑定验证器请求结构体"
+// @Success 200 {object} response.Response{data=entity.TwoFactorSetupVo}
+// @Failure 400 {object} response.Response
+// @Router /api/login/twoFactorSetup [post]
+func LoginSetupTwoFactor(c *gin.Context) {
+	var dto entity.TwoFactorLoginSetupDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	setup, err := TwoFactorService.LoginSetupTwoFactor(&dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, setup)
+}
+
+// @Summary 绑定验证器
+// @Description 生成TOTP密钥和二维码，使用验证器App扫码后调用开启两步验证接口
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=entity.TwoFactorSetupVo}
+// @Failure 400 {object} response.Response
+// @Router /api/me/setupTwoFactor [post]
+func SetupTwoFactor(c *gin.Context) {
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	setup, err := TwoFactorService.SetupTwoFactor(loggedUser.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, setup)
+}
+
+// @Summary 开启两步验证
+// @Description 验证验证器App中的验证码后开启两步验证，返回只显示一次的恢复码
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.EnableTwoFactorDto true "开启两步验证请求结构体"
+// @Success 200 {object} response.Response{data=entity.RecoveryCodesVo}
+// @Failure 400 {object} response.Response
+// @Router /api/me/enableTwoFactor [post]
+func EnableTwoFactor(c *gin.Context) {
+	var dto entity.EnableTwoFactorDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	recoveryCodes, err := TwoFactorService.EnableTwoFactor(loggedUser.ID, &dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, recoveryCodes)
+}
+
+// @Summary 关闭两步验证
+// @Description 验证密码和验证码(或恢复码)后关闭两步验证，所属角色要求两步验证时不能关闭
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.DisableTwoFactorDto true "关闭两步验证请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/me/disableTwoFactor [post]
+func DisableTwoFactor(c *gin.Context) {
+	var dto entity.DisableTwoFactorDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	if err := TwoFactorService.DisableTwoFactor(loggedUser.ID, &dto); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
+
+// @Summary 重新生成恢复码
+// @Description 验证验证码后重新生成恢复码，原有的恢复码全部作废
+// @Tags 当前用户
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.RegenerateRecoveryCodesDto true "重新生成恢复码请求结构体"
+// @Success 200 {object} response.Response{data=entity.RecoveryCodesVo}
+// @Failure 400 {object} response.Response
+// @Router /api/me/regenerateRecoveryCodes [post]
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var dto entity.RegenerateRecoveryCodesDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	loggedUser, ok := getLoggedUser(c)
+	if !ok {
+		response.Error(c, response.ErrAdminUnauthorized)
+		return
+	}
+	recoveryCodes, err := TwoFactorService.RegenerateRecoveryCodes(loggedUser.ID, &dto)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessWithData(c, recoveryCodes)
+}
+
+// @Summary 重置用户两步验证
+// @Description 关闭用户的两步验证并清除密钥和恢复码，用于用户丢失验证器的情况
+// @Tags 用户管理
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body entity.ResetTwoFactorDto true "重置两步验证请求结构体"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/adminService/resetTwoFactor [post]
+func ResetTwoFactor(c *gin.Context) {
+	var dto entity.ResetTwoFactorDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	scope, err := getDataScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loggedUser, _ := getLoggedUser(c)
+	if err := TwoFactorService.ResetTwoFactor(scope, loggedUser.ID, dto.ID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c)
+}
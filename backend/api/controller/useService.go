@@ -11,4 +11,16 @@ var (
 	SysAdminService = &service.SysAdminService{}
 	UploadService   = &service.UploadService{}
 	LogService      = &service.SysLogService{}
+	SessionService  = &service.SysSessionService{}
+
+	TwoFactorService = &service.TwoFactorService{}
+	DataScopeService = &service.DataScopeService{}
+	ApiTokenService  = &service.ApiTokenService{}
+	OidcService      = &service.OidcService{}
+	LdapService      = &service.LdapService{}
+	ScimService      = &service.ScimService{}
+
+	PasswordResetService   = &service.PasswordResetService{}
+	AdminInvitationService = &service.AdminInvitationService{}
+	PasskeyService         = &service.PasskeyService{}
 )
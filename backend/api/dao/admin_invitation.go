@@ -0,0 +1,58 @@
+package dao
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+
+	"gorm.io/gorm"
+)
+
+type AdminInvitationDao struct{}
+
+// 创建待激活的用户、分配角色并保存邀请
+func (d *AdminInvitationDao) CreateInvitedAdmin(roleIds []uint, user *entity.SysAdmin, invitation *entity.SysAdminInvitation) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := createAdmin(tx, roleIds, user); err != nil {
+			return err
+		}
+		invitation.AdminID = user.ID
+		return tx.Create(invitation).Error
+	})
+}
+
+// 根据用户id获取邀请
+func (d *AdminInvitationDao) GetInvitationByAdminId(adminId uint) (*entity.SysAdminInvitation, error) {
+	var invitation entity.SysAdminInvitation
+	if err := global.DB.Where("admin_id = ?", adminId).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// 根据令牌摘要获取邀请
+func (d *AdminInvitationDao) GetInvitationByTokenHash(tokenHash string) (*entity.SysAdminInvitation, error) {
+	var invitation entity.SysAdminInvitation
+	if err := global.DB.Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// 更新邀请，重新发送时更换令牌和过期时间
+func (d *AdminInvitationDao) UpdateInvitation(invitation *entity.SysAdminInvitation) error {
+	return global.DB.Save(invitation).Error
+}
+
+// 接受邀请：删除邀请并保存已激活的用户。邀请已被删除(并发接受或已撤销)时返回 gorm.ErrRecordNotFound
+func (d *AdminInvitationDao) ActivateAdmin(invitationId uint, user *entity.SysAdmin) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", invitationId).Delete(&entity.SysAdminInvitation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Save(user).Error
+	})
+}
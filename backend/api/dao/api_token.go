@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"time"
+)
+
+type ApiTokenDao struct{}
+
+// 创建API令牌
+func (d *ApiTokenDao) CreateApiToken(token *entity.SysApiToken) error {
+	return global.DB.Create(token).Error
+}
+
+// 根据令牌哈希查询API令牌，用于接口认证
+func (d *ApiTokenDao) GetApiTokenByHash(tokenHash string) (*entity.SysApiToken, error) {
+	var token entity.SysApiToken
+	if err := global.DB.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// 分页查询数据权限范围内用户的API令牌，adminId 不为0时只查询该用户的令牌
+func (d *ApiTokenDao) GetApiTokenList(scope *entity.DataScope, pageNum, pageSize int, adminId uint, name string) ([]entity.ApiTokenVo, int, error) {
+	query := global.DB.Model(&entity.SysApiToken{}).
+		Joins("JOIN sys_admin a ON sys_api_token.admin_id = a.id").
+		Scopes(adminDataScope(scope, "a"))
+	if adminId != 0 {
+		query = query.Where("sys_api_token.admin_id = ?", adminId)
+	}
+	if name != "" {
+		query = query.Where("sys_api_token.name LIKE ?", "%"+name+"%")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	tokenList := []entity.ApiTokenVo{}
+	err := query.Select("sys_api_token.*,a.username").
+		Limit(pageSize).Offset((pageNum - 1) * pageSize).
+		Order("sys_api_token.created_at DESC").
+		Scan(&tokenList).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return tokenList, int(count), nil
+}
+
+// 根据id查询数据权限范围内用户的API令牌
+func (d *ApiTokenDao) GetScopedApiToken(scope *entity.DataScope, id uint) (*entity.SysApiToken, error) {
+	var token entity.SysApiToken
+	err := global.DB.Model(&entity.SysApiToken{}).
+		Joins("JOIN sys_admin a ON sys_api_token.admin_id = a.id").
+		Scopes(adminDataScope(scope, "a")).
+		Where("sys_api_token.id = ?", id).
+		Select("sys_api_token.*").
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// 删除API令牌
+func (d *ApiTokenDao) DeleteApiToken(id uint) error {
+	return global.DB.Where("id = ?", id).Delete(&entity.SysApiToken{}).Error
+}
+
+// 记录令牌的最近使用时间和ip
+func (d *ApiTokenDao) TouchApiToken(id uint, ip string, now time.Time) error {
+	return global.DB.Model(&entity.SysApiToken{}).Where("id = ?", id).
+		Updates(map[string]any{"last_used_at": utils.HTime{Time: now}, "last_used_ip": ip}).Error
+}
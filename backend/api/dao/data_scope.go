@@ -0,0 +1,130 @@
+package dao
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+
+	"gorm.io/gorm"
+)
+
+type DataScopeDao struct{}
+
+// 查询角色自定义的部门id
+func (d *DataScopeDao) GetRoleDeptIds(roleIds []uint) ([]uint, error) {
+	var deptIds []uint
+	err := global.DB.Model(&entity.SysRoleDept{}).Where("role_id IN (?)", roleIds).Distinct().Pluck("dept_id", &deptIds).Error
+	if err != nil {
+		return nil, err
+	}
+	return deptIds, nil
+}
+
+// 根据 parent_id 查询部门及其全部下级部门的id
+func (d *DataScopeDao) GetDeptSubtreeIds(deptIds []uint) ([]uint, error) {
+	var depts []entity.SysDept
+	if err := global.DB.Model(&entity.SysDept{}).Select("id,parent_id").Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	children := make(map[uint][]uint)
+	for _, dept := range depts {
+		if dept.ParentID != nil {
+			children[*dept.ParentID] = append(children[*dept.ParentID], dept.ID)
+		}
+	}
+
+	// 广度优先遍历部门树，visited 同时防止数据异常时出现环
+	visited := make(map[uint]bool)
+	queue := append([]uint{}, deptIds...)
+	var subtree []uint
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		subtree = append(subtree, id)
+		queue = append(queue, children[id]...)
+	}
+	return subtree, nil
+}
+
+// 查询角色的数据权限
+func (d *DataScopeDao) GetRoleDataScope(roleID uint) (*entity.RoleDataScopeVo, error) {
+	var role entity.SysRole
+	if err := global.DB.Select("id,data_scope").Where("id = ?", roleID).First(&role).Error; err != nil {
+		return nil, err
+	}
+	vo := &entity.RoleDataScopeVo{DataScope: role.DataScope, DeptIDs: []uint{}}
+	if role.DataScope == entity.DataScopeCustom {
+		deptIds, err := d.GetRoleDeptIds([]uint{roleID})
+		if err != nil {
+			return nil, err
+		}
+		vo.DeptIDs = deptIds
+	}
+	return vo, nil
+}
+
+// 替换角色的数据权限
+func (d *DataScopeDao) AssignRoleDataScope(roleID, dataScope uint, deptIds []uint) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.SysRole{}).Where("id = ?", roleID).Update("data_scope", dataScope).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", roleID).Delete(&entity.SysRoleDept{}).Error; err != nil {
+			return err
+		}
+		if len(deptIds) == 0 {
+			return nil
+		}
+		var roleDepts []entity.SysRoleDept
+		for _, deptId := range deptIds {
+			roleDepts = append(roleDepts, entity.SysRoleDept{RoleID: roleID, DeptID: deptId})
+		}
+		return tx.Create(&roleDepts).Error
+	})
+}
+
+// 判断部门列表中的部门id是否都存在
+func (d *DataScopeDao) ExistsDeptIds(deptIds []uint) (bool, error) {
+	var count int64
+	err := global.DB.Model(&entity.SysDept{}).Where("id IN (?)", deptIds).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count == int64(len(deptIds)), nil
+}
+
+// 按数据权限过滤用户，table 为查询中用户表的表名或别名
+func adminDataScope(scope *entity.DataScope, table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if scope.All {
+			return db
+		}
+		return db.Where(adminScopeCondition(scope, table))
+	}
+}
+
+// 按数据权限过滤日志，column 为日志表中用户id或用户名的列，对应用户表的 key 列
+func logDataScope(scope *entity.DataScope, column, key string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if scope.All {
+			return db
+		}
+		admins := global.DB.Model(&entity.SysAdmin{}).Select(key).Where(adminScopeCondition(scope, "sys_admin"))
+		return db.Where(column+" IN (?)", admins)
+	}
+}
+
+// 可访问部门内的用户，以及允许访问本人数据时的用户自身
+func adminScopeCondition(scope *entity.DataScope, table string) *gorm.DB {
+	cond := global.DB.Where("1 = 0")
+	if len(scope.DeptIDs) > 0 {
+		cond = cond.Or(table+".dept_id IN (?)", scope.DeptIDs)
+	}
+	if scope.AdminID != 0 {
+		cond = cond.Or(table+".id = ?", scope.AdminID)
+	}
+	return cond
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"errors"
+	"go-admin-server/global"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 登录失败计数与锁定状态，基于redis实现
+// subject 为锁定对象，格式为 "user:用户名" 或 "ip:IP地址"
+type LoginGuardDao struct{}
+
+// 获取锁定截止时间，未锁定时返回零值
+func (d *LoginGuardDao) GetLockedUntil(subject string) (time.Time, error) {
+	value, err := global.RDB.Get(ctx, global.LoginLockPrefix+subject).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return time.Unix(value, 0), nil
+}
+
+// 记录一次登录失败，返回统计窗口内的失败次数
+func (d *LoginGuardDao) IncrFailures(subject string, window time.Duration) (int64, error) {
+	key := global.LoginFailPrefix + subject
+	count, err := global.RDB.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// 第一次失败时开始计算统计窗口
+	if count == 1 {
+		if err := global.RDB.Expire(ctx, key, window).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// 锁定次数加一并返回，ttl 内没有再次被锁定则锁定次数清零
+func (d *LoginGuardDao) IncrLockLevel(subject string, ttl time.Duration) (int64, error) {
+	key := global.LoginLockLevelPrefix + subject
+	level, err := global.RDB.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if err := global.RDB.Expire(ctx, key, ttl).Err(); err != nil {
+		return 0, err
+	}
+	return level, nil
+}
+
+// 锁定至指定时间，同时清空失败次数
+func (d *LoginGuardDao) Lock(subject string, until time.Time) error {
+	pipe := global.RDB.TxPipeline()
+	pipe.Set(ctx, global.LoginLockPrefix+subject, until.Unix(), time.Until(until))
+	pipe.Del(ctx, global.LoginFailPrefix+subject)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 清空失败次数
+func (d *LoginGuardDao) ClearFailures(subject string) error {
+	return global.RDB.Del(ctx, global.LoginFailPrefix+subject).Err()
+}
+
+// 解除锁定，同时清空失败次数和锁定次数
+func (d *LoginGuardDao) Unlock(subject string) error {
+	return global.RDB.Del(ctx,
+		global.LoginLockPrefix+subject,
+		global.LoginFailPrefix+subject,
+		global.LoginLockLevelPrefix+subject,
+	).Err()
+}
@@ -0,0 +1,43 @@
+package dao
+
+import (
+	"encoding/json"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 单点登录授权请求，基于redis实现
+type OidcDao struct{}
+
+func oidcStateKey(state string) string {
+	return global.OidcStatePrefix + state
+}
+
+// 保存授权请求
+func (d *OidcDao) SaveAuthRequest(state string, request *entity.OidcAuthRequest, ttl time.Duration) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return global.RDB.Set(ctx, oidcStateKey(state), data, ttl).Err()
+}
+
+// 取出并删除授权请求，每个 state 只能使用一次，不存在或已过期时返回nil
+func (d *OidcDao) TakeAuthRequest(state string) (*entity.OidcAuthRequest, error) {
+	data, err := global.RDB.GetDel(ctx, oidcStateKey(state)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var request entity.OidcAuthRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
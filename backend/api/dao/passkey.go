@@ -0,0 +1,102 @@
+package dao
+
+import (
+	"encoding/json"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 通行密钥：凭据保存在数据库中，注册和验证会话基于redis实现
+type PasskeyDao struct{}
+
+func passkeySessionKey(sessionId string) string {
+	return global.PasskeySessionPrefix + sessionId
+}
+
+// 查询用户的全部通行密钥
+func (d *PasskeyDao) GetPasskeysByAdminId(adminId uint) ([]entity.SysAdminCredential, error) {
+	passkeys := []entity.SysAdminCredential{}
+	if err := global.DB.Where("admin_id = ?", adminId).Order("created_at DESC").Find(&passkeys).Error; err != nil {
+		return nil, err
+	}
+	return passkeys, nil
+}
+
+// 统计用户的通行密钥数量
+func (d *PasskeyDao) CountPasskeys(adminId uint) (int64, error) {
+	var count int64
+	err := global.DB.Model(&entity.SysAdminCredential{}).Where("admin_id = ?", adminId).Count(&count).Error
+	return count, err
+}
+
+// 查询用户的通行密钥
+func (d *PasskeyDao) GetPasskey(adminId, id uint) (*entity.SysAdminCredential, error) {
+	var passkey entity.SysAdminCredential
+	if err := global.DB.Where("id = ? AND admin_id = ?", id, adminId).First(&passkey).Error; err != nil {
+		return nil, err
+	}
+	return &passkey, nil
+}
+
+// 凭据id是否已注册
+func (d *PasskeyDao) ExistsCredentialId(credentialId string) (bool, error) {
+	var count int64
+	err := global.DB.Model(&entity.SysAdminCredential{}).Where("credential_id = ?", credentialId).Count(&count).Error
+	return count > 0, err
+}
+
+// 创建通行密钥
+func (d *PasskeyDao) CreatePasskey(passkey *entity.SysAdminCredential) error {
+	return global.DB.Create(passkey).Error
+}
+
+// 修改通行密钥名称
+func (d *PasskeyDao) RenamePasskey(id uint, name string) error {
+	return global.DB.Model(&entity.SysAdminCredential{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// 删除通行密钥
+func (d *PasskeyDao) DeletePasskey(id uint) error {
+	return global.DB.Where("id = ?", id).Delete(&entity.SysAdminCredential{}).Error
+}
+
+// 验证通过后更新签名计数、同步状态和最近使用记录
+func (d *PasskeyDao) TouchPasskey(id uint, signCount uint32, backupState bool, ip string, now time.Time) error {
+	return global.DB.Model(&entity.SysAdminCredential{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"sign_count":   signCount,
+			"backup_state": backupState,
+			"last_used_at": utils.HTime{Time: now},
+			"last_used_ip": ip,
+		}).Error
+}
+
+// 保存注册或验证会话
+func (d *PasskeyDao) SaveSession(sessionId string, session *entity.PasskeySession, ttl time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return global.RDB.Set(ctx, passkeySessionKey(sessionId), data, ttl).Err()
+}
+
+// 取出并删除会话，每个会话只能使用一次，不存在或已过期时返回nil
+func (d *PasskeyDao) TakeSession(sessionId string) (*entity.PasskeySession, error) {
+	data, err := global.RDB.GetDel(ctx, passkeySessionKey(sessionId)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var session entity.PasskeySession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,47 @@
+package dao
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"time"
+)
+
+type PasswordHistoryDao struct{}
+
+// 查询用户最近使用过的 n 个密码哈希
+func (d *PasswordHistoryDao) GetRecentPasswords(adminId uint, n int) ([]string, error) {
+	var passwords []string
+	err := global.DB.Model(&entity.SysPasswordHistory{}).
+		Where("admin_id = ?", adminId).
+		Order("id DESC").
+		Limit(n).
+		Pluck("password", &passwords).Error
+	if err != nil {
+		return nil, err
+	}
+	return passwords, nil
+}
+
+// 记录密码历史，只保留最近的 keep 条记录
+func (d *PasswordHistoryDao) AddPasswordHistory(adminId uint, password string, keep int) error {
+	history := &entity.SysPasswordHistory{
+		AdminID:   adminId,
+		Password:  password,
+		CreatedAt: utils.HTime{Time: time.Now()},
+	}
+	if err := global.DB.Create(history).Error; err != nil {
+		return err
+	}
+	// 删除超出保留数量的旧记录
+	var keepIds []uint
+	err := global.DB.Model(&entity.SysPasswordHistory{}).
+		Where("admin_id = ?", adminId).
+		Order("id DESC").
+		Limit(keep).
+		Pluck("id", &keepIds).Error
+	if err != nil {
+		return err
+	}
+	return global.DB.Where("admin_id = ? AND id NOT IN ?", adminId, keepIds).Delete(&entity.SysPasswordHistory{}).Error
+}
@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"encoding/json"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 密码重置令牌，基于redis实现，只保存令牌的摘要
+type PasswordResetDao struct{}
+
+func passwordResetKey(tokenHash string) string {
+	return global.PasswordResetPrefix + tokenHash
+}
+
+func passwordResetAdminKey(adminId uint) string {
+	return global.PasswordResetAdminPrefix + strconv.FormatUint(uint64(adminId), 10)
+}
+
+// 保存令牌，同一用户之前申请的令牌随即失效
+func (d *PasswordResetDao) SaveToken(tokenHash string, token *entity.PasswordResetToken, ttl time.Duration) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	adminKey := passwordResetAdminKey(token.AdminID)
+	oldHash, err := global.RDB.Get(ctx, adminKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	pipe := global.RDB.TxPipeline()
+	if oldHash != "" {
+		pipe.Del(ctx, passwordResetKey(oldHash))
+	}
+	pipe.Set(ctx, passwordResetKey(tokenHash), data, ttl)
+	pipe.Set(ctx, adminKey, tokenHash, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// 查询令牌，不存在或已过期时返回nil
+func (d *PasswordResetDao) GetToken(tokenHash string) (*entity.PasswordResetToken, error) {
+	data, err := global.RDB.Get(ctx, passwordResetKey(tokenHash)).Bytes()
+	return decodePasswordResetToken(data, err)
+}
+
+// 取出并删除令牌，每个令牌只能使用一次，已被使用时返回nil
+func (d *PasswordResetDao) TakeToken(tokenHash string) (*entity.PasswordResetToken, error) {
+	data, err := global.RDB.GetDel(ctx, passwordResetKey(tokenHash)).Bytes()
+	token, err := decodePasswordResetToken(data, err)
+	if err != nil || token == nil {
+		return token, err
+	}
+	if err := global.RDB.Del(ctx, passwordResetAdminKey(token.AdminID)).Err(); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func decodePasswordResetToken(data []byte, err error) (*entity.PasswordResetToken, error) {
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var token entity.PasswordResetToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// 开始发送冷却，冷却期内返回false
+func (d *PasswordResetDao) AcquireCooldown(adminId uint, cooldown time.Duration) (bool, error) {
+	key := global.PasswordResetCooldownPrefix + strconv.FormatUint(uint64(adminId), 10)
+	return global.RDB.SetNX(ctx, key, 1, cooldown).Result()
+}
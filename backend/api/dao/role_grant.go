@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 只保留当前有效的角色授权：已到生效时间且未到失效时间，table 为查询中用户角色表的表名或别名
+func activeGrant(table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		now := time.Now()
+		return db.Where("("+table+".valid_from IS NULL OR "+table+".valid_from <= ?)", now).
+			Where("("+table+".valid_until IS NULL OR "+table+".valid_until > ?)", now)
+	}
+}
+
+// 查询已过期的角色授权
+func (d *SysRoleDao) GetExpiredGrants(now time.Time, limit int) ([]entity.SysAdminRole, error) {
+	var grants []entity.SysAdminRole
+	err := global.DB.Where("valid_until IS NOT NULL AND valid_until <= ?", now).
+		Order("valid_until").
+		Limit(limit).
+		Find(&grants).Error
+	if err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// 删除已过期的角色授权，授权在此期间被延期时不删除，返回是否删除
+func (d *SysRoleDao) DeleteExpiredGrant(adminId, roleId uint, now time.Time) (bool, error) {
+	result := global.DB.
+		Where("admin_id = ? AND role_id = ?", adminId, roleId).
+		Where("valid_until IS NOT NULL AND valid_until <= ?", now).
+		Delete(&entity.SysAdminRole{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// 查询用户角色授权下一次生效或失效的时间，没有时返回 nil，用于设置权限缓存的过期时间
+func (d *SysRoleDao) GetNextGrantChange(adminId uint, now time.Time) (*time.Time, error) {
+	var grants []entity.SysAdminRole
+	err := global.DB.Select("valid_from", "valid_until").
+		Where("admin_id = ?", adminId).
+		Where("(valid_from IS NOT NULL AND valid_from > ?) OR (valid_until IS NOT NULL AND valid_until > ?)", now, now).
+		Find(&grants).Error
+	if err != nil {
+		return nil, err
+	}
+	var next *time.Time
+	for _, grant := range grants {
+		for _, t := range []*utils.HTime{grant.ValidFrom, grant.ValidUntil} {
+			if t != nil && t.After(now) && (next == nil || t.Before(*next)) {
+				next = &t.Time
+			}
+		}
+	}
+	return next, nil
+}
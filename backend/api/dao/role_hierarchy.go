@@ -0,0 +1,150 @@
+package dao
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+)
+
+// 查询全部角色的继承关系，角色数量较少，直接加载到内存中处理
+func (d *SysRoleDao) GetRoleTree() (map[uint]entity.SysRole, error) {
+	var roles []entity.SysRole
+	if err := global.DB.Select("id,role_key,role_status,data_scope,parent_id").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	tree := make(map[uint]entity.SysRole, len(roles))
+	for _, role := range roles {
+		tree[role.ID] = role
+	}
+	return tree, nil
+}
+
+// RoleAncestors 沿父角色链向上查找角色继承的祖先角色，不包含角色自身，
+// 遇到已禁用的父角色时停止继承
+func RoleAncestors(tree map[uint]entity.SysRole, roleID uint) []uint {
+	var ancestors []uint
+	visited := map[uint]bool{roleID: true}
+	parentID := tree[roleID].ParentID
+	for parentID != nil && !visited[*parentID] {
+		parent, ok := tree[*parentID]
+		if !ok || parent.RoleStatus != 1 {
+			break
+		}
+		visited[parent.ID] = true
+		ancestors = append(ancestors, parent.ID)
+		parentID = parent.ParentID
+	}
+	return ancestors
+}
+
+// InheritsSuperRole 判断角色是否为超级管理员角色或沿父角色链继承自超级管理员角色；
+// 不区分父角色状态，避免重新启用父角色后获得超级管理员权限
+func InheritsSuperRole(tree map[uint]entity.SysRole, roleID uint) bool {
+	visited := make(map[uint]bool)
+	for id := &roleID; id != nil && !visited[*id]; id = tree[*id].ParentID {
+		role, ok := tree[*id]
+		if !ok {
+			return false
+		}
+		if role.RoleKey == global.SuperRoleKey {
+			return true
+		}
+		visited[*id] = true
+	}
+	return false
+}
+
+// 查询用户生效的角色：用户拥有的启用状态的角色，以及这些角色继承的祖先角色
+func (d *SysRoleDao) GetEffectiveRoles(adminId uint) ([]entity.SysRole, error) {
+	var roleIds []uint
+	err := global.DB.Model(&entity.SysAdminRole{}).
+		Joins("JOIN sys_role r ON sys_admin_role.role_id = r.id").
+		Where("sys_admin_role.admin_id = ?", adminId).
+		Where("r.role_status = ?", 1).
+		Scopes(activeGrant("sys_admin_role")).
+		Pluck("r.id", &roleIds).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(roleIds) == 0 {
+		return nil, nil
+	}
+	tree, err := d.GetRoleTree()
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []entity.SysRole
+	seen := make(map[uint]bool)
+	for _, roleId := range roleIds {
+		for _, id := range append([]uint{roleId}, RoleAncestors(tree, roleId)...) {
+			if !seen[id] {
+				seen[id] = true
+				roles = append(roles, tree[id])
+			}
+		}
+	}
+	return roles, nil
+}
+
+// 查询用户生效的角色id
+func (d *SysRoleDao) GetEffectiveRoleIds(adminId uint) ([]uint, error) {
+	roles, err := d.GetEffectiveRoles(adminId)
+	if err != nil {
+		return nil, err
+	}
+	roleIds := make([]uint, len(roles))
+	for i, role := range roles {
+		roleIds[i] = role.ID
+	}
+	return roleIds, nil
+}
+
+// 判断角色是否被其他角色继承
+func (d *SysRoleDao) HasSubRole(roleID uint) (bool, error) {
+	var count int64
+	if err := global.DB.Model(&entity.SysRole{}).Where("parent_id = ?", roleID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 查询角色及其所有下级角色的id，不区分角色状态
+func (d *SysRoleDao) GetRoleSubtreeIds(roleIds []uint) ([]uint, error) {
+	tree, err := d.GetRoleTree()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[uint][]uint)
+	for _, role := range tree {
+		if role.ParentID != nil {
+			children[*role.ParentID] = append(children[*role.ParentID], role.ID)
+		}
+	}
+	seen := make(map[uint]bool)
+	queue := append([]uint(nil), roleIds...)
+	var subtree []uint
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		subtree = append(subtree, id)
+		queue = append(queue, children[id]...)
+	}
+	return subtree, nil
+}
+
+// 查询拥有这些角色的用户id，包括尚未生效的授权
+func (d *SysRoleDao) GetRolesAdminIds(roleIds []uint) ([]uint, error) {
+	var adminIds []uint
+	err := global.DB.Model(&entity.SysAdminRole{}).
+		Where("role_id IN (?)", roleIds).
+		Distinct().
+		Pluck("admin_id", &adminIds).Error
+	if err != nil {
+		return nil, err
+	}
+	return adminIds, nil
+}
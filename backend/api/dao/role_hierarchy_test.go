@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+	"reflect"
+	"testing"
+)
+
+func TestRoleAncestors(t *testing.T) {
+	parent := func(id uint) *uint { return &id }
+	// 1 <- 2 <- 3 <- 4，5 已禁用，6 继承 5，7 和 8 互为父角色
+	tree := map[uint]entity.SysRole{
+		1: {ID: 1, RoleStatus: 1},
+		2: {ID: 2, RoleStatus: 1, ParentID: parent(1)},
+		3: {ID: 3, RoleStatus: 1, ParentID: parent(2)},
+		4: {ID: 4, RoleStatus: 1, ParentID: parent(3)},
+		5: {ID: 5, RoleStatus: 2, ParentID: parent(1)},
+		6: {ID: 6, RoleStatus: 1, ParentID: parent(5)},
+		7: {ID: 7, RoleStatus: 1, ParentID: parent(8)},
+		8: {ID: 8, RoleStatus: 1, ParentID: parent(7)},
+		9: {ID: 9, RoleStatus: 1, ParentID: parent(99)},
+	}
+	tests := []struct {
+		name   string
+		roleID uint
+		want   []uint
+	}{
+		{"root", 1, nil},
+		{"chain", 4, []uint{3, 2, 1}},
+		{"disabled parent stops inheritance", 6, nil},
+		{"cycle", 7, []uint{8}},
+		{"missing parent", 9, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoleAncestors(tree, tt.roleID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RoleAncestors(%d) = %v, want %v", tt.roleID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInheritsSuperRole(t *testing.T) {
+	parent := func(id uint) *uint { return &id }
+	// 1 为超级管理员角色，2 继承 1，3 继承 2，4 已禁用的超级管理员角色，5 继承 4，6 和 7 互为父角色
+	tree := map[uint]entity.SysRole{
+		1: {ID: 1, RoleKey: global.SuperRoleKey, RoleStatus: 1},
+		2: {ID: 2, RoleKey: "deputy", RoleStatus: 1, ParentID: parent(1)},
+		3: {ID: 3, RoleKey: "assistant", RoleStatus: 2, ParentID: parent(2)},
+		4: {ID: 4, RoleKey: global.SuperRoleKey, RoleStatus: 2},
+		5: {ID: 5, RoleKey: "standby", RoleStatus: 1, ParentID: parent(4)},
+		6: {ID: 6, RoleKey: "a", RoleStatus: 1, ParentID: parent(7)},
+		7: {ID: 7, RoleKey: "b", RoleStatus: 1, ParentID: parent(6)},
+		8: {ID: 8, RoleKey: "c", RoleStatus: 1, ParentID: parent(99)},
+	}
+	tests := []struct {
+		name   string
+		roleID uint
+		want   bool
+	}{
+		{"super role", 1, true},
+		{"inherits super role", 2, true},
+		{"inherits through disabled role", 3, true},
+		{"disabled super parent", 5, true},
+		{"cycle", 6, false},
+		{"missing parent", 8, false},
+		{"missing role", 100, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InheritsSuperRole(tree, tt.roleID); got != tt.want {
+				t.Errorf("InheritsSuperRole(%d) = %v, want %v", tt.roleID, got, tt.want)
+			}
+		})
+	}
+}
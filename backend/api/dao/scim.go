@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+
+	"gorm.io/gorm"
+)
+
+// SCIM 接口的查询，条件由 SCIM 过滤表达式解析得到
+type ScimDao struct{}
+
+// 按条件分页查询用户，按id排序，limit 为0时只统计总数
+func (d *ScimDao) GetAdmins(where string, args []any, offset, limit int) ([]entity.SysAdmin, int64, error) {
+	var admins []entity.SysAdmin
+	total, err := scimPage(global.DB.Model(&entity.SysAdmin{}), where, args, offset, limit, "sys_admin.id", &admins)
+	if err != nil {
+		return nil, 0, err
+	}
+	return admins, total, nil
+}
+
+// 按条件分页查询角色，按id排序，limit 为0时只统计总数
+func (d *ScimDao) GetRoles(where string, args []any, offset, limit int) ([]entity.SysRole, int64, error) {
+	var roles []entity.SysRole
+	total, err := scimPage(global.DB.Model(&entity.SysRole{}), where, args, offset, limit, "sys_role.id", &roles)
+	if err != nil {
+		return nil, 0, err
+	}
+	return roles, total, nil
+}
+
+func scimPage(query *gorm.DB, where string, args []any, offset, limit int, order string, dest any) (int64, error) {
+	if where != "" {
+		query = query.Where(where, args...)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	if limit == 0 || total <= int64(offset) {
+		return total, nil
+	}
+	if err := query.Order(order).Offset(offset).Limit(limit).Find(dest).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// 批量查询角色的成员
+func (d *ScimDao) GetRoleMembers(roleIds []uint) ([]entity.ScimMemberRow, error) {
+	var members []entity.ScimMemberRow
+	err := global.DB.Model(&entity.SysAdminRole{}).
+		Select("sys_admin_role.role_id,sys_admin_role.admin_id,a.username").
+		Joins("JOIN sys_admin a ON sys_admin_role.admin_id = a.id").
+		Where("sys_admin_role.role_id IN (?)", roleIds).
+		Order("sys_admin_role.admin_id").
+		Scan(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// 判断 SCIM 用户标识是否已被其他用户使用
+func (d *ScimDao) ExistsExternalID(externalId string, excludeId uint) (bool, error) {
+	var count int64
+	err := global.DB.Model(&entity.SysAdmin{}).
+		Where("scim_external_id = ? AND id <> ?", externalId, excludeId).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
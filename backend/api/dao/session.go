@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"encoding/json"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 登录会话，基于redis实现
+type SessionDao struct{}
+
+func sessionKey(sessionID string) string {
+	return global.SessionPrefix + sessionID
+}
+
+func adminSessionsKey(adminId uint) string {
+	return global.AdminSessionsPrefix + strconv.FormatUint(uint64(adminId), 10)
+}
+
+// 保存会话，同时记录到用户的会话集合中
+func (d *SessionDao) SaveSession(session *entity.SysSession, ttl time.Duration) error {
+	return d.saveSession(session, ttl, ttl)
+}
+
+// 保存模拟登录会话，会话的有效期比普通会话短，会话集合仍使用普通会话的有效期，避免集合先于其他会话过期
+func (d *SessionDao) SaveImpersonationSession(session *entity.SysSession, ttl, indexTTL time.Duration) error {
+	return d.saveSession(session, ttl, max(ttl, indexTTL))
+}
+
+func (d *SessionDao) saveSession(session *entity.SysSession, ttl, indexTTL time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	pipe := global.RDB.TxPipeline()
+	pipe.Set(ctx, sessionKey(session.SessionID), data, ttl)
+	pipe.SAdd(ctx, adminSessionsKey(session.AdminID), session.SessionID)
+	pipe.Expire(ctx, adminSessionsKey(session.AdminID), indexTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// 获取会话，会话不存在时返回nil
+func (d *SessionDao) GetSession(sessionID string) (*entity.SysSession, error) {
+	data, err := global.RDB.Get(ctx, sessionKey(sessionID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var session entity.SysSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// 更新会话的登录地点，保留会话原有的有效期，会话不存在时忽略
+func (d *SessionDao) UpdateSessionLocation(sessionID, location string) error {
+	session, err := d.GetSession(sessionID)
+	if err != nil || session == nil {
+		return err
+	}
+	session.Location = location
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	err = global.RDB.SetArgs(ctx, sessionKey(sessionID), data, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// 判断会话是否存在
+func (d *SessionDao) ExistsSession(sessionID string) (bool, error) {
+	count, err := global.RDB.Exists(ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 获取用户的所有会话，顺带清理集合中已过期的会话id
+func (d *SessionDao) GetAdminSessions(adminId uint) ([]entity.SysSession, error) {
+	sessionIDs, err := global.RDB.SMembers(ctx, adminSessionsKey(adminId)).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessions := []entity.SysSession{}
+	if len(sessionIDs) == 0 {
+		return sessions, nil
+	}
+
+	keys := make([]string, len(sessionIDs))
+	for i, sessionID := range sessionIDs {
+		keys[i] = sessionKey(sessionID)
+	}
+	values, err := global.RDB.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	var expired []any
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			expired = append(expired, sessionIDs[i])
+			continue
+		}
+		var session entity.SysSession
+		if err := json.Unmarshal([]byte(data), &session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if len(expired) > 0 {
+		global.RDB.SRem(ctx, adminSessionsKey(adminId), expired...)
+	}
+	return sessions, nil
+}
+
+// 删除用户的单个会话
+func (d *SessionDao) DeleteSession(adminId uint, sessionID string) error {
+	pipe := global.RDB.TxPipeline()
+	pipe.Del(ctx, sessionKey(sessionID))
+	pipe.SRem(ctx, adminSessionsKey(adminId), sessionID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 删除用户的所有会话，exceptSessionID 不为空时保留该会话
+func (d *SessionDao) DeleteAdminSessions(adminId uint, exceptSessionID string) error {
+	sessionIDs, err := global.RDB.SMembers(ctx, adminSessionsKey(adminId)).Result()
+	if err != nil {
+		return err
+	}
+	pipe := global.RDB.TxPipeline()
+	for _, sessionID := range sessionIDs {
+		if sessionID == exceptSessionID {
+			continue
+		}
+		pipe.Del(ctx, sessionKey(sessionID))
+		pipe.SRem(ctx, adminSessionsKey(adminId), sessionID)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
@@ -48,38 +48,101 @@ func (d *SysAdminDao) GetAdminByName(username string) (*entity.SysAdmin, error)
 	return &sysAdmin, nil
 }
 
+// 根据绑定的单点登录身份获取用户
+func (d *SysAdminDao) GetAdminByOidcIdentity(issuer, subject string) (*entity.SysAdmin, error) {
+	var sysAdmin entity.SysAdmin
+	err := global.DB.Where("oidc_issuer = ? AND oidc_subject = ?", issuer, subject).First(&sysAdmin).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sysAdmin, nil
+}
+
+// 根据邮箱获取用户，最多返回两条，用于判断邮箱是否唯一
+func (d *SysAdminDao) GetAdminsByEmail(email string) ([]entity.SysAdmin, error) {
+	var admins []entity.SysAdmin
+	if err := global.DB.Where("email = ?", email).Limit(2).Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+// 绑定单点登录身份
+func (d *SysAdminDao) BindOidcIdentity(adminId uint, issuer, subject string) error {
+	return global.DB.Model(&entity.SysAdmin{}).
+		Where("id = ?", adminId).
+		Updates(map[string]any{"oidc_issuer": issuer, "oidc_subject": subject}).Error
+}
+
+// 获取所有启用的目录账号，用于停用目录中已不存在的用户
+func (d *SysAdminDao) GetActiveLdapAdmins() ([]entity.SysAdmin, error) {
+	var admins []entity.SysAdmin
+	if err := global.DB.Where("ldap_dn <> '' AND status = 1").Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+// 查询所有超级管理员账号的id
+func (d *SysAdminDao) GetSuperAdminIds() ([]uint, error) {
+	var ids []uint
+	if err := global.DB.Model(&entity.SysAdmin{}).Where("is_super = ?", true).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// 根据id获取数据权限范围内的用户
+func (d *SysAdminDao) GetScopedAdminById(scope *entity.DataScope, userId uint) (*entity.SysAdmin, error) {
+	var sysAdmin entity.SysAdmin
+	err := global.DB.Scopes(adminDataScope(scope, "sys_admin")).Where("id = ?", userId).First(&sysAdmin).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sysAdmin, nil
+}
+
 // 创建用户，以及分配角色
-func (d *SysAdminDao) CreateAdmin(roleID uint, user *entity.SysAdmin) error {
+func (d *SysAdminDao) CreateAdmin(roleIds []uint, user *entity.SysAdmin) error {
 	return global.DB.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(user).Error; err != nil {
-			return err
-		}
-		sysAdminRole := &entity.SysAdminRole{
-			AdminID: user.ID,
-			RoleID:  roleID,
-		}
-		if err := tx.Create(sysAdminRole).Error; err != nil {
-			return err
-		}
-		return nil
+		return createAdmin(tx, roleIds, user)
 	})
 }
 
+// 在事务中创建用户并分配角色
+func createAdmin(tx *gorm.DB, roleIds []uint, user *entity.SysAdmin) error {
+	if err := tx.Create(user).Error; err != nil {
+		return err
+	}
+	// 单点登录自动创建的用户可能没有角色
+	if len(roleIds) == 0 {
+		return nil
+	}
+	var adminRoles []entity.SysAdminRole
+	for _, roleId := range roleIds {
+		adminRoles = append(adminRoles, entity.SysAdminRole{AdminID: user.ID, RoleID: roleId})
+	}
+	if err := tx.Create(&adminRoles).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 // 联表查询用户信息列表(联表查询)
-func (s *SysAdminDao) JointGetAdminList(pageNum, pageSize, status int, username, beginTime, endTime string) ([]entity.AdminList, int, error) {
+func (s *SysAdminDao) JointGetAdminList(scope *entity.DataScope, pageNum, pageSize, status int, username, beginTime, endTime string) ([]entity.AdminList, int, error) {
 	query := global.DB.Model(&entity.SysAdmin{}).
-		Select("sys_admin.*,ar.role_id,d.dept_name,p.post_name,r.role_name").
+		Select("sys_admin.*,d.dept_name,p.post_name,i.expires_at AS invitation_expires_at").
 		Joins("LEFT JOIN sys_dept d ON sys_admin.dept_id = d.id").
 		Joins("LEFT JOIN sys_post p ON sys_admin.post_id = p.id").
-		Joins("LEFT JOIN sys_admin_role ar ON sys_admin.id = ar.admin_id").
-		Joins("LEFT JOIN sys_role r ON ar.role_id = r.id")
+		Joins("LEFT JOIN sys_admin_invitation i ON sys_admin.id = i.admin_id").
+		Scopes(adminDataScope(scope, "sys_admin"))
 
 	query = query.Where("sys_admin.status = ?", status)
 	if username != "" {
-		query = query.Where("username = ?", username)
+		query = query.Where("sys_admin.username = ?", username)
 	}
 	if beginTime != "" && endTime != "" {
-		query = query.Where("created_at BETWEEN ? AND ?", beginTime, endTime)
+		query = query.Where("sys_admin.created_at BETWEEN ? AND ?", beginTime, endTime)
 	}
 
 	var count int64
@@ -97,11 +160,10 @@ func (s *SysAdminDao) JointGetAdminList(pageNum, pageSize, status int, username,
 }
 
 // 根据id查询单个用户信息(联表查询)
-func (d *SysAdminDao) JointGetAdminById(userId uint) (*entity.GetAdminByIdVo, error) {
+func (d *SysAdminDao) JointGetAdminById(scope *entity.DataScope, userId uint) (*entity.GetAdminByIdVo, error) {
 	var sysAdmin entity.GetAdminByIdVo
 	err := global.DB.Model(&entity.SysAdmin{}).
-		Select("sys_admin.*,sys_admin_role.role_id").
-		Joins("LEFT JOIN sys_admin_role ON sys_admin.id = sys_admin_role.admin_id").
+		Scopes(adminDataScope(scope, "sys_admin")).
 		Where("sys_admin.id = ?", userId).
 		First(&sysAdmin).Error
 	if err != nil {
@@ -110,25 +172,73 @@ func (d *SysAdminDao) JointGetAdminById(userId uint) (*entity.GetAdminByIdVo, er
 	return &sysAdmin, nil
 }
 
-// 修改用户角色
-func (d *SysAdminDao) UpdateAdminRole(userId, roleId uint) error {
+// 修改用户角色，仍然保留的角色不修改原有的授权有效期
+func (d *SysAdminDao) UpdateAdminRoles(userId uint, roleIds []uint) error {
 	return global.DB.Transaction(func(tx *gorm.DB) error {
-		// 删除旧角色
-		if err := tx.Model(&entity.SysAdminRole{}).Delete("admin_id = ?", userId).Error; err != nil {
+		// 删除不再保留的旧角色
+		query := tx.Where("admin_id = ?", userId)
+		if len(roleIds) > 0 {
+			query = query.Where("role_id NOT IN (?)", roleIds)
+		}
+		if err := query.Delete(&entity.SysAdminRole{}).Error; err != nil {
 			return err
 		}
+		if len(roleIds) == 0 {
+			return nil
+		}
+		var existing []uint
+		if err := tx.Model(&entity.SysAdminRole{}).Where("admin_id = ?", userId).Pluck("role_id", &existing).Error; err != nil {
+			return err
+		}
+		kept := make(map[uint]bool, len(existing))
+		for _, roleId := range existing {
+			kept[roleId] = true
+		}
 		// 分配新角色
-		adminRole := &entity.SysAdminRole{
-			AdminID: userId,
-			RoleID:  roleId,
+		var adminRoles []entity.SysAdminRole
+		for _, roleId := range roleIds {
+			if !kept[roleId] {
+				adminRoles = append(adminRoles, entity.SysAdminRole{AdminID: userId, RoleID: roleId})
+			}
 		}
-		if err := tx.Create(adminRole).Error; err != nil {
+		if len(adminRoles) == 0 {
+			return nil
+		}
+		if err := tx.Create(&adminRoles).Error; err != nil {
 			return err
 		}
 		return nil
 	})
 }
 
+// 批量查询用户已分配的角色
+func (d *SysAdminDao) GetAdminsRoles(adminIds []uint) ([]entity.AdminRoleVo, error) {
+	var adminRoles []entity.AdminRoleVo
+	err := global.DB.Model(&entity.SysAdminRole{}).
+		Select("sys_admin_role.admin_id,r.id,r.role_name,r.role_status,sys_admin_role.valid_from,sys_admin_role.valid_until,sys_admin_role.reason").
+		Joins("JOIN sys_role r ON sys_admin_role.role_id = r.id").
+		Where("sys_admin_role.admin_id IN (?)", adminIds).
+		Order("r.id").
+		Scan(&adminRoles).Error
+	if err != nil {
+		return nil, err
+	}
+	return adminRoles, nil
+}
+
+// 统计用户列表中数据权限范围内的用户数
+func (d *SysAdminDao) CountScopedAdmins(scope *entity.DataScope, adminIds []uint) (int64, error) {
+	var count int64
+	err := global.DB.Model(&entity.SysAdmin{}).
+		Scopes(adminDataScope(scope, "sys_admin")).
+		Where("id IN (?)", adminIds).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // 修改用户信息
 func (d *SysAdminDao) UpdateAdmin(sysAdmin *entity.SysAdmin) error {
 	return global.DB.Save(sysAdmin).Error
@@ -144,7 +254,61 @@ func (d *SysAdminDao) DeleteAdmin(userId uint) error {
 		if err := tx.Where("admin_id = ?", userId).Delete(&entity.SysAdminRole{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("admin_id = ?", userId).Delete(&entity.SysRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("admin_id = ?", userId).Delete(&entity.SysPasswordHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("admin_id = ?", userId).Delete(&entity.SysApiToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("admin_id = ?", userId).Delete(&entity.SysAdminInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("admin_id = ?", userId).Delete(&entity.SysAdminCredential{}).Error; err != nil {
+			return err
+		}
 		return nil
 	})
 }
 
+// 查询用户个人资料(联表查询部门、岗位名称)
+func (d *SysAdminDao) GetProfile(adminId uint) (*entity.ProfileVo, error) {
+	var profile entity.ProfileVo
+	err := global.DB.Model(&entity.SysAdmin{}).
+		Select("sys_admin.*,d.dept_name,p.post_name").
+		Joins("LEFT JOIN sys_dept d ON sys_admin.dept_id = d.id").
+		Joins("LEFT JOIN sys_post p ON sys_admin.post_id = p.id").
+		Where("sys_admin.id = ?", adminId).
+		Take(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// 查询用户已分配的启用状态的角色名称
+func (d *SysAdminDao) GetAdminRoleNames(adminId uint) ([]string, error) {
+	var roleNames []string
+	err := global.DB.Model(&entity.SysAdminRole{}).
+		Joins("JOIN sys_role r ON sys_admin_role.role_id = r.id").
+		Where("sys_admin_role.admin_id = ?", adminId).
+		Where("r.role_status = ?", 1).
+		Scopes(activeGrant("sys_admin_role")).
+		Pluck("r.role_name", &roleNames).Error
+	if err != nil {
+		return nil, err
+	}
+	return roleNames, nil
+}
+
+// 查询用户已分配的角色id
+func (d *SysAdminDao) GetAdminRoleIds(adminId uint) ([]uint, error) {
+	var roleIds []uint
+	err := global.DB.Model(&entity.SysAdminRole{}).Where("admin_id = ?", adminId).Pluck("role_id", &roleIds).Error
+	if err != nil {
+		return nil, err
+	}
+	return roleIds, nil
+}
@@ -88,7 +88,13 @@ func (d *SysDeptDao) HasChildDept(deptID uint) (bool, error) {
 
 // 根据id删除部门
 func (d *SysDeptDao) DeleteDept(deptID uint) error {
-	return global.DB.Where("id = ?", deptID).Delete(&entity.SysDept{}).Error
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", deptID).Delete(&entity.SysDept{}).Error; err != nil {
+			return err
+		}
+		// 同时删除角色数据权限中的该部门
+		return tx.Where("dept_id = ?", deptID).Delete(&entity.SysRoleDept{}).Error
+	})
 }
 
 // 获取部门下拉列表
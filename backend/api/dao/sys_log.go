@@ -3,31 +3,31 @@ package dao
 import (
 	"go-admin-server/api/entity"
 	"go-admin-server/common/utils"
+	"go-admin-server/core/logwriter"
 	"go-admin-server/global"
 	"time"
 )
 
 type SysLogDao struct{}
 
-// 创建登录日志
-func (d *SysLogDao) CreateLoginLog(username, ipAddr, loginLocation, browser, os, message string, loginStaus uint) {
+// 创建登录日志，异步写入，登录地点由后台根据IP补全
+func (d *SysLogDao) CreateLoginLog(username, ipAddr, browser, os, message string, loginStaus uint) {
 	loginLog := &entity.SysLoginLog{
-		Username:      username,
-		IpAddress:     ipAddr,
-		LoginLocation: loginLocation,
-		Browser:       browser,
-		Os:            os,
-		Message:       message,
-		LoginStatus:   loginStaus,
-		LoginAt:       utils.HTime{Time: time.Now()},
+		Username:    username,
+		IpAddress:   ipAddr,
+		Browser:     browser,
+		Os:          os,
+		Message:     message,
+		LoginStatus: loginStaus,
+		LoginAt:     utils.HTime{Time: time.Now()},
 	}
-	global.DB.Create(loginLog)
+	logwriter.WriteLoginLog(loginLog)
 }
 
 // 获取登录日志列表
-func (d *SysLogDao) GetLoginLogList(pageNum, pageSize int, username, beginTime, endTime string, loginStatus uint) ([]entity.SysLoginLog, int, error) {
+func (d *SysLogDao) GetLoginLogList(scope *entity.DataScope, pageNum, pageSize int, username, beginTime, endTime string, loginStatus uint, location entity.LoginLocationQuery) ([]entity.SysLoginLog, int, error) {
 	var loginLogList []entity.SysLoginLog
-	query := global.DB.Model(&entity.SysLoginLog{})
+	query := global.DB.Model(&entity.SysLoginLog{}).Scopes(logDataScope(scope, "username", "username"))
 
 	if loginStatus != 0 {
 		query = query.Where("login_status = ?", loginStatus)
@@ -41,6 +41,19 @@ func (d *SysLogDao) GetLoginLogList(pageNum, pageSize int, username, beginTime,
 		query = query.Where("login_at BETWEEN ? AND ?", beginTime, endTime)
 	}
 
+	if location.Country != "" {
+		query = query.Where("country = ?", location.Country)
+	}
+	if location.Region != "" {
+		query = query.Where("region = ?", location.Region)
+	}
+	if location.City != "" {
+		query = query.Where("city = ?", location.City)
+	}
+	if location.Isp != "" {
+		query = query.Where("isp LIKE ?", "%"+location.Isp+"%")
+	}
+
 	var count int64
 	if err := query.Count(&count).Error; err != nil {
 		return nil, 0, err
@@ -54,31 +67,31 @@ func (d *SysLogDao) GetLoginLogList(pageNum, pageSize int, username, beginTime,
 }
 
 // 根据id删除登录日志
-func (d *SysLogDao) DeleteLoginLog(logId uint) error {
-	return global.DB.Where("id = ?", logId).Delete(&entity.SysLoginLog{}).Error
+func (d *SysLogDao) DeleteLoginLog(scope *entity.DataScope, logId uint) error {
+	return global.DB.Scopes(logDataScope(scope, "username", "username")).Where("id = ?", logId).Delete(&entity.SysLoginLog{}).Error
 }
 
 // 批量删除登录日志
-func (d *SysLogDao) BatchDeleteLoginLog(logIds []uint) error {
-	return global.DB.Where("id IN (?)", logIds).Delete(&entity.SysLoginLog{}).Error
+func (d *SysLogDao) BatchDeleteLoginLog(scope *entity.DataScope, logIds []uint) error {
+	return global.DB.Scopes(logDataScope(scope, "username", "username")).Where("id IN (?)", logIds).Delete(&entity.SysLoginLog{}).Error
 }
 
-// 创建操作日志
-func (d *SysLogDao) CreateOperationLog(operaLog *entity.SysOperationLog) error {
-	return global.DB.Create(operaLog).Error
+// 创建操作日志，异步写入
+func (d *SysLogDao) CreateOperationLog(operaLog *entity.SysOperationLog) {
+	logwriter.WriteOperationLog(operaLog)
 }
 
 // 获取操作日志列表
-func (d *SysLogDao) GetOperationLogList(pageNum, pageSize int, username, beginTime, endTime string) ([]entity.SysOperationLog, int, error) {
+func (d *SysLogDao) GetOperationLogList(scope *entity.DataScope, pageNum, pageSize int, username, beginTime, endTime string) ([]entity.SysOperationLog, int, error) {
 	var operationLogList []entity.SysOperationLog
-	query := global.DB.Model(&entity.SysOperationLog{})
+	query := global.DB.Model(&entity.SysOperationLog{}).Scopes(logDataScope(scope, "admin_id", "id"))
 
 	if username != "" {
-		query = query.Where("username = ?", username)
+		query = query.Where("user_name = ?", username)
 	}
 
 	if beginTime != "" && endTime != "" {
-		query = query.Where("login_at BETWEEN ? AND ?", beginTime, endTime)
+		query = query.Where("created_at BETWEEN ? AND ?", beginTime, endTime)
 	}
 
 	var count int64
@@ -94,11 +107,11 @@ func (d *SysLogDao) GetOperationLogList(pageNum, pageSize int, username, beginTi
 }
 
 // 根据id删除操作日志
-func (d *SysLogDao) DeleteOpLog(logId uint) error {
-	return global.DB.Where("id = ?", logId).Delete(&entity.SysOperationLog{}).Error
+func (d *SysLogDao) DeleteOpLog(scope *entity.DataScope, logId uint) error {
+	return global.DB.Scopes(logDataScope(scope, "admin_id", "id")).Where("id = ?", logId).Delete(&entity.SysOperationLog{}).Error
 }
 
 // 批量删除操作日志
-func (d *SysLogDao) BatchDeleteOpLog(logIds []uint) error {
-	return global.DB.Where("id IN (?)", logIds).Delete(&entity.SysOperationLog{}).Error
+func (d *SysLogDao) BatchDeleteOpLog(scope *entity.DataScope, logIds []uint) error {
+	return global.DB.Scopes(logDataScope(scope, "admin_id", "id")).Where("id IN (?)", logIds).Delete(&entity.SysOperationLog{}).Error
 }
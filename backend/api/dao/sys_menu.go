@@ -1,8 +1,13 @@
 package dao
 
 import (
+	"errors"
 	"go-admin-server/api/entity"
+	"go-admin-server/core/permcache"
 	"go-admin-server/global"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type SysMenuDao struct{}
@@ -74,31 +79,103 @@ func (d *SysMenuDao) GetMenuDropdown() ([]entity.MenuDropdownVo, error) {
 	return dropdown, nil
 }
 
-// 获取当前登录用户的左侧菜单列表（一级菜单和二级菜单展示）
-func (d *SysMenuDao) LeftMenuList(adminId uint) ([]entity.FirstLevelMenuVo, error) {
-	// 先查询用户有权限的所有菜单id
-	var menuIds []uint
-	err := global.DB.Model(&entity.SysAdmin{}).
-		Joins("LEFT JOIN sys_admin_role ar ON sys_admin.id = ar.admin_id").
-		Joins("LEFT JOIN sys_role r ON ar.role_id = r.id").
-		Joins("LEFT JOIN sys_role_menu rm ON r.id = rm.menu_id").
-		Where("sys_admin.id = ?", adminId).
-		Where("r.role_status = ?", 1).
-		Pluck("rm.menu_id", &menuIds).Error
+// 获取用户的权限和左侧菜单，优先读取缓存，缓存不存在时从数据库加载并写入缓存
+func (d *SysMenuDao) GetAdminPermission(adminId uint) (*entity.AdminPermission, error) {
+	if permission, ok := permcache.Get(adminId); ok {
+		return permission, nil
+	}
+
+	version := permcache.CurrentVersion(adminId)
+	permission, err := d.loadAdminPermission(adminId)
+	if err != nil {
+		return nil, err
+	}
+	// 临时授权生效或失效时权限会发生变化，缓存不能超过这个时间
+	now := time.Now()
+	next, err := roleDao.GetNextGrantChange(adminId, now)
+	if err != nil {
+		return nil, err
+	}
+	var maxTTL time.Duration
+	if next != nil {
+		maxTTL = next.Sub(now)
+	}
+	permcache.Set(adminId, permission, maxTTL, version)
+	return permission, nil
+}
+
+// 从数据库加载用户的权限和左侧菜单，继承自父角色的权限同样生效
+func (d *SysMenuDao) loadAdminPermission(adminId uint) (*entity.AdminPermission, error) {
+	permission := &entity.AdminPermission{
+		RoleIDs:     []uint{},
+		Permissions: []string{},
+		Menus:       []entity.FirstLevelMenuVo{},
+	}
+	var admin entity.SysAdmin
+	if err := global.DB.Select("id", "is_super").Where("id = ?", adminId).Take(&admin).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return permission, nil
+		}
+		return nil, err
+	}
+	roles, err := roleDao.GetEffectiveRoles(adminId)
 	if err != nil {
 		return nil, err
 	}
+	if len(roles) == 0 && !admin.IsSuper {
+		return permission, nil
+	}
+	for _, role := range roles {
+		if role.RoleKey == global.SuperRoleKey {
+			permission.Super = true
+		}
+		permission.RoleIDs = append(permission.RoleIDs, role.ID)
+	}
 
-	if len(menuIds) == 0 {
-		return []entity.FirstLevelMenuVo{}, nil
+	// 超级管理员账号拥有全部菜单和按钮权限，不受角色限制
+	roleIds := permission.RoleIDs
+	if admin.IsSuper {
+		permission.Super = true
+		roleIds = nil
+	}
+	if permission.Menus, err = d.leftMenuList(roleIds); err != nil {
+		return nil, err
 	}
+	if permission.Permissions, err = d.permissionValues(roleIds); err != nil {
+		return nil, err
+	}
+	return permission, nil
+}
+
+// 只保留角色被分配的菜单，roleIds 为 nil 时不过滤，column 为查询中菜单id的列名
+func roleMenuScope(roleIds []uint, column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if roleIds == nil {
+			return db
+		}
+		return db.Where(column+" IN (?)", global.DB.Model(&entity.SysRoleMenu{}).Select("menu_id").Where("role_id IN (?)", roleIds))
+	}
+}
 
+// 获取当前登录用户的左侧菜单列表（一级菜单和二级菜单展示）
+func (d *SysMenuDao) LeftMenuList(adminId uint) ([]entity.FirstLevelMenuVo, error) {
+	permission, err := d.GetAdminPermission(adminId)
+	if err != nil {
+		return nil, err
+	}
+	return permission.Menus, nil
+}
+
+// 查询角色的左侧菜单列表，roleIds 为 nil 时查询全部菜单
+func (d *SysMenuDao) leftMenuList(roleIds []uint) ([]entity.FirstLevelMenuVo, error) {
 	// 查询一级菜单信息
-	var firstMenuList []entity.FirstLevelMenuVo
-	err = global.DB.Model(&entity.SysMenu{}).
+	firstMenuList := []entity.FirstLevelMenuVo{}
+	err := global.DB.Model(&entity.SysMenu{}).
 		Select("id", "menu_name", "menu_icon", "url").
-		Where("id IN (?)", menuIds).
+		Scopes(roleMenuScope(roleIds, "id")).
 		Where("menu_type = ?", 1).
+		Where("menu_status = ?", 1).
+		Order("sort").
 		Scan(&firstMenuList).Error
 	if err != nil {
 		return nil, err
@@ -109,10 +186,11 @@ func (d *SysMenuDao) LeftMenuList(adminId uint) ([]entity.FirstLevelMenuVo, erro
 		var children []entity.SecondLevelMenuVo
 		err = global.DB.Model(&entity.SysMenu{}).
 			Select("menu_name", "menu_icon", "url").
-			Where("id IN (?)", menuIds).
+			Scopes(roleMenuScope(roleIds, "id")).
 			Where("menu_type = ?", 2).
+			Where("menu_status = ?", 1).
 			Where("parent_id = ?", firstMenuList[i].ID).
-			Scan(&children).
+			Order("sort").
 			Scan(&children).Error
 		if err != nil {
 			return nil, err
@@ -124,19 +202,63 @@ func (d *SysMenuDao) LeftMenuList(adminId uint) ([]entity.FirstLevelMenuVo, erro
 
 // 获取登录用户权限列表
 func (s *SysMenuDao) GetPermissionList(adminId uint) ([]entity.PermissionListVo, error) {
-	var permissionList []entity.PermissionListVo
-	err := global.DB.Model(&entity.SysAdmin{}).Select("m.value").
-		Joins("LEFT JOIN sys_admin_role ar ON sys_admin.id = ar.admin_id").
-		Joins("LEFT JOIN sys_role r ON ar.role_id = r.id").
-		Joins("LEFT JOIN sys_role_menu rm ON r.id = rm.role_id").
-		Joins("LEFT JOIN sys_menu m ON rm.menu_id = m.id").
-		Where("sys_admin.id = ?", adminId).
-		Where("r.role_status = ?", 1).
-		Where("m.menu_status = ?", 1).
-		Where("m.menu_type = ?", 1).
-		Scan(&permissionList).Error
+	permission, err := s.GetAdminPermission(adminId)
 	if err != nil {
 		return nil, err
 	}
+	permissionList := make([]entity.PermissionListVo, len(permission.Permissions))
+	for i, value := range permission.Permissions {
+		permissionList[i].Value = value
+	}
 	return permissionList, nil
 }
+
+// 查询角色拥有的按钮权限值，roleIds 为 nil 时查询全部按钮权限；按权限值排序，保证权限列表的版本号稳定
+func (s *SysMenuDao) permissionValues(roleIds []uint) ([]string, error) {
+	values := []string{}
+	err := global.DB.Model(&entity.SysMenu{}).Distinct("value").
+		Scopes(roleMenuScope(roleIds, "id")).
+		Where("menu_status = ?", 1).
+		Where("menu_type = ?", 3).
+		Order("value").
+		Pluck("value", &values).Error
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// 判断用户是否拥有指定的按钮权限（拥有超级管理员角色的用户直接放行），继承自父角色的权限同样生效
+func (d *SysMenuDao) HasPermission(adminId uint, value string) (bool, error) {
+	permission, err := d.GetAdminPermission(adminId)
+	if err != nil {
+		return false, err
+	}
+	return permission.Has(value), nil
+}
+
+// 查询拥有菜单权限的角色id，用于菜单变化时失效权限缓存
+func (d *SysMenuDao) GetMenuRoleIds(menuIds ...uint) ([]uint, error) {
+	var roleIds []uint
+	err := global.DB.Model(&entity.SysRoleMenu{}).
+		Where("menu_id IN (?)", menuIds).
+		Distinct().
+		Pluck("role_id", &roleIds).Error
+	if err != nil {
+		return nil, err
+	}
+	return roleIds, nil
+}
+
+// 判断按钮权限值是否全部存在，values 不能有重复
+func (d *SysMenuDao) ExistsPermissionValues(values []string) (bool, error) {
+	var count int64
+	err := global.DB.Model(&entity.SysMenu{}).
+		Where("menu_type = ? AND value IN (?)", 3, values).
+		Distinct("value").
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count == int64(len(values)), nil
+}
@@ -9,6 +9,8 @@ import (
 
 type SysRoleDao struct{}
 
+var roleDao = &SysRoleDao{}
+
 // 检查角色名称是否存在
 func (d *SysRoleDao) ExistsByName(roleName string) (bool, error) {
 	var count int64
@@ -81,6 +83,10 @@ func (d *SysRoleDao) DeleteRole(roleID uint) error {
 		if err := tx.Where("role_id = ?", roleID).Delete(&entity.SysRoleMenu{}).Error; err != nil {
 			return err
 		}
+		// 删除角色-部门关联表中的数据
+		if err := tx.Where("role_id = ?", roleID).Delete(&entity.SysRoleDept{}).Error; err != nil {
+			return err
+		}
 		return nil
 	})
 }
@@ -143,13 +149,13 @@ func (d *SysRoleDao) AssignRoleMenus(roleID uint, menuIds []uint) error {
 	})
 }
 
-// 根据角色id，获取对应的三级菜单列表
-func (d *SysRoleDao) GetRoleMenus(roleID uint) ([]uint, error) {
+// 根据角色id列表，获取对应的三级菜单列表
+func (d *SysRoleDao) GetRoleMenus(roleIds []uint) ([]uint, error) {
 	var roleMenus []uint
 	err := global.DB.Model(&entity.SysRoleMenu{}).
-		Select("sys_role_menu.menu_id").
+		Distinct("sys_role_menu.menu_id").
 		Joins("LEFT JOIN sys_menu ON sys_role_menu.menu_id = sys_menu.id").
-		Where("sys_role_menu.role_id = ?", roleID).
+		Where("sys_role_menu.role_id IN (?)", roleIds).
 		Where("sys_menu.menu_type = ?", 3).
 		Scan(&roleMenus).Error
 	if err != nil {
@@ -157,3 +163,91 @@ func (d *SysRoleDao) GetRoleMenus(roleID uint) ([]uint, error) {
 	}
 	return roleMenus, nil
 }
+
+// 根据id列表获取角色
+func (d *SysRoleDao) GetRolesByIds(roleIds []uint) ([]entity.SysRole, error) {
+	var roles []entity.SysRole
+	if err := global.DB.Where("id IN (?)", roleIds).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// 根据角色关键字列表获取角色
+func (d *SysRoleDao) GetRolesByKeys(roleKeys []string) ([]entity.SysRole, error) {
+	var roles []entity.SysRole
+	if err := global.DB.Where("role_key IN (?)", roleKeys).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// 查询拥有角色的用户列表，只返回数据权限范围内的用户
+func (d *SysRoleDao) GetRoleAdminList(scope *entity.DataScope, roleID uint, pageNum, pageSize int, username string) ([]entity.RoleAdminVo, int, error) {
+	query := global.DB.Model(&entity.SysAdmin{}).
+		Select("sys_admin.id,sys_admin.username,sys_admin.nickname,sys_admin.status,d.dept_name,ar.valid_from,ar.valid_until,ar.reason").
+		Joins("JOIN sys_admin_role ar ON sys_admin.id = ar.admin_id").
+		Joins("LEFT JOIN sys_dept d ON sys_admin.dept_id = d.id").
+		Scopes(adminDataScope(scope, "sys_admin")).
+		Where("ar.role_id = ?", roleID)
+	if username != "" {
+		query = query.Where("sys_admin.username LIKE ?", "%"+username+"%")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var adminList []entity.RoleAdminVo
+	err := query.Limit(pageSize).Offset((pageNum - 1) * pageSize).Order("sys_admin.id").Scan(&adminList).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return adminList, int(count), nil
+}
+
+// 批量为角色添加用户，已拥有该角色的用户更新授权有效期和原因
+func (d *SysRoleDao) AddRoleAdmins(grant entity.SysAdminRole, adminIds []uint) error {
+	roleID := grant.RoleID
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		err := tx.Model(&entity.SysAdminRole{}).
+			Where("role_id = ? AND admin_id IN (?)", roleID, adminIds).
+			Pluck("admin_id", &existing).Error
+		if err != nil {
+			return err
+		}
+		exists := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			exists[id] = true
+		}
+		if len(existing) > 0 {
+			err := tx.Model(&entity.SysAdminRole{}).
+				Where("role_id = ? AND admin_id IN (?)", roleID, existing).
+				Updates(map[string]any{"valid_from": grant.ValidFrom, "valid_until": grant.ValidUntil, "reason": grant.Reason}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var adminRoles []entity.SysAdminRole
+		for _, adminId := range adminIds {
+			if !exists[adminId] {
+				exists[adminId] = true
+				adminRole := grant
+				adminRole.AdminID = adminId
+				adminRoles = append(adminRoles, adminRole)
+			}
+		}
+		if len(adminRoles) == 0 {
+			return nil
+		}
+		return tx.Create(&adminRoles).Error
+	})
+}
+
+// 批量从角色中移除用户
+func (d *SysRoleDao) RemoveRoleAdmins(roleID uint, adminIds []uint) error {
+	return global.DB.Where("role_id = ? AND admin_id IN (?)", roleID, adminIds).Delete(&entity.SysAdminRole{}).Error
+}
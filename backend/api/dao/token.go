@@ -0,0 +1,58 @@
+package dao
+
+import (
+	"errors"
+	"go-admin-server/global"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 令牌吊销列表，基于redis实现
+type TokenDao struct{}
+
+// 吊销单个令牌，记录保留到令牌过期为止
+func (d *TokenDao) RevokeToken(jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return global.RDB.Set(ctx, global.TokenBlacklistPrefix+jti, 1, ttl).Err()
+}
+
+// 判断令牌是否已被吊销
+func (d *TokenDao) IsTokenRevoked(jti string) (bool, error) {
+	count, err := global.RDB.Exists(ctx, global.TokenBlacklistPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 获取用户当前的令牌版本号
+func (d *TokenDao) GetTokenVersion(adminId uint) (int64, error) {
+	value, err := global.RDB.Get(ctx, global.TokenVersionPrefix+strconv.FormatUint(uint64(adminId), 10)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return value, nil
+}
+
+// 吊销用户所有未过期的令牌：令牌版本号加一后，携带旧版本号的令牌全部失效
+func (d *TokenDao) RevokeAdminTokens(adminId uint) error {
+	return global.RDB.Incr(ctx, global.TokenVersionPrefix+strconv.FormatUint(uint64(adminId), 10)).Err()
+}
+
+// 使用令牌(一次性)：令牌未被吊销时将其吊销并返回true，已被吊销则返回false
+// 用于刷新令牌轮换，保证同一个刷新令牌只能使用一次
+func (d *TokenDao) ConsumeToken(jti string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return false, nil
+	}
+	return global.RDB.SetNX(ctx, global.TokenBlacklistPrefix+jti, 1, ttl).Result()
+}
@@ -0,0 +1,137 @@
+package dao
+
+import (
+	"encoding/json"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 两步验证：登录挑战和TOTP防重放基于redis实现，恢复码保存在数据库中
+type TwoFactorDao struct{}
+
+func challengeKey(token string) string {
+	return global.LoginChallengePrefix + token
+}
+
+// 保存登录挑战
+func (d *TwoFactorDao) SaveChallenge(token string, challenge *entity.LoginChallenge, ttl time.Duration) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	pipe := global.RDB.TxPipeline()
+	pipe.HSet(ctx, challengeKey(token), "data", data, "attempts", 0)
+	pipe.Expire(ctx, challengeKey(token), ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// 获取登录挑战，挑战不存在或已过期时返回nil
+func (d *TwoFactorDao) GetChallenge(token string) (*entity.LoginChallenge, error) {
+	data, err := global.RDB.HGet(ctx, challengeKey(token), "data").Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var challenge entity.LoginChallenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// 登录挑战的验证次数加一并返回
+func (d *TwoFactorDao) IncrChallengeAttempts(token string) (int64, error) {
+	return global.RDB.HIncrBy(ctx, challengeKey(token), "attempts", 1).Result()
+}
+
+// 删除登录挑战
+func (d *TwoFactorDao) DeleteChallenge(token string) error {
+	return global.RDB.Del(ctx, challengeKey(token)).Err()
+}
+
+// 使用TOTP时间步：同一用户的同一时间步只能使用一次，首次使用返回true
+func (d *TwoFactorDao) UseTotpStep(adminId uint, step int64, ttl time.Duration) (bool, error) {
+	key := global.TotpUsedPrefix + strconv.FormatUint(uint64(adminId), 10) + ":" + strconv.FormatInt(step, 10)
+	return global.RDB.SetNX(ctx, key, 1, ttl).Result()
+}
+
+// 开启两步验证并替换恢复码
+func (d *TwoFactorDao) EnableTwoFactor(adminId uint, codeHashes []string) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.SysAdmin{}).Where("id = ?", adminId).Update("totp_enabled", true).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, adminId, codeHashes)
+	})
+}
+
+// 关闭两步验证，同时清除密钥和恢复码
+func (d *TwoFactorDao) DisableTwoFactor(adminId uint) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.SysAdmin{}).Where("id = ?", adminId).
+			Updates(map[string]any{"totp_secret": "", "totp_enabled": false}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("admin_id = ?", adminId).Delete(&entity.SysRecoveryCode{}).Error
+	})
+}
+
+// 保存待绑定的TOTP密钥，开启前需要先验证一次验证码
+func (d *TwoFactorDao) SaveTotpSecret(adminId uint, secret string) error {
+	return global.DB.Model(&entity.SysAdmin{}).Where("id = ?", adminId).Update("totp_secret", secret).Error
+}
+
+// 替换恢复码
+func (d *TwoFactorDao) ReplaceRecoveryCodes(adminId uint, codeHashes []string) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, adminId, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, adminId uint, codeHashes []string) error {
+	if err := tx.Where("admin_id = ?", adminId).Delete(&entity.SysRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	var codes []entity.SysRecoveryCode
+	for _, hash := range codeHashes {
+		codes = append(codes, entity.SysRecoveryCode{AdminID: adminId, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+// 使用恢复码：恢复码存在时将其删除并返回true
+func (d *TwoFactorDao) UseRecoveryCode(adminId uint, codeHash string) (bool, error) {
+	result := global.DB.Where("admin_id = ? AND code_hash = ?", adminId, codeHash).Delete(&entity.SysRecoveryCode{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// 判断用户是否拥有要求两步验证的启用状态的角色
+func (d *TwoFactorDao) IsTwoFactorRequired(adminId uint) (bool, error) {
+	var count int64
+	err := global.DB.Model(&entity.SysAdminRole{}).
+		Joins("JOIN sys_role r ON sys_admin_role.role_id = r.id").
+		Where("sys_admin_role.admin_id = ?", adminId).
+		Where("r.role_status = ? AND r.require_two_factor = ?", 1, true).
+		Scopes(activeGrant("sys_admin_role")).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
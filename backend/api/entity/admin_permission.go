@@ -0,0 +1,22 @@
+package entity
+
+// 用户生效的权限和左侧菜单，由用户当前有效的角色及其继承的父角色合并而来，会被缓存
+type AdminPermission struct {
+	Super       bool               `json:"super"`       // 是否拥有超级管理员角色
+	RoleIDs     []uint             `json:"roleIds"`     // 生效的角色id，用于按角色失效缓存
+	Permissions []string           `json:"permissions"` // 按钮权限值
+	Menus       []FirstLevelMenuVo `json:"menus"`       // 左侧菜单
+}
+
+// 是否拥有指定的按钮权限
+func (p *AdminPermission) Has(value string) bool {
+	if p.Super {
+		return true
+	}
+	for _, permission := range p.Permissions {
+		if permission == value {
+			return true
+		}
+	}
+	return false
+}
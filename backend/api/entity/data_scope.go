@@ -0,0 +1,30 @@
+package entity
+
+// 角色数据权限范围
+const (
+	DataScopeAll          uint = 1 // 全部数据
+	DataScopeCustom       uint = 2 // 自定义部门
+	DataScopeDept         uint = 3 // 本部门
+	DataScopeDeptAndChild uint = 4 // 本部门及以下
+	DataScopeSelf         uint = 5 // 仅本人
+)
+
+// 当前用户可访问的数据范围，由用户拥有的全部启用角色的数据权限合并而来
+type DataScope struct {
+	All     bool   // 可访问全部数据
+	DeptIDs []uint // 可访问的部门
+	AdminID uint   // 不为0时可访问本人的数据
+}
+
+// 是否可访问该部门的数据
+func (s *DataScope) ContainsDept(deptId uint) bool {
+	if s.All {
+		return true
+	}
+	for _, id := range s.DeptIDs {
+		if id == deptId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SCIM 协议使用的 schema，见 RFC 7643、RFC 7644
+const (
+	ScimSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	ScimSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ScimSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	ScimSchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ScimSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	ScimSchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// SCIM 资源的元数据
+type ScimMeta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+// SCIM 用户的姓名
+type ScimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIM 多值属性的元素，如邮箱、电话、用户所属组和组成员
+type ScimMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// SCIM 用户，对应 SysAdmin，groups 为用户的角色，只读
+type ScimUser struct {
+	Schemas      []string         `json:"schemas"`
+	ID           string           `json:"id,omitempty"`
+	ExternalID   string           `json:"externalId,omitempty"`
+	UserName     string           `json:"userName"`
+	Name         *ScimName        `json:"name,omitempty"`
+	DisplayName  string           `json:"displayName,omitempty"`
+	Emails       []ScimMultiValue `json:"emails,omitempty"`
+	PhoneNumbers []ScimMultiValue `json:"phoneNumbers,omitempty"`
+	Active       *bool            `json:"active,omitempty"` // 请求中未设置时为启用
+	Groups       []ScimMultiValue `json:"groups,omitempty"`
+	Meta         *ScimMeta        `json:"meta,omitempty"`
+}
+
+// SCIM 组，对应 SysRole，members 为拥有该角色的用户
+type ScimGroup struct {
+	Schemas     []string         `json:"schemas"`
+	ID          string           `json:"id,omitempty"`
+	ExternalID  string           `json:"externalId,omitempty"`
+	DisplayName string           `json:"displayName"`
+	Members     []ScimMultiValue `json:"members,omitempty"`
+	Meta        *ScimMeta        `json:"meta,omitempty"`
+}
+
+// SCIM 查询列表的参数
+type ScimListQuery struct {
+	Filter             string `form:"filter"`
+	StartIndex         int    `form:"startIndex"`         // 从1开始
+	Count              *int   `form:"count"`              // 为0时只返回总数
+	ExcludedAttributes string `form:"excludedAttributes"` // 逗号分隔，支持排除用户的 groups 和组的 members
+}
+
+// SCIM 列表响应
+type ScimListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int64    `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+// SCIM PATCH 请求
+type ScimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []ScimPatchOperation `json:"Operations"`
+}
+
+// SCIM PATCH 操作，op 为 add、replace 或 remove，不区分大小写
+type ScimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// 组成员关系，用于批量查询组的成员
+type ScimMemberRow struct {
+	RoleID   uint
+	AdminID  uint
+	Username string
+}
@@ -15,10 +15,25 @@ type SysAdmin struct {
 	Email     string      `gorm:"column:email;type:varchar(64)"`
 	Phone     string      `gorm:"column:phone;type:char(11)"`
 	Note      string      `gorm:"column:note;type:varchar(500);comment:'备注'"`
-	Status    uint        `gorm:"column:status;comment:'账号状态:1->启用,2->禁用';not null;default:1"`
+	Status    uint        `gorm:"column:status;comment:'账号状态:1->启用,2->禁用,3->待激活';not null;default:1"`
 	DeptID    uint        `gorm:"column:dept_id;comment:'部门id'"`
 	PostID    uint        `gorm:"column:post_id;comment:'岗位id'"`
 	CreatedAt utils.HTime `gorm:"column:created_at"`
+
+	TotpSecret  string `gorm:"column:totp_secret;type:varchar(255);comment:'加密存储的TOTP密钥'" json:"-"`
+	TotpEnabled bool   `gorm:"column:totp_enabled;comment:'是否开启两步验证';not null;default:false" json:"totpEnabled"`
+
+	PasswordChangedAt *utils.HTime `gorm:"column:password_changed_at;comment:'密码修改时间'" json:"passwordChangedAt"`
+
+	IsSuper bool `gorm:"column:is_super;comment:'是否为超级管理员，只能通过命令行设置';not null;default:false" json:"isSuper"`
+
+	IsServiceAccount bool `gorm:"column:is_service_account;comment:'是否为服务账号，不能交互式登录，只能使用API令牌';not null;default:false" json:"isServiceAccount"`
+
+	PasswordLoginDisabled bool    `gorm:"column:password_login_disabled;comment:'是否禁用密码登录，只能使用单点登录';not null;default:false" json:"passwordLoginDisabled"`
+	OidcIssuer            *string `gorm:"column:oidc_issuer;type:varchar(255);uniqueIndex:idx_oidc_identity;comment:'绑定的单点登录签发方'" json:"-"`
+	OidcSubject           *string `gorm:"column:oidc_subject;type:varchar(255);uniqueIndex:idx_oidc_identity;comment:'绑定的单点登录用户标识'" json:"-"`
+	LdapDN                string  `gorm:"column:ldap_dn;type:varchar(512);comment:'目录账号的DN，为空时为本地账号'" json:"ldapDn"`
+	ScimExternalID        *string `gorm:"column:scim_external_id;type:varchar(255);uniqueIndex;comment:'SCIM 身份提供方中的用户标识'" json:"-"`
 }
 
 func (SysAdmin) TableName() string {
@@ -44,10 +59,15 @@ type LoginDto struct {
 	CaptchaImage string `json:"captchaImage" binding:"required"`
 }
 
+// 刷新令牌请求结构体
+type RefreshTokenDto struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
 // 创建用户请求结构体
 type CreateAdminDto struct {
 	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Password string `json:"password" binding:"omitempty,password"` // 服务账号不需要密码
 	Nickname string `json:"nickname" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Phone    string `json:"phone" binding:"required"`
@@ -55,7 +75,11 @@ type CreateAdminDto struct {
 	Status   uint   `json:"staus" binding:"required,oneof=1 2"`
 	PostID   uint   `json:"postID" binding:"required"`
 	DeptID   uint   `json:"deptID" binding:"required"`
-	RoleID   uint   `json:"roleID" binding:"required"`
+	RoleIDs  []uint `json:"roleIds" binding:"required,min=1"`
+
+	ServiceAccount bool `json:"serviceAccount"` // 是否为服务账号，服务账号不能登录，只能使用API令牌
+
+	PasswordLoginDisabled bool `json:"passwordLoginDisabled"` // 禁用密码登录，只能使用单点登录
 }
 
 // 联表查询用户信息
@@ -63,17 +87,37 @@ type AdminList struct {
 	ID       uint   `json:"id"`       // ID
 	Username string `json:"username"` // 用户名
 	Nickname string `json:"nickname"` // 昵称
-	Status   uint   `json:"status"`   // 状态：1->启用,2->禁用
+	Status   uint   `json:"status"`   // 状态：1->启用,2->禁用,3->待激活(已邀请未接受)
 	PostId   uint   `json:"postId"`   // 岗位id
 	DeptId   uint   `json:"deptId"`   // 部门id
-	RoleId   uint   `json:"roleId" `  // 角色id
 	PostName string `json:"postName"` // 岗位名称
 	DeptName string `json:"deptName"` // 部门名称
-	RoleName string `json:"roleName"` // 角色名称
 	Icon     string `json:"icon"`     // 头像
 	Email    string `json:"email"`    // 邮箱
 	Phone    string `json:"phone"`    // 电话
 	Note     string `json:"note"`     // 备注
+	IsSuper  bool   `json:"isSuper"`  // 是否为超级管理员，不能删除或禁用
+
+	IsServiceAccount      bool `json:"isServiceAccount"`      // 是否为服务账号
+	PasswordLoginDisabled bool `json:"passwordLoginDisabled"` // 是否禁用密码登录
+
+	LdapDN string `json:"ldapDn"` // 目录账号的DN，为空时为本地账号
+
+	InvitationExpiresAt *utils.HTime `json:"invitationExpiresAt"` // 待激活用户的邀请过期时间，过期后需要重新发送
+
+	Roles []AdminRoleVo `json:"roles" gorm:"-"` // 角色列表
+}
+
+// 用户已分配的角色
+type AdminRoleVo struct {
+	AdminID    uint   `json:"-"`
+	ID         uint   `json:"id"`         // 角色id
+	RoleName   string `json:"roleName"`   // 角色名称
+	RoleStatus uint   `json:"roleStatus"` // 角色状态：1->启用,2->禁用
+
+	ValidFrom  *utils.HTime `json:"validFrom"`  // 授权生效时间
+	ValidUntil *utils.HTime `json:"validUntil"` // 授权失效时间
+	Reason     string       `json:"reason"`     // 授权原因
 }
 
 // 将联表查询的用户信息列表与分页信息放到一起返回给前端
@@ -89,13 +133,20 @@ type GetAdminByIdVo struct {
 	ID       uint   `json:"id"`       // ID
 	Username string `json:"username"` // 用户名
 	Nickname string `json:"nickname"` // 昵称
-	Status   uint   `json:"status"`   // 状态：1->启用,2->禁用
+	Status   uint   `json:"status"`   // 状态：1->启用,2->禁用,3->待激活(已邀请未接受)
 	PostId   uint   `json:"postId"`   // 岗位id
 	DeptId   uint   `json:"deptId"`   // 部门id
-	RoleId   uint   `json:"roleId" `  // 角色id
 	Email    string `json:"email"`    // 邮箱
 	Phone    string `json:"phone"`    // 手机号
 	Note     string `json:"note"`     // 备注
+	IsSuper  bool   `json:"isSuper"`  // 是否为超级管理员，不能删除或禁用
+
+	IsServiceAccount      bool `json:"isServiceAccount"`      // 是否为服务账号
+	PasswordLoginDisabled bool `json:"passwordLoginDisabled"` // 是否禁用密码登录
+
+	LdapDN string `json:"ldapDn"` // 目录账号的DN，为空时为本地账号
+
+	Roles []AdminRoleVo `json:"roles" gorm:"-"` // 角色列表
 }
 
 // 修改用户请求结构体
@@ -103,13 +154,15 @@ type UpdateAdminDto struct {
 	ID       uint    `json:"id" binding:"required"`
 	PostId   *uint   `json:"postId"`
 	DeptId   *uint   `json:"deptId"`
-	RoleId   *uint   `json:"roleId"`
+	RoleIds  []uint  `json:"roleIds"` // 为null时不修改角色，为空数组时移除全部角色
 	Username *string `json:"username"`
 	Nickname *string `json:"nickname"`
 	Phone    *string `json:"phone"`
 	Email    *string `json:"email" binding:"omitempty,email"`
 	Note     *string `json:"note"`
 	Status   *uint   `json:"status" binding:"omitempty,oneof=1 2"`
+
+	PasswordLoginDisabled *bool `json:"passwordLoginDisabled"` // 是否禁用密码登录，只能使用单点登录
 }
 
 // 删除用户请求结构体
@@ -123,10 +176,15 @@ type UpdateAdminStatusDto struct {
 	NewStatus uint `json:"newStatus" binding:"omitempty,oneof=1 2"`
 }
 
+// 解除登录锁定请求结构体
+type UnlockAdminDto struct {
+	ID uint `json:"id" binding:"required"`
+}
+
 // 重置密码请求结构体
 type ResetPasswordDto struct {
 	ID          uint   `json:"id" binding:"required"`
-	NewPassword string `json:"newPassword"`
+	NewPassword string `json:"newPassword" binding:"required,password"`
 }
 
 // 修改个人资料请求结构体
@@ -142,6 +200,31 @@ type UpdatePersonalDto struct {
 // 修改个人密码请求结构体
 type UpdatePasswordDto struct {
 	Password    string `json:"password" binding:"required"`
-	NewPassword string `json:"newPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,password"`
 	RePassword  string `json:"rePassword" binding:"required"`
 }
+
+// 当前登录用户个人资料响应结构体
+type ProfileVo struct {
+	ID        uint        `json:"id"`                 // ID
+	Username  string      `json:"username"`           // 用户名
+	Nickname  string      `json:"nickname"`           // 昵称
+	Icon      string      `json:"icon"`               // 头像
+	Email     string      `json:"email"`              // 邮箱
+	Phone     string      `json:"phone"`              // 电话
+	Note      string      `json:"note"`               // 备注
+	DeptId    uint        `json:"deptId"`             // 部门id
+	DeptName  string      `json:"deptName"`           // 部门名称
+	PostId    uint        `json:"postId"`             // 岗位id
+	PostName  string      `json:"postName"`           // 岗位名称
+	RoleNames []string    `json:"roleNames" gorm:"-"` // 角色名称列表
+	CreatedAt utils.HTime `json:"createdAt"`          // 创建时间
+
+	TotpEnabled bool `json:"totpEnabled"` // 是否开启两步验证
+}
+
+// 带版本号的个人资料，前端通过比较版本号判断数据是否发生变化
+type MyProfileVo struct {
+	Version string     `json:"version"`
+	Profile *ProfileVo `json:"profile"`
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"encoding/json"
+	"go-admin-server/common/utils"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// 通行密钥会话的用途，注册会话不能用于登录，反之亦然
+const (
+	PasskeyPurposeRegister  = "register"  // 注册通行密钥
+	PasskeyPurposeLogin     = "login"     // 免密码登录
+	PasskeyPurposeTwoFactor = "twoFactor" // 两步验证
+)
+
+// 通行密钥模型，保存认证器注册时生成的公钥，可以用于免密码登录或两步验证。每个用户可以注册多个
+type SysAdminCredential struct {
+	ID              uint         `json:"id" gorm:"column:id;primaryKey"`
+	AdminID         uint         `json:"adminId" gorm:"column:admin_id;comment:'所属用户id';index;not null"`
+	Name            string       `json:"name" gorm:"column:name;type:varchar(64);comment:'通行密钥名称';not null"`
+	CredentialID    string       `json:"-" gorm:"column:credential_id;type:varchar(512);comment:'凭据id，base64url编码';uniqueIndex;not null"`
+	PublicKey       []byte       `json:"-" gorm:"column:public_key;type:blob;comment:'COSE格式的公钥';not null"`
+	AttestationType string       `json:"-" gorm:"column:attestation_type;type:varchar(32);comment:'注册时的证明格式'"`
+	Transports      string       `json:"transports" gorm:"column:transports;type:varchar(255);comment:'认证器支持的传输方式，逗号分隔'"`
+	AAGUID          string       `json:"aaguid" gorm:"column:aaguid;type:varchar(36);comment:'认证器型号标识'"`
+	SignCount       uint32       `json:"-" gorm:"column:sign_count;comment:'签名计数，用于发现被复制的认证器';not null;default:0"`
+	BackupEligible  bool         `json:"backupEligible" gorm:"column:backup_eligible;comment:'是否可以同步到其他设备';not null;default:false"`
+	BackupState     bool         `json:"backupState" gorm:"column:backup_state;comment:'是否已同步到其他设备';not null;default:false"`
+	LastUsedAt      *utils.HTime `json:"lastUsedAt" gorm:"column:last_used_at;comment:'最近一次使用时间'"`
+	LastUsedIp      string       `json:"lastUsedIp" gorm:"column:last_used_ip;type:varchar(128);comment:'最近一次使用的ip'"`
+	CreatedAt       utils.HTime  `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (SysAdminCredential) TableName() string {
+	return "sys_admin_credential"
+}
+
+// 通行密钥会话，发起注册或验证时保存在redis中，完成时取出并删除，每个会话只能使用一次
+type PasskeySession struct {
+	Purpose        string               `json:"purpose"`                  // 用途
+	AdminID        uint                 `json:"adminId"`                  // 注册和两步验证时为发起的用户id，免密码登录时为0
+	ChallengeToken string               `json:"challengeToken,omitempty"` // 两步验证时对应的登录挑战令牌
+	Data           webauthn.SessionData `json:"data"`                     // 挑战值等校验数据
+}
+
+// 发起注册或验证的响应结构体
+type PasskeyOptionsVo struct {
+	SessionID string    `json:"sessionId"`                    // 会话id，完成时原样提交
+	Options   any       `json:"options" swaggertype:"object"` // 传给 navigator.credentials.create() 或 get() 的参数
+	ExpiresAt time.Time `json:"expiresAt"`                    // 需要在该时间前完成
+}
+
+// 完成注册通行密钥请求结构体
+type FinishPasskeyRegistrationDto struct {
+	SessionID  string          `json:"sessionId" binding:"required"`
+	Name       string          `json:"name" binding:"required,max=64"`                     // 通行密钥名称，便于区分不同设备
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"` // navigator.credentials.create() 返回的凭据
+}
+
+// 修改通行密钥名称请求结构体
+type RenamePasskeyDto struct {
+	ID   uint   `json:"id" binding:"required"`
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// 删除通行密钥请求结构体
+type DeletePasskeyDto struct {
+	ID uint `json:"id" binding:"required"`
+}
+
+// 通行密钥免密码登录请求结构体
+type PasskeyLoginDto struct {
+	SessionID  string          `json:"sessionId" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"` // navigator.credentials.get() 返回的凭据
+}
+
+// 发起通行密钥两步验证请求结构体
+type TwoFactorPasskeyOptionsDto struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+}
+
+// 通行密钥两步验证登录请求结构体
+type TwoFactorPasskeyLoginDto struct {
+	ChallengeToken string          `json:"challengeToken" binding:"required"`
+	SessionID      string          `json:"sessionId" binding:"required"`
+	Credential     json.RawMessage `json:"credential" binding:"required" swaggertype:"object"` // navigator.credentials.get() 返回的凭据
+}
@@ -0,0 +1,56 @@
+package entity
+
+import "go-admin-server/common/utils"
+
+// 用户邀请：被邀请的用户为待激活状态(status 为 3)，通过邮件中的链接设置密码后激活，激活后删除邀请
+type SysAdminInvitation struct {
+	ID        uint        `gorm:"column:id;primaryKey" json:"id"`
+	AdminID   uint        `gorm:"column:admin_id;uniqueIndex;not null;comment:'被邀请的用户id'" json:"adminId"`
+	TokenHash string      `gorm:"column:token_hash;type:char(64);uniqueIndex;not null;comment:'邀请令牌的SHA-256摘要'" json:"-"`
+	InvitedBy uint        `gorm:"column:invited_by;comment:'邀请人id'" json:"invitedBy"`
+	ExpiresAt utils.HTime `gorm:"column:expires_at;not null;comment:'邀请过期时间'" json:"expiresAt"`
+	SentAt    utils.HTime `gorm:"column:sent_at;comment:'最近一次发送邀请邮件的时间'" json:"sentAt"`
+	SendCount int         `gorm:"column:send_count;not null;default:0;comment:'邀请邮件发送次数'" json:"sendCount"`
+	CreatedAt utils.HTime `gorm:"column:created_at" json:"createdAt"`
+}
+
+func (SysAdminInvitation) TableName() string {
+	return "sys_admin_invitation"
+}
+
+// 邀请用户请求结构体，用户的密码由被邀请人设置
+type InviteAdminDto struct {
+	Username string `json:"username" binding:"required"`
+	Nickname string `json:"nickname" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Phone    string `json:"phone" binding:"omitempty,max=11"`
+	Note     string `json:"note"`
+	PostID   uint   `json:"postID" binding:"required"`
+	DeptID   uint   `json:"deptID" binding:"required"`
+	RoleIDs  []uint `json:"roleIds" binding:"required,min=1"`
+}
+
+// 重新发送、撤销邀请请求结构体
+type AdminInvitationDto struct {
+	ID uint `json:"id" binding:"required"` // 待激活用户的id
+}
+
+// 查询邀请信息请求结构体
+type InvitationTokenDto struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// 接受邀请请求结构体
+type AcceptInvitationDto struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,password"`
+	RePassword  string `json:"rePassword" binding:"required"`
+}
+
+// 邀请信息响应结构体，用于接受邀请页面展示
+type InvitationInfoVo struct {
+	Username  string      `json:"username"`
+	Nickname  string      `json:"nickname"`
+	Email     string      `json:"email"`
+	ExpiresAt utils.HTime `json:"expiresAt"`
+}
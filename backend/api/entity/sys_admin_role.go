@@ -1,8 +1,14 @@
 package entity
 
+import "go-admin-server/common/utils"
+
 type SysAdminRole struct {
 	AdminID uint `gorm:"admin_id"`
 	RoleID  uint `gorm:"role_id"`
+
+	ValidFrom  *utils.HTime `gorm:"column:valid_from;comment:'生效时间，为空时立即生效'"`
+	ValidUntil *utils.HTime `gorm:"column:valid_until;index;comment:'失效时间，为空时长期有效'"`
+	Reason     string       `gorm:"column:reason;type:varchar(255);comment:'授权原因'"`
 }
 
 func (SysAdminRole) TableName() string {
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"strings"
+)
+
+// API令牌的固定前缀，便于识别令牌类型以及在代码仓库中扫描泄露的令牌
+const ApiTokenPrefix = "gat_"
+
+// API令牌模型，用于脚本、CI等自动化调用接口。令牌明文只在创建时返回一次，数据库中只保存哈希值
+type SysApiToken struct {
+	ID          uint         `json:"id" gorm:"column:id;primaryKey"`
+	AdminID     uint         `json:"adminId" gorm:"column:admin_id;comment:'令牌所属用户id';index;not null"`
+	Name        string       `json:"name" gorm:"column:name;type:varchar(64);comment:'令牌名称';not null"`
+	TokenHash   string       `json:"-" gorm:"column:token_hash;type:char(64);comment:'令牌的SHA-256哈希';uniqueIndex;not null"`
+	TokenPrefix string       `json:"tokenPrefix" gorm:"column:token_prefix;type:varchar(16);comment:'令牌开头的几位字符，用于辨认令牌'"`
+	Scopes      string       `json:"-" gorm:"column:scopes;type:text;comment:'权限范围，逗号分隔的按钮权限值，为空时与所属用户的权限一致'"`
+	ExpiresAt   *utils.HTime `json:"expiresAt" gorm:"column:expires_at;comment:'过期时间，为空时长期有效'"`
+	LastUsedAt  *utils.HTime `json:"lastUsedAt" gorm:"column:last_used_at;comment:'最近一次使用时间'"`
+	LastUsedIp  string       `json:"lastUsedIp" gorm:"column:last_used_ip;type:varchar(128);comment:'最近一次使用的ip'"`
+	CreatedBy   string       `json:"createdBy" gorm:"column:created_by;type:varchar(64);comment:'创建人'"`
+	CreatedAt   utils.HTime  `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (SysApiToken) TableName() string {
+	return "sys_api_token"
+}
+
+// 令牌的权限范围列表，为空表示不限制
+func (t *SysApiToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(t.Scopes, ",")
+}
+
+// 令牌是否允许使用指定的按钮权限，还需要所属用户拥有该权限
+func (t *SysApiToken) AllowPermission(value string) bool {
+	if t.Scopes == "" {
+		return true
+	}
+	for _, scope := range t.ScopeList() {
+		if scope == value {
+			return true
+		}
+	}
+	return false
+}
+
+// 创建API令牌请求结构体
+type CreateApiTokenDto struct {
+	AdminID   uint         `json:"adminId"`                                  // 令牌所属的服务账号id，个人令牌不需要传
+	Name      string       `json:"name" binding:"required,max=64"`           // 令牌名称
+	Scopes    []string     `json:"scopes" binding:"omitempty,dive,required"` // 权限范围，按钮权限值列表，为空时与所属用户的权限一致
+	ExpiresAt *utils.HTime `json:"expiresAt"`                                // 过期时间，为空时长期有效
+}
+
+// 创建API令牌响应结构体，令牌明文只返回这一次
+type ApiTokenCreatedVo struct {
+	ID        uint         `json:"id"`
+	Name      string       `json:"name"`
+	Token     string       `json:"token"` // 令牌明文，请求时放在 X-API-Key 请求头或 Authorization: Bearer 中
+	ExpiresAt *utils.HTime `json:"expiresAt"`
+}
+
+// API令牌列表项
+type ApiTokenVo struct {
+	SysApiToken
+	Username  string   `json:"username"`         // 所属用户名
+	ScopeList []string `json:"scopes" gorm:"-"`  // 权限范围
+	Expired   bool     `json:"expired" gorm:"-"` // 是否已过期
+}
+
+// API令牌列表响应结构体
+type ApiTokenListVo response.PaginatedResult[ApiTokenVo]
+
+// 吊销API令牌请求结构体
+type RevokeApiTokenDto struct {
+	ID uint `json:"id" binding:"required"`
+}
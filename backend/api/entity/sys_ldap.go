@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// 目录同步请求结构体
+type LdapSyncDto struct {
+	DryRun bool `json:"dryRun"` // 只生成同步报告，不修改数据
+}
+
+// 目录同步报告中的一个用户
+type LdapSyncItem struct {
+	Username string   `json:"username"`
+	DN       string   `json:"dn"`
+	Changes  []string `json:"changes,omitempty"` // 创建或更新的内容
+	Reason   string   `json:"reason,omitempty"`  // 跳过、停用或失败的原因
+}
+
+// 目录同步报告，试运行时列出将要执行的变更
+type LdapSyncReport struct {
+	DryRun     bool           `json:"dryRun"`     // 是否为试运行
+	StartedAt  time.Time      `json:"startedAt"`  // 开始时间
+	FinishedAt time.Time      `json:"finishedAt"` // 结束时间
+	Total      int            `json:"total"`      // 目录中的用户数
+	Created    []LdapSyncItem `json:"created"`    // 创建的用户
+	Updated    []LdapSyncItem `json:"updated"`    // 更新的用户
+	Disabled   []LdapSyncItem `json:"disabled"`   // 停用的用户
+	Skipped    []LdapSyncItem `json:"skipped"`    // 跳过的用户
+	Failed     []LdapSyncItem `json:"failed"`     // 同步失败的用户
+	Warnings   []string       `json:"warnings"`   // 警告
+}
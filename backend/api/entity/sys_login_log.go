@@ -16,6 +16,11 @@ type SysLoginLog struct {
 	LoginStatus   uint        `json:"loginStatus" gorm:"column:login_status;comment:'登录状态: 1->成功,2->失败'"`
 	Message       string      `json:"message" gorm:"column:message;type:varchar(255);comment:'提示信息'"`
 	LoginAt       utils.HTime `json:"loginAt" gorm:"column:login_at;comment:'登录时间'"`
+
+	Country string `json:"country" gorm:"column:country;type:varchar(64);index;comment:'国家'"`
+	Region  string `json:"region" gorm:"column:region;type:varchar(64);index;comment:'省份/地区'"`
+	City    string `json:"city" gorm:"column:city;type:varchar(64);index;comment:'城市'"`
+	Isp     string `json:"isp" gorm:"column:isp;type:varchar(64);comment:'运营商'"`
 }
 
 func (SysLoginLog) TableName() string {
@@ -25,6 +30,14 @@ func (SysLoginLog) TableName() string {
 // 登录日志列表响应结构体
 type LoginLogListVo response.PaginatedResult[SysLoginLog]
 
+// 登录地点查询条件
+type LoginLocationQuery struct {
+	Country string
+	Region  string
+	City    string
+	Isp     string
+}
+
 // 删除登录日志请求结构体
 type DeleteLoginLogDto struct {
 	ID uint `json:"id"`
@@ -83,3 +83,15 @@ type FirstLevelMenuVo struct {
 type PermissionListVo struct {
 	Value string `json:"value"` // 权限
 }
+
+// 带版本号的左侧菜单列表，前端通过比较版本号判断菜单是否发生变化
+type MyMenusVo struct {
+	Version string             `json:"version"`
+	Menus   []FirstLevelMenuVo `json:"menus"`
+}
+
+// 带版本号的权限列表
+type MyPermissionsVo struct {
+	Version     string             `json:"version"`
+	Permissions []PermissionListVo `json:"permissions"`
+}
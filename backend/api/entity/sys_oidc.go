@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// 单点登录授权请求，发起登录时保存在redis中，回调时按 state 取出并删除
+type OidcAuthRequest struct {
+	Nonce        string `json:"nonce"`        // 防止 ID Token 重放
+	CodeVerifier string `json:"codeVerifier"` // PKCE 校验码，只保存在服务端
+}
+
+// 单点登录配置响应结构体，用于登录页决定是否显示单点登录入口
+type OidcConfigVo struct {
+	Enabled      bool   `json:"enabled"`      // 是否启用单点登录
+	ProviderName string `json:"providerName"` // 身份提供方名称
+}
+
+// 发起单点登录响应结构体
+type OidcAuthorizeVo struct {
+	AuthURL   string    `json:"authUrl"`   // 跳转到身份提供方的授权地址
+	State     string    `json:"state"`     // 前端保存后与回调参数比对，防止登录CSRF
+	ExpiresAt time.Time `json:"expiresAt"` // 需要在该时间前完成登录
+}
+
+// 单点登录回调请求结构体，参数为身份提供方重定向到前端回调页面时携带的 code 和 state
+type OidcLoginDto struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
@@ -14,6 +14,22 @@ type SysOperationLog struct {
 	Ip        string      `json:"ip" gorm:"column:ip;type:varchar(128)"`
 	Url       string      `json:"url" gorm:"column:url;type:varchar(500)"`
 	CreatedAt utils.HTime `json:"createdAt" gorm:"column:created_at"`
+
+	Module    string `json:"module" gorm:"column:module;type:varchar(64);comment:'业务模块'"`
+	Action    string `json:"action" gorm:"column:action;type:varchar(64);comment:'业务操作'"`
+	Query     string `json:"query" gorm:"column:query;type:varchar(1000);comment:'查询参数'"`
+	Body      string `json:"body" gorm:"column:body;type:text;comment:'请求体，敏感字段已脱敏'"`
+	Status    int    `json:"status" gorm:"column:status;comment:'HTTP状态码'"`
+	Code      int    `json:"code" gorm:"column:code;comment:'业务状态码'"`
+	ErrorMsg  string `json:"errorMsg" gorm:"column:error_msg;type:varchar(500);comment:'错误信息'"`
+	Latency   int64  `json:"latency" gorm:"column:latency;comment:'耗时(毫秒)'"`
+	UserAgent string `json:"userAgent" gorm:"column:user_agent;type:varchar(500)"`
+
+	ImpersonatorID   uint   `json:"impersonatorId" gorm:"column:impersonator_id;comment:'模拟登录的发起人id，AdminID 为被模拟的用户'"`
+	ImpersonatorName string `json:"impersonatorName" gorm:"column:impersonator_name;type:varchar(64);comment:'模拟登录的发起人用户名'"`
+
+	ApiTokenID   uint   `json:"apiTokenId" gorm:"column:api_token_id;comment:'使用API令牌调用时的令牌id'"`
+	ApiTokenName string `json:"apiTokenName" gorm:"column:api_token_name;type:varchar(64);comment:'使用API令牌调用时的令牌名称'"`
 }
 
 func (SysOperationLog) TableName() string {
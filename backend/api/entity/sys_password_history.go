@@ -0,0 +1,15 @@
+package entity
+
+import "go-admin-server/common/utils"
+
+// 密码历史模型，记录用户使用过的密码哈希，用于禁止重复使用最近的密码
+type SysPasswordHistory struct {
+	ID        uint        `gorm:"column:id;primaryKey"`
+	AdminID   uint        `gorm:"column:admin_id;comment:'用户id';index;not null"`
+	Password  string      `gorm:"column:password;type:varchar(64);comment:'密码哈希';not null"`
+	CreatedAt utils.HTime `gorm:"column:created_at"`
+}
+
+func (SysPasswordHistory) TableName() string {
+	return "sys_password_history"
+}
@@ -0,0 +1,21 @@
+package entity
+
+// 找回密码请求结构体，账号可以是用户名或邮箱
+type ForgotPasswordDto struct {
+	Account      string `json:"account" binding:"required"`
+	CaptchaID    string `json:"captchaId" binding:"required"`
+	CaptchaImage string `json:"captchaImage" binding:"required"`
+}
+
+// 通过邮件链接重置密码请求结构体
+type PasswordResetDto struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,password"`
+	RePassword  string `json:"rePassword" binding:"required"`
+}
+
+// 密码重置令牌，以令牌摘要为键保存在redis中。申请后密码被修改过时令牌失效
+type PasswordResetToken struct {
+	AdminID           uint  `json:"adminId"`
+	PasswordChangedAt int64 `json:"passwordChangedAt"` // 申请时的密码修改时间(unix 毫秒)
+}
@@ -13,6 +13,11 @@ type SysRole struct {
 	RoleStatus  uint        `gorm:"column:role_status;comment:'角色状态: 1->启用,2->禁用';not null;default:1" json:"roleStatus"`
 	Description string      `gorm:"column:description;type:varchar(500)" json:"description"`
 	CreatedAt   utils.HTime `gorm:"column:created_at" json:"createdAt"`
+
+	RequireTwoFactor bool `gorm:"column:require_two_factor;comment:'是否要求开启两步验证';not null;default:false" json:"requireTwoFactor"`
+	DataScope        uint `gorm:"column:data_scope;comment:'数据权限: 1->全部,2->自定义部门,3->本部门,4->本部门及以下,5->仅本人';not null;default:1" json:"dataScope"`
+
+	ParentID *uint `gorm:"column:parent_id;comment:'父角色id，继承父角色的菜单权限'" json:"parentId"`
 }
 
 func (SysRole) TableName() string {
@@ -25,6 +30,11 @@ type CreateRoleDto struct {
 	RoleKey     string `json:"roleKey" binding:"required"`
 	RoleStatus  uint   `json:"roleStatus" binding:"omitempty,oneof=1 2"`
 	Description string `json:"description" binding:"omitempty"`
+
+	RequireTwoFactor bool `json:"requireTwoFactor"`                              // 是否要求拥有该角色的用户开启两步验证
+	DataScope        uint `json:"dataScope" binding:"omitempty,oneof=1 2 3 4 5"` // 数据权限，默认全部数据，自定义部门通过分配数据权限设置
+
+	ParentID uint `json:"parentId"` // 父角色id，为0时不继承
 }
 
 // 查询角色列表响应结构体，将角色列表与分页信息封装到响应结构体中
@@ -42,6 +52,28 @@ type UpdateRoleDto struct {
 	RoleKey     *string `json:"roleKey"`
 	RoleStatus  *uint   `json:"roleStatus" binding:"omitempty,oneof=1 2"`
 	Description *string `json:"description"`
+
+	RequireTwoFactor *bool `json:"requireTwoFactor"`
+
+	ParentID *uint `json:"parentId"` // 为0时取消继承
+}
+
+// 查询角色数据权限请求结构体
+type GetRoleDataScopeDto struct {
+	ID uint `json:"id" binding:"required"`
+}
+
+// 角色数据权限响应结构体
+type RoleDataScopeVo struct {
+	DataScope uint   `json:"dataScope"` // 数据权限
+	DeptIDs   []uint `json:"deptIds"`   // 自定义部门
+}
+
+// 分配角色数据权限请求结构体
+type AssignRoleDataScopeDto struct {
+	ID        uint   `json:"id" binding:"required"`
+	DataScope uint   `json:"dataScope" binding:"required,oneof=1 2 3 4 5"`
+	DeptIDs   []uint `json:"deptIds"` // 数据权限为自定义部门时可访问的部门
 }
 
 // 删除角色请求结构体
@@ -66,8 +98,45 @@ type GetRoleMenusDto struct {
 	ID uint `json:"id" binding:"required"`
 }
 
+// 角色权限列表响应结构体，继承的权限不包含角色直接分配的权限
+type RoleMenusVo struct {
+	MenuIDs          []uint `json:"menuIds"`          // 直接分配的权限
+	InheritedMenuIDs []uint `json:"inheritedMenuIds"` // 从父角色继承的权限
+}
+
 // 角色分配的权限（菜单）列表
 type AssignRoleMenusDto struct {
 	ID      uint   `json:"id" binding:"required"` // 角色id
 	MenuIDs []uint `json:"menuIds" binding:"required"`
 }
+
+// 查询拥有角色的用户列表响应结构体
+type RoleAdminListVo response.PaginatedResult[RoleAdminVo]
+
+// 拥有角色的用户
+type RoleAdminVo struct {
+	ID       uint   `json:"id"`       // 用户id
+	Username string `json:"username"` // 用户名
+	Nickname string `json:"nickname"` // 昵称
+	Status   uint   `json:"status"`   // 状态：1->启用,2->禁用
+	DeptName string `json:"deptName"` // 部门名称
+
+	ValidFrom  *utils.HTime `json:"validFrom"`  // 授权生效时间
+	ValidUntil *utils.HTime `json:"validUntil"` // 授权失效时间
+	Reason     string       `json:"reason"`     // 授权原因
+}
+
+// 批量为角色添加用户请求结构体，可设置授权有效期用于临时授权
+type AddRoleAdminsDto struct {
+	ID         uint         `json:"id" binding:"required"` // 角色id
+	AdminIDs   []uint       `json:"adminIds" binding:"required,min=1"`
+	ValidFrom  *utils.HTime `json:"validFrom"`                // 生效时间，为空时立即生效
+	ValidUntil *utils.HTime `json:"validUntil"`               // 失效时间，为空时长期有效
+	Reason     string       `json:"reason" binding:"max=255"` // 授权原因
+}
+
+// 批量从角色中移除用户请求结构体
+type RoleAdminsDto struct {
+	ID       uint   `json:"id" binding:"required"` // 角色id
+	AdminIDs []uint `json:"adminIds" binding:"required,min=1"`
+}
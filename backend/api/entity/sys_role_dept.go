@@ -0,0 +1,11 @@
+package entity
+
+// 角色部门模型，数据权限为自定义部门时，角色可访问的部门
+type SysRoleDept struct {
+	RoleID uint `gorm:"column:role_id;comment:'角色id';not null"`
+	DeptID uint `gorm:"column:dept_id;comment:'部门id';not null"`
+}
+
+func (SysRoleDept) TableName() string {
+	return "sys_role_dept"
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "go-admin-server/common/utils"
+
+// 登录方式
+const (
+	LoginMethodPassword = "password" // 用户名密码登录
+	LoginMethodOidc     = "oidc"     // OIDC 单点登录
+	LoginMethodLdap     = "ldap"     // LDAP 目录账号密码登录
+	LoginMethodPasskey  = "passkey"  // 通行密钥免密码登录
+)
+
+// 登录会话，存储在redis中，每次登录生成一个会话，令牌通过会话id与之关联
+type SysSession struct {
+	SessionID    string      `json:"sessionId"`    // 会话id
+	AdminID      uint        `json:"adminId"`      // 用户id
+	Username     string      `json:"username"`     // 用户名
+	Device       string      `json:"device"`       // 设备
+	Browser      string      `json:"browser"`      // 浏览器
+	Os           string      `json:"os"`           // 操作系统
+	Ip           string      `json:"ip"`           // 登录ip
+	Location     string      `json:"location"`     // 登录地点
+	LoginAt      utils.HTime `json:"loginAt"`      // 登录时间
+	LastActiveAt utils.HTime `json:"lastActiveAt"` // 最近一次刷新令牌的时间
+	Current      bool        `json:"current"`      // 是否为当前请求所在的会话
+
+	ImpersonatorID   uint   `json:"impersonatorId,omitempty"`   // 模拟登录会话的发起人id
+	ImpersonatorName string `json:"impersonatorName,omitempty"` // 模拟登录会话的发起人用户名
+
+	LoginMethod string `json:"loginMethod"` // 登录方式：password、oidc、ldap、passkey，为空时为密码登录
+}
+
+// 吊销会话请求结构体
+type RevokeSessionDto struct {
+	SessionID string `json:"sessionId" binding:"required"`
+}
+
+// 强制用户下线请求结构体
+type ForceLogoutDto struct {
+	ID uint `json:"id" binding:"required"`
+}
+
+// 模拟登录请求结构体
+type ImpersonateDto struct {
+	ID uint `json:"id" binding:"required"` // 被模拟的用户id
+}
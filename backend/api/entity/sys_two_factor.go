@@ -0,0 +1,80 @@
+package entity
+
+import "time"
+
+// 两步验证恢复码模型，只保存恢复码的哈希值，每个恢复码只能使用一次
+type SysRecoveryCode struct {
+	ID       uint   `gorm:"column:id;primaryKey"`
+	AdminID  uint   `gorm:"column:admin_id;comment:'用户id';index;not null"`
+	CodeHash string `gorm:"column:code_hash;type:char(64);comment:'恢复码的SHA-256哈希';not null"`
+}
+
+func (SysRecoveryCode) TableName() string {
+	return "sys_recovery_code"
+}
+
+// 两步验证挑战，密码验证通过后保存在redis中，等待用户提交验证码
+type LoginChallenge struct {
+	AdminID  uint   `json:"adminId"`
+	Username string `json:"username"`
+	Ip       string `json:"ip"`
+	Browser  string `json:"browser"`
+	Os       string `json:"os"`
+	Device   string `json:"device"`
+
+	LoginMethod string `json:"loginMethod"` // 第一步验证使用的登录方式
+}
+
+// 需要两步验证时的登录响应结构体
+type LoginChallengeVo struct {
+	TwoFactorRequired bool      `json:"twoFactorRequired"` // 固定为true，表示需要继续进行两步验证
+	SetupRequired     bool      `json:"setupRequired"`     // 所属角色要求两步验证但尚未绑定，需要先绑定验证器
+	ChallengeToken    string    `json:"challengeToken"`    // 挑战令牌
+	ExpiresAt         time.Time `json:"expiresAt"`         // 挑战令牌过期时间
+
+	PasskeyAvailable bool `json:"passkeyAvailable"` // 已注册通行密钥，可以使用通行密钥代替验证码
+}
+
+// 两步验证登录请求结构体，Code 可以是验证器App中的6位验证码或恢复码
+type TwoFactorLoginDto struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// 登录时绑定两步验证请求结构体
+type TwoFactorLoginSetupDto struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+}
+
+// 绑定两步验证响应结构体
+type TwoFactorSetupVo struct {
+	Secret     string `json:"secret"`     // base32 编码的密钥，无法扫码时手动输入
+	OtpauthURL string `json:"otpauthUrl"` // otpauth:// 地址
+	QRCode     string `json:"qrCode"`     // base64 编码的二维码图片
+}
+
+// 开启两步验证请求结构体
+type EnableTwoFactorDto struct {
+	Code string `json:"code" binding:"required,len=6"`
+}
+
+// 关闭两步验证请求结构体
+type DisableTwoFactorDto struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// 重新生成恢复码请求结构体
+type RegenerateRecoveryCodesDto struct {
+	Code string `json:"code" binding:"required,len=6"`
+}
+
+// 恢复码响应结构体，恢复码只在生成时返回一次
+type RecoveryCodesVo struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// 重置用户两步验证请求结构体
+type ResetTwoFactorDto struct {
+	ID uint `json:"id" binding:"required"`
+}
@@ -0,0 +1,223 @@
+// 用户邀请：管理员预先设置部门、岗位和角色后向被邀请人发送邮件，用户为待激活状态(status 为 3)，
+// 被邀请人通过邮件中的一次性链接设置密码后激活。邀请过期后可以重新发送，撤销邀请时删除待激活的用户
+
+package service
+
+import (
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/mailer"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const defaultInvitationTTL = 72 * time.Hour
+
+type AdminInvitationService struct{}
+
+// 是否可以邀请用户
+func invitationEnabled() bool {
+	return global.Config.Invitation.AcceptURL != "" && mailer.Enabled()
+}
+
+func invitationTTL() time.Duration {
+	if ttl := global.Config.Invitation.TTL; ttl > 0 {
+		return ttl
+	}
+	return defaultInvitationTTL
+}
+
+// 邀请用户：创建待激活的用户并发送邀请邮件。邮件发送失败时邀请仍然保留，可以重新发送
+func (s *AdminInvitationService) InviteAdmin(scope *entity.DataScope, operatorId uint, dto *entity.InviteAdminDto) error {
+	if !invitationEnabled() {
+		return response.ErrInvitationDisabled
+	}
+	roleIds, err := checkNewAdmin(scope, operatorId, dto.Username, dto.Nickname, dto.DeptID, dto.PostID, dto.RoleIDs)
+	if err != nil {
+		return err
+	}
+	// 接受邀请前使用随机密码，不能登录
+	password, err := utils.RandomHex(32)
+	if err != nil {
+		return response.ErrServerError
+	}
+	user := &entity.SysAdmin{
+		Username:  dto.Username,
+		Nickname:  dto.Nickname,
+		Email:     dto.Email,
+		Phone:     dto.Phone,
+		Status:    3,
+		Note:      dto.Note,
+		DeptID:    dto.DeptID,
+		PostID:    dto.PostID,
+		CreatedAt: utils.HTime{Time: time.Now()},
+	}
+	if err := applyNewPassword(user, password); err != nil {
+		return response.ErrServerError
+	}
+	invitation := &entity.SysAdminInvitation{
+		InvitedBy: operatorId,
+		CreatedAt: utils.HTime{Time: time.Now()},
+	}
+	token, err := renewInvitation(invitation)
+	if err != nil {
+		return err
+	}
+	if err := AdminInvitationDao.CreateInvitedAdmin(roleIds, user, invitation); err != nil {
+		return response.ErrServerError
+	}
+	global.Logger.Info("Invited admin", zap.Uint("adminId", user.ID), zap.String("username", user.Username), zap.Uint("invitedBy", operatorId))
+	return sendInvitationMail(user, invitation, token)
+}
+
+// 重新发送邀请：更换链接并重新计算有效期，之前发送的链接失效
+func (s *AdminInvitationService) ResendInvitation(scope *entity.DataScope, id uint) error {
+	if !invitationEnabled() {
+		return response.ErrInvitationDisabled
+	}
+	user, invitation, err := getPendingInvitation(scope, id)
+	if err != nil {
+		return err
+	}
+	token, err := renewInvitation(invitation)
+	if err != nil {
+		return err
+	}
+	if err := AdminInvitationDao.UpdateInvitation(invitation); err != nil {
+		return response.ErrServerError
+	}
+	return sendInvitationMail(user, invitation, token)
+}
+
+// 撤销邀请：删除待激活的用户
+func (s *AdminInvitationService) RevokeInvitation(scope *entity.DataScope, id uint) error {
+	user, _, err := getPendingInvitation(scope, id)
+	if err != nil {
+		return err
+	}
+	if err := SysAdminDao.DeleteAdmin(user.ID); err != nil {
+		return response.ErrServerError
+	}
+	global.Logger.Info("Revoked admin invitation", zap.Uint("adminId", user.ID), zap.String("username", user.Username))
+	return invalidateAdminPermissions(user.ID)
+}
+
+// 查询邀请信息，用于接受邀请页面展示账号
+func (s *AdminInvitationService) GetInvitationInfo(token string) (*entity.InvitationInfoVo, error) {
+	user, invitation, err := getInvitationByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.InvitationInfoVo{
+		Username:  user.Username,
+		Nickname:  user.Nickname,
+		Email:     user.Email,
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+// 接受邀请：设置密码并激活账号，邀请随即删除
+func (s *AdminInvitationService) AcceptInvitation(dto *entity.AcceptInvitationDto) error {
+	if dto.NewPassword != dto.RePassword {
+		return response.ErrPasswordInConsistent
+	}
+	user, invitation, err := getInvitationByToken(dto.Token)
+	if err != nil {
+		return err
+	}
+	if err := checkNewPassword(nil, dto.NewPassword); err != nil {
+		return err
+	}
+	if err := applyNewPassword(user, dto.NewPassword); err != nil {
+		return response.ErrServerError
+	}
+	user.Status = 1
+	if err := AdminInvitationDao.ActivateAdmin(invitation.ID, user); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.ErrInvitationInvalid
+		}
+		return response.ErrServerError
+	}
+	recordPasswordHistory(user)
+	global.Logger.Info("Admin accepted invitation", zap.Uint("adminId", user.ID), zap.String("username", user.Username))
+	return nil
+}
+
+// 获取数据权限范围内待激活用户的邀请
+func getPendingInvitation(scope *entity.DataScope, id uint) (*entity.SysAdmin, *entity.SysAdminInvitation, error) {
+	user, err := getScopedAdmin(scope, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Status != 3 {
+		return nil, nil, response.ErrAdminNotPending
+	}
+	invitation, err := AdminInvitationDao.GetInvitationByAdminId(user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, response.ErrAdminNotPending
+		}
+		return nil, nil, response.ErrServerError
+	}
+	return user, invitation, nil
+}
+
+// 根据邀请链接中的令牌获取待激活的用户和邀请
+func getInvitationByToken(token string) (*entity.SysAdmin, *entity.SysAdminInvitation, error) {
+	invitation, err := AdminInvitationDao.GetInvitationByTokenHash(encrypt.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, response.ErrInvitationInvalid
+		}
+		return nil, nil, response.ErrServerError
+	}
+	if !invitation.ExpiresAt.After(time.Now()) {
+		return nil, nil, response.ErrInvitationExpired
+	}
+	user, err := SysAdminDao.GetAdminById(invitation.AdminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, response.ErrInvitationInvalid
+		}
+		return nil, nil, response.ErrServerError
+	}
+	if user.Status != 3 {
+		return nil, nil, response.ErrInvitationInvalid
+	}
+	return user, invitation, nil
+}
+
+// 生成新的邀请令牌并更新有效期和发送记录，返回令牌明文，需要调用方保存邀请
+func renewInvitation(invitation *entity.SysAdminInvitation) (string, error) {
+	token, err := utils.RandomHex(32)
+	if err != nil {
+		return "", response.ErrServerError
+	}
+	now := time.Now()
+	invitation.TokenHash = encrypt.HashToken(token)
+	invitation.ExpiresAt = utils.HTime{Time: now.Add(invitationTTL())}
+	invitation.SentAt = utils.HTime{Time: now}
+	invitation.SendCount++
+	return token, nil
+}
+
+// 发送邀请邮件，管理员需要知道发送结果，因此同步发送
+func sendInvitationMail(user *entity.SysAdmin, invitation *entity.SysAdminInvitation, token string) error {
+	data := map[string]any{
+		"Username":  user.Username,
+		"Nickname":  user.Nickname,
+		"AcceptURL": tokenLink(global.Config.Invitation.AcceptURL, token),
+		"ExpiresAt": invitation.ExpiresAt.Format("2006-01-02 15:04"),
+	}
+	if err := mailer.SendTemplate([]string{user.Email}, "账号邀请", "invitation", data); err != nil {
+		global.Logger.Error("Failed to send invitation mail", zap.Uint("adminId", user.ID), zap.Error(err))
+		return response.ErrInvitationMailFailed
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/mailer"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testNewPassword = "NewPass456"
+
+type invitationEnv struct {
+	mailer *fakeMailer
+	root   *entity.SysAdmin
+}
+
+// 启用邀请，创建部门、岗位、角色和发起邀请的超级管理员
+func setupInvitation(t *testing.T) *invitationEnv {
+	t.Helper()
+	setupTestEnv(t)
+	seedLdapData(t)
+	global.Config.Invitation = config.Invitation{AcceptURL: "https://admin.example.com/accept", TTL: time.Hour}
+	if err := mailer.Setup(config.Mail{}); err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeMailer{sent: make(chan *mailer.Message, 10)}
+	mailer.SetMailer(fake)
+	t.Cleanup(func() { mailer.SetMailer(nil) })
+
+	root := newTestAdmin(t, "root", "")
+	root.IsSuper = true
+	mustCreate(t, root)
+	return &invitationEnv{mailer: fake, root: root}
+}
+
+// 邀请 carol 并从邮件中取出令牌
+func (e *invitationEnv) invite(t *testing.T) (*entity.SysAdmin, string) {
+	t.Helper()
+	dto := &entity.InviteAdminDto{Username: "carol", Nickname: "Carol", Email: "carol@example.com", DeptID: 2, PostID: 1, RoleIDs: []uint{1, 3}}
+	if err := (&AdminInvitationService{}).InviteAdmin(&entity.DataScope{All: true}, e.root.ID, dto); err != nil {
+		t.Fatalf("InviteAdmin() error = %v", err)
+	}
+	return adminByName(t, "carol"), e.mailedToken(t)
+}
+
+// 取出邀请邮件中的令牌
+func (e *invitationEnv) mailedToken(t *testing.T) string {
+	t.Helper()
+	msg := e.mailer.wait(t)
+	if len(msg.To) != 1 || msg.To[0] != "carol@example.com" {
+		t.Fatalf("mail sent to %v, want carol@example.com", msg.To)
+	}
+	match := resetTokenPattern.FindStringSubmatch(msg.Text)
+	if match == nil {
+		t.Fatalf("no accept link in mail: %s", msg.Text)
+	}
+	return match[1]
+}
+
+func acceptInvitation(token, password string) error {
+	return (&AdminInvitationService{}).AcceptInvitation(&entity.AcceptInvitationDto{Token: token, NewPassword: password, RePassword: password})
+}
+
+func TestInvitationFlow(t *testing.T) {
+	env := setupInvitation(t)
+	carol, token := env.invite(t)
+	if carol.Status != 3 || carol.DeptID != 2 || carol.PostID != 1 {
+		t.Errorf("invited admin status, dept, post = %d, %d, %d", carol.Status, carol.DeptID, carol.PostID)
+	}
+	if roleIds := adminRoleIds(t, carol.ID); !reflect.DeepEqual(roleIds, []uint{1, 3}) {
+		t.Errorf("invited admin roles = %v, want [1 3]", roleIds)
+	}
+	// 待激活用户在用户列表中单独显示，并带有邀请过期时间
+	list, err := (&SysAdminService{}).JointGetAdminList(&entity.DataScope{All: true}, 1, 10, 3, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Username != "carol" || list.Data[0].InvitationExpiresAt == nil {
+		t.Errorf("pending admin list = %+v", list.Data)
+	}
+	// 激活前不能登录
+	if err := passwordLogin(t, "10.0.0.1", "carol", testOldPassword); err == nil {
+		t.Error("pending admin logged in")
+	}
+
+	info, err := (&AdminInvitationService{}).GetInvitationInfo(token)
+	if err != nil || info.Username != "carol" || info.Email != "carol@example.com" {
+		t.Fatalf("GetInvitationInfo() = %+v, %v", info, err)
+	}
+	if err := acceptInvitation(token, testNewPassword); err != nil {
+		t.Fatalf("AcceptInvitation() error = %v", err)
+	}
+	activated := adminByName(t, "carol")
+	if activated.Status != 1 || !encrypt.VerifyPassword(activated.Password, testNewPassword) {
+		t.Errorf("activated admin status = %d", activated.Status)
+	}
+	// 邀请链接只能使用一次
+	if err := acceptInvitation(token, "OtherPass789"); err != response.ErrInvitationInvalid {
+		t.Errorf("AcceptInvitation() again error = %v, want %v", err, response.ErrInvitationInvalid)
+	}
+}
+
+func TestAcceptInvitationInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, carol *entity.SysAdmin, token string) string // 返回提交的令牌
+		dto     func(token string) *entity.AcceptInvitationDto
+		err     error
+	}{
+		{
+			name:    "unknown token",
+			prepare: func(*testing.T, *entity.SysAdmin, string) string { return "unknown" },
+			err:     response.ErrInvitationInvalid,
+		},
+		{
+			name: "expired",
+			prepare: func(t *testing.T, carol *entity.SysAdmin, token string) string {
+				global.DB.Model(&entity.SysAdminInvitation{}).Where("admin_id = ?", carol.ID).Update("expires_at", time.Now().Add(-time.Minute))
+				return token
+			},
+			err: response.ErrInvitationExpired,
+		},
+		{
+			name: "revoked",
+			prepare: func(t *testing.T, carol *entity.SysAdmin, token string) string {
+				if err := (&AdminInvitationService{}).RevokeInvitation(&entity.DataScope{All: true}, carol.ID); err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+			err: response.ErrInvitationInvalid,
+		},
+		{
+			name: "passwords differ",
+			dto: func(token string) *entity.AcceptInvitationDto {
+				return &entity.AcceptInvitationDto{Token: token, NewPassword: testNewPassword, RePassword: "OtherPass789"}
+			},
+			err: response.ErrPasswordInConsistent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupInvitation(t)
+			carol, token := env.invite(t)
+			if tt.prepare != nil {
+				token = tt.prepare(t, carol, token)
+			}
+			dto := &entity.AcceptInvitationDto{Token: token, NewPassword: testNewPassword, RePassword: testNewPassword}
+			if tt.dto != nil {
+				dto = tt.dto(token)
+			}
+			if err := (&AdminInvitationService{}).AcceptInvitation(dto); err != tt.err {
+				t.Errorf("AcceptInvitation() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestResendAndRevokeInvitation(t *testing.T) {
+	env := setupInvitation(t)
+	invitations := &AdminInvitationService{}
+	scope := &entity.DataScope{All: true}
+	carol, oldToken := env.invite(t)
+
+	if err := invitations.ResendInvitation(scope, carol.ID); err != nil {
+		t.Fatalf("ResendInvitation() error = %v", err)
+	}
+	newToken := env.mailedToken(t)
+	// 重新发送后之前的链接失效
+	if _, err := invitations.GetInvitationInfo(oldToken); err != response.ErrInvitationInvalid {
+		t.Errorf("old token error = %v, want %v", err, response.ErrInvitationInvalid)
+	}
+	if _, err := invitations.GetInvitationInfo(newToken); err != nil {
+		t.Errorf("new token error = %v", err)
+	}
+	var invitation entity.SysAdminInvitation
+	if err := global.DB.Where("admin_id = ?", carol.ID).Take(&invitation).Error; err != nil || invitation.SendCount != 2 {
+		t.Errorf("invitation send count = %d, %v, want 2", invitation.SendCount, err)
+	}
+
+	// 已激活的用户没有邀请
+	for name, operation := range map[string]func(uint) error{
+		"resend": func(id uint) error { return invitations.ResendInvitation(scope, id) },
+		"revoke": func(id uint) error { return invitations.RevokeInvitation(scope, id) },
+	} {
+		if err := operation(env.root.ID); err != response.ErrAdminNotPending {
+			t.Errorf("%s active admin error = %v, want %v", name, err, response.ErrAdminNotPending)
+		}
+	}
+
+	// 撤销邀请时删除待激活的用户
+	if err := invitations.RevokeInvitation(scope, carol.ID); err != nil {
+		t.Fatalf("RevokeInvitation() error = %v", err)
+	}
+	if exists, _ := SysAdminDao.ExistsByName("carol"); exists {
+		t.Error("revoked admin was not deleted")
+	}
+	if _, err := invitations.GetInvitationInfo(newToken); err != response.ErrInvitationInvalid {
+		t.Errorf("revoked token error = %v, want %v", err, response.ErrInvitationInvalid)
+	}
+}
+
+func TestInvitationDisabled(t *testing.T) {
+	env := setupInvitation(t)
+	global.Config.Invitation.AcceptURL = ""
+	dto := &entity.InviteAdminDto{Username: "carol", Nickname: "Carol", Email: "carol@example.com", DeptID: 2, PostID: 1, RoleIDs: []uint{1}}
+	if err := (&AdminInvitationService{}).InviteAdmin(&entity.DataScope{All: true}, env.root.ID, dto); err != response.ErrInvitationDisabled {
+		t.Errorf("InviteAdmin() error = %v, want %v", err, response.ErrInvitationDisabled)
+	}
+}
@@ -0,0 +1,193 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 按钮权限：1 admin:list，2 admin:delete；角色：1 超级管理员角色，10 拥有 admin:list，11 拥有全部按钮权限
+func seedPermissions(t *testing.T) {
+	t.Helper()
+	mustCreate(t,
+		&entity.SysMenu{ID: 1, MenuName: "用户查询", MenuType: 3, MenuStatus: 1, Value: "admin:list"},
+		&entity.SysMenu{ID: 2, MenuName: "用户删除", MenuType: 3, MenuStatus: 1, Value: "admin:delete"},
+		&entity.SysRole{ID: 1, RoleName: "超级管理员", RoleKey: global.SuperRoleKey, RoleStatus: 1, DataScope: entity.DataScopeAll},
+		&entity.SysRole{ID: 10, RoleName: "只读", RoleKey: "reader", RoleStatus: 1, DataScope: entity.DataScopeAll},
+		&entity.SysRole{ID: 11, RoleName: "管理", RoleKey: "writer", RoleStatus: 1, DataScope: entity.DataScopeAll},
+		&entity.SysRoleMenu{RoleID: 10, MenuID: 1},
+		&entity.SysRoleMenu{RoleID: 11, MenuID: 1},
+		&entity.SysRoleMenu{RoleID: 11, MenuID: 2},
+	)
+}
+
+// 创建用户并分配角色
+func newRoleAdmin(t *testing.T, username string, serviceAccount bool, roleIds ...uint) *entity.SysAdmin {
+	t.Helper()
+	user := newTestAdmin(t, username, "")
+	user.IsServiceAccount = serviceAccount
+	mustCreate(t, user)
+	for _, roleId := range roleIds {
+		mustCreate(t, &entity.SysAdminRole{AdminID: user.ID, RoleID: roleId})
+	}
+	return user
+}
+
+func TestCreateApiTokenPermission(t *testing.T) {
+	setupTestEnv(t)
+	seedPermissions(t)
+	root := newTestAdmin(t, "root", "")
+	root.IsSuper = true
+	mustCreate(t, root)
+	alice := newRoleAdmin(t, "alice", false, 10)
+	reader := newRoleAdmin(t, "reader-bot", true, 10)
+	writer := newRoleAdmin(t, "writer-bot", true, 11)
+	superRole := newRoleAdmin(t, "super-bot", true, 1)
+	superAccount := newRoleAdmin(t, "root-bot", true)
+	superAccount.IsSuper = true
+	if err := SysAdminDao.UpdateAdmin(superAccount); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		operator *entity.SysAdmin
+		account  *entity.SysAdmin
+		err      error
+	}{
+		{"same permissions", alice, reader, nil},
+		{"more permissions than operator", alice, writer, response.ErrApiTokenDenied},
+		{"super role", alice, superRole, response.ErrApiTokenDenied},
+		{"super account", alice, superAccount, response.ErrApiTokenDenied},
+		{"super operator", root, writer, nil},
+		// 超级管理员也不能为拥有超级管理员权限的服务账号创建令牌
+		{"super operator for super role", root, superRole, response.ErrApiTokenDenied},
+		{"super operator for super account", root, superAccount, response.ErrApiTokenDenied},
+		{"not service account", root, alice, response.ErrNotServiceAccount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dto := &entity.CreateApiTokenDto{AdminID: tt.account.ID, Name: tt.name}
+			created, err := (&ApiTokenService{}).CreateApiToken(&entity.DataScope{All: true}, tt.operator.ID, tt.operator.Username, dto)
+			if err != tt.err {
+				t.Fatalf("CreateApiToken() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && created.Token == "" {
+				t.Error("CreateApiToken() returned no token")
+			}
+		})
+	}
+}
+
+func TestCreateMyApiToken(t *testing.T) {
+	past := utils.HTime{Time: time.Now().Add(-time.Minute)}
+	future := utils.HTime{Time: time.Now().Add(time.Hour)}
+	tests := []struct {
+		name       string
+		dto        entity.CreateApiTokenDto
+		err        error
+		wantScopes string
+	}{
+		{"no scopes", entity.CreateApiTokenDto{Name: "ci"}, nil, ""},
+		{"duplicate scopes", entity.CreateApiTokenDto{Name: "ci", Scopes: []string{"admin:list", "admin:delete", "admin:list"}}, nil, "admin:list,admin:delete"},
+		{"unknown scope", entity.CreateApiTokenDto{Name: "ci", Scopes: []string{"admin:list", "admin:missing"}}, response.ErrInvalidApiTokenScope, ""},
+		{"future expiry", entity.CreateApiTokenDto{Name: "ci", ExpiresAt: &future}, nil, ""},
+		{"past expiry", entity.CreateApiTokenDto{Name: "ci", ExpiresAt: &past}, response.ErrInvalidApiTokenExpiry, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			seedPermissions(t)
+			alice := newRoleAdmin(t, "alice", false, 11)
+
+			created, err := (&ApiTokenService{}).CreateMyApiToken(alice.ID, &tt.dto)
+			if err != tt.err {
+				t.Fatalf("CreateMyApiToken() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			// 只保存令牌的哈希值
+			var saved entity.SysApiToken
+			if err := global.DB.Take(&saved, created.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(created.Token, entity.ApiTokenPrefix) || saved.TokenHash != encrypt.HashToken(created.Token) ||
+				!strings.HasPrefix(created.Token, saved.TokenPrefix) {
+				t.Errorf("saved token hash, prefix = %q, %q for token %q", saved.TokenHash, saved.TokenPrefix, created.Token)
+			}
+			if saved.AdminID != alice.ID || saved.CreatedBy != "alice" || saved.Scopes != tt.wantScopes {
+				t.Errorf("saved token = %+v, want scopes %q", saved, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestApiTokenListAndRevoke(t *testing.T) {
+	setupTestEnv(t)
+	seedPermissions(t)
+	alice := newRoleAdmin(t, "alice", false, 11)
+	bob := newRoleAdmin(t, "bob", false, 11)
+	tokens := &ApiTokenService{}
+	expired := utils.HTime{Time: time.Now().Add(-time.Minute)}
+	mustCreate(t,
+		&entity.SysApiToken{ID: 1, AdminID: alice.ID, Name: "alice-ci", TokenHash: "1", Scopes: "admin:list"},
+		&entity.SysApiToken{ID: 2, AdminID: alice.ID, Name: "alice-old", TokenHash: "2", ExpiresAt: &expired},
+		&entity.SysApiToken{ID: 3, AdminID: bob.ID, Name: "bob-ci", TokenHash: "3"},
+	)
+
+	list, err := tokens.GetMyApiTokenList(alice.ID, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{} // 令牌名称 -> 是否已过期
+	for _, token := range list.Data {
+		got[token.Name] = token.Expired
+		if token.Name == "alice-ci" && !reflect.DeepEqual(token.ScopeList, []string{"admin:list"}) {
+			t.Errorf("alice-ci scopes = %v", token.ScopeList)
+		}
+	}
+	if want := map[string]bool{"alice-ci": false, "alice-old": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("alice tokens = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name   string
+		revoke func() error
+		err    error
+	}{
+		{"revoke token of other admin", func() error { return tokens.RevokeMyApiToken(alice.ID, 3) }, response.ErrApiTokenNotExists},
+		{"revoke missing token", func() error { return tokens.RevokeMyApiToken(alice.ID, 9) }, response.ErrApiTokenNotExists},
+		{"revoke own token", func() error { return tokens.RevokeMyApiToken(alice.ID, 1) }, nil},
+		{"revoke out of data scope", func() error { return tokens.RevokeApiToken(selfScope(alice.ID), 3) }, response.ErrApiTokenNotExists},
+		{"revoke in data scope", func() error { return tokens.RevokeApiToken(&entity.DataScope{All: true}, 3) }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.revoke(); err != tt.err {
+				t.Errorf("error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+	var remaining []uint
+	global.DB.Model(&entity.SysApiToken{}).Order("id").Pluck("id", &remaining)
+	if !reflect.DeepEqual(remaining, []uint{2}) {
+		t.Errorf("remaining tokens = %v, want [2]", remaining)
+	}
+}
+
+func TestServiceAccountLogin(t *testing.T) {
+	setupTestEnv(t)
+	newRoleAdmin(t, "robot", true)
+	if err := passwordLogin(t, "10.0.0.1", "robot", testOldPassword); err != response.ErrServiceAccountLogin {
+		t.Errorf("Login() error = %v, want %v", err, response.ErrServiceAccountLogin)
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+
+	"gorm.io/gorm"
+)
+
+type DataScopeService struct{}
+
+// 获取用户的数据权限：合并用户生效角色(包括继承的父角色)的数据权限，任一角色为全部数据或超级管理员时可访问全部数据
+func (s *DataScopeService) GetDataScope(adminId uint) (*entity.DataScope, error) {
+	admin, err := SysAdminDao.GetAdminById(adminId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrAdminNotExists
+		}
+		return nil, response.ErrServerError
+	}
+	if admin.IsSuper {
+		return &entity.DataScope{All: true}, nil
+	}
+	roles, err := SysRoleDao.GetEffectiveRoles(adminId)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+
+	scope := &entity.DataScope{}
+	var customRoleIds, rootDeptIds []uint
+	deptIds := make(map[uint]bool)
+	for _, role := range roles {
+		switch {
+		case role.RoleKey == global.SuperRoleKey || role.DataScope == entity.DataScopeAll:
+			return &entity.DataScope{All: true}, nil
+		case role.DataScope == entity.DataScopeCustom:
+			customRoleIds = append(customRoleIds, role.ID)
+		case role.DataScope == entity.DataScopeDept:
+			deptIds[admin.DeptID] = true
+		case role.DataScope == entity.DataScopeDeptAndChild:
+			rootDeptIds = []uint{admin.DeptID}
+		case role.DataScope == entity.DataScopeSelf:
+			scope.AdminID = admin.ID
+		}
+	}
+
+	if len(customRoleIds) > 0 {
+		ids, err := DataScopeDao.GetRoleDeptIds(customRoleIds)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		for _, id := range ids {
+			deptIds[id] = true
+		}
+	}
+	if len(rootDeptIds) > 0 {
+		ids, err := DataScopeDao.GetDeptSubtreeIds(rootDeptIds)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		for _, id := range ids {
+			deptIds[id] = true
+		}
+	}
+	for id := range deptIds {
+		scope.DeptIDs = append(scope.DeptIDs, id)
+	}
+	return scope, nil
+}
+
+// 根据id获取数据权限范围内的用户，范围外的用户视为不存在
+func getScopedAdmin(scope *entity.DataScope, adminId uint) (*entity.SysAdmin, error) {
+	user, err := SysAdminDao.GetScopedAdminById(scope, adminId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrAdminNotExists
+		}
+		return nil, response.ErrServerError
+	}
+	return user, nil
+}
@@ -0,0 +1,69 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestGetDataScope(t *testing.T) {
+	parent := func(id uint) *uint { return &id }
+	role := func(id uint, key string, status, scope uint, parentID *uint) *entity.SysRole {
+		return &entity.SysRole{ID: id, RoleName: key, RoleKey: key, RoleStatus: status, DataScope: scope, ParentID: parentID}
+	}
+	tests := []struct {
+		name    string
+		roleIds []uint
+		isSuper bool
+		want    entity.DataScope // AdminID 为1表示可访问本人的数据
+	}{
+		{"super admin", nil, true, entity.DataScope{All: true}},
+		{"no roles", nil, false, entity.DataScope{}},
+		{"custom depts", []uint{2}, false, entity.DataScope{DeptIDs: []uint{3}}},
+		{"dept and children", []uint{7}, false, entity.DataScope{DeptIDs: []uint{1, 2}}},
+		{"inherit all data", []uint{4}, false, entity.DataScope{All: true}},
+		{"inherit custom depts", []uint{5}, false, entity.DataScope{DeptIDs: []uint{3}, AdminID: 1}},
+		{"disabled parent not inherited", []uint{6}, false, entity.DataScope{AdminID: 1}},
+		{"inherit super role", []uint{9}, false, entity.DataScope{All: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			mustCreate(t,
+				&entity.SysDept{ID: 1, DeptName: "总部", DeptType: 1, DeptStatus: 1},
+				&entity.SysDept{ID: 2, DeptName: "研发部", DeptType: 3, DeptStatus: 1, ParentID: parent(1)},
+				&entity.SysDept{ID: 3, DeptName: "分部", DeptType: 1, DeptStatus: 1},
+				role(1, "all", 1, entity.DataScopeAll, nil),
+				role(2, "custom", 1, entity.DataScopeCustom, nil),
+				role(3, "disabled", 2, entity.DataScopeAll, nil),
+				role(4, "all-child", 1, entity.DataScopeSelf, parent(1)),
+				role(5, "custom-child", 1, entity.DataScopeSelf, parent(2)),
+				role(6, "disabled-child", 1, entity.DataScopeSelf, parent(3)),
+				role(7, "dept", 1, entity.DataScopeDeptAndChild, nil),
+				role(8, global.SuperRoleKey, 1, entity.DataScopeSelf, nil),
+				role(9, "super-child", 1, entity.DataScopeSelf, parent(8)),
+				&entity.SysRoleDept{RoleID: 2, DeptID: 3},
+			)
+			user := newTestAdmin(t, "alice", "")
+			user.DeptID = 1
+			user.IsSuper = tt.isSuper
+			mustCreate(t, user)
+			for _, roleId := range tt.roleIds {
+				mustCreate(t, &entity.SysAdminRole{AdminID: user.ID, RoleID: roleId})
+			}
+
+			scope, err := (&DataScopeService{}).GetDataScope(user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(scope.DeptIDs)
+			if !reflect.DeepEqual(*scope, tt.want) {
+				t.Errorf("GetDataScope() = %+v, want %+v", *scope, tt.want)
+			}
+		})
+	}
+}
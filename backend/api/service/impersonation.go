@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/pkg/jwt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 模拟登录：为被模拟的用户创建单独的登录会话，签发同时携带双方身份的令牌，用于以该用户的视角排查问题
+func (s *SysAdminService) Impersonate(scope *entity.DataScope, claims *jwt.CustomClaims, ip, browser, Os, device string, targetId uint) (*entity.SysAdmin, *jwt.TokenPair, error) {
+	// 不能在模拟登录期间再次发起模拟
+	if claims.Impersonator != nil {
+		return nil, nil, response.ErrImpersonating
+	}
+	target, err := getScopedAdmin(scope, targetId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target.ID == claims.JwtAdmin.ID {
+		return nil, nil, response.ErrImpersonateDenied
+	}
+	if err := checkImpersonatable(claims.JwtAdmin.ID, target.ID); err != nil {
+		return nil, nil, err
+	}
+	if target.Status == 2 {
+		return nil, nil, response.ErrAdminDisabled
+	}
+	if target.Status == 3 {
+		return nil, nil, response.ErrAdminPending
+	}
+
+	version, err := TokenDao.GetTokenVersion(target.ID)
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	sessionID, err := utils.RandomHex(16)
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	now := utils.HTime{Time: time.Now()}
+	session := &entity.SysSession{
+		SessionID:        sessionID,
+		AdminID:          target.ID,
+		Username:         target.Username,
+		Device:           device,
+		Browser:          browser,
+		Os:               Os,
+		Ip:               ip,
+		LoginAt:          now,
+		LastActiveAt:     now,
+		ImpersonatorID:   claims.JwtAdmin.ID,
+		ImpersonatorName: claims.JwtAdmin.Username,
+	}
+	if err := SessionDao.SaveImpersonationSession(session, min(jwt.ImpersonationTTL(), jwt.RefreshTTL()), jwt.RefreshTTL()); err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	go fillSessionLocation(sessionID, ip)
+
+	tokenPair, err := jwt.GenerateImpersonationTokenPair(target, sessionID, version, &jwt.Impersonator{
+		ID:           claims.JwtAdmin.ID,
+		Username:     claims.JwtAdmin.Username,
+		SessionID:    claims.SessionID,
+		TokenVersion: claims.TokenVersion,
+	})
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	return target, tokenPair, nil
+}
+
+// 检查模拟登录是否会越权：被模拟的用户拥有超级管理员权限(超级管理员账号或超级管理员角色)，
+// 或者拥有发起人没有的按钮权限时都不能模拟
+func checkImpersonatable(operatorId, targetId uint) error {
+	covered, err := permissionCovered(operatorId, targetId)
+	if err != nil {
+		return err
+	}
+	if !covered {
+		return response.ErrImpersonateDenied
+	}
+	return nil
+}
+
+// 操作人的权限是否覆盖目标用户的权限：目标用户没有超级管理员权限，且拥有的按钮权限操作人都有
+func permissionCovered(operatorId, targetId uint) (bool, error) {
+	targetPermission, err := SysMenuDao.GetAdminPermission(targetId)
+	if err != nil {
+		return false, response.ErrServerError
+	}
+	if targetPermission.Super {
+		return false, nil
+	}
+	operatorPermission, err := SysMenuDao.GetAdminPermission(operatorId)
+	if err != nil {
+		return false, response.ErrServerError
+	}
+	for _, value := range targetPermission.Permissions {
+		if !operatorPermission.Has(value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// 结束模拟登录：删除模拟登录会话，回到发起人原来的登录会话并重新签发令牌
+func (s *SysAdminService) EndImpersonation(claims *jwt.CustomClaims) (*entity.SysAdmin, *jwt.TokenPair, error) {
+	impersonator := claims.Impersonator
+	if impersonator == nil {
+		return nil, nil, response.ErrNotImpersonating
+	}
+	if err := SessionDao.DeleteSession(claims.JwtAdmin.ID, claims.SessionID); err != nil {
+		return nil, nil, response.ErrServerError
+	}
+
+	user, err := SysAdminDao.GetAdminById(impersonator.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, response.ErrAdminNotExists
+		}
+		return nil, nil, response.ErrServerError
+	}
+	if user.Status != 1 {
+		return nil, nil, response.ErrAdminDisabled
+	}
+	// 发起人原来的会话已失效时需要重新登录
+	version, err := TokenDao.GetTokenVersion(user.ID)
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	if version != impersonator.TokenVersion {
+		return nil, nil, response.ErrTokenRevoked
+	}
+	session, err := SessionDao.GetSession(impersonator.SessionID)
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	if session == nil || session.AdminID != user.ID {
+		return nil, nil, response.ErrTokenRevoked
+	}
+	session.LastActiveAt = utils.HTime{Time: time.Now()}
+	if err := SessionDao.SaveSession(session, jwt.RefreshTTL()); err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	tokenPair, err := jwt.GenerateTokenPair(user, session.SessionID, tokenScope(user, session.LoginMethod), version)
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	return user, tokenPair, nil
+}
+
+// 检查模拟登录会话是否仍然有效，返回刷新后会话的剩余有效期：
+// 发起人的令牌被整体吊销、或已超过模拟登录的最长有效期时失效
+func impersonationSessionTTL(impersonator *jwt.Impersonator, session *entity.SysSession) (time.Duration, error) {
+	version, err := TokenDao.GetTokenVersion(impersonator.ID)
+	if err != nil {
+		return 0, response.ErrServerError
+	}
+	if version != impersonator.TokenVersion {
+		return 0, response.ErrTokenRevoked
+	}
+	ttl := min(time.Until(session.LoginAt.Add(jwt.ImpersonationTTL())), jwt.RefreshTTL())
+	if ttl <= 0 {
+		return 0, response.ErrTokenRevoked
+	}
+	return ttl, nil
+}
@@ -0,0 +1,183 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/pkg/jwt"
+	"testing"
+)
+
+// 解析访问令牌，失败时结束测试
+func parseTestToken(t *testing.T, token string) *jwt.CustomClaims {
+	t.Helper()
+	claims, err := jwt.ParseToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+// 用户：root 超级管理员账号，super 拥有超级管理员角色，writer、writer2 拥有查询和删除权限，reader 只有查询权限
+func seedImpersonation(t *testing.T) {
+	t.Helper()
+	seedPermissions(t)
+	root := newTestAdmin(t, "root", "")
+	root.IsSuper = true
+	mustCreate(t, root)
+	newRoleAdmin(t, "super", false, 1)
+	newRoleAdmin(t, "writer", false, 11)
+	newRoleAdmin(t, "writer2", false, 11)
+	newRoleAdmin(t, "reader", false, 10)
+	disabled := newRoleAdmin(t, "disabled", false, 10)
+	disabled.Status = 2
+	if err := SysAdminDao.UpdateAdmin(disabled); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImpersonate(t *testing.T) {
+	tests := []struct {
+		name          string
+		operator      string
+		target        string
+		impersonating bool // 发起人是否正在模拟登录
+		scope         entity.DataScope
+		err           error
+	}{
+		{"fewer permissions", "writer", "reader", false, entity.DataScope{All: true}, nil},
+		{"same permissions", "writer", "writer2", false, entity.DataScope{All: true}, nil},
+		{"super admin", "root", "writer", false, entity.DataScope{All: true}, nil},
+		{"more permissions", "reader", "writer", false, entity.DataScope{All: true}, response.ErrImpersonateDenied},
+		{"super role target", "writer", "super", false, entity.DataScope{All: true}, response.ErrImpersonateDenied},
+		{"super account target", "writer", "root", false, entity.DataScope{All: true}, response.ErrImpersonateDenied},
+		{"self", "writer", "writer", false, entity.DataScope{All: true}, response.ErrImpersonateDenied},
+		{"disabled target", "writer", "disabled", false, entity.DataScope{All: true}, response.ErrAdminDisabled},
+		{"out of data scope", "writer", "reader", false, entity.DataScope{}, response.ErrAdminNotExists},
+		{"already impersonating", "writer", "reader", true, entity.DataScope{All: true}, response.ErrImpersonating},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			seedImpersonation(t)
+			operator, target := adminByName(t, tt.operator), adminByName(t, tt.target)
+			claims := parseTestToken(t, loginTestAdmin(t, operator, "operator-1").AccessToken)
+			if tt.impersonating {
+				claims.Impersonator = &jwt.Impersonator{ID: 99, Username: "other"}
+			}
+
+			user, pair, err := (&SysAdminService{}).Impersonate(&tt.scope, claims, "10.0.0.1", "Chrome", "Linux", "", target.ID)
+			if err != tt.err {
+				t.Fatalf("Impersonate() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			// 令牌同时携带被模拟的用户和发起人
+			got := parseTestToken(t, pair.AccessToken)
+			waitSessionLocation(t, got.SessionID)
+			if user.ID != target.ID || got.JwtAdmin.ID != target.ID || got.Impersonator == nil ||
+				got.Impersonator.ID != operator.ID || got.Impersonator.SessionID != "operator-1" {
+				t.Errorf("impersonation claims = %+v, impersonator %+v", got.JwtAdmin, got.Impersonator)
+			}
+			session, err := SessionDao.GetSession(got.SessionID)
+			if err != nil || session == nil || session.AdminID != target.ID || session.ImpersonatorID != operator.ID {
+				t.Errorf("impersonation session = %+v, %v", session, err)
+			}
+		})
+	}
+}
+
+func TestEndImpersonation(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, operator *entity.SysAdmin)
+		err     error
+	}{
+		{"back to original session", nil, nil},
+		{"operator tokens revoked", func(t *testing.T, operator *entity.SysAdmin) {
+			if err := TokenDao.RevokeAdminTokens(operator.ID); err != nil {
+				t.Fatal(err)
+			}
+		}, response.ErrTokenRevoked},
+		{"original session removed", func(t *testing.T, operator *entity.SysAdmin) {
+			if err := SessionDao.DeleteSession(operator.ID, "operator-1"); err != nil {
+				t.Fatal(err)
+			}
+		}, response.ErrTokenRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			seedImpersonation(t)
+			operator, target := adminByName(t, "writer"), adminByName(t, "reader")
+			claims := parseTestToken(t, loginTestAdmin(t, operator, "operator-1").AccessToken)
+			admins := &SysAdminService{}
+			_, pair, err := admins.Impersonate(&entity.DataScope{All: true}, claims, "10.0.0.1", "Chrome", "Linux", "", target.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			impersonation := parseTestToken(t, pair.AccessToken)
+			waitSessionLocation(t, impersonation.SessionID)
+			if tt.prepare != nil {
+				tt.prepare(t, operator)
+			}
+
+			user, restored, err := admins.EndImpersonation(impersonation)
+			if err != tt.err {
+				t.Fatalf("EndImpersonation() error = %v, want %v", err, tt.err)
+			}
+			// 模拟登录会话总是被删除
+			if session, _ := SessionDao.GetSession(impersonation.SessionID); session != nil {
+				t.Error("impersonation session was not deleted")
+			}
+			if err != nil {
+				return
+			}
+			got := parseTestToken(t, restored.AccessToken)
+			if user.ID != operator.ID || got.JwtAdmin.ID != operator.ID || got.SessionID != "operator-1" || got.Impersonator != nil {
+				t.Errorf("restored claims = %+v, session %s, impersonator %+v", got.JwtAdmin, got.SessionID, got.Impersonator)
+			}
+		})
+	}
+
+	t.Run("not impersonating", func(t *testing.T) {
+		setupTestEnv(t)
+		alice := newTestAdmin(t, "alice", "")
+		mustCreate(t, alice)
+		claims := parseTestToken(t, loginTestAdmin(t, alice, "alice-1").AccessToken)
+		if _, _, err := (&SysAdminService{}).EndImpersonation(claims); err != response.ErrNotImpersonating {
+			t.Errorf("EndImpersonation() error = %v, want %v", err, response.ErrNotImpersonating)
+		}
+	})
+}
+
+func TestRefreshImpersonationToken(t *testing.T) {
+	setupTestEnv(t)
+	seedImpersonation(t)
+	operator, target := adminByName(t, "writer"), adminByName(t, "reader")
+	claims := parseTestToken(t, loginTestAdmin(t, operator, "operator-1").AccessToken)
+	admins := &SysAdminService{}
+	_, pair, err := admins.Impersonate(&entity.DataScope{All: true}, claims, "10.0.0.1", "Chrome", "Linux", "", target.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitSessionLocation(t, parseTestToken(t, pair.AccessToken).SessionID)
+
+	// 刷新后仍然是模拟登录令牌
+	refreshed, err := admins.RefreshToken(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if got := parseTestToken(t, refreshed.AccessToken); got.Impersonator == nil || got.Impersonator.ID != operator.ID {
+		t.Errorf("refreshed impersonator = %+v", got.Impersonator)
+	}
+	// 发起人的令牌被整体吊销后模拟令牌不能再刷新
+	if err := TokenDao.RevokeAdminTokens(operator.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := admins.RefreshToken(refreshed.RefreshToken); err != response.ErrTokenRevoked {
+		t.Errorf("RefreshToken() error = %v, want %v", err, response.ErrTokenRevoked)
+	}
+}
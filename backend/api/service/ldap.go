@@ -0,0 +1,454 @@
+// LDAP 目录账号：登录时先由目录认证并同步账号信息，定时按目录创建、更新和停用用户，
+// 并按 OU 和用户组映射部门和角色
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/ldap"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 同步执行锁的过期时间，防止实例异常退出后锁一直存在
+const ldapSyncLockTTL = 30 * time.Minute
+
+type LdapService struct{}
+
+// 检查登录密码：启用LDAP时先由目录认证，认证通过后按目录信息更新或创建本地账号；
+// 目录中没有该用户时再校验本地账号的密码，目录账号只能使用目录密码。
+// 超级管理员和服务账号始终使用本地密码。密码错误时返回的用户为nil
+func verifyLoginPassword(user *entity.SysAdmin, username, password string) (*entity.SysAdmin, string, error) {
+	if ldap.Enabled() && (user == nil || (!user.IsSuper && !user.IsServiceAccount)) {
+		entry, err := ldap.Authenticate(username, password)
+		switch {
+		case err == nil:
+			user, err := applyLdapLogin(user, entry)
+			return user, entity.LoginMethodLdap, err
+		case errors.Is(err, ldap.ErrUserNotFound), errors.Is(err, ldap.ErrInvalidCredentials):
+			if user == nil || user.LdapDN != "" {
+				return nil, "", nil
+			}
+		default:
+			global.Logger.Error("LDAP authentication failed", zap.String("username", username), zap.Error(err))
+			// 目录不可用时，目录账号只有开启了 fallback_local 才能使用本地密码登录
+			if user == nil || (user.LdapDN != "" && !ldap.Config().FallbackLocal) {
+				return nil, "", response.ErrLdapUnavailable
+			}
+		}
+	}
+	if user == nil || !encrypt.VerifyPassword(user.Password, password) {
+		return nil, "", nil
+	}
+	return user, entity.LoginMethodPassword, nil
+}
+
+// 目录认证通过后同步本地账号，没有本地账号时按配置创建
+func applyLdapLogin(user *entity.SysAdmin, entry *ldap.Entry) (*entity.SysAdmin, error) {
+	cfg := ldap.Config()
+	if entry.Disabled {
+		return nil, response.ErrAdminDisabled
+	}
+	if user == nil && !cfg.CreateOnLogin {
+		return nil, response.ErrSsoAccountNotFound
+	}
+	plan, err := planLdapAdmin(cfg, user, entry)
+	if err == nil {
+		err = applyLdapPlan(plan)
+	}
+	if err != nil {
+		if user == nil {
+			return nil, err
+		}
+		// 已有账号同步失败不影响登录，下次登录或定时同步时重试
+		global.Logger.Warn("Failed to sync admin from ldap on login", zap.Uint("adminId", user.ID), zap.Error(err))
+		return user, nil
+	}
+	return plan.user, nil
+}
+
+// 本地账号按目录信息同步的计划
+type ldapPlan struct {
+	user         *entity.SysAdmin // 同步后的用户，创建时id为0
+	create       bool             // 是否创建用户
+	roleIds      []uint           // 同步后的角色
+	rolesChanged bool             // 角色是否变化
+	changes      []string         // 变更内容，用于同步报告
+}
+
+// 比较目录用户与本地账号，生成同步计划，不修改数据。目录中为空的属性不会清空本地的值
+func planLdapAdmin(cfg config.Ldap, user *entity.SysAdmin, entry *ldap.Entry) (*ldapPlan, error) {
+	wantedIds, err := roleIdsByKeys(ldapRoleKeys(cfg, entry.Groups))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return planLdapCreate(cfg, entry, wantedIds)
+	}
+
+	plan := &ldapPlan{}
+	updated := *user
+	if updated.LdapDN != entry.DN {
+		plan.changes = append(plan.changes, fmt.Sprintf("DN: %q -> %q", updated.LdapDN, entry.DN))
+		updated.LdapDN = entry.DN
+	}
+	// 昵称已被其他用户占用时保留原昵称
+	if entry.Nickname != "" && entry.Nickname != updated.Nickname {
+		exists, err := SysAdminDao.ExistsNickname(entry.Nickname)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		if !exists {
+			plan.changes = append(plan.changes, fmt.Sprintf("昵称: %q -> %q", updated.Nickname, entry.Nickname))
+			updated.Nickname = entry.Nickname
+		}
+	}
+	if entry.Email != "" && entry.Email != updated.Email {
+		plan.changes = append(plan.changes, fmt.Sprintf("邮箱: %q -> %q", updated.Email, entry.Email))
+		updated.Email = entry.Email
+	}
+	if phone := ldapPhone(entry); phone != "" && phone != updated.Phone {
+		plan.changes = append(plan.changes, fmt.Sprintf("手机号: %q -> %q", updated.Phone, phone))
+		updated.Phone = phone
+	}
+	if deptId := ldapDeptID(cfg, entry); deptId != 0 && deptId != updated.DeptID {
+		if err := checkLdapDept(deptId); err != nil {
+			return nil, err
+		}
+		plan.changes = append(plan.changes, fmt.Sprintf("部门: %d -> %d", updated.DeptID, deptId))
+		updated.DeptID = deptId
+	}
+
+	// 只增减 role_mappings 中出现的角色，管理员手动分配的其他角色保持不变
+	if len(cfg.RoleMappings) > 0 {
+		managedKeys := make([]string, 0, len(cfg.RoleMappings))
+		for _, mapping := range cfg.RoleMappings {
+			managedKeys = append(managedKeys, mapping.RoleKey)
+		}
+		managedIds, err := roleIdsByKeys(managedKeys)
+		if err != nil {
+			return nil, err
+		}
+		oldRoleIds, err := SysAdminDao.GetAdminRoleIds(user.ID)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		oldRoleIds = uniqueIds(oldRoleIds)
+		newRoleIds := mergeManagedRoles(oldRoleIds, managedIds, wantedIds)
+		if !sameIds(oldRoleIds, newRoleIds) {
+			plan.changes = append(plan.changes, fmt.Sprintf("角色: %v -> %v", oldRoleIds, newRoleIds))
+			plan.roleIds = newRoleIds
+			plan.rolesChanged = true
+		}
+	}
+	plan.user = &updated
+	return plan, nil
+}
+
+// 创建用户的计划：没有映射到部门时使用默认部门，使用随机密码，只能通过目录密码登录
+func planLdapCreate(cfg config.Ldap, entry *ldap.Entry, roleIds []uint) (*ldapPlan, error) {
+	deptId := ldapDeptID(cfg, entry)
+	if deptId == 0 {
+		deptId = cfg.DefaultDeptID
+	}
+	if err := checkProvisionDeptPost(deptId, cfg.DefaultPostID); err != nil {
+		return nil, err
+	}
+	nickname, err := uniqueNickname(entry.Username, entry.Nickname)
+	if err != nil {
+		return nil, err
+	}
+	password, err := utils.RandomHex(32)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	user := &entity.SysAdmin{
+		Username:  entry.Username,
+		Nickname:  nickname,
+		Email:     entry.Email,
+		Phone:     ldapPhone(entry),
+		Status:    1,
+		DeptID:    deptId,
+		PostID:    cfg.DefaultPostID,
+		CreatedAt: utils.HTime{Time: time.Now()},
+
+		LdapDN: entry.DN,
+	}
+	if err := applyNewPassword(user, password); err != nil {
+		return nil, response.ErrServerError
+	}
+	return &ldapPlan{
+		user:    user,
+		create:  true,
+		roleIds: roleIds,
+		changes: []string{
+			fmt.Sprintf("昵称: %q", nickname),
+			fmt.Sprintf("部门: %d", deptId),
+			fmt.Sprintf("角色: %v", roleIds),
+		},
+	}, nil
+}
+
+// 执行同步计划
+func applyLdapPlan(plan *ldapPlan) error {
+	if plan.create {
+		if err := SysAdminDao.CreateAdmin(plan.roleIds, plan.user); err != nil {
+			return response.ErrServerError
+		}
+		global.Logger.Info("Provisioned admin from ldap",
+			zap.Uint("adminId", plan.user.ID), zap.String("username", plan.user.Username), zap.String("dn", plan.user.LdapDN))
+		return nil
+	}
+	if len(plan.changes) == 0 {
+		return nil
+	}
+	if err := SysAdminDao.UpdateAdmin(plan.user); err != nil {
+		return response.ErrServerError
+	}
+	if plan.rolesChanged {
+		if err := SysAdminDao.UpdateAdminRoles(plan.user.ID, plan.roleIds); err != nil {
+			return response.ErrServerError
+		}
+	}
+	global.Logger.Info("Synced admin from ldap", zap.Uint("adminId", plan.user.ID), zap.Strings("changes", plan.changes))
+	// 部门和角色变化都会影响权限和数据范围
+	return invalidateAdminPermissions(plan.user.ID)
+}
+
+// 按用户所在 OU 或所属组映射部门，使用第一个匹配的映射，没有匹配时返回0
+func ldapDeptID(cfg config.Ldap, entry *ldap.Entry) uint {
+	for _, mapping := range cfg.DeptMappings {
+		if ldap.InDN(entry.DN, mapping.DN) {
+			return mapping.DeptID
+		}
+		for _, group := range entry.Groups {
+			if ldap.InDN(group, mapping.DN) {
+				return mapping.DeptID
+			}
+		}
+	}
+	return 0
+}
+
+// 用户所属组映射的角色关键字
+func ldapRoleKeys(cfg config.Ldap, groups []string) []string {
+	var roleKeys []string
+	for _, mapping := range cfg.RoleMappings {
+		for _, group := range groups {
+			if ldap.InDN(group, mapping.Group) {
+				roleKeys = append(roleKeys, mapping.RoleKey)
+				break
+			}
+		}
+	}
+	return roleKeys
+}
+
+// 目录中的手机号，去掉空格和短横线后超过本地字段长度时忽略
+func ldapPhone(entry *ldap.Entry) string {
+	phone := strings.NewReplacer(" ", "", "-", "").Replace(entry.Phone)
+	if len(phone) > 11 {
+		return ""
+	}
+	return phone
+}
+
+// 检查映射的部门存在且未停用
+func checkLdapDept(deptId uint) error {
+	dept, err := SysDeptDao.GetDeptById(deptId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.ErrDeptNotExists
+		}
+		return response.ErrServerError
+	}
+	if dept.DeptStatus == 2 {
+		return response.ErrDeptDisabled
+	}
+	return nil
+}
+
+// 手动同步目录，dryRun 为 true 时只生成报告
+func (s *LdapService) SyncDirectory(dryRun bool) (*entity.LdapSyncReport, error) {
+	return SyncLdapDirectory(dryRun)
+}
+
+// SweepLdapDirectory 定时同步目录，结果写入审计日志
+func SweepLdapDirectory(ctx context.Context) {
+	if !ldap.Enabled() {
+		return
+	}
+	report, err := SyncLdapDirectory(false)
+	if err != nil {
+		global.Logger.Error("Scheduled ldap sync failed", zap.Error(err))
+		return
+	}
+	body, _ := json.Marshal(report)
+	SysLogDao.CreateOperationLog(&entity.SysOperationLog{
+		Username:  "system",
+		Method:    "SYSTEM",
+		CreatedAt: utils.HTime{Time: report.FinishedAt},
+		Module:    "目录同步",
+		Action:    "定时同步目录",
+		Body:      string(body),
+		Code:      response.CodeSuccess,
+	})
+}
+
+// SyncLdapDirectory 按目录同步本地账号：创建目录中新增的用户，更新已关联用户的属性、部门和角色，
+// 停用目录中已删除或已停用的用户。停用的用户不会自动启用，超级管理员和服务账号不参与同步。
+// dryRun 为 true 时只生成报告，不修改数据
+func SyncLdapDirectory(dryRun bool) (*entity.LdapSyncReport, error) {
+	cfg := ldap.Config()
+	if !cfg.Enabled {
+		return nil, response.ErrLdapDisabled
+	}
+	if !dryRun {
+		locked, err := global.RDB.SetNX(context.Background(), global.LdapSyncLock, 1, ldapSyncLockTTL).Result()
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		if !locked {
+			return nil, response.ErrLdapSyncRunning
+		}
+		defer global.RDB.Del(context.Background(), global.LdapSyncLock)
+	}
+
+	report := &entity.LdapSyncReport{
+		DryRun:    dryRun,
+		StartedAt: time.Now(),
+		Created:   []entity.LdapSyncItem{},
+		Updated:   []entity.LdapSyncItem{},
+		Disabled:  []entity.LdapSyncItem{},
+		Skipped:   []entity.LdapSyncItem{},
+		Failed:    []entity.LdapSyncItem{},
+		Warnings:  []string{},
+	}
+	entries, err := ldap.SearchUsers()
+	if err != nil {
+		global.Logger.Error("Failed to search ldap users", zap.Error(err))
+		return nil, response.ErrLdapUnavailable
+	}
+	report.Total = len(entries)
+
+	seen := make(map[string]bool, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		seen[strings.ToLower(entry.Username)] = true
+		syncLdapEntry(cfg, entry, dryRun, report)
+	}
+
+	if cfg.Sync.DisableMissing {
+		// 目录返回空结果多半是过滤器或权限配置错误，不能据此停用全部目录账号
+		if len(entries) == 0 {
+			report.Warnings = append(report.Warnings, "目录未返回任何用户，已跳过停用")
+		} else {
+			disableMissingLdapAdmins(seen, dryRun, report)
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	global.Logger.Info("LDAP directory synced",
+		zap.Bool("dryRun", dryRun), zap.Int("total", report.Total),
+		zap.Int("created", len(report.Created)), zap.Int("updated", len(report.Updated)),
+		zap.Int("disabled", len(report.Disabled)), zap.Int("failed", len(report.Failed)))
+	return report, nil
+}
+
+// 同步目录中的一个用户
+func syncLdapEntry(cfg config.Ldap, entry *ldap.Entry, dryRun bool, report *entity.LdapSyncReport) {
+	item := entity.LdapSyncItem{Username: entry.Username, DN: entry.DN}
+	user, err := SysAdminDao.GetAdminByName(entry.Username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			item.Reason = response.ErrServerError.Error()
+			report.Failed = append(report.Failed, item)
+			return
+		}
+		user = nil
+	}
+	if user != nil && (user.IsSuper || user.IsServiceAccount) {
+		item.Reason = "超级管理员和服务账号不参与同步"
+		report.Skipped = append(report.Skipped, item)
+		return
+	}
+
+	if entry.Disabled {
+		// 目录中已停用的用户不创建，已关联的本地账号同步停用
+		if user != nil && user.LdapDN != "" && user.Status == 1 && cfg.Sync.DisableMissing {
+			item.Reason = "目录中已停用"
+			disableLdapAdmin(user, item, dryRun, report)
+		}
+		return
+	}
+	if user == nil && !cfg.Sync.CreateUsers {
+		return
+	}
+
+	plan, err := planLdapAdmin(cfg, user, entry)
+	if err != nil {
+		item.Reason = err.Error()
+		report.Failed = append(report.Failed, item)
+		return
+	}
+	if len(plan.changes) == 0 {
+		return
+	}
+	item.Changes = plan.changes
+	if !dryRun {
+		if err := applyLdapPlan(plan); err != nil {
+			item.Reason = err.Error()
+			report.Failed = append(report.Failed, item)
+			return
+		}
+	}
+	if plan.create {
+		report.Created = append(report.Created, item)
+	} else {
+		report.Updated = append(report.Updated, item)
+	}
+}
+
+// 停用目录中已不存在的目录账号
+func disableMissingLdapAdmins(seen map[string]bool, dryRun bool, report *entity.LdapSyncReport) {
+	admins, err := SysAdminDao.GetActiveLdapAdmins()
+	if err != nil {
+		report.Warnings = append(report.Warnings, "查询目录账号失败，已跳过停用")
+		return
+	}
+	for i := range admins {
+		user := &admins[i]
+		if seen[strings.ToLower(user.Username)] || user.IsSuper || user.IsServiceAccount {
+			continue
+		}
+		item := entity.LdapSyncItem{Username: user.Username, DN: user.LdapDN, Reason: "目录中已不存在"}
+		disableLdapAdmin(user, item, dryRun, report)
+	}
+}
+
+// 停用目录账号并吊销令牌
+func disableLdapAdmin(user *entity.SysAdmin, item entity.LdapSyncItem, dryRun bool, report *entity.LdapSyncReport) {
+	if !dryRun {
+		user.Status = 2
+		if err := SysAdminDao.UpdateAdmin(user); err != nil {
+			item.Reason = response.ErrServerError.Error()
+			report.Failed = append(report.Failed, item)
+			return
+		}
+		_ = revokeAdminTokens(user.ID)
+		global.Logger.Info("Disabled ldap admin", zap.Uint("adminId", user.ID), zap.String("reason", item.Reason))
+	}
+	report.Disabled = append(report.Disabled, item)
+}
@@ -0,0 +1,362 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"go-admin-server/pkg/ldap"
+	"go-admin-server/pkg/ldap/ldaptest"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+const (
+	testLdapBindDN   = "cn=reader,dc=example,dc=com"
+	testLdapPassword = "reader-secret"
+)
+
+// 部门：1 总部(默认部门)，2 研发部(映射 ou=dev)；角色：1 dev、2 ops(按组映射)，3 manual(手动分配)
+func seedLdapData(t *testing.T) {
+	t.Helper()
+	mustCreate(t,
+		&entity.SysDept{ID: 1, DeptName: "总部", DeptType: 1, DeptStatus: 1},
+		&entity.SysDept{ID: 2, DeptName: "研发部", DeptType: 3, DeptStatus: 1},
+		&entity.SysPost{ID: 1, PostName: "员工", PostCode: "staff", PostStatus: 1},
+		&entity.SysRole{ID: 1, RoleName: "开发", RoleKey: "dev", RoleStatus: 1},
+		&entity.SysRole{ID: 2, RoleName: "运维", RoleKey: "ops", RoleStatus: 1},
+		&entity.SysRole{ID: 3, RoleName: "手动", RoleKey: "manual", RoleStatus: 1},
+	)
+}
+
+func testDirectory() []ldaptest.Entry {
+	return []ldaptest.Entry{
+		{
+			DN:       "uid=alice,ou=dev,dc=example,dc=com",
+			Password: "alice-secret",
+			Attributes: map[string][]string{
+				"objectClass":     {"person"},
+				"uid":             {"alice"},
+				"cn":              {"Alice"},
+				"mail":            {"alice@example.com"},
+				"telephoneNumber": {"138-0000-0000"},
+				"memberOf":        {"cn=devs,ou=groups,dc=example,dc=com"},
+			},
+		},
+		{
+			DN:       "uid=bob,ou=sales,dc=example,dc=com",
+			Password: "bob-secret",
+			Attributes: map[string][]string{
+				"objectClass":        {"person"},
+				"uid":                {"bob"},
+				"userAccountControl": {"514"},
+			},
+		},
+		{
+			DN:       "uid=carol,ou=sales,dc=example,dc=com",
+			Password: "carol-secret",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"carol"},
+				"cn":          {"Carol"},
+				"memberOf":    {"cn=ops,ou=groups,dc=example,dc=com"},
+			},
+		},
+	}
+}
+
+// 启动测试目录服务并启用LDAP认证
+func setupLdap(t *testing.T, modify func(c *config.Ldap)) *ldaptest.Server {
+	t.Helper()
+	setupTestEnv(t)
+	seedLdapData(t)
+	server, err := ldaptest.NewServer(testLdapBindDN, testLdapPassword, testDirectory()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	c := config.Ldap{
+		Enabled:         true,
+		URL:             server.URL,
+		BindDN:          testLdapBindDN,
+		BindPassword:    testLdapPassword,
+		BaseDN:          "dc=example,dc=com",
+		Timeout:         5 * time.Second,
+		ActiveDirectory: true,
+		CreateOnLogin:   true,
+		DefaultDeptID:   1,
+		DefaultPostID:   1,
+		DeptMappings:    []config.LdapDeptMapping{{DN: "ou=dev,dc=example,dc=com", DeptID: 2}},
+		RoleMappings: []config.LdapRoleMapping{
+			{Group: "cn=devs,ou=groups,dc=example,dc=com", RoleKey: "dev"},
+			{Group: "cn=ops,ou=groups,dc=example,dc=com", RoleKey: "ops"},
+		},
+		Sync: config.LdapSync{CreateUsers: true, DisableMissing: true},
+	}
+	if modify != nil {
+		modify(&c)
+	}
+	if err := ldap.Setup(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ldap.Setup(config.Ldap{}) })
+	return server
+}
+
+func adminRoleIds(t *testing.T, adminId uint) []uint {
+	t.Helper()
+	roleIds, err := SysAdminDao.GetAdminRoleIds(adminId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(roleIds)
+	return roleIds
+}
+
+func adminByName(t *testing.T, username string) *entity.SysAdmin {
+	t.Helper()
+	user, err := SysAdminDao.GetAdminByName(username)
+	if err != nil {
+		t.Fatalf("get admin %s: %v", username, err)
+	}
+	return user
+}
+
+func TestLdapLoginCreatesAdmin(t *testing.T) {
+	setupLdap(t, nil)
+
+	user, method, err := verifyLoginPassword(nil, "alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("verifyLoginPassword() error = %v", err)
+	}
+	if user == nil || method != entity.LoginMethodLdap {
+		t.Fatalf("verifyLoginPassword() = %v, %q, want an ldap login", user, method)
+	}
+	created := adminByName(t, "alice")
+	if created.ID != user.ID || created.LdapDN != "uid=alice,ou=dev,dc=example,dc=com" {
+		t.Errorf("created admin = %+v", created)
+	}
+	if created.Nickname != "Alice" || created.Email != "alice@example.com" || created.Phone != "13800000000" {
+		t.Errorf("created admin attributes = %q %q %q", created.Nickname, created.Email, created.Phone)
+	}
+	if created.DeptID != 2 || created.PostID != 1 {
+		t.Errorf("created admin dept, post = %d, %d, want 2, 1", created.DeptID, created.PostID)
+	}
+	if roleIds := adminRoleIds(t, created.ID); !reflect.DeepEqual(roleIds, []uint{1}) {
+		t.Errorf("created admin roles = %v, want [1]", roleIds)
+	}
+}
+
+func TestLdapLoginSyncsAdmin(t *testing.T) {
+	setupLdap(t, nil)
+	user := newTestAdmin(t, "alice", "old@example.com")
+	user.DeptID, user.PostID = 1, 1
+	mustCreate(t, user)
+	if err := SysAdminDao.UpdateAdminRoles(user.ID, []uint{2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, method, err := verifyLoginPassword(user, "alice", "alice-secret")
+	if err != nil || got == nil || method != entity.LoginMethodLdap {
+		t.Fatalf("verifyLoginPassword() = %v, %q, %v, want an ldap login", got, method, err)
+	}
+	synced := adminByName(t, "alice")
+	if synced.Email != "alice@example.com" || synced.DeptID != 2 || synced.LdapDN == "" {
+		t.Errorf("synced admin = %+v", synced)
+	}
+	// ops 由组映射管理被移除，手动分配的 manual 保留
+	if roleIds := adminRoleIds(t, user.ID); !reflect.DeepEqual(roleIds, []uint{1, 3}) {
+		t.Errorf("synced admin roles = %v, want [1 3]", roleIds)
+	}
+}
+
+func TestLdapLoginPassword(t *testing.T) {
+	setupLdap(t, nil)
+	local := newTestAdmin(t, "zoe", "")
+	directory := newTestAdmin(t, "carol", "")
+	directory.LdapDN = "uid=carol,ou=sales,dc=example,dc=com"
+	super := newTestAdmin(t, "alice", "")
+	super.IsSuper = true
+	mustCreate(t, local, directory, super)
+
+	tests := []struct {
+		name     string
+		user     *entity.SysAdmin
+		username string
+		password string
+		method   string // 为空表示密码错误
+		err      error
+	}{
+		{"directory password", directory, "carol", "carol-secret", entity.LoginMethodLdap, nil},
+		{"wrong directory password", directory, "carol", "wrong", "", nil},
+		{"directory account cannot use local password", directory, "carol", testOldPassword, "", nil},
+		{"local account not in directory", local, "zoe", testOldPassword, entity.LoginMethodPassword, nil},
+		{"wrong local password", local, "zoe", "wrong", "", nil},
+		{"unknown user", nil, "nobody", "secret", "", nil},
+		{"super admin uses local password", super, "alice", testOldPassword, entity.LoginMethodPassword, nil},
+		{"super admin ignores directory password", super, "alice", "alice-secret", "", nil},
+		{"disabled in directory", nil, "bob", "bob-secret", "", response.ErrAdminDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, method, err := verifyLoginPassword(tt.user, tt.username, tt.password)
+			if err != tt.err {
+				t.Fatalf("verifyLoginPassword() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && method != tt.method || (user == nil) != (tt.method == "") {
+				t.Errorf("verifyLoginPassword() = %v, %q, want method %q", user, method, tt.method)
+			}
+		})
+	}
+}
+
+func TestLdapLoginWithoutCreate(t *testing.T) {
+	setupLdap(t, func(c *config.Ldap) { c.CreateOnLogin = false })
+
+	if _, _, err := verifyLoginPassword(nil, "alice", "alice-secret"); err != response.ErrSsoAccountNotFound {
+		t.Errorf("verifyLoginPassword() error = %v, want %v", err, response.ErrSsoAccountNotFound)
+	}
+	if _, err := SysAdminDao.GetAdminByName("alice"); err == nil {
+		t.Error("admin should not be created")
+	}
+}
+
+func TestLdapLoginUnavailable(t *testing.T) {
+	for _, fallback := range []bool{false, true} {
+		server := setupLdap(t, func(c *config.Ldap) { c.FallbackLocal = fallback })
+		server.Close()
+		local := newTestAdmin(t, "zoe", "")
+		directory := newTestAdmin(t, "carol", "")
+		directory.LdapDN = "uid=carol,ou=sales,dc=example,dc=com"
+		mustCreate(t, local, directory)
+
+		// 本地账号不受目录影响
+		if user, method, err := verifyLoginPassword(local, "zoe", testOldPassword); err != nil || user == nil || method != entity.LoginMethodPassword {
+			t.Errorf("fallback=%v local login = %v, %q, %v", fallback, user, method, err)
+		}
+		if _, _, err := verifyLoginPassword(nil, "nobody", "secret"); err != response.ErrLdapUnavailable {
+			t.Errorf("fallback=%v unknown user error = %v, want %v", fallback, err, response.ErrLdapUnavailable)
+		}
+		user, method, err := verifyLoginPassword(directory, "carol", testOldPassword)
+		if fallback {
+			if err != nil || user == nil || method != entity.LoginMethodPassword {
+				t.Errorf("fallback directory login = %v, %q, %v, want local password login", user, method, err)
+			}
+		} else if err != response.ErrLdapUnavailable {
+			t.Errorf("directory login error = %v, want %v", err, response.ErrLdapUnavailable)
+		}
+	}
+}
+
+func syncedUsernames(items []entity.LdapSyncItem) []string {
+	usernames := make([]string, 0, len(items))
+	for _, item := range items {
+		usernames = append(usernames, item.Username)
+	}
+	slices.Sort(usernames)
+	return usernames
+}
+
+func TestSyncLdapDirectory(t *testing.T) {
+	server := setupLdap(t, nil)
+	super := newTestAdmin(t, "carol", "")
+	super.IsSuper = true
+	mustCreate(t, super)
+
+	// 只生成报告，不修改数据
+	report, err := SyncLdapDirectory(true)
+	if err != nil {
+		t.Fatalf("SyncLdapDirectory(dryRun) error = %v", err)
+	}
+	if got := syncedUsernames(report.Created); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Errorf("dry run created = %v, want [alice]", got)
+	}
+	if got := syncedUsernames(report.Skipped); !reflect.DeepEqual(got, []string{"carol"}) {
+		t.Errorf("dry run skipped = %v, want [carol]", got)
+	}
+	if _, err := SysAdminDao.GetAdminByName("alice"); err == nil {
+		t.Error("dry run should not create admins")
+	}
+
+	report, err = SyncLdapDirectory(false)
+	if err != nil {
+		t.Fatalf("SyncLdapDirectory() error = %v", err)
+	}
+	if report.Total != 3 || len(report.Created) != 1 || len(report.Failed) != 0 {
+		t.Errorf("report = %+v", report)
+	}
+	alice := adminByName(t, "alice")
+	if alice.DeptID != 2 || alice.Status != 1 {
+		t.Errorf("created admin = %+v", alice)
+	}
+
+	// 目录中的属性变化同步到本地，目录中已删除的用户被停用
+	entries := testDirectory()
+	entries[0].Attributes["mail"] = []string{"alice@corp.example.com"}
+	server.SetEntries(entries[1:]...)
+	report, err = SyncLdapDirectory(false)
+	if err != nil {
+		t.Fatalf("SyncLdapDirectory() error = %v", err)
+	}
+	if got := syncedUsernames(report.Disabled); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Errorf("disabled = %v, want [alice]", got)
+	}
+	if alice = adminByName(t, "alice"); alice.Status != 2 {
+		t.Errorf("alice status = %d, want 2", alice.Status)
+	}
+	if super = adminByName(t, "carol"); super.Status != 1 {
+		t.Error("super admin should not be disabled")
+	}
+
+	// 停用的用户不会自动启用，但属性仍然同步
+	server.SetEntries(entries...)
+	report, err = SyncLdapDirectory(false)
+	if err != nil {
+		t.Fatalf("SyncLdapDirectory() error = %v", err)
+	}
+	if got := syncedUsernames(report.Updated); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Errorf("updated = %v, want [alice]", got)
+	}
+	if alice = adminByName(t, "alice"); alice.Status != 2 || alice.Email != "alice@corp.example.com" {
+		t.Errorf("alice = status %d email %s", alice.Status, alice.Email)
+	}
+}
+
+func TestSyncLdapDirectoryEmpty(t *testing.T) {
+	server := setupLdap(t, nil)
+	directory := newTestAdmin(t, "alice", "")
+	directory.LdapDN = "uid=alice,ou=dev,dc=example,dc=com"
+	mustCreate(t, directory)
+
+	// 目录返回空结果时不停用任何用户
+	server.SetEntries()
+	report, err := SyncLdapDirectory(false)
+	if err != nil {
+		t.Fatalf("SyncLdapDirectory() error = %v", err)
+	}
+	if len(report.Disabled) != 0 || len(report.Warnings) != 1 {
+		t.Errorf("report disabled = %v, warnings = %v", report.Disabled, report.Warnings)
+	}
+	if adminByName(t, "alice").Status != 1 {
+		t.Error("admin should stay active")
+	}
+}
+
+func TestSyncLdapDirectoryLocked(t *testing.T) {
+	setupLdap(t, nil)
+	if err := global.RDB.Set(t.Context(), global.LdapSyncLock, 1, time.Minute).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncLdapDirectory(false); err != response.ErrLdapSyncRunning {
+		t.Errorf("SyncLdapDirectory() error = %v, want %v", err, response.ErrLdapSyncRunning)
+	}
+	// 只生成报告时不需要加锁
+	if _, err := SyncLdapDirectory(true); err != nil {
+		t.Errorf("SyncLdapDirectory(dryRun) error = %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"testing"
+	"time"
+)
+
+func TestRecordFailureBackoff(t *testing.T) {
+	setupTestEnv(t)
+	cfg := config.LoginSecurity{MaxUserFailures: 2, FailureWindow: time.Minute, LockDuration: time.Minute, MaxLockDuration: 4 * time.Minute}
+	// 每次锁定的时长翻倍，不超过最长锁定时长
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		if until, err := recordFailure(userSubject("alice"), cfg.MaxUserFailures, cfg); err != nil || !until.IsZero() {
+			t.Fatalf("lock %d: first failure = %v, %v, want not locked", i+1, until, err)
+		}
+		until, err := recordFailure(userSubject("alice"), cfg.MaxUserFailures, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := time.Until(until); got < want-2*time.Second || got > want {
+			t.Errorf("lock %d: duration = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+// 使用用户名和密码登录
+func passwordLogin(t *testing.T, ip, username, password string) error {
+	t.Helper()
+	_, _, _, err := (&SysAdminService{}).Login(ip, "Chrome", "Linux", "", &entity.LoginDto{
+		Username:     username,
+		Password:     password,
+		CaptchaID:    testCaptcha(t),
+		CaptchaImage: "1234",
+	})
+	return err
+}
+
+func TestLoginLockout(t *testing.T) {
+	tests := []struct {
+		name      string
+		usernames []string // 依次使用错误的密码登录
+		lockCode  int
+	}{
+		{"account locked", []string{"alice", "alice", "alice"}, response.CodeAccountLocked},
+		{"username case and spaces", []string{"alice", "ALICE", " Alice "}, response.CodeAccountLocked},
+		{"ip locked", []string{"u1", "u2", "u3", "u4"}, response.CodeIPLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			global.Config.LoginSecurity = config.LoginSecurity{MaxUserFailures: 3, MaxIPFailures: 4}
+			mustCreate(t, newTestAdmin(t, "alice", ""))
+
+			for i, username := range tt.usernames {
+				err := passwordLogin(t, "10.0.0.1", username, "WrongPass1")
+				if i < len(tt.usernames)-1 && err != response.ErrLoginError {
+					t.Fatalf("attempt %d: Login() error = %v, want %v", i+1, err, response.ErrLoginError)
+				}
+				if i == len(tt.usernames)-1 && errorCode(err) != tt.lockCode {
+					t.Fatalf("attempt %d: Login() error = %v, want code %d", i+1, err, tt.lockCode)
+				}
+			}
+			// 锁定期间密码正确也不能登录
+			if err := passwordLogin(t, "10.0.0.1", "alice", testOldPassword); errorCode(err) != tt.lockCode {
+				t.Errorf("Login() while locked error = %v, want code %d", err, tt.lockCode)
+			}
+			// 锁定事件记录在登录日志中
+			var lockLogs int64
+			global.DB.Model(&entity.SysLoginLog{}).Where("login_status = ? AND message LIKE ?", 2, "登录失败次数过多%").Count(&lockLogs)
+			if lockLogs < 2 {
+				t.Errorf("lock login logs = %d, want at least 2", lockLogs)
+			}
+		})
+	}
+}
+
+func TestUnlockAdmin(t *testing.T) {
+	setupTestEnv(t)
+	global.Config.LoginSecurity = config.LoginSecurity{MaxUserFailures: 1}
+	root, alice, _ := seedSuperRoles(t)
+	carol := newTestAdmin(t, "carol", "")
+	mustCreate(t, carol)
+	for _, user := range []*entity.SysAdmin{root, carol} {
+		if err := passwordLogin(t, "10.0.0.1", user.Username, "WrongPass1"); errorCode(err) != response.CodeAccountLocked {
+			t.Fatalf("Login(%s) error = %v, want account locked", user.Username, err)
+		}
+	}
+
+	admins := &SysAdminService{}
+	scope := &entity.DataScope{All: true}
+	// 超级管理员账号只能由自己解锁
+	if err := admins.UnlockAdmin(scope, alice.ID, root.ID); err != response.ErrSuperAdminProtected {
+		t.Errorf("UnlockAdmin(root) error = %v, want %v", err, response.ErrSuperAdminProtected)
+	}
+	if err := admins.UnlockAdmin(scope, alice.ID, carol.ID); err != nil {
+		t.Fatalf("UnlockAdmin(carol) error = %v", err)
+	}
+	if err := checkLoginLocked(carol.Username, "10.0.0.2"); err != nil {
+		t.Errorf("carol is still locked: %v", err)
+	}
+	if err := checkLoginLocked(root.Username, "10.0.0.2"); errorCode(err) != response.CodeAccountLocked {
+		t.Errorf("root lock = %v, want account locked", err)
+	}
+}
@@ -0,0 +1,333 @@
+// OIDC 单点登录：发起授权、回调登录、匹配或自动创建用户，以及按身份提供方的用户组同步角色
+
+package service
+
+import (
+	"context"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+	"go-admin-server/pkg/oidc"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OidcService struct{}
+
+// 查询单点登录配置，登录页据此决定是否显示单点登录入口
+func (s *OidcService) GetOidcConfig() *entity.OidcConfigVo {
+	cfg := oidc.Config()
+	if !cfg.Enabled {
+		return &entity.OidcConfigVo{}
+	}
+	return &entity.OidcConfigVo{Enabled: true, ProviderName: cfg.ProviderName}
+}
+
+// 发起单点登录：state、nonce 和 PKCE 校验码保存在服务端，返回身份提供方的授权地址
+func (s *OidcService) Authorize() (*entity.OidcAuthorizeVo, error) {
+	cfg := oidc.Config()
+	if !cfg.Enabled {
+		return nil, response.ErrSsoDisabled
+	}
+	state, err := utils.RandomHex(16)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	nonce, err := utils.RandomHex(16)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	request := &entity.OidcAuthRequest{
+		Nonce:        nonce,
+		CodeVerifier: oidc.GenerateVerifier(),
+	}
+	authURL, err := oidc.AuthCodeURL(context.Background(), state, nonce, request.CodeVerifier)
+	if err != nil {
+		global.Logger.Error("Failed to build oidc auth url", zap.Error(err))
+		return nil, response.ErrServerError
+	}
+	if err := OidcDao.SaveAuthRequest(state, request, cfg.StateTTL); err != nil {
+		return nil, response.ErrServerError
+	}
+	return &entity.OidcAuthorizeVo{
+		AuthURL:   authURL,
+		State:     state,
+		ExpiresAt: time.Now().Add(cfg.StateTTL),
+	}, nil
+}
+
+// 单点登录回调：校验身份提供方返回的授权码，匹配或创建用户后签发令牌，需要两步验证时返回挑战令牌
+func (s *OidcService) Login(ip, browser, Os, device string, dto *entity.OidcLoginDto) (*entity.SysAdmin, *jwt.TokenPair, *entity.LoginChallengeVo, error) {
+	cfg := oidc.Config()
+	if !cfg.Enabled {
+		return nil, nil, nil, response.ErrSsoDisabled
+	}
+	// 每个 state 只能使用一次
+	request, err := OidcDao.TakeAuthRequest(dto.State)
+	if err != nil {
+		return nil, nil, nil, response.ErrServerError
+	}
+	if request == nil {
+		return nil, nil, nil, response.ErrSsoStateInvalid
+	}
+	identity, err := oidc.Exchange(context.Background(), dto.Code, request.CodeVerifier, request.Nonce)
+	if err != nil {
+		global.Logger.Warn("OIDC authentication failed", zap.Error(err))
+		SysLogDao.CreateLoginLog("", ip, browser, Os, "单点登录认证失败", 2)
+		return nil, nil, nil, response.ErrSsoAuthFailed
+	}
+	// 登录日志中的用户名，身份提供方未返回用户名时使用用户标识
+	logName := identity.Username
+	if logName == "" {
+		logName = identity.Subject
+	}
+
+	user, err := findOidcAdmin(cfg, identity)
+	if err == nil && user == nil {
+		if !cfg.Provisioning.Enabled {
+			err = response.ErrSsoAccountNotFound
+		} else {
+			user, err = provisionOidcAdmin(cfg, identity)
+		}
+	} else if err == nil {
+		err = syncOidcGroupRoles(cfg, user, identity.Groups)
+	}
+	if err != nil {
+		SysLogDao.CreateLoginLog(logName, ip, browser, Os, "单点登录失败："+err.Error(), 2)
+		return nil, nil, nil, err
+	}
+
+	// 检测账号状态
+	if user.Status == 2 {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "账号已停用", 2)
+		return nil, nil, nil, response.ErrAdminDisabled
+	}
+	if user.Status == 3 {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "账号未激活", 2)
+		return nil, nil, nil, response.ErrAdminPending
+	}
+	if user.IsServiceAccount {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "服务账号不能登录", 2)
+		return nil, nil, nil, response.ErrServiceAccountLogin
+	}
+
+	// 身份提供方已完成多因素认证时可以跳过本地两步验证
+	if !cfg.SkipTwoFactor {
+		challenge, err := createLoginChallenge(user, entity.LoginMethodOidc, ip, browser, Os, device)
+		if err != nil {
+			SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "服务器故障", 2)
+			return nil, nil, nil, response.ErrServerError
+		}
+		if challenge != nil {
+			return user, nil, challenge, nil
+		}
+	}
+
+	// 生成token
+	tokenPair, err := issueTokenPair(user, entity.LoginMethodOidc, ip, browser, Os, device)
+	if err != nil {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "服务器故障", 2)
+		return nil, nil, nil, response.ErrServerError
+	}
+	SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "单点登录成功", 1)
+	return user, tokenPair, nil, nil
+}
+
+// 查找单点登录身份对应的用户：优先使用已绑定的身份，否则按 match_by 的顺序匹配用户名或邮箱，
+// 匹配成功后绑定身份。没有匹配的用户时返回nil
+func findOidcAdmin(cfg config.Oidc, identity *oidc.Identity) (*entity.SysAdmin, error) {
+	user, err := SysAdminDao.GetAdminByOidcIdentity(identity.Issuer, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.ErrServerError
+	}
+
+	for _, field := range cfg.MatchBy {
+		user, err := matchOidcAdmin(cfg, identity, field)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			continue
+		}
+		// 同一身份提供方中已绑定了其他身份，可能是身份提供方中的用户名或邮箱被复用
+		if user.OidcIssuer != nil && *user.OidcIssuer == identity.Issuer {
+			return nil, response.ErrSsoAccountConflict
+		}
+		// 高权限账号和服务账号不能仅凭用户名或邮箱相同就被接管
+		if user.IsSuper || user.IsServiceAccount {
+			return nil, response.ErrSsoLinkDenied
+		}
+		if err := SysAdminDao.BindOidcIdentity(user.ID, identity.Issuer, identity.Subject); err != nil {
+			return nil, response.ErrServerError
+		}
+		global.Logger.Info("Linked admin to oidc identity",
+			zap.Uint("adminId", user.ID), zap.String("issuer", identity.Issuer), zap.String("subject", identity.Subject))
+		return user, nil
+	}
+	return nil, nil
+}
+
+// 按用户名或邮箱匹配用户，邮箱必须已验证且只对应一个用户
+func matchOidcAdmin(cfg config.Oidc, identity *oidc.Identity, field string) (*entity.SysAdmin, error) {
+	switch field {
+	case "username":
+		if identity.Username == "" {
+			return nil, nil
+		}
+		user, err := SysAdminDao.GetAdminByName(identity.Username)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, response.ErrServerError
+		}
+		return user, nil
+	case "email":
+		if identity.Email == "" || !(identity.EmailVerified || cfg.AllowUnverifiedEmail) {
+			return nil, nil
+		}
+		admins, err := SysAdminDao.GetAdminsByEmail(identity.Email)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		if len(admins) != 1 {
+			return nil, nil
+		}
+		return &admins[0], nil
+	}
+	return nil, nil
+}
+
+// 自动创建用户：使用配置的默认部门、岗位和角色，并合并用户组映射的角色；
+// 创建的用户使用随机密码并禁用密码登录
+func provisionOidcAdmin(cfg config.Oidc, identity *oidc.Identity) (*entity.SysAdmin, error) {
+	if identity.Username == "" {
+		global.Logger.Warn("OIDC identity has no username claim", zap.String("claim", cfg.UsernameClaim))
+		return nil, response.ErrSsoAuthFailed
+	}
+	nameExists, err := SysAdminDao.ExistsByName(identity.Username)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if nameExists {
+		return nil, response.ErrAdminNameExists
+	}
+	nickname, err := uniqueNickname(identity.Username, identity.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkProvisionDeptPost(cfg.Provisioning.DeptID, cfg.Provisioning.PostID); err != nil {
+		return nil, err
+	}
+
+	roleKeys := append(groupRoleKeys(cfg, identity.Groups), cfg.Provisioning.RoleKeys...)
+	roleIds, err := roleIdsByKeys(roleKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := utils.RandomHex(32)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	sysAdmin := &entity.SysAdmin{
+		Username:  identity.Username,
+		Nickname:  nickname,
+		Email:     identity.Email,
+		Status:    1,
+		DeptID:    cfg.Provisioning.DeptID,
+		PostID:    cfg.Provisioning.PostID,
+		CreatedAt: utils.HTime{Time: time.Now()},
+
+		PasswordLoginDisabled: true,
+		OidcIssuer:            &identity.Issuer,
+		OidcSubject:           &identity.Subject,
+	}
+	if err := applyNewPassword(sysAdmin, password); err != nil {
+		return nil, response.ErrServerError
+	}
+	if err := SysAdminDao.CreateAdmin(roleIds, sysAdmin); err != nil {
+		return nil, response.ErrServerError
+	}
+	global.Logger.Info("Provisioned admin from oidc identity",
+		zap.Uint("adminId", sysAdmin.ID), zap.String("username", sysAdmin.Username), zap.Uints("roleIds", roleIds))
+	return sysAdmin, nil
+}
+
+// 用户所属用户组映射的角色关键字
+func groupRoleKeys(cfg config.Oidc, groups []string) []string {
+	inGroup := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		inGroup[group] = true
+	}
+	var roleKeys []string
+	for _, mapping := range cfg.GroupRoles {
+		if inGroup[mapping.Group] {
+			roleKeys = append(roleKeys, mapping.RoleKey)
+		}
+	}
+	return roleKeys
+}
+
+// 根据角色关键字查询角色id，不存在的角色关键字记录警告后忽略
+func roleIdsByKeys(roleKeys []string) ([]uint, error) {
+	roleKeys = uniqueStrings(roleKeys)
+	if len(roleKeys) == 0 {
+		return nil, nil
+	}
+	roles, err := SysRoleDao.GetRolesByKeys(roleKeys)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if len(roles) != len(roleKeys) {
+		global.Logger.Warn("Some mapped role keys do not exist", zap.Strings("roleKeys", roleKeys))
+	}
+	roleIds := make([]uint, 0, len(roles))
+	for _, role := range roles {
+		roleIds = append(roleIds, role.ID)
+	}
+	return roleIds, nil
+}
+
+// 按用户组同步角色：只增减 group_roles 中出现的角色，管理员手动分配的其他角色保持不变
+func syncOidcGroupRoles(cfg config.Oidc, user *entity.SysAdmin, groups []string) error {
+	if !cfg.SyncGroupRoles || len(cfg.GroupRoles) == 0 {
+		return nil
+	}
+	managedKeys := make([]string, 0, len(cfg.GroupRoles))
+	for _, mapping := range cfg.GroupRoles {
+		managedKeys = append(managedKeys, mapping.RoleKey)
+	}
+	managedIds, err := roleIdsByKeys(managedKeys)
+	if err != nil {
+		return err
+	}
+	wantedIds, err := roleIdsByKeys(groupRoleKeys(cfg, groups))
+	if err != nil {
+		return err
+	}
+	oldRoleIds, err := SysAdminDao.GetAdminRoleIds(user.ID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	newRoleIds := mergeManagedRoles(oldRoleIds, managedIds, wantedIds)
+	if sameIds(uniqueIds(oldRoleIds), newRoleIds) {
+		return nil
+	}
+	if err := SysAdminDao.UpdateAdminRoles(user.ID, newRoleIds); err != nil {
+		return response.ErrServerError
+	}
+	global.Logger.Info("Synced admin roles from oidc groups",
+		zap.Uint("adminId", user.ID), zap.Uints("oldRoleIds", oldRoleIds), zap.Uints("newRoleIds", newRoleIds))
+	return invalidateAdminPermissions(user.ID)
+}
@@ -0,0 +1,216 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/pkg/oidc"
+	"reflect"
+	"testing"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func TestFindOidcAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.Oidc
+		setup    func(t *testing.T)
+		identity oidc.Identity
+		want     string // 匹配到的用户名，为空表示没有匹配
+		err      error
+	}{
+		{
+			name:     "bound identity",
+			setup:    func(t *testing.T) { bindTestIdentity(t, "alice", "sub-alice") },
+			identity: oidc.Identity{Subject: "sub-alice", Username: "renamed"},
+			want:     "alice",
+		},
+		{
+			name:     "no match by",
+			identity: oidc.Identity{Subject: "sub-new", Username: "alice", Email: "alice@example.com", EmailVerified: true},
+		},
+		{
+			name:     "match username",
+			config:   config.Oidc{MatchBy: []string{"username"}},
+			identity: oidc.Identity{Subject: "sub-new", Username: "alice"},
+			want:     "alice",
+		},
+		{
+			name:     "match verified email",
+			config:   config.Oidc{MatchBy: []string{"username", "email"}},
+			identity: oidc.Identity{Subject: "sub-new", Username: "a.lice", Email: "alice@example.com", EmailVerified: true},
+			want:     "alice",
+		},
+		{
+			name:     "unverified email",
+			config:   config.Oidc{MatchBy: []string{"email"}},
+			identity: oidc.Identity{Subject: "sub-new", Email: "alice@example.com"},
+		},
+		{
+			name:     "unverified email allowed",
+			config:   config.Oidc{MatchBy: []string{"email"}, AllowUnverifiedEmail: true},
+			identity: oidc.Identity{Subject: "sub-new", Email: "alice@example.com"},
+			want:     "alice",
+		},
+		{
+			name:     "shared email",
+			config:   config.Oidc{MatchBy: []string{"email"}},
+			setup:    func(t *testing.T) { mustCreate(t, newTestAdmin(t, "alice2", "alice@example.com")) },
+			identity: oidc.Identity{Subject: "sub-new", Email: "alice@example.com", EmailVerified: true},
+		},
+		{
+			name:     "already bound to another identity",
+			config:   config.Oidc{MatchBy: []string{"username"}},
+			setup:    func(t *testing.T) { bindTestIdentity(t, "alice", "sub-alice") },
+			identity: oidc.Identity{Subject: "sub-new", Username: "alice"},
+			err:      response.ErrSsoAccountConflict,
+		},
+		{
+			name:     "super admin",
+			config:   config.Oidc{MatchBy: []string{"username"}},
+			identity: oidc.Identity{Subject: "sub-new", Username: "root"},
+			err:      response.ErrSsoLinkDenied,
+		},
+		{
+			name:     "service account",
+			config:   config.Oidc{MatchBy: []string{"username"}},
+			identity: oidc.Identity{Subject: "sub-new", Username: "robot"},
+			err:      response.ErrSsoLinkDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			root := newTestAdmin(t, "root", "root@example.com")
+			root.IsSuper = true
+			robot := newTestAdmin(t, "robot", "")
+			robot.IsServiceAccount = true
+			mustCreate(t, newTestAdmin(t, "alice", "alice@example.com"), root, robot)
+			if tt.setup != nil {
+				tt.setup(t)
+			}
+
+			tt.identity.Issuer = testIssuer
+			user, err := findOidcAdmin(tt.config, &tt.identity)
+			if err != tt.err {
+				t.Fatalf("findOidcAdmin() error = %v, want %v", err, tt.err)
+			}
+			var got string
+			if user != nil {
+				got = user.Username
+			}
+			if got != tt.want {
+				t.Fatalf("findOidcAdmin() = %q, want %q", got, tt.want)
+			}
+			// 匹配成功后绑定身份，下次登录直接按身份查找
+			if tt.want != "" {
+				bound, err := SysAdminDao.GetAdminByOidcIdentity(testIssuer, tt.identity.Subject)
+				if err != nil || bound.Username != tt.want {
+					t.Errorf("identity bound to %v, %v, want %s", bound, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// 为用户绑定测试身份提供方中的身份
+func bindTestIdentity(t *testing.T, username, subject string) {
+	t.Helper()
+	if err := SysAdminDao.BindOidcIdentity(adminByName(t, username).ID, testIssuer, subject); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProvisionOidcAdmin(t *testing.T) {
+	setupTestEnv(t)
+	seedLdapData(t)
+	mustCreate(t, &entity.SysAdmin{Username: "taken", Nickname: "Alice", Password: "x", Status: 1})
+	cfg := config.Oidc{
+		GroupRoles:   []config.OidcGroupRole{{Group: "devs", RoleKey: "dev"}, {Group: "ops", RoleKey: "ops"}},
+		Provisioning: config.OidcProvisioning{Enabled: true, DeptID: 1, PostID: 1, RoleKeys: []string{"manual", "missing"}},
+	}
+
+	identity := &oidc.Identity{Issuer: testIssuer, Subject: "sub-alice", Username: "alice", Name: "Alice", Groups: []string{"devs"}}
+	user, err := provisionOidcAdmin(cfg, identity)
+	if err != nil {
+		t.Fatalf("provisionOidcAdmin() error = %v", err)
+	}
+	created := adminByName(t, "alice")
+	if !created.PasswordLoginDisabled || created.OidcSubject == nil || *created.OidcSubject != "sub-alice" {
+		t.Errorf("created admin = %+v", created)
+	}
+	// 昵称已被占用时使用用户名
+	if created.ID != user.ID || created.Nickname != "alice" || created.DeptID != 1 {
+		t.Errorf("created admin nickname, dept = %q, %d", created.Nickname, created.DeptID)
+	}
+	if roleIds := adminRoleIds(t, created.ID); !reflect.DeepEqual(roleIds, []uint{1, 3}) {
+		t.Errorf("created admin roles = %v, want [1 3]", roleIds)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.Oidc
+		identity oidc.Identity
+		err      error
+	}{
+		{"no username", cfg, oidc.Identity{Subject: "sub-2"}, response.ErrSsoAuthFailed},
+		{"username exists", cfg, oidc.Identity{Subject: "sub-2", Username: "taken"}, response.ErrAdminNameExists},
+		{"missing dept", config.Oidc{Provisioning: config.OidcProvisioning{DeptID: 9, PostID: 1}}, oidc.Identity{Subject: "sub-2", Username: "bob"}, response.ErrDeptNotExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.identity.Issuer = testIssuer
+			if _, err := provisionOidcAdmin(tt.cfg, &tt.identity); err != tt.err {
+				t.Errorf("provisionOidcAdmin() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSyncOidcGroupRoles(t *testing.T) {
+	setupTestEnv(t)
+	seedLdapData(t)
+	user := newTestAdmin(t, "alice", "")
+	mustCreate(t, user)
+	cfg := config.Oidc{
+		SyncGroupRoles: true,
+		GroupRoles:     []config.OidcGroupRole{{Group: "devs", RoleKey: "dev"}, {Group: "ops", RoleKey: "ops"}},
+	}
+
+	tests := []struct {
+		name   string
+		before []uint
+		groups []string
+		want   []uint
+	}{
+		{"add mapped role", []uint{3}, []string{"devs"}, []uint{1, 3}},
+		{"remove mapped role", []uint{1, 2, 3}, []string{"ops", "unmapped"}, []uint{2, 3}},
+		{"no groups", []uint{1, 3}, nil, []uint{3}},
+		{"unchanged", []uint{2}, []string{"ops"}, []uint{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SysAdminDao.UpdateAdminRoles(user.ID, tt.before); err != nil {
+				t.Fatal(err)
+			}
+			if err := syncOidcGroupRoles(cfg, user, tt.groups); err != nil {
+				t.Fatalf("syncOidcGroupRoles() error = %v", err)
+			}
+			if roleIds := adminRoleIds(t, user.ID); !reflect.DeepEqual(roleIds, tt.want) {
+				t.Errorf("roles = %v, want %v", roleIds, tt.want)
+			}
+		})
+	}
+
+	// 未开启同步时不修改角色
+	cfg.SyncGroupRoles = false
+	if err := syncOidcGroupRoles(cfg, user, []string{"devs"}); err != nil {
+		t.Fatal(err)
+	}
+	if roleIds := adminRoleIds(t, user.ID); !reflect.DeepEqual(roleIds, []uint{2}) {
+		t.Errorf("roles = %v, want [2]", roleIds)
+	}
+}
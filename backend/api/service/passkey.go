@@ -0,0 +1,460 @@
+// 通行密钥(WebAuthn)：注册和管理个人的通行密钥，使用通行密钥免密码登录，或在密码登录后代替验证码完成两步验证。
+// 免密码登录要求认证器验证用户(PIN、指纹等)，本身已是多因素认证，不再需要两步验证
+
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+	"go-admin-server/pkg/passkey"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PasskeyService struct{}
+
+// 用户是否有可以使用的通行密钥，未启用通行密钥时已注册的通行密钥不能使用
+func hasPasskeys(adminId uint) (bool, error) {
+	if !passkey.Enabled() {
+		return false, nil
+	}
+	count, err := PasskeyDao.CountPasskeys(adminId)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 加载用户已注册的通行密钥，返回注册和验证时使用的用户
+func loadPasskeyUser(user *entity.SysAdmin) (*passkey.User, []entity.SysAdminCredential, error) {
+	passkeys, err := PasskeyDao.GetPasskeysByAdminId(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	credentials := make([]webauthn.Credential, 0, len(passkeys))
+	for i := range passkeys {
+		credentials = append(credentials, toWebAuthnCredential(&passkeys[i]))
+	}
+	return &passkey.User{
+		ID:          user.ID,
+		Name:        user.Username,
+		DisplayName: user.Nickname,
+		Credentials: credentials,
+	}, passkeys, nil
+}
+
+// 数据库中的通行密钥转换为验证时使用的凭据
+func toWebAuthnCredential(p *entity.SysAdminCredential) webauthn.Credential {
+	id, _ := base64.RawURLEncoding.DecodeString(p.CredentialID)
+	var transports []protocol.AuthenticatorTransport
+	if p.Transports != "" {
+		for _, transport := range strings.Split(p.Transports, ",") {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+	}
+	var aaguid []byte
+	if parsed, err := uuid.Parse(p.AAGUID); err == nil {
+		aaguid = parsed[:]
+	}
+	return webauthn.Credential{
+		ID:              id,
+		PublicKey:       p.PublicKey,
+		AttestationType: p.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: p.BackupEligible,
+			BackupState:    p.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    aaguid,
+			SignCount: p.SignCount,
+		},
+	}
+}
+
+// 保存注册或验证会话，返回前端调用浏览器接口的参数
+func savePasskeySession(session *entity.PasskeySession, options any) (*entity.PasskeyOptionsVo, error) {
+	sessionId, err := utils.RandomHex(32)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	ttl := passkey.Config().Timeout
+	if err := PasskeyDao.SaveSession(sessionId, session, ttl); err != nil {
+		return nil, response.ErrServerError
+	}
+	return &entity.PasskeyOptionsVo{
+		SessionID: sessionId,
+		Options:   options,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// 取出指定用途的会话，会话不存在、已过期或用途不符时返回错误
+func takePasskeySession(sessionId, purpose string) (*entity.PasskeySession, error) {
+	session, err := PasskeyDao.TakeSession(sessionId)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if session == nil || session.Purpose != purpose {
+		return nil, response.ErrPasskeySessionInvalid
+	}
+	return session, nil
+}
+
+// 验证通过后检查签名计数并记录使用情况。签名计数没有增加说明认证器可能已被复制，拒绝使用
+func usePasskey(user *entity.SysAdmin, passkeys []entity.SysAdminCredential, credential *webauthn.Credential, ip string) error {
+	credentialId := base64.RawURLEncoding.EncodeToString(credential.ID)
+	var used *entity.SysAdminCredential
+	for i := range passkeys {
+		if passkeys[i].CredentialID == credentialId {
+			used = &passkeys[i]
+			break
+		}
+	}
+	if used == nil {
+		return response.ErrPasskeyVerifyFailed
+	}
+	if credential.Authenticator.CloneWarning {
+		global.Logger.Warn("Passkey sign count did not increase, the authenticator may be cloned",
+			zap.Uint("adminId", user.ID), zap.Uint("passkeyId", used.ID))
+		return response.ErrPasskeyVerifyFailed
+	}
+	if err := PasskeyDao.TouchPasskey(used.ID, credential.Authenticator.SignCount, credential.Flags.BackupState, ip, time.Now()); err != nil {
+		global.Logger.Warn("Failed to update passkey usage", zap.Uint("passkeyId", used.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// 查询个人的通行密钥列表
+func (s *PasskeyService) GetMyPasskeys(adminId uint) ([]entity.SysAdminCredential, error) {
+	passkeys, err := PasskeyDao.GetPasskeysByAdminId(adminId)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	return passkeys, nil
+}
+
+// 开始注册通行密钥，已注册的认证器不能重复注册
+func (s *PasskeyService) BeginRegistration(adminId uint) (*entity.PasskeyOptionsVo, error) {
+	if !passkey.Enabled() {
+		return nil, response.ErrPasskeyDisabled
+	}
+	user, err := getAdmin(adminId)
+	if err != nil {
+		return nil, err
+	}
+	passkeyUser, passkeys, err := loadPasskeyUser(user)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if len(passkeys) >= passkey.Config().MaxPasskeys {
+		return nil, response.ErrPasskeyLimit
+	}
+	options, data, err := passkey.BeginRegistration(passkeyUser)
+	if err != nil {
+		global.Logger.Error("Failed to begin passkey registration", zap.Uint("adminId", adminId), zap.Error(err))
+		return nil, response.ErrServerError
+	}
+	return savePasskeySession(&entity.PasskeySession{
+		Purpose: entity.PasskeyPurposeRegister,
+		AdminID: user.ID,
+		Data:    *data,
+	}, options)
+}
+
+// 完成注册通行密钥：校验认证器返回的注册数据后保存公钥
+func (s *PasskeyService) FinishRegistration(adminId uint, dto *entity.FinishPasskeyRegistrationDto) (*entity.SysAdminCredential, error) {
+	if !passkey.Enabled() {
+		return nil, response.ErrPasskeyDisabled
+	}
+	session, err := takePasskeySession(dto.SessionID, entity.PasskeyPurposeRegister)
+	if err != nil {
+		return nil, err
+	}
+	if session.AdminID != adminId {
+		return nil, response.ErrPasskeySessionInvalid
+	}
+	user, err := getAdmin(adminId)
+	if err != nil {
+		return nil, err
+	}
+	passkeyUser, passkeys, err := loadPasskeyUser(user)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if len(passkeys) >= passkey.Config().MaxPasskeys {
+		return nil, response.ErrPasskeyLimit
+	}
+	credential, err := passkey.FinishRegistration(passkeyUser, session.Data, dto.Credential)
+	if err != nil {
+		global.Logger.Warn("Passkey registration failed", zap.Uint("adminId", adminId), zap.Error(err))
+		return nil, response.ErrPasskeyVerifyFailed
+	}
+	credentialId := base64.RawURLEncoding.EncodeToString(credential.ID)
+	exists, err := PasskeyDao.ExistsCredentialId(credentialId)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if exists {
+		return nil, response.ErrPasskeyExists
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	aaguid := ""
+	if parsed, err := uuid.FromBytes(credential.Authenticator.AAGUID); err == nil {
+		aaguid = parsed.String()
+	}
+	created := &entity.SysAdminCredential{
+		AdminID:         user.ID,
+		Name:            strings.TrimSpace(dto.Name),
+		CredentialID:    credentialId,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          aaguid,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		CreatedAt:       utils.HTime{Time: time.Now()},
+	}
+	if err := PasskeyDao.CreatePasskey(created); err != nil {
+		return nil, response.ErrServerError
+	}
+	global.Logger.Info("Passkey registered", zap.Uint("adminId", user.ID), zap.Uint("passkeyId", created.ID))
+	return created, nil
+}
+
+// 修改个人通行密钥的名称
+func (s *PasskeyService) RenamePasskey(adminId uint, dto *entity.RenamePasskeyDto) error {
+	if _, err := getMyPasskey(adminId, dto.ID); err != nil {
+		return err
+	}
+	if err := PasskeyDao.RenamePasskey(dto.ID, strings.TrimSpace(dto.Name)); err != nil {
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 删除个人的通行密钥，删除后不能再使用该通行密钥登录
+func (s *PasskeyService) DeletePasskey(adminId uint, dto *entity.DeletePasskeyDto) error {
+	if _, err := getMyPasskey(adminId, dto.ID); err != nil {
+		return err
+	}
+	if err := PasskeyDao.DeletePasskey(dto.ID); err != nil {
+		return response.ErrServerError
+	}
+	global.Logger.Info("Passkey deleted", zap.Uint("adminId", adminId), zap.Uint("passkeyId", dto.ID))
+	return nil
+}
+
+// 获取个人的通行密钥
+func getMyPasskey(adminId, id uint) (*entity.SysAdminCredential, error) {
+	found, err := PasskeyDao.GetPasskey(adminId, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrPasskeyNotExists
+		}
+		return nil, response.ErrServerError
+	}
+	return found, nil
+}
+
+// 开始免密码登录，由认证器选择通行密钥
+func (s *PasskeyService) BeginLogin() (*entity.PasskeyOptionsVo, error) {
+	if !passkey.Enabled() || !passkey.Config().Passwordless {
+		return nil, response.ErrPasskeyDisabled
+	}
+	options, data, err := passkey.BeginDiscoverableLogin()
+	if err != nil {
+		global.Logger.Error("Failed to begin passkey login", zap.Error(err))
+		return nil, response.ErrServerError
+	}
+	return savePasskeySession(&entity.PasskeySession{
+		Purpose: entity.PasskeyPurposeLogin,
+		Data:    *data,
+	}, options)
+}
+
+// 通行密钥免密码登录：根据认证器返回的用户句柄找到用户，验证签名后签发令牌
+func (s *PasskeyService) Login(ip, browser, Os, device string, dto *entity.PasskeyLoginDto) (*entity.SysAdmin, *jwt.TokenPair, error) {
+	if !passkey.Enabled() || !passkey.Config().Passwordless {
+		return nil, nil, response.ErrPasskeyDisabled
+	}
+	session, err := takePasskeySession(dto.SessionID, entity.PasskeyPurposeLogin)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		user     *entity.SysAdmin
+		passkeys []entity.SysAdminCredential
+	)
+	findUser := func(adminId uint) (*passkey.User, error) {
+		found, err := SysAdminDao.GetAdminById(adminId)
+		if err != nil {
+			return nil, err
+		}
+		passkeyUser, list, err := loadPasskeyUser(found)
+		if err != nil {
+			return nil, err
+		}
+		user, passkeys = found, list
+		return passkeyUser, nil
+	}
+	_, credential, err := passkey.FinishDiscoverableLogin(session.Data, dto.Credential, findUser)
+	if err != nil {
+		username := ""
+		if user != nil {
+			username = user.Username
+		}
+		global.Logger.Warn("Passkey login failed", zap.String("username", username), zap.String("ip", ip), zap.Error(err))
+		return nil, nil, passkeyLoginFailed(user, ip, browser, Os)
+	}
+
+	// 检查账号和IP是否已被锁定
+	if err := checkLoginLocked(user.Username, ip); err != nil {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, err.Error(), 2)
+		return nil, nil, err
+	}
+	if err := usePasskey(user, passkeys, credential, ip); err != nil {
+		return nil, nil, passkeyLoginFailed(user, ip, browser, Os)
+	}
+	// 检测账号状态
+	if user.Status == 2 {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "账号已停用", 2)
+		return nil, nil, response.ErrAdminDisabled
+	}
+	if user.Status == 3 {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "账号未激活", 2)
+		return nil, nil, response.ErrAdminPending
+	}
+	if user.IsServiceAccount {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "服务账号不能登录", 2)
+		return nil, nil, response.ErrServiceAccountLogin
+	}
+	// 只允许单点登录的账号
+	if user.PasswordLoginDisabled {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "已禁用密码登录", 2)
+		return nil, nil, response.ErrPasswordLoginDisabled
+	}
+
+	// 生成token
+	tokenPair, err := issueTokenPair(user, entity.LoginMethodPasskey, ip, browser, Os, device)
+	if err != nil {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "服务器故障", 2)
+		return nil, nil, response.ErrServerError
+	}
+
+	// 登录成功
+	clearLoginFailures(user.Username)
+	SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "登录成功", 1)
+	return user, tokenPair, nil
+}
+
+// 通行密钥免密码登录失败：与密码登录一样记录登录日志和失败次数，失败次数过多时锁定账号或IP；
+// 认证器返回的用户不存在时只记录IP的失败次数
+func passkeyLoginFailed(user *entity.SysAdmin, ip, browser, Os string) error {
+	username := ""
+	if user != nil {
+		username = user.Username
+	}
+	SysLogDao.CreateLoginLog(username, ip, browser, Os, "通行密钥验证失败", 2)
+	var lockErr error
+	if user != nil {
+		lockErr = recordLoginFailure(user.Username, ip)
+	} else {
+		lockErr = recordIPFailure(ip)
+	}
+	if lockErr != nil {
+		SysLogDao.CreateLoginLog(username, ip, browser, Os, lockErr.Error(), 2)
+		return lockErr
+	}
+	return response.ErrPasskeyVerifyFailed
+}
+
+// 开始通行密钥两步验证，只允许使用挑战对应用户已注册的通行密钥
+func (s *PasskeyService) BeginTwoFactor(dto *entity.TwoFactorPasskeyOptionsDto) (*entity.PasskeyOptionsVo, error) {
+	if !passkey.Enabled() {
+		return nil, response.ErrPasskeyDisabled
+	}
+	challenge, err := getLoginChallenge(dto.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := getAdmin(challenge.AdminID)
+	if err != nil {
+		return nil, err
+	}
+	passkeyUser, passkeys, err := loadPasskeyUser(user)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if len(passkeys) == 0 {
+		return nil, response.ErrPasskeyNotExists
+	}
+	options, data, err := passkey.BeginLogin(passkeyUser)
+	if err != nil {
+		global.Logger.Error("Failed to begin passkey two factor", zap.Uint("adminId", user.ID), zap.Error(err))
+		return nil, response.ErrServerError
+	}
+	return savePasskeySession(&entity.PasskeySession{
+		Purpose:        entity.PasskeyPurposeTwoFactor,
+		AdminID:        user.ID,
+		ChallengeToken: dto.ChallengeToken,
+		Data:           *data,
+	}, options)
+}
+
+// 通行密钥两步验证登录：验证通过后签发令牌，验证失败计入挑战的验证次数和登录失败次数
+func (s *PasskeyService) LoginTwoFactor(dto *entity.TwoFactorPasskeyLoginDto) (*entity.SysAdmin, *jwt.TokenPair, error) {
+	if !passkey.Enabled() {
+		return nil, nil, response.ErrPasskeyDisabled
+	}
+	session, err := takePasskeySession(dto.SessionID, entity.PasskeyPurposeTwoFactor)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session.ChallengeToken != dto.ChallengeToken {
+		return nil, nil, response.ErrPasskeySessionInvalid
+	}
+	challenge, user, err := verifyingLoginChallenge(dto.ChallengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.ID != session.AdminID {
+		return nil, nil, response.ErrPasskeySessionInvalid
+	}
+	passkeyUser, passkeys, err := loadPasskeyUser(user)
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	credential, err := passkey.FinishLogin(passkeyUser, session.Data, dto.Credential)
+	if err == nil {
+		err = usePasskey(user, passkeys, credential, challenge.Ip)
+	} else {
+		global.Logger.Warn("Passkey two factor failed", zap.Uint("adminId", user.ID), zap.Error(err))
+	}
+	if err != nil {
+		return nil, nil, loginChallengeFailed(dto.ChallengeToken, challenge, user, "通行密钥验证失败", response.ErrPasskeyVerifyFailed)
+	}
+	_ = TwoFactorDao.DeleteChallenge(dto.ChallengeToken)
+
+	tokenPair, err := completeLoginChallenge(challenge, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokenPair, nil
+}
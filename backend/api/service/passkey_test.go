@@ -0,0 +1,611 @@
+//go:build cgo
+
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+	"go-admin-server/pkg/passkey"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost"
+)
+
+// 启用通行密钥免密码登录，测试结束后关闭
+func setupTestPasskey(t *testing.T) {
+	t.Helper()
+	if err := passkey.Setup(config.WebAuthn{Enabled: true, RPID: testRPID, Origins: []string{testOrigin}, Passwordless: true}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = passkey.Setup(config.WebAuthn{}) })
+}
+
+// 测试用的软件认证器，使用 P-256 密钥签名
+type testAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	handle    []byte // 用户句柄
+	signCount uint32
+}
+
+func newTestAuthenticator(t *testing.T, adminId uint) *testAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		t.Fatal(err)
+	}
+	return &testAuthenticator{key: key, id: id, handle: passkey.UserHandle(adminId)}
+}
+
+// COSE格式的公钥
+func (a *testAuthenticator) publicKey(t *testing.T) []byte {
+	t.Helper()
+	point, err := a.key.PublicKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := point.Bytes() // 0x04 | X | Y
+	data, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: raw[1:33],
+		YCoord: raw[33:],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// 把认证器的通行密钥保存到用户名下
+func (a *testAuthenticator) register(t *testing.T, adminId uint) {
+	t.Helper()
+	mustCreate(t, &entity.SysAdminCredential{
+		AdminID:         adminId,
+		Name:            "test",
+		CredentialID:    base64.RawURLEncoding.EncodeToString(a.id),
+		PublicKey:       a.publicKey(t),
+		AttestationType: "none",
+	})
+}
+
+// 生成注册数据，返回 navigator.credentials.create() 的结果，不提供证明(attestation: none)
+func (a *testAuthenticator) attest(t *testing.T, challenge protocol.URLEncodedBase64) []byte {
+	t.Helper()
+	clientData, _ := json.Marshal(map[string]string{
+		"type":      "webauthn.create",
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    testOrigin,
+	})
+	rpIdHash := sha256.Sum256([]byte(testRPID))
+	authData := append(rpIdHash[:], byte(protocol.FlagUserPresent|protocol.FlagUserVerified|protocol.FlagAttestedCredentialData))
+	authData = binary.BigEndian.AppendUint32(authData, a.signCount)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.id)))
+	authData = append(append(authData, a.id...), a.publicKey(t)...)
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := base64.RawURLEncoding.EncodeToString
+	body, _ := json.Marshal(map[string]any{
+		"id":    encode(a.id),
+		"rawId": encode(a.id),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    encode(clientData),
+			"attestationObject": encode(attestation),
+		},
+	})
+	return body
+}
+
+// 对挑战签名，返回 navigator.credentials.get() 的结果
+func (a *testAuthenticator) assert(t *testing.T, challenge protocol.URLEncodedBase64) []byte {
+	t.Helper()
+	clientData, _ := json.Marshal(map[string]string{
+		"type":      "webauthn.get",
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    testOrigin,
+	})
+	a.signCount++
+	rpIdHash := sha256.Sum256([]byte(testRPID))
+	authData := append(rpIdHash[:], byte(protocol.FlagUserPresent|protocol.FlagUserVerified))
+	authData = binary.BigEndian.AppendUint32(authData, a.signCount)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := base64.RawURLEncoding.EncodeToString
+	body, _ := json.Marshal(map[string]any{
+		"id":    encode(a.id),
+		"rawId": encode(a.id),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    encode(clientData),
+			"authenticatorData": encode(authData),
+			"signature":         encode(signature),
+			"userHandle":        encode(a.handle),
+		},
+	})
+	return body
+}
+
+// 发起免密码登录，返回会话id和挑战值
+func beginPasskeyLogin(t *testing.T) (string, protocol.URLEncodedBase64) {
+	t.Helper()
+	vo, err := (&PasskeyService{}).BeginLogin()
+	if err != nil {
+		t.Fatalf("BeginLogin() error = %v", err)
+	}
+	return vo.SessionID, vo.Options.(*protocol.CredentialAssertion).Response.Challenge
+}
+
+// 使用认证器完成注册
+func registerPasskey(t *testing.T, adminId uint, authenticator *testAuthenticator) (*entity.SysAdminCredential, error) {
+	t.Helper()
+	vo, err := (&PasskeyService{}).BeginRegistration(adminId)
+	if err != nil {
+		t.Fatalf("BeginRegistration() error = %v", err)
+	}
+	return (&PasskeyService{}).FinishRegistration(adminId, &entity.FinishPasskeyRegistrationDto{
+		SessionID:  vo.SessionID,
+		Name:       " YubiKey ",
+		Credential: authenticator.attest(t, vo.Options.(*protocol.CredentialCreation).Response.Challenge),
+	})
+}
+
+// 使用认证器免密码登录
+func passkeyLogin(t *testing.T, authenticator *testAuthenticator) (*entity.SysAdmin, *jwt.TokenPair, error) {
+	t.Helper()
+	sessionId, challenge := beginPasskeyLogin(t)
+	user, pair, err := (&PasskeyService{}).Login("10.0.0.1", "Chrome", "Linux", "", &entity.PasskeyLoginDto{
+		SessionID:  sessionId,
+		Credential: authenticator.assert(t, challenge),
+	})
+	if err == nil {
+		waitSessionLocation(t, parseTestToken(t, pair.AccessToken).SessionID)
+	}
+	return user, pair, err
+}
+
+// 返回业务错误码，不是业务错误时返回0
+func errorCode(err error) int {
+	var businessErr *response.BusinessError
+	if errors.As(err, &businessErr) {
+		return businessErr.Code
+	}
+	return 0
+}
+
+func TestPasskeyLoginFailureLock(t *testing.T) {
+	tests := []struct {
+		name       string
+		credential func(t *testing.T, registered *testAuthenticator, challenge protocol.URLEncodedBase64) []byte
+		lockCode   int // 失败次数达到阈值后的错误码
+	}{
+		{
+			name: "invalid signature",
+			credential: func(t *testing.T, registered *testAuthenticator, challenge protocol.URLEncodedBase64) []byte {
+				// 使用其他密钥签名，凭据id和用户句柄与已注册的通行密钥相同
+				forged := newTestAuthenticator(t, 0)
+				forged.id, forged.handle = registered.id, registered.handle
+				return forged.assert(t, challenge)
+			},
+			lockCode: response.CodeAccountLocked,
+		},
+		{
+			name: "unregistered passkey",
+			credential: func(t *testing.T, registered *testAuthenticator, challenge protocol.URLEncodedBase64) []byte {
+				other := newTestAuthenticator(t, 0)
+				other.handle = registered.handle
+				return other.assert(t, challenge)
+			},
+			lockCode: response.CodeAccountLocked,
+		},
+		{
+			name: "unknown user",
+			credential: func(t *testing.T, _ *testAuthenticator, challenge protocol.URLEncodedBase64) []byte {
+				return newTestAuthenticator(t, 99).assert(t, challenge)
+			},
+			lockCode: response.CodeIPLocked,
+		},
+		{
+			name: "malformed credential",
+			credential: func(*testing.T, *testAuthenticator, protocol.URLEncodedBase64) []byte {
+				return []byte(`{"id":"x"}`)
+			},
+			lockCode: response.CodeIPLocked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			setupTestPasskey(t)
+			global.Config.LoginSecurity = config.LoginSecurity{MaxUserFailures: 3, MaxIPFailures: 3}
+			alice := newTestAdmin(t, "alice", "")
+			mustCreate(t, alice)
+			registered := newTestAuthenticator(t, alice.ID)
+			registered.register(t, alice.ID)
+
+			passkeys := &PasskeyService{}
+			for i := 1; i <= 3; i++ {
+				sessionId, challenge := beginPasskeyLogin(t)
+				_, _, err := passkeys.Login("10.0.0.1", "Chrome", "Linux", "", &entity.PasskeyLoginDto{
+					SessionID:  sessionId,
+					Credential: tt.credential(t, registered, challenge),
+				})
+				if i < 3 && err != response.ErrPasskeyVerifyFailed {
+					t.Fatalf("attempt %d: Login() error = %v, want %v", i, err, response.ErrPasskeyVerifyFailed)
+				}
+				if i == 3 && errorCode(err) != tt.lockCode {
+					t.Fatalf("attempt %d: Login() error = %v, want code %d", i, err, tt.lockCode)
+				}
+			}
+
+			// 锁定后使用正确的通行密钥也不能登录
+			sessionId, challenge := beginPasskeyLogin(t)
+			_, _, err := passkeys.Login("10.0.0.1", "Chrome", "Linux", "", &entity.PasskeyLoginDto{
+				SessionID:  sessionId,
+				Credential: registered.assert(t, challenge),
+			})
+			if errorCode(err) != tt.lockCode {
+				t.Errorf("Login() after lock error = %v, want code %d", err, tt.lockCode)
+			}
+			var failures int64
+			global.DB.Model(&entity.SysLoginLog{}).Where("login_status = ?", 2).Count(&failures)
+			if failures < 3 {
+				t.Errorf("failed login logs = %d, want at least 3", failures)
+			}
+		})
+	}
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(t *testing.T, alice, bob *entity.SysAdmin) (*entity.SysAdminCredential, error)
+		err      error
+	}{
+		{
+			name: "registered",
+			register: func(t *testing.T, alice, _ *entity.SysAdmin) (*entity.SysAdminCredential, error) {
+				return registerPasskey(t, alice.ID, newTestAuthenticator(t, alice.ID))
+			},
+		},
+		{
+			name: "passkey of another admin",
+			register: func(t *testing.T, alice, bob *entity.SysAdmin) (*entity.SysAdminCredential, error) {
+				authenticator := newTestAuthenticator(t, bob.ID)
+				authenticator.register(t, bob.ID)
+				return registerPasskey(t, alice.ID, authenticator)
+			},
+			err: response.ErrPasskeyExists,
+		},
+		{
+			name: "session of another admin",
+			register: func(t *testing.T, alice, bob *entity.SysAdmin) (*entity.SysAdminCredential, error) {
+				vo, err := (&PasskeyService{}).BeginRegistration(bob.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return (&PasskeyService{}).FinishRegistration(alice.ID, &entity.FinishPasskeyRegistrationDto{
+					SessionID:  vo.SessionID,
+					Name:       "YubiKey",
+					Credential: newTestAuthenticator(t, alice.ID).attest(t, vo.Options.(*protocol.CredentialCreation).Response.Challenge),
+				})
+			},
+			err: response.ErrPasskeySessionInvalid,
+		},
+		{
+			name: "wrong challenge",
+			register: func(t *testing.T, alice, _ *entity.SysAdmin) (*entity.SysAdminCredential, error) {
+				vo, err := (&PasskeyService{}).BeginRegistration(alice.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return (&PasskeyService{}).FinishRegistration(alice.ID, &entity.FinishPasskeyRegistrationDto{
+					SessionID:  vo.SessionID,
+					Name:       "YubiKey",
+					Credential: newTestAuthenticator(t, alice.ID).attest(t, []byte("another challenge")),
+				})
+			},
+			err: response.ErrPasskeyVerifyFailed,
+		},
+		{
+			name: "session used twice",
+			register: func(t *testing.T, alice, _ *entity.SysAdmin) (*entity.SysAdminCredential, error) {
+				vo, err := (&PasskeyService{}).BeginRegistration(alice.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				dto := &entity.FinishPasskeyRegistrationDto{
+					SessionID:  vo.SessionID,
+					Name:       "YubiKey",
+					Credential: newTestAuthenticator(t, alice.ID).attest(t, vo.Options.(*protocol.CredentialCreation).Response.Challenge),
+				}
+				if _, err := (&PasskeyService{}).FinishRegistration(alice.ID, dto); err != nil {
+					t.Fatal(err)
+				}
+				dto.Credential = newTestAuthenticator(t, alice.ID).attest(t, vo.Options.(*protocol.CredentialCreation).Response.Challenge)
+				return (&PasskeyService{}).FinishRegistration(alice.ID, dto)
+			},
+			err: response.ErrPasskeySessionInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			setupTestPasskey(t)
+			alice, bob := newTestAdmin(t, "alice", ""), newTestAdmin(t, "bob", "")
+			mustCreate(t, alice)
+			mustCreate(t, bob)
+
+			created, err := tt.register(t, alice, bob)
+			if err != tt.err {
+				t.Fatalf("FinishRegistration() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if created.AdminID != alice.ID || created.Name != "YubiKey" || created.AttestationType != "none" {
+				t.Errorf("registered passkey = %+v", created)
+			}
+			// 注册后出现在个人的通行密钥列表中
+			passkeys, _ := (&PasskeyService{}).GetMyPasskeys(alice.ID)
+			if len(passkeys) != 1 || passkeys[0].ID != created.ID {
+				t.Errorf("GetMyPasskeys() = %+v", passkeys)
+			}
+		})
+	}
+}
+
+func TestPasskeyRegistrationDisabled(t *testing.T) {
+	setupTestEnv(t)
+	alice := newTestAdmin(t, "alice", "")
+	mustCreate(t, alice)
+	if _, err := (&PasskeyService{}).BeginRegistration(alice.ID); err != response.ErrPasskeyDisabled {
+		t.Errorf("BeginRegistration() error = %v, want %v", err, response.ErrPasskeyDisabled)
+	}
+	// 只启用通行密钥两步验证时不能免密码登录
+	if err := passkey.Setup(config.WebAuthn{Enabled: true, RPID: testRPID, Origins: []string{testOrigin}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = passkey.Setup(config.WebAuthn{}) })
+	if _, err := (&PasskeyService{}).BeginLogin(); err != response.ErrPasskeyDisabled {
+		t.Errorf("BeginLogin() error = %v, want %v", err, response.ErrPasskeyDisabled)
+	}
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, alice *entity.SysAdmin, authenticator *testAuthenticator)
+		err     error
+	}{
+		{name: "logged in"},
+		{
+			name: "sign count not increased",
+			prepare: func(t *testing.T, _ *entity.SysAdmin, authenticator *testAuthenticator) {
+				if _, _, err := passkeyLogin(t, authenticator); err != nil {
+					t.Fatal(err)
+				}
+				// 复制的认证器使用相同的签名计数
+				authenticator.signCount--
+			},
+			err: response.ErrPasskeyVerifyFailed,
+		},
+		{
+			name: "disabled admin",
+			prepare: func(t *testing.T, alice *entity.SysAdmin, _ *testAuthenticator) {
+				global.DB.Model(alice).Update("status", 2)
+			},
+			err: response.ErrAdminDisabled,
+		},
+		{
+			name: "password login disabled",
+			prepare: func(t *testing.T, alice *entity.SysAdmin, _ *testAuthenticator) {
+				global.DB.Model(alice).Update("password_login_disabled", true)
+			},
+			err: response.ErrPasswordLoginDisabled,
+		},
+		{
+			name: "deleted passkey",
+			prepare: func(t *testing.T, alice *entity.SysAdmin, _ *testAuthenticator) {
+				passkeys, _ := (&PasskeyService{}).GetMyPasskeys(alice.ID)
+				if err := (&PasskeyService{}).DeletePasskey(alice.ID, &entity.DeletePasskeyDto{ID: passkeys[0].ID}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			err: response.ErrPasskeyVerifyFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			setupTestPasskey(t)
+			alice := newTestAdmin(t, "alice", "")
+			mustCreate(t, alice)
+			authenticator := newTestAuthenticator(t, alice.ID)
+			authenticator.register(t, alice.ID)
+			if tt.prepare != nil {
+				tt.prepare(t, alice, authenticator)
+			}
+
+			user, pair, err := passkeyLogin(t, authenticator)
+			if err != tt.err {
+				t.Fatalf("Login() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			claims := parseTestToken(t, pair.AccessToken)
+			if user.ID != alice.ID || claims.JwtAdmin.ID != alice.ID {
+				t.Errorf("logged in as %d, token for %d, want %d", user.ID, claims.JwtAdmin.ID, alice.ID)
+			}
+			if session, _ := SessionDao.GetSession(claims.SessionID); session == nil || session.LoginMethod != entity.LoginMethodPasskey {
+				t.Errorf("login session = %+v", session)
+			}
+			// 记录签名计数，之后的登录签名计数必须增加
+			passkeys, _ := (&PasskeyService{}).GetMyPasskeys(alice.ID)
+			if len(passkeys) != 1 || passkeys[0].SignCount != authenticator.signCount {
+				t.Errorf("passkey sign count = %+v, want %d", passkeys, authenticator.signCount)
+			}
+		})
+	}
+}
+
+func TestPasskeyLoginSessionUsedOnce(t *testing.T) {
+	setupTestEnv(t)
+	setupTestPasskey(t)
+	alice := newTestAdmin(t, "alice", "")
+	mustCreate(t, alice)
+	authenticator := newTestAuthenticator(t, alice.ID)
+	authenticator.register(t, alice.ID)
+
+	sessionId, challenge := beginPasskeyLogin(t)
+	dto := &entity.PasskeyLoginDto{SessionID: sessionId, Credential: authenticator.assert(t, challenge)}
+	_, pair, err := (&PasskeyService{}).Login("10.0.0.1", "Chrome", "Linux", "", dto)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	waitSessionLocation(t, parseTestToken(t, pair.AccessToken).SessionID)
+	dto.Credential = authenticator.assert(t, challenge)
+	if _, _, err := (&PasskeyService{}).Login("10.0.0.1", "Chrome", "Linux", "", dto); err != response.ErrPasskeySessionInvalid {
+		t.Errorf("Login() with used session error = %v, want %v", err, response.ErrPasskeySessionInvalid)
+	}
+}
+
+func TestManageMyPasskeys(t *testing.T) {
+	setupTestEnv(t)
+	setupTestPasskey(t)
+	alice, bob := newTestAdmin(t, "alice", ""), newTestAdmin(t, "bob", "")
+	mustCreate(t, alice)
+	mustCreate(t, bob)
+	newTestAuthenticator(t, alice.ID).register(t, alice.ID)
+	newTestAuthenticator(t, bob.ID).register(t, bob.ID)
+	passkeys, _ := (&PasskeyService{}).GetMyPasskeys(alice.ID)
+	bobPasskeys, _ := (&PasskeyService{}).GetMyPasskeys(bob.ID)
+	own, others := passkeys[0].ID, bobPasskeys[0].ID
+
+	tests := []struct {
+		name      string
+		operation func(id uint) error
+	}{
+		{"rename", func(id uint) error {
+			return (&PasskeyService{}).RenamePasskey(alice.ID, &entity.RenamePasskeyDto{ID: id, Name: " Laptop "})
+		}},
+		{"delete", func(id uint) error {
+			return (&PasskeyService{}).DeletePasskey(alice.ID, &entity.DeletePasskeyDto{ID: id})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 不能修改其他用户的通行密钥
+			if err := tt.operation(others); err != response.ErrPasskeyNotExists {
+				t.Errorf("%s passkey of another admin error = %v, want %v", tt.name, err, response.ErrPasskeyNotExists)
+			}
+			if err := tt.operation(own); err != nil {
+				t.Errorf("%s own passkey error = %v", tt.name, err)
+			}
+		})
+	}
+	if passkeys, _ := (&PasskeyService{}).GetMyPasskeys(alice.ID); len(passkeys) != 0 {
+		t.Errorf("passkeys after delete = %+v", passkeys)
+	}
+	if passkeys, _ := (&PasskeyService{}).GetMyPasskeys(bob.ID); len(passkeys) != 1 || passkeys[0].Name != "test" {
+		t.Errorf("passkeys of another admin = %+v", passkeys)
+	}
+}
+
+func TestPasskeyTwoFactor(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool // 是否使用用户已注册的通行密钥
+		err   error
+	}{
+		{"verified", true, nil},
+		{"passkey of another admin", false, response.ErrPasskeyVerifyFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			setupTestPasskey(t)
+			alice, bob := newTestAdmin(t, "alice", ""), newTestAdmin(t, "bob", "")
+			alice.TotpEnabled = true
+			mustCreate(t, alice)
+			mustCreate(t, bob)
+			authenticator := newTestAuthenticator(t, alice.ID)
+			authenticator.register(t, alice.ID)
+			if !tt.valid {
+				authenticator = newTestAuthenticator(t, bob.ID)
+				authenticator.register(t, bob.ID)
+			}
+
+			_, pair, challenge, err := (&SysAdminService{}).Login("10.0.0.1", "Chrome", "Linux", "", &entity.LoginDto{
+				Username:     "alice",
+				Password:     testOldPassword,
+				CaptchaID:    testCaptcha(t),
+				CaptchaImage: "1234",
+			})
+			if err != nil || pair != nil || challenge == nil || !challenge.PasskeyAvailable {
+				t.Fatalf("password Login() = %+v, %+v, %v, want passkey challenge", pair, challenge, err)
+			}
+			vo, err := (&PasskeyService{}).BeginTwoFactor(&entity.TwoFactorPasskeyOptionsDto{ChallengeToken: challenge.ChallengeToken})
+			if err != nil {
+				t.Fatalf("BeginTwoFactor() error = %v", err)
+			}
+			user, pair, err := (&PasskeyService{}).LoginTwoFactor(&entity.TwoFactorPasskeyLoginDto{
+				ChallengeToken: challenge.ChallengeToken,
+				SessionID:      vo.SessionID,
+				Credential:     authenticator.assert(t, vo.Options.(*protocol.CredentialAssertion).Response.Challenge),
+			})
+			if err != tt.err {
+				t.Fatalf("LoginTwoFactor() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			claims := parseTestToken(t, pair.AccessToken)
+			waitSessionLocation(t, claims.SessionID)
+			if user.ID != alice.ID || claims.JwtAdmin.ID != alice.ID {
+				t.Errorf("logged in as %d, token for %d, want %d", user.ID, claims.JwtAdmin.ID, alice.ID)
+			}
+			// 挑战只能使用一次
+			if _, err := (&PasskeyService{}).BeginTwoFactor(&entity.TwoFactorPasskeyOptionsDto{ChallengeToken: challenge.ChallengeToken}); err != response.ErrChallengeInvalid {
+				t.Errorf("BeginTwoFactor() with used challenge error = %v, want %v", err, response.ErrChallengeInvalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// 密码策略：密码强度、禁止重复使用最近的密码、密码最长使用时间
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/validator"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 校验新密码是否符合密码策略，以及是否与当前密码或最近使用过的密码相同
+func checkNewPassword(user *entity.SysAdmin, password string) error {
+	if err := validator.CheckPassword(password); err != nil {
+		return response.ErrPasswordPolicy(err.Error())
+	}
+	historyCount := validator.PasswordPolicy().HistoryCount
+	if user == nil || historyCount <= 0 {
+		return nil
+	}
+	if encrypt.VerifyPassword(user.Password, password) {
+		return response.ErrPasswordReused
+	}
+	recentPasswords, err := PasswordHistoryDao.GetRecentPasswords(user.ID, historyCount)
+	if err != nil {
+		return response.ErrServerError
+	}
+	for _, hash := range recentPasswords {
+		if encrypt.VerifyPassword(hash, password) {
+			return response.ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// 加密新密码并更新密码修改时间，需要调用方保存用户
+func applyNewPassword(user *entity.SysAdmin, password string) error {
+	hashPassword, err := encrypt.EncryptPassword(password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashPassword
+	user.PasswordChangedAt = &utils.HTime{Time: time.Now()}
+	return nil
+}
+
+// 保存用户后记录密码历史
+func recordPasswordHistory(user *entity.SysAdmin) {
+	historyCount := validator.PasswordPolicy().HistoryCount
+	if historyCount <= 0 {
+		return
+	}
+	if err := PasswordHistoryDao.AddPasswordHistory(user.ID, user.Password, historyCount); err != nil {
+		global.Logger.Error("Failed to record password history", zap.Uint("adminId", user.ID), zap.Error(err))
+	}
+}
+
+// 判断密码是否已超过最长使用时间，未记录密码修改时间时以创建时间为准
+func isPasswordExpired(user *entity.SysAdmin) bool {
+	maxAge := validator.PasswordPolicy().MaxAge
+	if maxAge <= 0 {
+		return false
+	}
+	changedAt := user.CreatedAt.Time
+	if user.PasswordChangedAt != nil {
+		changedAt = user.PasswordChangedAt.Time
+	}
+	if changedAt.IsZero() {
+		return false
+	}
+	return time.Since(changedAt) > maxAge
+}
@@ -0,0 +1,216 @@
+// 通过邮件自助重置密码：按用户名或邮箱申请，向账号的邮箱发送一次性的重置链接，链接中的令牌只以摘要保存在redis中。
+// 无论账号是否存在、能否重置，申请接口的响应都相同，避免泄露账号信息
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/mailer"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPasswordResetTTL      = 30 * time.Minute
+	defaultPasswordResetCooldown = time.Minute
+)
+
+type PasswordResetService struct{}
+
+// 获取找回密码配置，未配置的项使用默认值
+func passwordResetConfig() config.PasswordReset {
+	cfg := global.Config.PasswordReset
+	if cfg.TokenTTL <= 0 {
+		cfg.TokenTTL = defaultPasswordResetTTL
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultPasswordResetCooldown
+	}
+	return cfg
+}
+
+// 是否可以通过邮件找回密码
+func passwordResetEnabled() bool {
+	cfg := global.Config.PasswordReset
+	return cfg.Enabled && cfg.ResetURL != "" && mailer.Enabled()
+}
+
+// 申请重置密码：验证码正确即返回成功，符合条件的账号在后台发送重置邮件
+func (s *PasswordResetService) ForgotPassword(ip string, dto *entity.ForgotPasswordDto) error {
+	if !passwordResetEnabled() {
+		return response.ErrPasswordResetDisabled
+	}
+	if !captchaStore.Verify(dto.CaptchaID, dto.CaptchaImage, true) {
+		return response.ErrCaptchaError
+	}
+	user, err := findPasswordResetAdmin(strings.TrimSpace(dto.Account))
+	if err != nil {
+		return err
+	}
+	if user == nil || !passwordResettable(user) {
+		global.Logger.Info("Password reset requested for unknown or ineligible account", zap.String("account", dto.Account), zap.String("ip", ip))
+		return nil
+	}
+
+	cfg := passwordResetConfig()
+	// 冷却期内不重复发送，避免被用来向用户的邮箱大量发送邮件
+	acquired, err := PasswordResetDao.AcquireCooldown(user.ID, cfg.Cooldown)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if !acquired {
+		global.Logger.Info("Password reset requested during cooldown", zap.Uint("adminId", user.ID), zap.String("ip", ip))
+		return nil
+	}
+	token, err := utils.RandomHex(32)
+	if err != nil {
+		return response.ErrServerError
+	}
+	resetToken := &entity.PasswordResetToken{AdminID: user.ID, PasswordChangedAt: passwordChangedStamp(user)}
+	if err := PasswordResetDao.SaveToken(encrypt.HashToken(token), resetToken, cfg.TokenTTL); err != nil {
+		return response.ErrServerError
+	}
+
+	data := map[string]any{
+		"Username":  user.Username,
+		"Nickname":  user.Nickname,
+		"ResetURL":  tokenLink(cfg.ResetURL, token),
+		"ExpiresIn": formatDuration(cfg.TokenTTL),
+		"IP":        ip,
+	}
+	// 后台发送，响应时间不随账号是否存在而变化
+	go sendPasswordMail(user, "重置密码", "password_reset", data)
+	global.Logger.Info("Password reset mail requested", zap.Uint("adminId", user.ID), zap.String("ip", ip))
+	return nil
+}
+
+// 使用邮件中的令牌重置密码，成功后令牌失效，吊销用户的全部令牌并解除登录锁定
+func (s *PasswordResetService) ResetPassword(ip string, dto *entity.PasswordResetDto) error {
+	if !passwordResetEnabled() {
+		return response.ErrPasswordResetDisabled
+	}
+	if dto.NewPassword != dto.RePassword {
+		return response.ErrPasswordInConsistent
+	}
+	tokenHash := encrypt.HashToken(dto.Token)
+	resetToken, err := PasswordResetDao.GetToken(tokenHash)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if resetToken == nil {
+		return response.ErrPasswordResetInvalid
+	}
+	user, err := SysAdminDao.GetAdminById(resetToken.AdminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.ErrPasswordResetInvalid
+		}
+		return response.ErrServerError
+	}
+	// 申请后账号被停用或密码已被修改时链接失效
+	if !passwordResettable(user) || passwordChangedStamp(user) != resetToken.PasswordChangedAt {
+		return response.ErrPasswordResetInvalid
+	}
+	// 先检查密码策略，不符合时链接仍然可用
+	if err := checkNewPassword(user, dto.NewPassword); err != nil {
+		return err
+	}
+	// 取出令牌，并发使用同一链接时只有一个请求成功
+	resetToken, err = PasswordResetDao.TakeToken(tokenHash)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if resetToken == nil {
+		return response.ErrPasswordResetInvalid
+	}
+
+	if err := applyNewPassword(user, dto.NewPassword); err != nil {
+		return response.ErrServerError
+	}
+	if err := SysAdminDao.UpdateAdmin(user); err != nil {
+		return response.ErrServerError
+	}
+	recordPasswordHistory(user)
+	if err := LoginGuardDao.Unlock(userSubject(user.Username)); err != nil {
+		global.Logger.Error("Failed to unlock admin after password reset", zap.Uint("adminId", user.ID), zap.Error(err))
+	}
+	global.Logger.Info("Password reset by mail", zap.Uint("adminId", user.ID), zap.String("ip", ip))
+
+	data := map[string]any{
+		"Username":  user.Username,
+		"Nickname":  user.Nickname,
+		"ChangedAt": user.PasswordChangedAt.Format("2006-01-02 15:04:05"),
+		"IP":        ip,
+	}
+	go sendPasswordMail(user, "密码已重置", "password_changed", data)
+	return revokeAdminTokens(user.ID)
+}
+
+// 按用户名查找账号，找不到且输入的是邮箱时按邮箱查找，多个账号使用同一邮箱时需要使用用户名申请
+func findPasswordResetAdmin(account string) (*entity.SysAdmin, error) {
+	user, err := SysAdminDao.GetAdminByName(account)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.ErrServerError
+	}
+	if !strings.Contains(account, "@") {
+		return nil, nil
+	}
+	users, err := SysAdminDao.GetAdminsByEmail(account)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if len(users) != 1 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
+
+// 可以通过邮件重置密码的账号：已启用、有邮箱、使用本地密码登录。超级管理员通过命令行恢复，服务账号不能登录
+func passwordResettable(user *entity.SysAdmin) bool {
+	return user.Status == 1 && user.Email != "" && user.LdapDN == "" &&
+		!user.IsSuper && !user.IsServiceAccount && !user.PasswordLoginDisabled
+}
+
+// 密码修改时间，用于判断申请后密码是否被修改过
+func passwordChangedStamp(user *entity.SysAdmin) int64 {
+	if user.PasswordChangedAt == nil {
+		return 0
+	}
+	return user.PasswordChangedAt.UnixMilli()
+}
+
+// 邮件中的链接：在前端页面地址后加上 token 参数
+func tokenLink(pageURL, token string) string {
+	separator := "?"
+	if strings.Contains(pageURL, "?") {
+		separator = "&"
+	}
+	return pageURL + separator + "token=" + url.QueryEscape(token)
+}
+
+func formatDuration(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%d 小时", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%d 分钟", int((d+time.Minute-1)/time.Minute))
+}
+
+func sendPasswordMail(user *entity.SysAdmin, subject, template string, data map[string]any) {
+	if err := mailer.SendTemplate([]string{user.Email}, subject, template, data); err != nil {
+		global.Logger.Error("Failed to send password mail", zap.Uint("adminId", user.ID), zap.String("template", template), zap.Error(err))
+	}
+}
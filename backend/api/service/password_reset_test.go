@@ -0,0 +1,340 @@
+//go:build cgo
+
+package service
+
+import (
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/mailer"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+const testOldPassword = "OldPass123"
+
+var resetTokenPattern = regexp.MustCompile(`token=([0-9a-f]+)`)
+
+// 记录发送的邮件
+type fakeMailer struct {
+	sent chan *mailer.Message
+}
+
+func (m *fakeMailer) Send(msg *mailer.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+// 等待后台发送的邮件
+func (m *fakeMailer) wait(t *testing.T) *mailer.Message {
+	t.Helper()
+	select {
+	case msg := <-m.sent:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail sent")
+		return nil
+	}
+}
+
+type passwordResetEnv struct {
+	mr     *miniredis.Miniredis
+	mailer *fakeMailer
+	user   *entity.SysAdmin
+}
+
+// 启用找回密码并创建一个可以重置密码的用户
+func setupPasswordReset(t *testing.T) *passwordResetEnv {
+	t.Helper()
+	mr := setupTestEnv(t)
+	global.Config.PasswordReset = config.PasswordReset{
+		Enabled:  true,
+		ResetURL: "https://admin.example.com/reset-password",
+		TokenTTL: 30 * time.Minute,
+		Cooldown: time.Minute,
+	}
+	if err := mailer.Setup(config.Mail{}); err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeMailer{sent: make(chan *mailer.Message, 10)}
+	mailer.SetMailer(fake)
+	t.Cleanup(func() { mailer.SetMailer(nil) })
+
+	user := newTestAdmin(t, "alice", "alice@example.com")
+	mustCreate(t, user)
+	return &passwordResetEnv{mr: mr, mailer: fake, user: user}
+}
+
+func newTestAdmin(t *testing.T, username, email string) *entity.SysAdmin {
+	t.Helper()
+	hash, err := encrypt.EncryptPassword(testOldPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &entity.SysAdmin{
+		Username:  username,
+		Nickname:  username,
+		Password:  hash,
+		Email:     email,
+		Status:    1,
+		CreatedAt: utils.HTime{Time: time.Now()},
+	}
+}
+
+// 保存一个验证码，返回验证码id
+func testCaptcha(t *testing.T) string {
+	t.Helper()
+	id, err := utils.RandomHex(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := captchaStore.Set(id, "1234"); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// 申请重置密码并从邮件中取出令牌
+func (e *passwordResetEnv) requestToken(t *testing.T, account string) string {
+	t.Helper()
+	dto := &entity.ForgotPasswordDto{Account: account, CaptchaID: testCaptcha(t), CaptchaImage: "1234"}
+	if err := (&PasswordResetService{}).ForgotPassword("127.0.0.1", dto); err != nil {
+		t.Fatalf("ForgotPassword() error = %v", err)
+	}
+	msg := e.mailer.wait(t)
+	if len(msg.To) != 1 || msg.To[0] != e.user.Email {
+		t.Fatalf("mail sent to %v, want %s", msg.To, e.user.Email)
+	}
+	match := resetTokenPattern.FindStringSubmatch(msg.Text)
+	if match == nil {
+		t.Fatalf("no reset link in mail: %s", msg.Text)
+	}
+	return match[1]
+}
+
+func resetPassword(token, password string) error {
+	return (&PasswordResetService{}).ResetPassword("127.0.0.1", &entity.PasswordResetDto{
+		Token:       token,
+		NewPassword: password,
+		RePassword:  password,
+	})
+}
+
+// 令牌摘要之外不应在redis中保存令牌
+func resetTokenKeys(mr *miniredis.Miniredis) []string {
+	var keys []string
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, global.PasswordResetPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestPasswordResetFlow(t *testing.T) {
+	env := setupPasswordReset(t)
+	subject := userSubject(env.user.Username)
+	env.mr.Set(global.LoginLockPrefix+subject, "1")
+
+	token := env.requestToken(t, "alice")
+	if keys := resetTokenKeys(env.mr); len(keys) != 1 || keys[0] != global.PasswordResetPrefix+encrypt.HashToken(token) {
+		t.Fatalf("reset token keys = %v, want only the token hash", keys)
+	}
+
+	if err := resetPassword(token, "NewPass456"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	user, err := SysAdminDao.GetAdminById(env.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encrypt.VerifyPassword(user.Password, "NewPass456") {
+		t.Error("password was not changed")
+	}
+	if user.PasswordChangedAt == nil {
+		t.Error("password changed time was not recorded")
+	}
+	if env.mr.Exists(global.LoginLockPrefix + subject) {
+		t.Error("login lock was not cleared")
+	}
+	if version, _ := env.mr.Get(global.TokenVersionPrefix + strconv.FormatUint(uint64(user.ID), 10)); version != "1" {
+		t.Errorf("token version = %q, want tokens to be revoked", version)
+	}
+	if msg := env.mailer.wait(t); !strings.Contains(msg.Subject, "密码已重置") {
+		t.Errorf("notification subject = %q", msg.Subject)
+	}
+
+	// 令牌只能使用一次
+	if err := resetPassword(token, "OtherPass789"); err != response.ErrPasswordResetInvalid {
+		t.Errorf("reuse token error = %v, want %v", err, response.ErrPasswordResetInvalid)
+	}
+	if keys := resetTokenKeys(env.mr); len(keys) != 0 {
+		t.Errorf("reset token keys = %v, want none", keys)
+	}
+}
+
+func TestPasswordResetByEmail(t *testing.T) {
+	env := setupPasswordReset(t)
+	token := env.requestToken(t, " alice@example.com ")
+	if err := resetPassword(token, "NewPass456"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+}
+
+func TestForgotPasswordIneligible(t *testing.T) {
+	tests := []struct {
+		name    string
+		account string
+		modify  func(user *entity.SysAdmin)
+	}{
+		{"unknown account", "nobody", nil},
+		{"unknown email", "nobody@example.com", nil},
+		{"disabled", "alice", func(user *entity.SysAdmin) { user.Status = 2 }},
+		{"no email", "alice", func(user *entity.SysAdmin) { user.Email = "" }},
+		{"super admin", "alice", func(user *entity.SysAdmin) { user.IsSuper = true }},
+		{"service account", "alice", func(user *entity.SysAdmin) { user.IsServiceAccount = true }},
+		{"ldap account", "alice", func(user *entity.SysAdmin) { user.LdapDN = "uid=alice,dc=example,dc=com" }},
+		{"password login disabled", "alice", func(user *entity.SysAdmin) { user.PasswordLoginDisabled = true }},
+		// 多个账号使用同一邮箱时需要使用用户名申请
+		{"shared email", "alice@example.com", func(*entity.SysAdmin) {
+			mustCreate(t, newTestAdmin(t, "alice2", "alice@example.com"))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupPasswordReset(t)
+			if tt.modify != nil {
+				tt.modify(env.user)
+				if err := global.DB.Save(env.user).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+			dto := &entity.ForgotPasswordDto{Account: tt.account, CaptchaID: testCaptcha(t), CaptchaImage: "1234"}
+			// 与成功申请的响应相同
+			if err := (&PasswordResetService{}).ForgotPassword("127.0.0.1", dto); err != nil {
+				t.Fatalf("ForgotPassword() error = %v", err)
+			}
+			if keys := resetTokenKeys(env.mr); len(keys) != 0 {
+				t.Errorf("reset token keys = %v, want none", keys)
+			}
+		})
+	}
+}
+
+func TestForgotPasswordRejected(t *testing.T) {
+	env := setupPasswordReset(t)
+	dto := &entity.ForgotPasswordDto{Account: "alice", CaptchaID: testCaptcha(t), CaptchaImage: "0000"}
+	if err := (&PasswordResetService{}).ForgotPassword("127.0.0.1", dto); err != response.ErrCaptchaError {
+		t.Errorf("wrong captcha error = %v, want %v", err, response.ErrCaptchaError)
+	}
+
+	mailer.SetMailer(nil)
+	dto = &entity.ForgotPasswordDto{Account: "alice", CaptchaID: testCaptcha(t), CaptchaImage: "1234"}
+	if err := (&PasswordResetService{}).ForgotPassword("127.0.0.1", dto); err != response.ErrPasswordResetDisabled {
+		t.Errorf("mail disabled error = %v, want %v", err, response.ErrPasswordResetDisabled)
+	}
+	if err := resetPassword("token", "NewPass456"); err != response.ErrPasswordResetDisabled {
+		t.Errorf("mail disabled error = %v, want %v", err, response.ErrPasswordResetDisabled)
+	}
+	if keys := resetTokenKeys(env.mr); len(keys) != 0 {
+		t.Errorf("reset token keys = %v, want none", keys)
+	}
+}
+
+func TestForgotPasswordCooldown(t *testing.T) {
+	env := setupPasswordReset(t)
+	first := env.requestToken(t, "alice")
+
+	// 冷却期内不发送邮件，之前的链接仍然有效
+	dto := &entity.ForgotPasswordDto{Account: "alice", CaptchaID: testCaptcha(t), CaptchaImage: "1234"}
+	if err := (&PasswordResetService{}).ForgotPassword("127.0.0.1", dto); err != nil {
+		t.Fatalf("ForgotPassword() error = %v", err)
+	}
+	select {
+	case msg := <-env.mailer.sent:
+		t.Fatalf("mail sent during cooldown: %s", msg.Subject)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// 冷却期后重新申请，之前的链接失效
+	env.mr.FastForward(time.Minute)
+	second := env.requestToken(t, "alice")
+	if err := resetPassword(first, "NewPass456"); err != response.ErrPasswordResetInvalid {
+		t.Errorf("old token error = %v, want %v", err, response.ErrPasswordResetInvalid)
+	}
+	if err := resetPassword(second, "NewPass456"); err != nil {
+		t.Errorf("ResetPassword() error = %v", err)
+	}
+}
+
+func TestResetPasswordInvalid(t *testing.T) {
+	t.Run("unknown token", func(t *testing.T) {
+		setupPasswordReset(t)
+		if err := resetPassword("0123456789abcdef", "NewPass456"); err != response.ErrPasswordResetInvalid {
+			t.Errorf("error = %v, want %v", err, response.ErrPasswordResetInvalid)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		env := setupPasswordReset(t)
+		token := env.requestToken(t, "alice")
+		env.mr.FastForward(31 * time.Minute)
+		if err := resetPassword(token, "NewPass456"); err != response.ErrPasswordResetInvalid {
+			t.Errorf("error = %v, want %v", err, response.ErrPasswordResetInvalid)
+		}
+	})
+
+	t.Run("password changed after request", func(t *testing.T) {
+		env := setupPasswordReset(t)
+		token := env.requestToken(t, "alice")
+		env.user.PasswordChangedAt = &utils.HTime{Time: time.Now().Add(time.Second)}
+		if err := global.DB.Save(env.user).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := resetPassword(token, "NewPass456"); err != response.ErrPasswordResetInvalid {
+			t.Errorf("error = %v, want %v", err, response.ErrPasswordResetInvalid)
+		}
+	})
+
+	t.Run("disabled after request", func(t *testing.T) {
+		env := setupPasswordReset(t)
+		token := env.requestToken(t, "alice")
+		env.user.Status = 2
+		if err := global.DB.Save(env.user).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := resetPassword(token, "NewPass456"); err != response.ErrPasswordResetInvalid {
+			t.Errorf("error = %v, want %v", err, response.ErrPasswordResetInvalid)
+		}
+	})
+
+	t.Run("password mismatch", func(t *testing.T) {
+		env := setupPasswordReset(t)
+		token := env.requestToken(t, "alice")
+		dto := &entity.PasswordResetDto{Token: token, NewPassword: "NewPass456", RePassword: "NewPass457"}
+		if err := (&PasswordResetService{}).ResetPassword("127.0.0.1", dto); err != response.ErrPasswordInConsistent {
+			t.Errorf("error = %v, want %v", err, response.ErrPasswordInConsistent)
+		}
+	})
+
+	t.Run("policy failure keeps token", func(t *testing.T) {
+		env := setupPasswordReset(t)
+		token := env.requestToken(t, "alice")
+		var businessErr *response.BusinessError
+		if err := resetPassword(token, "short"); !errors.As(err, &businessErr) || businessErr.Code != response.CodePasswordPolicy {
+			t.Errorf("error = %v, want a password policy error", err)
+		}
+		if err := resetPassword(token, "NewPass456"); err != nil {
+			t.Errorf("ResetPassword() error = %v", err)
+		}
+	})
+}
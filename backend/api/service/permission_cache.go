@@ -0,0 +1,41 @@
+package service
+
+import (
+	"go-admin-server/common/response"
+	"go-admin-server/core/permcache"
+	"go-admin-server/global"
+
+	"go.uber.org/zap"
+)
+
+// 用户角色变化后删除用户的权限缓存
+func invalidateAdminPermissions(adminIds ...uint) error {
+	if err := permcache.InvalidateAdmins(adminIds...); err != nil {
+		global.Logger.Error("Failed to invalidate admin permission cache", zap.Uints("adminIds", adminIds), zap.Error(err))
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 角色变化后删除拥有该角色或其下级角色的用户的权限缓存。
+// 角色从禁用变为启用、或修改父角色时，缓存中还没有记录这些角色，因此同时按授权关系查找用户
+func invalidateRolePermissions(roleIds ...uint) error {
+	if len(roleIds) == 0 {
+		return nil
+	}
+	subtree, err := SysRoleDao.GetRoleSubtreeIds(roleIds)
+	if err != nil {
+		global.Logger.Error("Failed to query sub roles", zap.Uints("roleIds", roleIds), zap.Error(err))
+		return response.ErrServerError
+	}
+	adminIds, err := SysRoleDao.GetRolesAdminIds(subtree)
+	if err != nil {
+		global.Logger.Error("Failed to query role admins", zap.Uints("roleIds", subtree), zap.Error(err))
+		return response.ErrServerError
+	}
+	if err := permcache.Invalidate(adminIds, subtree); err != nil {
+		global.Logger.Error("Failed to invalidate role permission cache", zap.Uints("roleIds", subtree), zap.Error(err))
+		return response.ErrServerError
+	}
+	return nil
+}
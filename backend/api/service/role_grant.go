@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const expiredGrantBatchSize = 500
+
+// SweepExpiredRoleGrants 回收已过期的临时角色授权：删除授权记录，吊销用户已签发的令牌，并记录审计日志
+func SweepExpiredRoleGrants(ctx context.Context) {
+	now := time.Now()
+	for ctx.Err() == nil {
+		grants, err := SysRoleDao.GetExpiredGrants(now, expiredGrantBatchSize)
+		if err != nil {
+			global.Logger.Error("Failed to query expired role grants", zap.Error(err))
+			return
+		}
+		revoked := make(map[uint]bool)
+		for _, grant := range grants {
+			deleted, err := SysRoleDao.DeleteExpiredGrant(grant.AdminID, grant.RoleID, now)
+			if err != nil {
+				global.Logger.Error("Failed to delete expired role grant", zap.Uint("adminId", grant.AdminID), zap.Uint("roleId", grant.RoleID), zap.Error(err))
+				return
+			}
+			if !deleted {
+				continue
+			}
+			writeGrantExpiredLog(grant, now)
+			if !revoked[grant.AdminID] {
+				revoked[grant.AdminID] = true
+				// 失败时已记录日志，权限查询会忽略过期授权，权限缓存也不会超过授权的失效时间，不影响继续回收
+				_ = invalidateAdminPermissions(grant.AdminID)
+				_ = revokeAdminTokens(grant.AdminID)
+			}
+		}
+		if len(grants) < expiredGrantBatchSize {
+			return
+		}
+	}
+}
+
+// 记录过期授权被回收的审计日志
+func writeGrantExpiredLog(grant entity.SysAdminRole, now time.Time) {
+	body, _ := json.Marshal(map[string]any{
+		"adminId":    grant.AdminID,
+		"roleId":     grant.RoleID,
+		"validFrom":  grant.ValidFrom,
+		"validUntil": grant.ValidUntil,
+		"reason":     grant.Reason,
+	})
+	SysLogDao.CreateOperationLog(&entity.SysOperationLog{
+		Username:  "system",
+		Method:    "SYSTEM",
+		CreatedAt: utils.HTime{Time: now},
+		Module:    "角色管理",
+		Action:    "回收过期角色授权",
+		Body:      string(body),
+		Code:      response.CodeSuccess,
+	})
+}
@@ -0,0 +1,1093 @@
+// SCIM 2.0 用户和组的自动配置：身份提供方通过 /scim/v2/Users 和 /scim/v2/Groups 推送用户生命周期变更，
+// 用户对应 SysAdmin，组对应 SysRole，组成员对应 SysAdminRole。超级管理员、服务账号和超级管理员角色只读
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	scimDefaultMaxResults = 100
+	scimDefaultBaseURL    = "/scim/v2"
+
+	scimGrantReason   = "SCIM" // 通过 SCIM 添加的组成员的授权原因
+	scimRoleKeyPrefix = "scim_"
+)
+
+type ScimService struct{}
+
+// 查询用户列表
+func (s *ScimService) ListUsers(query *entity.ScimListQuery) (*entity.ScimListResponse, error) {
+	where, args, err := parseScimFilter(query.Filter, scimUserColumns)
+	if err != nil {
+		return nil, err
+	}
+	startIndex, count := scimPaging(query)
+	admins, total, err := ScimDao.GetAdmins(where, args, startIndex-1, count)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	users, err := toScimUsers(admins, !scimExcluded(query, "groups"))
+	if err != nil {
+		return nil, err
+	}
+	return scimList(total, startIndex, users), nil
+}
+
+// 根据id查询用户
+func (s *ScimService) GetUser(id string) (*entity.ScimUser, error) {
+	user, err := getScimAdmin(id)
+	if err != nil {
+		return nil, err
+	}
+	return toScimUser(user)
+}
+
+// 创建用户：使用配置的默认部门和岗位，随机密码，默认只能通过单点登录登录
+func (s *ScimService) CreateUser(dto *entity.ScimUser) (*entity.ScimUser, error) {
+	cfg := global.Config.Scim
+	if dto.UserName == "" {
+		return nil, scimInvalidValue("userName 是必填项")
+	}
+	nameExists, err := SysAdminDao.ExistsByName(dto.UserName)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if nameExists {
+		return nil, scimConflict(response.ErrAdminNameExists.Message)
+	}
+	if err := checkProvisionDeptPost(cfg.DefaultDeptID, cfg.DefaultPostID); err != nil {
+		return nil, err
+	}
+	nickname, err := uniqueNickname(dto.UserName, scimDisplayName(dto))
+	if err != nil {
+		return nil, err
+	}
+	password, err := utils.RandomHex(32)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+
+	user := &entity.SysAdmin{
+		Username:  dto.UserName,
+		Nickname:  nickname,
+		Status:    1,
+		DeptID:    cfg.DefaultDeptID,
+		PostID:    cfg.DefaultPostID,
+		CreatedAt: utils.HTime{Time: time.Now()},
+
+		PasswordLoginDisabled: !cfg.AllowPasswordLogin,
+	}
+	if err := applyScimUserAttributes(user, dto); err != nil {
+		return nil, err
+	}
+	if err := applyNewPassword(user, password); err != nil {
+		return nil, response.ErrServerError
+	}
+	if err := SysAdminDao.CreateAdmin(nil, user); err != nil {
+		return nil, response.ErrServerError
+	}
+	global.Logger.Info("Provisioned admin from scim", zap.Uint("adminId", user.ID), zap.String("username", user.Username))
+	return toScimUser(user)
+}
+
+// 替换用户：请求中没有的邮箱、电话和 externalId 会被清空，未设置 active 时保持原状态
+func (s *ScimService) ReplaceUser(id string, dto *entity.ScimUser) (*entity.ScimUser, error) {
+	user, err := getWritableScimAdmin(id)
+	if err != nil {
+		return nil, err
+	}
+	before := *user
+	if dto.UserName == "" {
+		return nil, scimInvalidValue("userName 是必填项")
+	}
+	if err := setScimUserName(user, dto.UserName); err != nil {
+		return nil, err
+	}
+	if displayName := scimDisplayName(dto); displayName != "" {
+		if err := setScimNickname(user, displayName); err != nil {
+			return nil, err
+		}
+	}
+	user.Email, user.Phone, user.ScimExternalID = "", "", nil
+	if err := applyScimUserAttributes(user, dto); err != nil {
+		return nil, err
+	}
+	if err := saveScimAdmin(&before, user); err != nil {
+		return nil, err
+	}
+	return toScimUser(user)
+}
+
+// 修改用户的部分属性
+func (s *ScimService) PatchUser(id string, dto *entity.ScimPatchRequest) (*entity.ScimUser, error) {
+	user, err := getWritableScimAdmin(id)
+	if err != nil {
+		return nil, err
+	}
+	before := *user
+	for _, operation := range dto.Operations {
+		err := applyScimPatch(operation, func(path string, value json.RawMessage, remove bool) error {
+			return patchScimUser(user, path, value, remove)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := saveScimAdmin(&before, user); err != nil {
+		return nil, err
+	}
+	return toScimUser(user)
+}
+
+// 删除用户
+func (s *ScimService) DeleteUser(id string) error {
+	user, err := getWritableScimAdmin(id)
+	if err != nil {
+		return err
+	}
+	if err := SysAdminDao.DeleteAdmin(user.ID); err != nil {
+		return response.ErrServerError
+	}
+	global.Logger.Info("Deleted admin from scim", zap.Uint("adminId", user.ID), zap.String("username", user.Username))
+	if err := invalidateAdminPermissions(user.ID); err != nil {
+		return err
+	}
+	return revokeAdminTokens(user.ID)
+}
+
+// 查询组列表
+func (s *ScimService) ListGroups(query *entity.ScimListQuery) (*entity.ScimListResponse, error) {
+	where, args, err := parseScimFilter(query.Filter, scimGroupColumns)
+	if err != nil {
+		return nil, err
+	}
+	startIndex, count := scimPaging(query)
+	roles, total, err := ScimDao.GetRoles(where, args, startIndex-1, count)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	groups, err := toScimGroups(roles, !scimExcluded(query, "members"))
+	if err != nil {
+		return nil, err
+	}
+	return scimList(total, startIndex, groups), nil
+}
+
+// 根据id查询组
+func (s *ScimService) GetGroup(id string) (*entity.ScimGroup, error) {
+	role, err := getScimRole(id)
+	if err != nil {
+		return nil, err
+	}
+	return toScimGroup(role)
+}
+
+// 创建组：生成 scim_ 开头的角色关键字，数据权限默认仅本人，菜单权限需要管理员在角色管理中分配
+func (s *ScimService) CreateGroup(dto *entity.ScimGroup) (*entity.ScimGroup, error) {
+	if dto.DisplayName == "" {
+		return nil, scimInvalidValue("displayName 是必填项")
+	}
+	nameExists, err := SysRoleDao.ExistsByName(dto.DisplayName)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if nameExists {
+		return nil, scimConflict(response.ErrRoleNameExists.Message)
+	}
+	memberIds, err := scimMemberIds(dto.Members)
+	if err != nil {
+		return nil, err
+	}
+	roleKey, err := scimRoleKey()
+	if err != nil {
+		return nil, err
+	}
+	role := &entity.SysRole{
+		RoleName:    dto.DisplayName,
+		RoleKey:     roleKey,
+		RoleStatus:  1,
+		Description: "由 SCIM 创建",
+		CreatedAt:   utils.HTime{Time: time.Now()},
+		DataScope:   5,
+	}
+	if err := SysRoleDao.CreateRole(role); err != nil {
+		return nil, response.ErrServerError
+	}
+	global.Logger.Info("Provisioned role from scim", zap.Uint("roleId", role.ID), zap.String("roleName", role.RoleName))
+	if err := addScimMembers(role.ID, memberIds); err != nil {
+		return nil, err
+	}
+	return toScimGroup(role)
+}
+
+// 替换组：修改名称，并将成员设置为请求中的成员
+func (s *ScimService) ReplaceGroup(id string, dto *entity.ScimGroup) (*entity.ScimGroup, error) {
+	role, err := getWritableScimRole(id)
+	if err != nil {
+		return nil, err
+	}
+	if dto.DisplayName == "" {
+		return nil, scimInvalidValue("displayName 是必填项")
+	}
+	memberIds, err := scimMemberIds(dto.Members)
+	if err != nil {
+		return nil, err
+	}
+	if err := renameScimRole(role, dto.DisplayName); err != nil {
+		return nil, err
+	}
+	if err := replaceScimMembers(role.ID, memberIds); err != nil {
+		return nil, err
+	}
+	return toScimGroup(role)
+}
+
+// 修改组的名称或增减成员
+func (s *ScimService) PatchGroup(id string, dto *entity.ScimPatchRequest) (*entity.ScimGroup, error) {
+	role, err := getWritableScimRole(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range dto.Operations {
+		op := strings.ToLower(operation.Op)
+		// members[value eq "1"] 表示单个成员
+		if path := normalizeScimPath(operation.Path); strings.HasPrefix(path, "members[") {
+			memberId, ok := scimMemberFilterId(operation.Path)
+			if !ok {
+				return nil, response.NewScimError(http.StatusBadRequest, response.ScimTypeInvalidPath, "不支持的路径："+operation.Path)
+			}
+			if op == "remove" {
+				err = removeScimMembers(role.ID, []uint{memberId})
+			} else {
+				err = addScimMembers(role.ID, []uint{memberId})
+			}
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err := applyScimPatch(operation, func(path string, value json.RawMessage, remove bool) error {
+			return patchScimGroup(role, op, path, value, remove)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return toScimGroup(role)
+}
+
+// 删除组，同时移除所有成员
+func (s *ScimService) DeleteGroup(id string) error {
+	role, err := getWritableScimRole(id)
+	if err != nil {
+		return err
+	}
+	hasSubRole, err := SysRoleDao.HasSubRole(role.ID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if hasSubRole {
+		return response.NewScimError(http.StatusConflict, "", response.ErrHasSubRole.Message)
+	}
+	adminIds, err := SysRoleDao.GetRolesAdminIds([]uint{role.ID})
+	if err != nil {
+		return response.ErrServerError
+	}
+	if err := SysRoleDao.DeleteRole(role.ID); err != nil {
+		return response.ErrServerError
+	}
+	if len(adminIds) > 0 {
+		if err := SysRoleDao.RemoveRoleAdmins(role.ID, adminIds); err != nil {
+			return response.ErrServerError
+		}
+	}
+	global.Logger.Info("Deleted role from scim", zap.Uint("roleId", role.ID), zap.String("roleName", role.RoleName))
+	if err := invalidateAdminPermissions(adminIds...); err != nil {
+		return err
+	}
+	if err := invalidateRolePermissions(role.ID); err != nil {
+		return err
+	}
+	return revokeAdminsTokens(adminIds)
+}
+
+// 服务提供方配置，身份提供方据此判断支持的功能
+func (s *ScimService) GetServiceProviderConfig() map[string]any {
+	supported := func(ok bool) map[string]any { return map[string]any{"supported": ok} }
+	return map[string]any{
+		"schemas":        []string{entity.ScimSchemaServiceProviderConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": scimMaxResults()},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "使用配置的 SCIM 令牌认证",
+			"primary":     true,
+		}},
+		"meta": map[string]any{"resourceType": "ServiceProviderConfig", "location": scimBaseURL() + "/ServiceProviderConfig"},
+	}
+}
+
+// 支持的资源类型
+func (s *ScimService) GetResourceTypes() *entity.ScimListResponse {
+	resourceType := func(name, endpoint, schema string) map[string]any {
+		return map[string]any{
+			"schemas":  []string{entity.ScimSchemaResourceType},
+			"id":       name,
+			"name":     name,
+			"endpoint": endpoint,
+			"schema":   schema,
+			"meta":     map[string]any{"resourceType": "ResourceType", "location": scimBaseURL() + "/ResourceTypes/" + name},
+		}
+	}
+	resourceTypes := []map[string]any{
+		resourceType("User", "/Users", entity.ScimSchemaUser),
+		resourceType("Group", "/Groups", entity.ScimSchemaGroup),
+	}
+	return scimList(int64(len(resourceTypes)), 1, resourceTypes)
+}
+
+func scimBaseURL() string {
+	if baseURL := strings.TrimSuffix(global.Config.Scim.BaseURL, "/"); baseURL != "" {
+		return baseURL
+	}
+	return scimDefaultBaseURL
+}
+
+func scimLocation(resource string, id uint) string {
+	return fmt.Sprintf("%s/%s/%d", scimBaseURL(), resource, id)
+}
+
+func scimMaxResults() int {
+	if global.Config.Scim.MaxResults > 0 {
+		return global.Config.Scim.MaxResults
+	}
+	return scimDefaultMaxResults
+}
+
+// 分页参数：startIndex 从1开始，count 不超过 max_results
+func scimPaging(query *entity.ScimListQuery) (int, int) {
+	startIndex := query.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count := scimMaxResults()
+	if query.Count != nil && *query.Count < count {
+		count = max(*query.Count, 0)
+	}
+	return startIndex, count
+}
+
+// 是否在 excludedAttributes 中排除了属性
+func scimExcluded(query *entity.ScimListQuery, attribute string) bool {
+	for _, excluded := range strings.Split(query.ExcludedAttributes, ",") {
+		if normalizeScimPath(excluded) == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+func scimList[T any](total int64, startIndex int, resources []T) *entity.ScimListResponse {
+	return &entity.ScimListResponse{
+		Schemas:      []string{entity.ScimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+func scimInvalidValue(detail string) error {
+	return response.NewScimError(http.StatusBadRequest, response.ScimTypeInvalidValue, detail)
+}
+
+func scimConflict(detail string) error {
+	return response.NewScimError(http.StatusConflict, response.ScimTypeUniqueness, detail)
+}
+
+func scimNotFound(detail string) error {
+	return response.NewScimError(http.StatusNotFound, "", detail)
+}
+
+// 根据 SCIM 资源id获取用户
+func getScimAdmin(id string) (*entity.SysAdmin, error) {
+	adminId, ok := scimUintValue(id)
+	if !ok {
+		return nil, scimNotFound(response.ErrAdminNotExists.Message)
+	}
+	user, err := SysAdminDao.GetAdminById(adminId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, scimNotFound(response.ErrAdminNotExists.Message)
+		}
+		return nil, response.ErrServerError
+	}
+	return user, nil
+}
+
+// 获取可以通过 SCIM 修改的用户，超级管理员和服务账号只读
+func getWritableScimAdmin(id string) (*entity.SysAdmin, error) {
+	user, err := getScimAdmin(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuper || user.IsServiceAccount {
+		return nil, response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, "超级管理员和服务账号不能通过SCIM修改")
+	}
+	return user, nil
+}
+
+// 根据 SCIM 资源id获取角色
+func getScimRole(id string) (*entity.SysRole, error) {
+	roleId, ok := scimUintValue(id)
+	if !ok {
+		return nil, scimNotFound(response.ErrRoleNotExists.Message)
+	}
+	role, err := SysRoleDao.GetRoleByID(roleId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, scimNotFound(response.ErrRoleNotExists.Message)
+		}
+		return nil, response.ErrServerError
+	}
+	return role, nil
+}
+
+// 获取可以通过 SCIM 修改的角色，超级管理员角色只读
+func getWritableScimRole(id string) (*entity.SysRole, error) {
+	role, err := getScimRole(id)
+	if err != nil {
+		return nil, err
+	}
+	super, err := inheritsSuperRole(role.ID)
+	if err != nil {
+		return nil, err
+	}
+	if super {
+		return nil, response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, "超级管理员角色不能通过SCIM修改")
+	}
+	return role, nil
+}
+
+func toScimUser(user *entity.SysAdmin) (*entity.ScimUser, error) {
+	users, err := toScimUsers([]entity.SysAdmin{*user}, true)
+	if err != nil {
+		return nil, err
+	}
+	return &users[0], nil
+}
+
+// 转换为 SCIM 用户，includeGroups 为 true 时查询用户的角色作为 groups
+func toScimUsers(admins []entity.SysAdmin, includeGroups bool) ([]entity.ScimUser, error) {
+	groups := make(map[uint][]entity.ScimMultiValue)
+	if includeGroups && len(admins) > 0 {
+		adminIds := make([]uint, 0, len(admins))
+		for _, admin := range admins {
+			adminIds = append(adminIds, admin.ID)
+		}
+		roles, err := SysAdminDao.GetAdminsRoles(adminIds)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		for _, role := range roles {
+			groups[role.AdminID] = append(groups[role.AdminID], entity.ScimMultiValue{
+				Value:   strconv.FormatUint(uint64(role.ID), 10),
+				Display: role.RoleName,
+				Ref:     scimLocation("Groups", role.ID),
+			})
+		}
+	}
+
+	users := make([]entity.ScimUser, 0, len(admins))
+	for _, admin := range admins {
+		active := admin.Status == 1
+		user := entity.ScimUser{
+			Schemas:     []string{entity.ScimSchemaUser},
+			ID:          strconv.FormatUint(uint64(admin.ID), 10),
+			UserName:    admin.Username,
+			Name:        &entity.ScimName{Formatted: admin.Nickname},
+			DisplayName: admin.Nickname,
+			Active:      &active,
+			Groups:      groups[admin.ID],
+			Meta:        &entity.ScimMeta{ResourceType: "User", Location: scimLocation("Users", admin.ID)},
+		}
+		if admin.ScimExternalID != nil {
+			user.ExternalID = *admin.ScimExternalID
+		}
+		if admin.Email != "" {
+			user.Emails = []entity.ScimMultiValue{{Value: admin.Email, Type: "work", Primary: true}}
+		}
+		if admin.Phone != "" {
+			user.PhoneNumbers = []entity.ScimMultiValue{{Value: admin.Phone, Type: "mobile", Primary: true}}
+		}
+		if !admin.CreatedAt.IsZero() {
+			created := admin.CreatedAt.Time
+			user.Meta.Created = &created
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func toScimGroup(role *entity.SysRole) (*entity.ScimGroup, error) {
+	groups, err := toScimGroups([]entity.SysRole{*role}, true)
+	if err != nil {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// 转换为 SCIM 组，includeMembers 为 true 时查询拥有角色的用户作为 members
+func toScimGroups(roles []entity.SysRole, includeMembers bool) ([]entity.ScimGroup, error) {
+	members := make(map[uint][]entity.ScimMultiValue)
+	if includeMembers && len(roles) > 0 {
+		roleIds := make([]uint, 0, len(roles))
+		for _, role := range roles {
+			roleIds = append(roleIds, role.ID)
+		}
+		rows, err := ScimDao.GetRoleMembers(roleIds)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		for _, row := range rows {
+			members[row.RoleID] = append(members[row.RoleID], entity.ScimMultiValue{
+				Value:   strconv.FormatUint(uint64(row.AdminID), 10),
+				Display: row.Username,
+				Ref:     scimLocation("Users", row.AdminID),
+			})
+		}
+	}
+
+	groups := make([]entity.ScimGroup, 0, len(roles))
+	for _, role := range roles {
+		group := entity.ScimGroup{
+			Schemas:     []string{entity.ScimSchemaGroup},
+			ID:          strconv.FormatUint(uint64(role.ID), 10),
+			DisplayName: role.RoleName,
+			Members:     members[role.ID],
+			Meta:        &entity.ScimMeta{ResourceType: "Group", Location: scimLocation("Groups", role.ID)},
+		}
+		if !role.CreatedAt.IsZero() {
+			created := role.CreatedAt.Time
+			group.Meta.Created = &created
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// 用户的显示名称：displayName、name.formatted 或姓名拼接
+func scimDisplayName(user *entity.ScimUser) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	if user.Name == nil {
+		return ""
+	}
+	if user.Name.Formatted != "" {
+		return user.Name.Formatted
+	}
+	return strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName)
+}
+
+// 多值属性中的主要值，没有标记主要值时使用第一个
+func scimPrimaryValue(values []entity.ScimMultiValue) string {
+	for _, value := range values {
+		if value.Primary {
+			return value.Value
+		}
+	}
+	if len(values) > 0 {
+		return values[0].Value
+	}
+	return ""
+}
+
+// 设置创建或替换用户时的邮箱、电话、状态和 externalId
+func applyScimUserAttributes(user *entity.SysAdmin, dto *entity.ScimUser) error {
+	if email := scimPrimaryValue(dto.Emails); email != "" {
+		if err := setScimEmail(user, email); err != nil {
+			return err
+		}
+	}
+	if phone := scimPrimaryValue(dto.PhoneNumbers); phone != "" {
+		if err := setScimPhone(user, phone); err != nil {
+			return err
+		}
+	}
+	if dto.Active != nil {
+		setScimActive(user, *dto.Active)
+	}
+	if dto.ExternalID != "" {
+		return setScimExternalID(user, dto.ExternalID)
+	}
+	return nil
+}
+
+func setScimUserName(user *entity.SysAdmin, username string) error {
+	if username == "" {
+		return scimInvalidValue("userName 是必填项")
+	}
+	if username == user.Username {
+		return nil
+	}
+	// 目录账号按用户名与目录关联
+	if user.LdapDN != "" {
+		return response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, response.ErrLdapAccountManaged.Message)
+	}
+	exists, err := SysAdminDao.ExistsByName(username)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if exists {
+		return scimConflict(response.ErrAdminNameExists.Message)
+	}
+	user.Username = username
+	return nil
+}
+
+func setScimNickname(user *entity.SysAdmin, nickname string) error {
+	if nickname == "" || nickname == user.Nickname {
+		return nil
+	}
+	exists, err := SysAdminDao.ExistsNickname(nickname)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if exists {
+		return scimConflict(response.ErrAdminNicknameExists.Message)
+	}
+	user.Nickname = nickname
+	return nil
+}
+
+func setScimEmail(user *entity.SysAdmin, email string) error {
+	if len(email) > 64 {
+		return scimInvalidValue("邮箱长度不能超过64个字符")
+	}
+	user.Email = email
+	return nil
+}
+
+// 去掉空格和短横线后不能超过11位
+func setScimPhone(user *entity.SysAdmin, phone string) error {
+	phone = strings.NewReplacer(" ", "", "-", "").Replace(phone)
+	if len(phone) > 11 {
+		return scimInvalidValue("电话号码不能超过11位")
+	}
+	user.Phone = phone
+	return nil
+}
+
+func setScimActive(user *entity.SysAdmin, active bool) {
+	if active {
+		user.Status = 1
+	} else {
+		user.Status = 2
+	}
+}
+
+func setScimExternalID(user *entity.SysAdmin, externalId string) error {
+	if user.ScimExternalID != nil && *user.ScimExternalID == externalId {
+		return nil
+	}
+	exists, err := ScimDao.ExistsExternalID(externalId, user.ID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if exists {
+		return scimConflict("externalId 已被其他用户使用")
+	}
+	user.ScimExternalID = &externalId
+	return nil
+}
+
+// 保存用户，停用时吊销令牌和会话
+func saveScimAdmin(before, user *entity.SysAdmin) error {
+	if err := SysAdminDao.UpdateAdmin(user); err != nil {
+		return response.ErrServerError
+	}
+	if before.Status == 1 && user.Status == 2 {
+		global.Logger.Info("Deactivated admin from scim", zap.Uint("adminId", user.ID), zap.String("username", user.Username))
+		return revokeAdminTokens(user.ID)
+	}
+	return nil
+}
+
+// 执行一个 PATCH 操作：没有 path 时 value 为属性名到值的对象，逐个属性执行
+func applyScimPatch(operation entity.ScimPatchOperation, apply func(path string, value json.RawMessage, remove bool) error) error {
+	op := strings.ToLower(operation.Op)
+	if op != "add" && op != "replace" && op != "remove" {
+		return response.NewScimError(http.StatusBadRequest, response.ScimTypeInvalidSyntax, "不支持的操作："+operation.Op)
+	}
+	remove := op == "remove"
+	if operation.Path != "" {
+		return apply(scimPatchPath(operation.Path), operation.Value, remove)
+	}
+	if remove {
+		return response.NewScimError(http.StatusBadRequest, response.ScimTypeNoTarget, "remove 操作必须指定 path")
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(operation.Value, &values); err != nil {
+		return response.NewScimError(http.StatusBadRequest, response.ScimTypeInvalidSyntax, "没有 path 时 value 必须是对象")
+	}
+	for path, value := range values {
+		if err := apply(normalizeScimPath(path), value, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PATCH 的属性路径，去掉值过滤条件，如 emails[type eq "work"].value 视为 emails.value
+func scimPatchPath(path string) string {
+	path = normalizeScimPath(path)
+	if start := strings.Index(path, "["); start >= 0 {
+		if end := strings.Index(path[start:], "]"); end >= 0 {
+			path = path[:start] + path[start+end+1:]
+		}
+	}
+	return path
+}
+
+// 修改用户的一个属性，不支持的属性(如企业扩展属性)忽略
+func patchScimUser(user *entity.SysAdmin, path string, value json.RawMessage, remove bool) error {
+	switch path {
+	case "username":
+		if remove {
+			return response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, "userName 不能删除")
+		}
+		username, err := scimStringValue(value)
+		if err != nil {
+			return err
+		}
+		return setScimUserName(user, username)
+	case "displayname", "name.formatted":
+		// 昵称不能为空，删除时保留原值
+		if remove {
+			return nil
+		}
+		nickname, err := scimStringValue(value)
+		if err != nil {
+			return err
+		}
+		return setScimNickname(user, nickname)
+	case "name":
+		if remove {
+			return nil
+		}
+		var name entity.ScimName
+		if err := json.Unmarshal(value, &name); err != nil {
+			return scimInvalidValue("name 必须是对象")
+		}
+		return setScimNickname(user, scimDisplayName(&entity.ScimUser{Name: &name}))
+	case "emails", "emails.value":
+		if remove {
+			user.Email = ""
+			return nil
+		}
+		email, err := scimMultiValue(path, value)
+		if err != nil {
+			return err
+		}
+		return setScimEmail(user, email)
+	case "phonenumbers", "phonenumbers.value":
+		if remove {
+			user.Phone = ""
+			return nil
+		}
+		phone, err := scimMultiValue(path, value)
+		if err != nil {
+			return err
+		}
+		return setScimPhone(user, phone)
+	case "active":
+		if remove {
+			return response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, "active 不能删除")
+		}
+		active, err := scimBoolValue(value)
+		if err != nil {
+			return err
+		}
+		setScimActive(user, active)
+		return nil
+	case "externalid":
+		if remove {
+			user.ScimExternalID = nil
+			return nil
+		}
+		externalId, err := scimStringValue(value)
+		if err != nil {
+			return err
+		}
+		return setScimExternalID(user, externalId)
+	case "groups":
+		return response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, "groups 为只读属性，请通过组的 members 修改")
+	}
+	return nil
+}
+
+// 修改组的一个属性，不支持的属性忽略
+func patchScimGroup(role *entity.SysRole, op, path string, value json.RawMessage, remove bool) error {
+	switch path {
+	case "displayname":
+		if remove {
+			return response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, "displayName 不能删除")
+		}
+		displayName, err := scimStringValue(value)
+		if err != nil {
+			return err
+		}
+		return renameScimRole(role, displayName)
+	case "members":
+		var members []entity.ScimMultiValue
+		if len(value) > 0 && string(value) != "null" {
+			if err := json.Unmarshal(value, &members); err != nil {
+				return scimInvalidValue("members 必须是数组")
+			}
+		}
+		memberIds, err := scimMemberIds(members)
+		if err != nil {
+			return err
+		}
+		switch {
+		case op == "replace":
+			return replaceScimMembers(role.ID, memberIds)
+		case remove && len(value) == 0:
+			return replaceScimMembers(role.ID, nil)
+		case remove:
+			return removeScimMembers(role.ID, memberIds)
+		}
+		return addScimMembers(role.ID, memberIds)
+	}
+	return nil
+}
+
+func scimStringValue(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", scimInvalidValue("属性值必须是字符串")
+	}
+	return s, nil
+}
+
+// 布尔值，兼容以字符串形式发送布尔值的身份提供方
+func scimBoolValue(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+	return false, scimInvalidValue("属性值必须是布尔值")
+}
+
+// 多值属性的值：path 为 xxx.value 时为字符串，否则为数组
+func scimMultiValue(path string, value json.RawMessage) (string, error) {
+	if strings.HasSuffix(path, ".value") {
+		return scimStringValue(value)
+	}
+	var values []entity.ScimMultiValue
+	if err := json.Unmarshal(value, &values); err != nil {
+		return "", scimInvalidValue(path + " 必须是数组")
+	}
+	return scimPrimaryValue(values), nil
+}
+
+// 从 members[value eq "1"] 中取出成员id
+func scimMemberFilterId(path string) (uint, bool) {
+	start, end := strings.Index(path, "["), strings.LastIndex(path, "]")
+	if start < 0 || end < start {
+		return 0, false
+	}
+	tokens, err := tokenizeScimFilter(path[start+1 : end])
+	if err != nil || len(tokens) != 3 || !strings.EqualFold(tokens[0], "value") || !strings.EqualFold(tokens[1], "eq") {
+		return 0, false
+	}
+	value, err := parseScimValue(tokens[2])
+	if err != nil {
+		return 0, false
+	}
+	return scimUintValue(value)
+}
+
+// 检查组成员都是存在的用户，超级管理员和服务账号的角色不能通过 SCIM 修改
+func scimMemberIds(members []entity.ScimMultiValue) ([]uint, error) {
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		id, ok := scimUintValue(member.Value)
+		if !ok {
+			return nil, scimInvalidValue("无效的成员：" + member.Value)
+		}
+		ids = append(ids, id)
+	}
+	ids = uniqueIds(ids)
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	admins, _, err := ScimDao.GetAdmins("sys_admin.id IN (?)", []any{ids}, 0, len(ids))
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if len(admins) != len(ids) {
+		return nil, scimInvalidValue(response.ErrAdminNotExists.Message)
+	}
+	for _, admin := range admins {
+		if admin.IsSuper || admin.IsServiceAccount {
+			return nil, response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, "超级管理员和服务账号不能通过SCIM修改")
+		}
+	}
+	// 拥有超级管理员角色或继承它的角色的用户同样受保护
+	roles, err := SysAdminDao.GetAdminsRoles(ids)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	roleIds := make([]uint, 0, len(roles))
+	for _, role := range roles {
+		roleIds = append(roleIds, role.ID)
+	}
+	super, err := inheritsSuperRole(uniqueIds(roleIds)...)
+	if err != nil {
+		return nil, err
+	}
+	if super {
+		return nil, response.NewScimError(http.StatusBadRequest, response.ScimTypeMutability, "超级管理员和服务账号不能通过SCIM修改")
+	}
+	return ids, nil
+}
+
+// 生成未被使用的角色关键字
+func scimRoleKey() (string, error) {
+	for range 5 {
+		suffix, err := utils.RandomHex(4)
+		if err != nil {
+			return "", response.ErrServerError
+		}
+		roleKey := scimRoleKeyPrefix + suffix
+		exists, err := SysRoleDao.ExistsByKey(roleKey)
+		if err != nil {
+			return "", response.ErrServerError
+		}
+		if !exists {
+			return roleKey, nil
+		}
+	}
+	return "", response.ErrServerError
+}
+
+func renameScimRole(role *entity.SysRole, displayName string) error {
+	if displayName == "" {
+		return scimInvalidValue("displayName 是必填项")
+	}
+	if displayName == role.RoleName {
+		return nil
+	}
+	exists, err := SysRoleDao.ExistsByName(displayName)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if exists {
+		return scimConflict(response.ErrRoleNameExists.Message)
+	}
+	role.RoleName = displayName
+	if err := SysRoleDao.UpdateRole(role); err != nil {
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 添加组成员，成员的权限缓存和令牌随之失效
+func addScimMembers(roleId uint, adminIds []uint) error {
+	if len(adminIds) == 0 {
+		return nil
+	}
+	if _, err := scimMemberIds(scimMemberValues(adminIds)); err != nil {
+		return err
+	}
+	if err := SysRoleDao.AddRoleAdmins(entity.SysAdminRole{RoleID: roleId, Reason: scimGrantReason}, adminIds); err != nil {
+		return response.ErrServerError
+	}
+	if err := invalidateAdminPermissions(adminIds...); err != nil {
+		return err
+	}
+	return revokeAdminsTokens(adminIds)
+}
+
+// 移除组成员
+func removeScimMembers(roleId uint, adminIds []uint) error {
+	if len(adminIds) == 0 {
+		return nil
+	}
+	if _, err := scimMemberIds(scimMemberValues(adminIds)); err != nil {
+		return err
+	}
+	if err := SysRoleDao.RemoveRoleAdmins(roleId, adminIds); err != nil {
+		return response.ErrServerError
+	}
+	if err := invalidateAdminPermissions(adminIds...); err != nil {
+		return err
+	}
+	return revokeAdminsTokens(adminIds)
+}
+
+// 将组成员设置为 adminIds，只增减有变化的成员
+func replaceScimMembers(roleId uint, adminIds []uint) error {
+	oldIds, err := SysRoleDao.GetRolesAdminIds([]uint{roleId})
+	if err != nil {
+		return response.ErrServerError
+	}
+	wanted := make(map[uint]bool, len(adminIds))
+	for _, id := range adminIds {
+		wanted[id] = true
+	}
+	existing := make(map[uint]bool, len(oldIds))
+	var removed []uint
+	for _, id := range oldIds {
+		existing[id] = true
+		if !wanted[id] {
+			removed = append(removed, id)
+		}
+	}
+	var added []uint
+	for _, id := range adminIds {
+		if !existing[id] {
+			added = append(added, id)
+		}
+	}
+	if err := removeScimMembers(roleId, removed); err != nil {
+		return err
+	}
+	return addScimMembers(roleId, added)
+}
+
+func scimMemberValues(adminIds []uint) []entity.ScimMultiValue {
+	values := make([]entity.ScimMultiValue, 0, len(adminIds))
+	for _, id := range adminIds {
+		values = append(values, entity.ScimMultiValue{Value: strconv.FormatUint(uint64(id), 10)})
+	}
+	return values
+}
@@ -0,0 +1,436 @@
+// SCIM 过滤表达式(RFC 7644 3.4.2.2)：解析为 SQL 条件，支持 and、or、not、括号、
+// 值路径 members[value eq "1"] 以及 eq、ne、co、sw、ew、gt、ge、lt、le、pr 运算符
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-admin-server/common/response"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 属性的类型，决定可用的运算符和参数的转换方式
+type scimAttrKind int
+
+const (
+	scimString     scimAttrKind = iota // 字符串，不区分大小写
+	scimID                             // 资源id
+	scimTime                           // 时间
+	scimActive                         // 账号状态，true 对应 status = 1
+	scimMembership                     // 组成员关系，只支持 eq、ne 和 pr
+	scimUnstored                       // 未保存的属性，条件总是不成立
+)
+
+// 过滤表达式中的属性对应的 SQL 列
+type scimColumn struct {
+	expr   string       // 列名；成员关系为带一个参数的条件
+	prExpr string       // 成员关系的 pr 条件
+	kind   scimAttrKind // 属性类型
+}
+
+// 用户资源可过滤的属性，键为小写的属性路径
+var scimUserColumns = map[string]scimColumn{
+	"id":                 {expr: "sys_admin.id", kind: scimID},
+	"username":           {expr: "sys_admin.username", kind: scimString},
+	"externalid":         {expr: "sys_admin.scim_external_id", kind: scimString},
+	"displayname":        {expr: "sys_admin.nickname", kind: scimString},
+	"name.formatted":     {expr: "sys_admin.nickname", kind: scimString},
+	"emails":             {expr: "sys_admin.email", kind: scimString},
+	"emails.value":       {expr: "sys_admin.email", kind: scimString},
+	"phonenumbers":       {expr: "sys_admin.phone", kind: scimString},
+	"phonenumbers.value": {expr: "sys_admin.phone", kind: scimString},
+	"active":             {expr: "sys_admin.status", kind: scimActive},
+	"meta.created":       {expr: "sys_admin.created_at", kind: scimTime},
+	"groups": {
+		expr:   "sys_admin.id IN (SELECT admin_id FROM sys_admin_role WHERE role_id = ?)",
+		prExpr: "sys_admin.id IN (SELECT admin_id FROM sys_admin_role)",
+		kind:   scimMembership,
+	},
+	"groups.value": {
+		expr:   "sys_admin.id IN (SELECT admin_id FROM sys_admin_role WHERE role_id = ?)",
+		prExpr: "sys_admin.id IN (SELECT admin_id FROM sys_admin_role)",
+		kind:   scimMembership,
+	},
+}
+
+// 组资源可过滤的属性，键为小写的属性路径
+var scimGroupColumns = map[string]scimColumn{
+	"id":           {expr: "sys_role.id", kind: scimID},
+	"displayname":  {expr: "sys_role.role_name", kind: scimString},
+	"externalid":   {kind: scimUnstored},
+	"meta.created": {expr: "sys_role.created_at", kind: scimTime},
+	"members": {
+		expr:   "sys_role.id IN (SELECT role_id FROM sys_admin_role WHERE admin_id = ?)",
+		prExpr: "sys_role.id IN (SELECT role_id FROM sys_admin_role)",
+		kind:   scimMembership,
+	},
+	"members.value": {
+		expr:   "sys_role.id IN (SELECT role_id FROM sys_admin_role WHERE admin_id = ?)",
+		prExpr: "sys_role.id IN (SELECT role_id FROM sys_admin_role)",
+		kind:   scimMembership,
+	},
+}
+
+// 属性路径统一为小写，并去掉资源 schema 前缀
+func normalizeScimPath(path string) string {
+	path = strings.ToLower(strings.TrimSpace(path))
+	for _, schema := range []string{"urn:ietf:params:scim:schemas:core:2.0:user:", "urn:ietf:params:scim:schemas:core:2.0:group:"} {
+		path = strings.TrimPrefix(path, schema)
+	}
+	return path
+}
+
+// 解析过滤表达式，返回 SQL 条件和参数，表达式为空时返回空条件
+func parseScimFilter(filter string, columns map[string]scimColumn) (string, []any, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", nil, nil
+	}
+	tokens, err := tokenizeScimFilter(filter)
+	if err != nil {
+		return "", nil, err
+	}
+	p := &scimFilterParser{tokens: tokens, columns: columns}
+	sql, args, err := p.parseOr()
+	if err != nil {
+		return "", nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return "", nil, invalidScimFilter("unexpected %q", p.tokens[p.pos])
+	}
+	return sql, args, nil
+}
+
+func invalidScimFilter(format string, args ...any) error {
+	return response.NewScimError(http.StatusBadRequest, response.ScimTypeInvalidFilter, fmt.Sprintf("invalid filter: "+format, args...))
+}
+
+// 拆分为括号、方括号、带引号的字符串和单词
+func tokenizeScimFilter(filter string) ([]string, error) {
+	var tokens []string
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case strings.ContainsRune("()[]", r):
+			tokens = append(tokens, string(r))
+			i++
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				return nil, invalidScimFilter("unterminated string")
+			}
+			tokens = append(tokens, string(runes[i:j+1]))
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && !strings.ContainsRune(" \t\n\r()[]\"", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type scimFilterParser struct {
+	tokens  []string
+	pos     int
+	columns map[string]scimColumn
+	prefix  string // 值路径中的属性前缀，如 members.
+}
+
+func (p *scimFilterParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *scimFilterParser) next() string {
+	token := p.peek()
+	if token != "" {
+		p.pos++
+	}
+	return token
+}
+
+func (p *scimFilterParser) expect(token string) error {
+	if p.next() != token {
+		return invalidScimFilter("expected %q", token)
+	}
+	return nil
+}
+
+// or 的优先级低于 and
+func (p *scimFilterParser) parseOr() (string, []any, error) {
+	sql, args, err := p.parseAnd()
+	if err != nil {
+		return "", nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, rightArgs, err := p.parseAnd()
+		if err != nil {
+			return "", nil, err
+		}
+		sql = "(" + sql + " OR " + right + ")"
+		args = append(args, rightArgs...)
+	}
+	return sql, args, nil
+}
+
+func (p *scimFilterParser) parseAnd() (string, []any, error) {
+	sql, args, err := p.parseUnary()
+	if err != nil {
+		return "", nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, rightArgs, err := p.parseUnary()
+		if err != nil {
+			return "", nil, err
+		}
+		sql = "(" + sql + " AND " + right + ")"
+		args = append(args, rightArgs...)
+	}
+	return sql, args, nil
+}
+
+func (p *scimFilterParser) parseUnary() (string, []any, error) {
+	token := p.peek()
+	switch {
+	case strings.EqualFold(token, "not"):
+		p.next()
+		if err := p.expect("("); err != nil {
+			return "", nil, err
+		}
+		sql, args, err := p.parseOr()
+		if err != nil {
+			return "", nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + sql + ")", args, nil
+	case token == "(":
+		p.next()
+		sql, args, err := p.parseOr()
+		if err != nil {
+			return "", nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return "", nil, err
+		}
+		return "(" + sql + ")", args, nil
+	case token == "" || strings.ContainsAny(token, "()[]\""):
+		return "", nil, invalidScimFilter("expected attribute")
+	}
+	return p.parseAttrExpr()
+}
+
+// 属性表达式：attr pr、attr op value 或值路径 attr[filter]
+func (p *scimFilterParser) parseAttrExpr() (string, []any, error) {
+	path := p.prefix + normalizeScimPath(p.next())
+	if p.peek() == "[" {
+		if p.prefix != "" {
+			return "", nil, invalidScimFilter("nested value path")
+		}
+		p.next()
+		p.prefix = path + "."
+		sql, args, err := p.parseOr()
+		p.prefix = ""
+		if err != nil {
+			return "", nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", nil, err
+		}
+		return "(" + sql + ")", args, nil
+	}
+
+	column, ok := p.columns[path]
+	if !ok {
+		return "", nil, invalidScimFilter("unsupported attribute %q", path)
+	}
+	op := strings.ToLower(p.next())
+	if op == "pr" {
+		return column.present(), nil, nil
+	}
+	if !isScimOperator(op) {
+		return "", nil, invalidScimFilter("unsupported operator %q", op)
+	}
+	value, err := parseScimValue(p.next())
+	if err != nil {
+		return "", nil, err
+	}
+	return column.compare(op, value)
+}
+
+func isScimOperator(op string) bool {
+	switch op {
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+		return true
+	}
+	return false
+}
+
+// 比较值：带引号的字符串、true、false、null 或数字
+func parseScimValue(token string) (any, error) {
+	switch {
+	case strings.HasPrefix(token, `"`):
+		var value string
+		if err := json.Unmarshal([]byte(token), &value); err != nil {
+			return nil, invalidScimFilter("invalid string %s", token)
+		}
+		return value, nil
+	case token == "true", token == "false":
+		return token == "true", nil
+	case token == "null":
+		return nil, nil
+	}
+	if _, err := strconv.ParseFloat(token, 64); err == nil {
+		return token, nil
+	}
+	return nil, invalidScimFilter("invalid value %q", token)
+}
+
+// 属性有值的条件
+func (c scimColumn) present() string {
+	switch c.kind {
+	case scimString:
+		return "(" + c.expr + " IS NOT NULL AND " + c.expr + " <> '')"
+	case scimMembership:
+		return c.prExpr
+	case scimUnstored:
+		return "1 = 0"
+	}
+	return c.expr + " IS NOT NULL"
+}
+
+// 比较条件
+func (c scimColumn) compare(op string, value any) (string, []any, error) {
+	if value == nil {
+		// 与 null 比较等同于判断属性是否有值
+		switch op {
+		case "eq":
+			return "NOT " + c.present(), nil, nil
+		case "ne":
+			return c.present(), nil, nil
+		}
+		return "", nil, invalidScimFilter("null only supports eq and ne")
+	}
+
+	switch c.kind {
+	case scimUnstored:
+		return "1 = 0", nil, nil
+	case scimActive:
+		active, ok := value.(bool)
+		if !ok || (op != "eq" && op != "ne") {
+			return "", nil, invalidScimFilter("active only supports eq and ne with a boolean")
+		}
+		if active == (op == "ne") {
+			return c.expr + " <> 1", nil, nil
+		}
+		return c.expr + " = 1", nil, nil
+	case scimMembership:
+		id, ok := scimUintValue(value)
+		if op != "eq" && op != "ne" {
+			return "", nil, invalidScimFilter("members only supports eq, ne and pr")
+		}
+		sql := c.expr
+		if op == "ne" {
+			sql = "NOT " + sql
+		}
+		if !ok {
+			// 不存在的id
+			if op == "ne" {
+				return "1 = 1", nil, nil
+			}
+			return "1 = 0", nil, nil
+		}
+		return sql, []any{id}, nil
+	case scimID:
+		id, ok := scimUintValue(value)
+		if !ok {
+			if op == "ne" {
+				return "1 = 1", nil, nil
+			}
+			return "1 = 0", nil, nil
+		}
+		return compareScimColumn(c.expr, op, id)
+	case scimTime:
+		s, _ := value.(string)
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return "", nil, invalidScimFilter("invalid time %q", s)
+		}
+		return compareScimColumn(c.expr, op, t)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", nil, invalidScimFilter("expected a string value")
+	}
+	return compareScimColumn(c.expr, op, s)
+}
+
+func compareScimColumn(expr, op string, value any) (string, []any, error) {
+	switch op {
+	case "eq":
+		return expr + " = ?", []any{value}, nil
+	case "ne":
+		return expr + " <> ?", []any{value}, nil
+	case "gt":
+		return expr + " > ?", []any{value}, nil
+	case "ge":
+		return expr + " >= ?", []any{value}, nil
+	case "lt":
+		return expr + " < ?", []any{value}, nil
+	case "le":
+		return expr + " <= ?", []any{value}, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", nil, invalidScimFilter("%s only supports strings", op)
+	}
+	s = escapeLike(s)
+	switch op {
+	case "co":
+		s = "%" + s + "%"
+	case "sw":
+		s = s + "%"
+	case "ew":
+		s = "%" + s
+	}
+	return expr + " LIKE ?", []any{s}, nil
+}
+
+// 转义 LIKE 中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// 资源id为字符串形式的正整数
+func scimUintValue(value any) (uint, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
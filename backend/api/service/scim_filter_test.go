@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"go-admin-server/common/response"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseScimFilter(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		filter  string
+		columns map[string]scimColumn
+		sql     string
+		args    []any
+	}{
+		{"empty", "  ", scimUserColumns, "", nil},
+		{"eq", `userName eq "alice"`, scimUserColumns, "sys_admin.username = ?", []any{"alice"}},
+		{"case insensitive attribute and operator", `USERNAME EQ "alice"`, scimUserColumns, "sys_admin.username = ?", []any{"alice"}},
+		{"schema prefix", `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice"`, scimUserColumns, "sys_admin.username = ?", []any{"alice"}},
+		{"escaped quote", `userName eq "a\"b"`, scimUserColumns, "sys_admin.username = ?", []any{`a"b`}},
+		{"ne", `emails.value ne "a@example.com"`, scimUserColumns, "sys_admin.email <> ?", []any{"a@example.com"}},
+		{"co escapes wildcards", `userName co "a%_b"`, scimUserColumns, "sys_admin.username LIKE ?", []any{`%a\%\_b%`}},
+		{"sw", `userName sw "adm"`, scimUserColumns, "sys_admin.username LIKE ?", []any{"adm%"}},
+		{"ew", `userName ew "min"`, scimUserColumns, "sys_admin.username LIKE ?", []any{"%min"}},
+		{"pr string", `externalId pr`, scimUserColumns, "(sys_admin.scim_external_id IS NOT NULL AND sys_admin.scim_external_id <> '')", nil},
+		{"eq null", `externalId eq null`, scimUserColumns, "NOT (sys_admin.scim_external_id IS NOT NULL AND sys_admin.scim_external_id <> '')", nil},
+		{"active true", `active eq true`, scimUserColumns, "sys_admin.status = 1", nil},
+		{"active ne true", `active ne true`, scimUserColumns, "sys_admin.status <> 1", nil},
+		{"id", `id eq "12"`, scimUserColumns, "sys_admin.id = ?", []any{uint(12)}},
+		{"unknown id never matches", `id eq "abc"`, scimUserColumns, "1 = 0", nil},
+		{"time", `meta.created gt "2024-01-02T03:04:05Z"`, scimUserColumns, "sys_admin.created_at > ?", []any{created}},
+		{
+			"and binds tighter than or", `userName eq "a" or userName eq "b" and active eq true`, scimUserColumns,
+			"(sys_admin.username = ? OR (sys_admin.username = ? AND sys_admin.status = 1))", []any{"a", "b"},
+		},
+		{
+			"not and parentheses", `not (userName eq "a" or userName eq "b")`, scimUserColumns,
+			"NOT ((sys_admin.username = ? OR sys_admin.username = ?))", []any{"a", "b"},
+		},
+		{
+			"value path", `members[value eq "5"]`, scimGroupColumns,
+			"(sys_role.id IN (SELECT role_id FROM sys_admin_role WHERE admin_id = ?))", []any{uint(5)},
+		},
+		{"members ne", `members ne "5"`, scimGroupColumns, "NOT sys_role.id IN (SELECT role_id FROM sys_admin_role WHERE admin_id = ?)", []any{uint(5)}},
+		{"members pr", `members pr`, scimGroupColumns, "sys_role.id IN (SELECT role_id FROM sys_admin_role)", nil},
+		{"unstored attribute", `externalId eq "x"`, scimGroupColumns, "1 = 0", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := parseScimFilter(tt.filter, tt.columns)
+			if err != nil {
+				t.Fatalf("parseScimFilter(%q) error: %v", tt.filter, err)
+			}
+			if sql != tt.sql {
+				t.Errorf("sql = %s, want %s", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestParseScimFilterInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		columns map[string]scimColumn
+	}{
+		{"unknown attribute", `password eq "x"`, scimUserColumns},
+		{"group attribute on users", `displayName eq "x" and members pr`, scimUserColumns},
+		{"unknown operator", `userName like "a"`, scimUserColumns},
+		{"missing value", `userName eq`, scimUserColumns},
+		{"unquoted string", `userName eq alice`, scimUserColumns},
+		{"unterminated string", `userName eq "alice`, scimUserColumns},
+		{"trailing token", `userName eq "a" "b"`, scimUserColumns},
+		{"missing right operand", `userName eq "a" and`, scimUserColumns},
+		{"unbalanced parenthesis", `(userName eq "a"`, scimUserColumns},
+		{"not without parenthesis", `not userName eq "a"`, scimUserColumns},
+		{"nested value path", `members[value[value eq "1"]]`, scimGroupColumns},
+		{"unclosed value path", `members[value eq "1"`, scimGroupColumns},
+		{"active with string", `active eq "true"`, scimUserColumns},
+		{"active gt", `active gt true`, scimUserColumns},
+		{"members co", `members co "1"`, scimGroupColumns},
+		{"null gt", `userName gt null`, scimUserColumns},
+		{"invalid time", `meta.created gt "yesterday"`, scimUserColumns},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseScimFilter(tt.filter, tt.columns)
+			var scimErr *response.ScimError
+			if !errors.As(err, &scimErr) {
+				t.Fatalf("parseScimFilter(%q) error = %v, want a SCIM error", tt.filter, err)
+			}
+			if scimErr.ScimType != response.ScimTypeInvalidFilter {
+				t.Errorf("scimType = %q, want %q", scimErr.ScimType, response.ScimTypeInvalidFilter)
+			}
+		})
+	}
+}
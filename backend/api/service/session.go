@@ -0,0 +1,74 @@
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+type SysSessionService struct{}
+
+// 获取用户的所有登录会话，currentSessionID 对应的会话会被标记为当前会话
+func (s *SysSessionService) GetMySessions(adminId uint, currentSessionID string) ([]entity.SysSession, error) {
+	sessions, err := SessionDao.GetAdminSessions(adminId)
+	if err != nil {
+		global.Logger.Error("Failed to get admin sessions", zap.Uint("adminId", adminId), zap.Error(err))
+		return nil, response.ErrServerError
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].SessionID == currentSessionID
+	}
+	// 按最近活跃时间倒序
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActiveAt.After(sessions[j].LastActiveAt.Time)
+	})
+	return sessions, nil
+}
+
+// 吊销用户自己的某个会话
+func (s *SysSessionService) RevokeSession(adminId uint, sessionID string) error {
+	session, err := SessionDao.GetSession(sessionID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	// 只能吊销属于自己的会话
+	if session == nil || session.AdminID != adminId {
+		return response.ErrSessionNotExists
+	}
+	if err := SessionDao.DeleteSession(adminId, sessionID); err != nil {
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 吊销用户除当前会话外的所有会话
+func (s *SysSessionService) RevokeOtherSessions(adminId uint, currentSessionID string) error {
+	if err := SessionDao.DeleteAdminSessions(adminId, currentSessionID); err != nil {
+		global.Logger.Error("Failed to delete admin sessions", zap.Uint("adminId", adminId), zap.Error(err))
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 退出登录，删除当前会话
+func (s *SysSessionService) Logout(adminId uint, currentSessionID string) error {
+	if err := SessionDao.DeleteSession(adminId, currentSessionID); err != nil {
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 强制用户下线，删除该用户的所有会话并吊销所有令牌
+func (s *SysSessionService) ForceLogout(scope *entity.DataScope, operatorId, adminId uint) error {
+	user, err := getScopedAdmin(scope, adminId)
+	if err != nil {
+		return err
+	}
+	if err := checkAdminRemovable(operatorId, user); err != nil {
+		return err
+	}
+	return revokeAdminTokens(user.ID)
+}
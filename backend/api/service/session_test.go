@@ -0,0 +1,101 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// 用户当前的会话id，按字典序排列
+func sessionIds(t *testing.T, adminId uint) []string {
+	t.Helper()
+	sessions, err := SessionDao.GetAdminSessions(adminId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, session := range sessions {
+		ids = append(ids, session.SessionID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestSessionManagement(t *testing.T) {
+	sessions := &SysSessionService{}
+	tests := []struct {
+		name      string
+		operation func(root, alice, bob *entity.SysAdmin) error
+		err       error
+		want      []string // 操作后 alice 的会话
+	}{
+		{"revoke own session", func(_, alice, _ *entity.SysAdmin) error {
+			return sessions.RevokeSession(alice.ID, "alice-2")
+		}, nil, []string{"alice-1"}},
+		{"revoke session of other admin", func(_, _, bob *entity.SysAdmin) error {
+			return sessions.RevokeSession(bob.ID, "alice-2")
+		}, response.ErrSessionNotExists, []string{"alice-1", "alice-2"}},
+		{"revoke missing session", func(_, alice, _ *entity.SysAdmin) error {
+			return sessions.RevokeSession(alice.ID, "missing")
+		}, response.ErrSessionNotExists, []string{"alice-1", "alice-2"}},
+		{"revoke other sessions", func(_, alice, _ *entity.SysAdmin) error {
+			return sessions.RevokeOtherSessions(alice.ID, "alice-1")
+		}, nil, []string{"alice-1"}},
+		{"logout", func(_, alice, _ *entity.SysAdmin) error {
+			return sessions.Logout(alice.ID, "alice-1")
+		}, nil, []string{"alice-2"}},
+		{"force logout", func(root, alice, _ *entity.SysAdmin) error {
+			return sessions.ForceLogout(&entity.DataScope{All: true}, root.ID, alice.ID)
+		}, nil, []string{}},
+		{"force logout self", func(_, alice, _ *entity.SysAdmin) error {
+			return sessions.ForceLogout(&entity.DataScope{All: true}, alice.ID, alice.ID)
+		}, response.ErrOperateSelf, []string{"alice-1", "alice-2"}},
+		{"force logout out of data scope", func(root, alice, _ *entity.SysAdmin) error {
+			return sessions.ForceLogout(&entity.DataScope{}, root.ID, alice.ID)
+		}, response.ErrAdminNotExists, []string{"alice-1", "alice-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			root, alice, bob := seedSuperRoles(t)
+			loginTestAdmin(t, alice, "alice-1")
+			loginTestAdmin(t, alice, "alice-2")
+			loginTestAdmin(t, bob, "bob-1")
+
+			if err := tt.operation(root, alice, bob); err != tt.err {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if got := sessionIds(t, alice.ID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alice sessions = %v, want %v", got, tt.want)
+			}
+			// 其他用户的会话不受影响
+			if got := sessionIds(t, bob.ID); !reflect.DeepEqual(got, []string{"bob-1"}) {
+				t.Errorf("bob sessions = %v, want [bob-1]", got)
+			}
+		})
+	}
+}
+
+func TestGetMySessions(t *testing.T) {
+	setupTestEnv(t)
+	alice := newTestAdmin(t, "alice", "")
+	mustCreate(t, alice)
+	loginTestAdmin(t, alice, "alice-1")
+	loginTestAdmin(t, alice, "alice-2")
+
+	list, err := (&SysSessionService{}).GetMySessions(alice.ID, "alice-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := map[string]bool{}
+	for _, session := range list {
+		current[session.SessionID] = session.Current
+	}
+	if want := map[string]bool{"alice-1": false, "alice-2": true}; !reflect.DeepEqual(current, want) {
+		t.Errorf("sessions = %v, want %v", current, want)
+	}
+}
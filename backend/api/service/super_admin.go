@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"go-admin-server/api/dao"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+
+	"gorm.io/gorm"
+)
+
+// 检查用户是否可以被删除、禁用或重置凭据(密码、两步验证、登录会话)：超级管理员账号受保护，
+// 用户也不能对自己进行这些操作，避免所有人都无法登录；修改自己的密码等请使用个人中心
+func checkAdminRemovable(operatorId uint, user *entity.SysAdmin) error {
+	if user.IsSuper {
+		return response.ErrSuperAdminProtected
+	}
+	if user.ID == operatorId {
+		return response.ErrOperateSelf
+	}
+	return checkAdminManageable(operatorId, user)
+}
+
+// 检查操作人是否可以管理其他用户：超级管理员账号只能由自己管理，
+// 拥有超级管理员角色的用户只能由超级管理员管理，避免通过重置密码等方式登录为超级管理员
+func checkAdminManageable(operatorId uint, user *entity.SysAdmin) error {
+	if user.ID == operatorId {
+		return nil
+	}
+	if user.IsSuper {
+		return response.ErrSuperAdminProtected
+	}
+	permission, err := SysMenuDao.GetAdminPermission(user.ID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if !permission.Super {
+		return nil
+	}
+	super, err := isSuperOperator(operatorId)
+	if err != nil {
+		return err
+	}
+	if !super {
+		return response.ErrSuperAdminProtected
+	}
+	return nil
+}
+
+// 操作人是否拥有超级管理员权限(超级管理员账号或超级管理员角色)
+func isSuperOperator(operatorId uint) (bool, error) {
+	permission, err := SysMenuDao.GetAdminPermission(operatorId)
+	if err != nil {
+		return false, response.ErrServerError
+	}
+	return permission.Super, nil
+}
+
+// 检查操作人是否可以授予超级管理员角色，直接分配角色和通过父角色继承都视为授予
+func checkSuperRoleGrantable(operatorId uint) error {
+	super, err := isSuperOperator(operatorId)
+	if err != nil {
+		return err
+	}
+	if !super {
+		return response.ErrSuperRoleDenied
+	}
+	return nil
+}
+
+// 检查操作人是否可以授予或修改这些角色：超级管理员角色和继承自超级管理员角色的角色需要操作人是超级管理员
+func checkSuperRolesGrantable(operatorId uint, roleIds ...uint) error {
+	super, err := inheritsSuperRole(roleIds...)
+	if err != nil {
+		return err
+	}
+	if super {
+		return checkSuperRoleGrantable(operatorId)
+	}
+	return nil
+}
+
+// 角色中是否有超级管理员角色或继承自超级管理员角色的角色
+func inheritsSuperRole(roleIds ...uint) (bool, error) {
+	if len(roleIds) == 0 {
+		return false, nil
+	}
+	tree, err := SysRoleDao.GetRoleTree()
+	if err != nil {
+		return false, response.ErrServerError
+	}
+	for _, roleId := range roleIds {
+		if dao.InheritsSuperRole(tree, roleId) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PromoteSuperAdmin 将用户设置为超级管理员并启用账号，解除登录锁定，仅供命令行使用
+func (s *SysAdminService) PromoteSuperAdmin(username string) error {
+	user, err := SysAdminDao.GetAdminByName(username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.ErrAdminNotExists
+		}
+		return err
+	}
+	user.IsSuper = true
+	user.Status = 1
+	if err := SysAdminDao.UpdateAdmin(user); err != nil {
+		return err
+	}
+	if err := LoginGuardDao.Unlock(userSubject(user.Username)); err != nil {
+		return err
+	}
+	return invalidateAdminPermissions(user.ID)
+}
+
+// RecoverSuperAdmin 找回超级管理员：设置为超级管理员、重置密码、关闭两步验证，并吊销已签发的令牌，仅供命令行使用
+func (s *SysAdminService) RecoverSuperAdmin(username, password string) error {
+	// 只检查密码策略，找回时允许使用曾经用过的密码
+	if err := checkNewPassword(nil, password); err != nil {
+		return err
+	}
+	if err := s.PromoteSuperAdmin(username); err != nil {
+		return err
+	}
+	user, err := SysAdminDao.GetAdminByName(username)
+	if err != nil {
+		return err
+	}
+	if err := applyNewPassword(user, password); err != nil {
+		return err
+	}
+	// 找回后需要使用密码登录
+	user.PasswordLoginDisabled = false
+	if err := SysAdminDao.UpdateAdmin(user); err != nil {
+		return err
+	}
+	recordPasswordHistory(user)
+	if err := TwoFactorDao.DisableTwoFactor(user.ID); err != nil {
+		return err
+	}
+	return revokeAdminTokens(user.ID)
+}
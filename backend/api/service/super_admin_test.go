@@ -0,0 +1,228 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"strconv"
+	"testing"
+)
+
+// 角色：1 超级管理员角色，2 继承 1，3 普通角色；用户：root 超级管理员账号，alice 拥有角色 3，bob 拥有角色 2
+func seedSuperRoles(t *testing.T) (root, alice, bob *entity.SysAdmin) {
+	t.Helper()
+	superId := uint(1)
+	mustCreate(t,
+		&entity.SysRole{ID: 1, RoleName: "超级管理员", RoleKey: global.SuperRoleKey, RoleStatus: 1, DataScope: entity.DataScopeAll},
+		&entity.SysRole{ID: 2, RoleName: "副管理员", RoleKey: "deputy", RoleStatus: 1, DataScope: entity.DataScopeAll, ParentID: &superId},
+		&entity.SysRole{ID: 3, RoleName: "开发", RoleKey: "dev", RoleStatus: 1, DataScope: entity.DataScopeAll},
+	)
+	root = newTestAdmin(t, "root", "")
+	root.IsSuper = true
+	alice = newTestAdmin(t, "alice", "")
+	bob = newTestAdmin(t, "bob", "")
+	mustCreate(t, root, alice, bob)
+	mustCreate(t,
+		&entity.SysAdminRole{AdminID: alice.ID, RoleID: 3},
+		&entity.SysAdminRole{AdminID: bob.ID, RoleID: 2},
+	)
+	return root, alice, bob
+}
+
+func TestSuperRoleProtected(t *testing.T) {
+	roles := &SysRoleService{}
+	ptr := func(s string) *string { return &s }
+	parent := uint(1)
+	tests := []struct {
+		name      string
+		operation func(operatorId uint) error
+		denied    bool  // 非超级管理员是否被拒绝
+		superErr  error // 超级管理员操作的结果
+	}{
+		{"edit super role", func(id uint) error {
+			return roles.UpdateRole(id, &entity.UpdateRoleDto{ID: 1, Description: ptr("x")})
+		}, true, nil},
+		{"edit inherited super role", func(id uint) error {
+			return roles.UpdateRole(id, &entity.UpdateRoleDto{ID: 2, Description: ptr("x")})
+		}, true, nil},
+		{"edit normal role", func(id uint) error {
+			return roles.UpdateRole(id, &entity.UpdateRoleDto{ID: 3, Description: ptr("x")})
+		}, false, nil},
+		{"change key from super", func(id uint) error {
+			return roles.UpdateRole(id, &entity.UpdateRoleDto{ID: 1, RoleKey: ptr("admin")})
+		}, true, nil},
+		{"change key to super", func(id uint) error {
+			return roles.UpdateRole(id, &entity.UpdateRoleDto{ID: 3, RoleKey: ptr(global.SuperRoleKey)})
+		}, true, response.ErrRoleKeyExists},
+		{"inherit super role", func(id uint) error {
+			return roles.UpdateRole(id, &entity.UpdateRoleDto{ID: 3, ParentID: &parent})
+		}, true, nil},
+		{"create super role", func(id uint) error {
+			return roles.CreateRole(id, &entity.CreateRoleDto{RoleName: "新角色", RoleKey: global.SuperRoleKey})
+		}, true, response.ErrRoleKeyExists},
+		{"create inherited super role", func(id uint) error {
+			return roles.CreateRole(id, &entity.CreateRoleDto{RoleName: "新角色", RoleKey: "new", ParentID: 1})
+		}, true, nil},
+		{"disable super role", func(id uint) error {
+			return roles.UpdateRoleStatus(id, &entity.UpdateRoleStatusDto{ID: 1, NewStatus: 2})
+		}, true, nil},
+		{"disable inherited super role", func(id uint) error {
+			return roles.UpdateRoleStatus(id, &entity.UpdateRoleStatusDto{ID: 2, NewStatus: 2})
+		}, true, nil},
+		{"disable normal role", func(id uint) error {
+			return roles.UpdateRoleStatus(id, &entity.UpdateRoleStatusDto{ID: 3, NewStatus: 2})
+		}, false, nil},
+		{"delete super role", func(id uint) error {
+			return roles.DeleteRole(id, 1)
+		}, true, response.ErrHasSubRole},
+		{"delete inherited super role", func(id uint) error {
+			return roles.DeleteRole(id, 2)
+		}, true, nil},
+		{"delete normal role", func(id uint) error {
+			return roles.DeleteRole(id, 3)
+		}, false, nil},
+		{"add admins to inherited super role", func(id uint) error {
+			return roles.AddRoleAdmins(&entity.DataScope{All: true}, id, &entity.AddRoleAdminsDto{ID: 2, AdminIDs: []uint{2}})
+		}, true, nil},
+		{"remove admins from inherited super role", func(id uint) error {
+			return roles.RemoveRoleAdmins(&entity.DataScope{All: true}, id, &entity.RoleAdminsDto{ID: 2, AdminIDs: []uint{3}})
+		}, true, nil},
+		{"assign inherited super role", func(id uint) error {
+			_, err := checkAssignableRoles(id, []uint{2, 3}, []uint{3})
+			return err
+		}, true, nil},
+		{"keep inherited super role", func(id uint) error {
+			_, err := checkAssignableRoles(id, []uint{2, 3}, []uint{2})
+			return err
+		}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			_, alice, _ := seedSuperRoles(t)
+			want := error(nil)
+			if tt.denied {
+				want = response.ErrSuperRoleDenied
+			}
+			if err := tt.operation(alice.ID); err != want {
+				t.Errorf("operator alice: error = %v, want %v", err, want)
+			}
+		})
+		t.Run(tt.name+" by super admin", func(t *testing.T) {
+			setupTestEnv(t)
+			root, _, _ := seedSuperRoles(t)
+			if err := tt.operation(root.ID); err != tt.superErr {
+				t.Errorf("operator root: error = %v, want %v", err, tt.superErr)
+			}
+		})
+	}
+}
+
+func TestScimSuperRoleReadOnly(t *testing.T) {
+	setupTestEnv(t)
+	_, alice, bob := seedSuperRoles(t)
+
+	for roleId, writable := range map[uint]bool{1: false, 2: false, 3: true} {
+		if _, err := getWritableScimRole(strconv.Itoa(int(roleId))); (err == nil) != writable {
+			t.Errorf("getWritableScimRole(%d) error = %v, want writable %v", roleId, err, writable)
+		}
+	}
+	// 拥有继承超级管理员角色的用户不能通过 SCIM 加入或移出组
+	member := func(id uint) entity.ScimMultiValue { return entity.ScimMultiValue{Value: strconv.Itoa(int(id))} }
+	if _, err := scimMemberIds([]entity.ScimMultiValue{member(alice.ID)}); err != nil {
+		t.Errorf("scimMemberIds(alice) error = %v", err)
+	}
+	if _, err := scimMemberIds([]entity.ScimMultiValue{member(alice.ID), member(bob.ID)}); err == nil {
+		t.Error("scimMemberIds() accepted a member holding an inherited super role")
+	}
+}
+
+func TestSuperAdminProtected(t *testing.T) {
+	admins := &SysAdminService{}
+	scope := &entity.DataScope{All: true}
+	operations := map[string]func(operatorId, adminId uint) error{
+		"delete": func(operatorId, adminId uint) error {
+			return admins.DeleteAdmin(scope, operatorId, adminId)
+		},
+		"disable": func(operatorId, adminId uint) error {
+			return admins.UpdateAdminStatus(scope, operatorId, &entity.UpdateAdminStatusDto{ID: adminId, NewStatus: 2})
+		},
+		"reset password": func(operatorId, adminId uint) error {
+			return admins.ResetPassword(scope, operatorId, &entity.ResetPasswordDto{ID: adminId, NewPassword: "NewPass456"})
+		},
+	}
+	tests := []struct {
+		operator string
+		target   string
+		err      error
+	}{
+		{"alice", "root", response.ErrSuperAdminProtected},
+		{"root", "root", response.ErrSuperAdminProtected},
+		// bob 拥有继承超级管理员角色的角色，只能由超级管理员管理
+		{"alice", "bob", response.ErrSuperAdminProtected},
+		{"root", "bob", nil},
+		{"alice", "alice", response.ErrOperateSelf},
+		{"alice", "carol", nil},
+	}
+	for name, operation := range operations {
+		for _, tt := range tests {
+			t.Run(name+" "+tt.target+" by "+tt.operator, func(t *testing.T) {
+				setupTestEnv(t)
+				seedSuperRoles(t)
+				mustCreate(t, newTestAdmin(t, "carol", ""))
+
+				target := adminByName(t, tt.target)
+				if err := operation(adminByName(t, tt.operator).ID, target.ID); err != tt.err {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				// 被拒绝时用户保持不变
+				if tt.err != nil {
+					if after := adminByName(t, tt.target); after.Status != 1 || after.Password != target.Password {
+						t.Errorf("%s was modified", tt.target)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRecoverSuperAdmin(t *testing.T) {
+	setupTestEnv(t)
+	global.Config.LoginSecurity.MaxUserFailures = 1
+	carol := newTestAdmin(t, "carol", "")
+	carol.Status = 2
+	carol.PasswordLoginDisabled = true
+	carol.TotpEnabled = true
+	mustCreate(t, carol)
+	pair := loginTestAdmin(t, carol, "session-1")
+	if err := recordLoginFailure(carol.Username, "10.0.0.1"); err == nil {
+		t.Fatal("carol was not locked")
+	}
+
+	if err := (&SysAdminService{}).RecoverSuperAdmin("carol", "NewPass456"); err != nil {
+		t.Fatalf("RecoverSuperAdmin() error = %v", err)
+	}
+	recovered := adminByName(t, "carol")
+	if !recovered.IsSuper || recovered.Status != 1 || recovered.PasswordLoginDisabled || recovered.TotpEnabled {
+		t.Errorf("recovered admin = %+v", recovered)
+	}
+	if !encrypt.VerifyPassword(recovered.Password, "NewPass456") {
+		t.Error("password was not reset")
+	}
+	if err := checkLoginLocked(carol.Username, "10.0.0.2"); err != nil {
+		t.Errorf("carol is still locked: %v", err)
+	}
+	if _, err := (&SysAdminService{}).RefreshToken(pair.RefreshToken); err != response.ErrTokenRevoked {
+		t.Errorf("RefreshToken() error = %v, want %v", err, response.ErrTokenRevoked)
+	}
+	// 找回后拥有全部权限
+	if super, err := isSuperOperator(carol.ID); err != nil || !super {
+		t.Errorf("isSuperOperator() = %v, %v, want true", super, err)
+	}
+	if err := (&SysAdminService{}).RecoverSuperAdmin("missing", "NewPass456"); err != response.ErrAdminNotExists {
+		t.Errorf("RecoverSuperAdmin(missing) error = %v, want %v", err, response.ErrAdminNotExists)
+	}
+}
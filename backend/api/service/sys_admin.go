@@ -5,121 +5,269 @@ import (
 	"go-admin-server/api/entity"
 	"go-admin-server/common/response"
 	"go-admin-server/common/utils"
+	"go-admin-server/global"
 	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/geoip"
 	"go-admin-server/pkg/jwt"
+	"slices"
 	"time"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type SysAdminService struct{}
 
+// 吊销用户所有未过期的令牌并删除所有登录会话，用户需要重新登录
+func revokeAdminTokens(adminId uint) error {
+	if err := TokenDao.RevokeAdminTokens(adminId); err != nil {
+		global.Logger.Error("Failed to revoke admin tokens", zap.Uint("adminId", adminId), zap.Error(err))
+		return response.ErrServerError
+	}
+	if err := SessionDao.DeleteAdminSessions(adminId, ""); err != nil {
+		global.Logger.Error("Failed to delete admin sessions", zap.Uint("adminId", adminId), zap.Error(err))
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 创建登录会话，并为会话签发访问令牌和刷新令牌
+func issueTokenPair(user *entity.SysAdmin, loginMethod, ip, browser, Os, device string) (*jwt.TokenPair, error) {
+	version, err := TokenDao.GetTokenVersion(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	sessionID, err := utils.RandomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	now := utils.HTime{Time: time.Now()}
+	session := &entity.SysSession{
+		SessionID:    sessionID,
+		AdminID:      user.ID,
+		Username:     user.Username,
+		Device:       device,
+		Browser:      browser,
+		Os:           Os,
+		Ip:           ip,
+		LoginAt:      now,
+		LastActiveAt: now,
+		LoginMethod:  loginMethod,
+	}
+	if err := SessionDao.SaveSession(session, jwt.RefreshTTL()); err != nil {
+		return nil, err
+	}
+	// 登录地点查询较慢，在后台补全，不影响登录
+	go fillSessionLocation(sessionID, ip)
+	return jwt.GenerateTokenPair(user, sessionID, tokenScope(user, loginMethod), version)
+}
+
+// 根据IP查询会话的登录地点
+func fillSessionLocation(sessionID, ip string) {
+	location := geoip.Lookup(ip).String()
+	if err := SessionDao.UpdateSessionLocation(sessionID, location); err != nil {
+		global.Logger.Error("Failed to update session location", zap.String("sessionId", sessionID), zap.Error(err))
+	}
+}
+
+// 使用本地密码登录且密码已过期时令牌只能用于修改密码，单点登录和LDAP登录不受本地密码过期限制
+func tokenScope(user *entity.SysAdmin, loginMethod string) string {
+	localPassword := loginMethod == "" || loginMethod == entity.LoginMethodPassword
+	if localPassword && isPasswordExpired(user) {
+		return jwt.ScopePasswordChange
+	}
+	return ""
+}
+
 // 用户登录
-func (s *SysAdminService) Login(ip, browser, Os string, dto *entity.LoginDto) (*entity.SysAdmin, string, error) {
+func (s *SysAdminService) Login(ip, browser, Os, device string, dto *entity.LoginDto) (*entity.SysAdmin, *jwt.TokenPair, *entity.LoginChallengeVo, error) {
+	// 检查账号和IP是否已被锁定
+	if err := checkLoginLocked(dto.Username, ip); err != nil {
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, err.Error(), 2)
+		return nil, nil, nil, err
+	}
 	// 先检查验证码
 	if !captchaStore.Verify(dto.CaptchaID, dto.CaptchaImage, true) {
-		SysLogDao.CreateLoginLog(dto.Username, ip, utils.GetRealAddressByIP(ip), browser, Os, "验证码错误或失效", 2)
-		return nil, "", response.ErrCaptchaError
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "验证码错误或失效", 2)
+		return nil, nil, nil, response.ErrCaptchaError
 	}
-	// 根据名称获取用户
+	// 根据名称获取用户，启用LDAP时目录中的用户可以还没有本地账号
 	user, err := SysAdminDao.GetAdminByName(dto.Username)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			SysLogDao.CreateLoginLog(dto.Username, ip, utils.GetRealAddressByIP(ip), browser, Os, "用户名或密码错误", 2)
-			return nil, "", response.ErrLoginError
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "服务器故障", 2)
+			return nil, nil, nil, response.ErrServerError
 		}
-		SysLogDao.CreateLoginLog(dto.Username, ip, utils.GetRealAddressByIP(ip), browser, Os, "服务器故障", 2)
-		return nil, "", response.ErrServerError
+		user = nil
 	}
-	// 检查密码
-	if !encrypt.VerifyPassword(user.Password, dto.Password) {
-		SysLogDao.CreateLoginLog(dto.Username, ip, utils.GetRealAddressByIP(ip), browser, Os, "用户名或密码错误", 2)
-		return nil, "", response.ErrLoginError
+	// 检查密码，启用LDAP时先由目录认证
+	user, loginMethod, err := verifyLoginPassword(user, dto.Username, dto.Password)
+	if err != nil {
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, err.Error(), 2)
+		return nil, nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, nil, s.loginFailed(ip, browser, Os, dto.Username)
 	}
 
 	// 检测账号状态
 	if user.Status == 2 {
-		SysLogDao.CreateLoginLog(dto.Username, ip, utils.GetRealAddressByIP(ip), browser, Os, "账号已停用", 2)
-		return nil, "", response.ErrAdminDisabled
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "账号已停用", 2)
+		return nil, nil, nil, response.ErrAdminDisabled
+	}
+	if user.Status == 3 {
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "账号未激活", 2)
+		return nil, nil, nil, response.ErrAdminPending
+	}
+	// 服务账号只能使用API令牌
+	if user.IsServiceAccount {
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "服务账号不能登录", 2)
+		return nil, nil, nil, response.ErrServiceAccountLogin
+	}
+	// 只允许单点登录的账号
+	if user.PasswordLoginDisabled {
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "已禁用密码登录", 2)
+		return nil, nil, nil, response.ErrPasswordLoginDisabled
+	}
+
+	// 需要两步验证时先返回挑战令牌，验证码通过后再签发令牌
+	challenge, err := createLoginChallenge(user, loginMethod, ip, browser, Os, device)
+	if err != nil {
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "服务器故障", 2)
+		return nil, nil, nil, response.ErrServerError
+	}
+	if challenge != nil {
+		return user, nil, challenge, nil
 	}
 
 	// 生成token
-	tokenString, err := jwt.GenerateToken(user)
+	tokenPair, err := issueTokenPair(user, loginMethod, ip, browser, Os, device)
 	if err != nil {
-		SysLogDao.CreateLoginLog(dto.Username, ip, utils.GetRealAddressByIP(ip), browser, Os, "服务器故障", 2)
-		return nil, "", response.ErrServerError
+		SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "服务器故障", 2)
+		return nil, nil, nil, response.ErrServerError
 	}
 
 	// 登录成功
-	SysLogDao.CreateLoginLog(dto.Username, ip, utils.GetRealAddressByIP(ip), browser, Os, "登录成功", 1)
-	return user, tokenString, nil
+	clearLoginFailures(dto.Username)
+	SysLogDao.CreateLoginLog(dto.Username, ip, browser, Os, "登录成功", 1)
+	return user, tokenPair, nil, nil
 }
 
-// 创建用户
-func (s *SysAdminService) CreateAdmin(dto *entity.CreateAdminDto) error {
-	// 检查名称是否已被占用
-	nameExists, err := SysAdminDao.ExistsByName(dto.Username)
+// 用户名或密码错误：记录登录日志和失败次数，失败次数过多时锁定账号或IP并记录锁定事件
+func (s *SysAdminService) loginFailed(ip, browser, Os, username string) error {
+	SysLogDao.CreateLoginLog(username, ip, browser, Os, "用户名或密码错误", 2)
+	if err := recordLoginFailure(username, ip); err != nil {
+		SysLogDao.CreateLoginLog(username, ip, browser, Os, err.Error(), 2)
+		return err
+	}
+	return response.ErrLoginError
+}
+
+// 解除用户的登录锁定
+func (s *SysAdminService) UnlockAdmin(scope *entity.DataScope, operatorId, id uint) error {
+	user, err := getScopedAdmin(scope, id)
 	if err != nil {
-		return response.ErrServerError
+		return err
 	}
-	if nameExists {
-		return response.ErrAdminNameExists
+	if err := checkAdminManageable(operatorId, user); err != nil {
+		return err
 	}
+	if err := LoginGuardDao.Unlock(userSubject(user.Username)); err != nil {
+		return response.ErrServerError
+	}
+	return nil
+}
 
-	// 检查昵称是否已被占用
-	nicknameExists, err := SysAdminDao.ExistsNickname(dto.Nickname)
+// 刷新令牌：校验刷新令牌后签发新的令牌对，旧的刷新令牌随即吊销，保证每个刷新令牌只能使用一次
+func (s *SysAdminService) RefreshToken(refreshToken string) (*jwt.TokenPair, error) {
+	claims, err := jwt.ParseToken(refreshToken)
+	if err != nil || claims.TokenType != jwt.TokenTypeRefresh {
+		return nil, response.ErrTokenInvalid
+	}
+	// 用户的令牌版本号已变化，说明令牌已被整体吊销
+	version, err := TokenDao.GetTokenVersion(claims.JwtAdmin.ID)
 	if err != nil {
-		return response.ErrServerError
+		return nil, response.ErrServerError
 	}
-	if nicknameExists {
-		return response.ErrAdminNicknameExists
+	if version != claims.TokenVersion {
+		return nil, response.ErrTokenRevoked
 	}
-
-	// 检查部门
-	sysDept, err := SysDeptDao.GetDeptById(dto.DeptID)
+	user, err := SysAdminDao.GetAdminById(claims.JwtAdmin.ID)
 	if err != nil {
-		// 如果部门不存在
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return response.ErrDeptNotExists
+			return nil, response.ErrAdminNotExists
 		}
-		return response.ErrServerError
+		return nil, response.ErrServerError
 	}
-	// 部门已停用
-	if sysDept.DeptStatus == 2 {
-		return response.ErrDeptDisabled
+	if user.Status != 1 {
+		return nil, response.ErrAdminDisabled
 	}
-
-	// 检查岗位
-	sysPost, err := SysPostDao.GetSysPostById(dto.PostID)
+	// 会话已被删除(退出登录、被强制下线)时不能再刷新
+	session, err := SessionDao.GetSession(claims.SessionID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return response.ErrPostNotExists
-		}
-		return response.ErrServerError
+		return nil, response.ErrServerError
 	}
-	if sysPost.PostStatus == 2 {
-		return response.ErrPostDisabled
+	if session == nil || session.AdminID != user.ID {
+		return nil, response.ErrTokenRevoked
 	}
-
-	// 检查角色
-	sysRole, err := SysRoleDao.GetRoleByID(dto.RoleID)
+	// 吊销旧的刷新令牌
+	consumed, err := TokenDao.ConsumeToken(claims.RegisteredClaims.ID, claims.ExpiresAt.Time)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return response.ErrRoleNotExists
+		return nil, response.ErrServerError
+	}
+	if !consumed {
+		return nil, response.ErrTokenRevoked
+	}
+	// 模拟登录会话不会因刷新令牌延长有效期，并且不受被模拟用户的密码过期限制
+	if claims.Impersonator != nil {
+		ttl, err := impersonationSessionTTL(claims.Impersonator, session)
+		if err != nil {
+			return nil, err
 		}
-		return response.ErrServerError
+		session.LastActiveAt = utils.HTime{Time: time.Now()}
+		if err := SessionDao.SaveImpersonationSession(session, ttl, jwt.RefreshTTL()); err != nil {
+			return nil, response.ErrServerError
+		}
+		tokenPair, err := jwt.GenerateImpersonationTokenPair(user, session.SessionID, version, claims.Impersonator)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		return tokenPair, nil
+	}
+	// 刷新会话的活跃时间与有效期
+	session.LastActiveAt = utils.HTime{Time: time.Now()}
+	if err := SessionDao.SaveSession(session, jwt.RefreshTTL()); err != nil {
+		return nil, response.ErrServerError
 	}
-	if sysRole.RoleStatus == 2 {
-		return response.ErrRoleDisabled
+	tokenPair, err := jwt.GenerateTokenPair(user, session.SessionID, tokenScope(user, session.LoginMethod), version)
+	if err != nil {
+		return nil, response.ErrServerError
 	}
+	return tokenPair, nil
+}
 
-	// 密码加密
-	hashPassword, _ := encrypt.EncryptPassword(dto.Password)
+// 创建用户
+func (s *SysAdminService) CreateAdmin(scope *entity.DataScope, operatorId uint, dto *entity.CreateAdminDto) error {
+	roleIds, err := checkNewAdmin(scope, operatorId, dto.Username, dto.Nickname, dto.DeptID, dto.PostID, dto.RoleIDs)
+	if err != nil {
+		return err
+	}
+
+	// 服务账号和未设置密码的单点登录账号使用随机密码；其他用户检查密码策略
+	password := dto.Password
+	if dto.ServiceAccount || (dto.PasswordLoginDisabled && password == "") {
+		random, err := utils.RandomHex(32)
+		if err != nil {
+			return response.ErrServerError
+		}
+		password = random
+	} else if err := checkNewPassword(nil, password); err != nil {
+		return err
+	}
 
 	// 创建用户的同时，分配角色
 	sysAdmin := &entity.SysAdmin{
 		Username:  dto.Username,
-		Password:  hashPassword,
 		Nickname:  dto.Nickname,
 		Email:     dto.Email,
 		Phone:     dto.Phone,
@@ -128,15 +276,79 @@ func (s *SysAdminService) CreateAdmin(dto *entity.CreateAdminDto) error {
 		DeptID:    dto.DeptID,
 		PostID:    dto.PostID,
 		CreatedAt: utils.HTime{Time: time.Now()},
+
+		IsServiceAccount: dto.ServiceAccount,
+
+		PasswordLoginDisabled: dto.PasswordLoginDisabled,
 	}
-	if err := SysAdminDao.CreateAdmin(dto.RoleID, sysAdmin); err != nil {
+	// 密码加密
+	if err := applyNewPassword(sysAdmin, password); err != nil {
 		return response.ErrServerError
 	}
+	if err := SysAdminDao.CreateAdmin(roleIds, sysAdmin); err != nil {
+		return response.ErrServerError
+	}
+	recordPasswordHistory(sysAdmin)
 	return nil
 }
 
+// 创建用户前的检查：用户名和昵称未被占用，部门在数据权限范围内且部门、岗位、角色可用，返回去重后的角色id
+func checkNewAdmin(scope *entity.DataScope, operatorId uint, username, nickname string, deptId, postId uint, roleIds []uint) ([]uint, error) {
+	// 检查名称是否已被占用
+	nameExists, err := SysAdminDao.ExistsByName(username)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if nameExists {
+		return nil, response.ErrAdminNameExists
+	}
+
+	// 检查昵称是否已被占用
+	nicknameExists, err := SysAdminDao.ExistsNickname(nickname)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if nicknameExists {
+		return nil, response.ErrAdminNicknameExists
+	}
+
+	// 只能在数据权限范围内的部门创建用户
+	if !scope.ContainsDept(deptId) {
+		return nil, response.ErrDataScopeDenied
+	}
+
+	// 检查部门
+	sysDept, err := SysDeptDao.GetDeptById(deptId)
+	if err != nil {
+		// 如果部门不存在
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrDeptNotExists
+		}
+		return nil, response.ErrServerError
+	}
+	// 部门已停用
+	if sysDept.DeptStatus == 2 {
+		return nil, response.ErrDeptDisabled
+	}
+
+	// 检查岗位
+	sysPost, err := SysPostDao.GetSysPostById(postId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrPostNotExists
+		}
+		return nil, response.ErrServerError
+	}
+	if sysPost.PostStatus == 2 {
+		return nil, response.ErrPostDisabled
+	}
+
+	// 检查角色
+	return checkAssignableRoles(operatorId, roleIds, nil)
+}
+
 // 联表查询用户信息列表
-func (s *SysAdminService) JointGetAdminList(pageNum, pageSize, status int, username, beginTime, endTime string) (*entity.AdminListVo, error) {
+func (s *SysAdminService) JointGetAdminList(scope *entity.DataScope, pageNum, pageSize, status int, username, beginTime, endTime string) (*entity.AdminListVo, error) {
 	if pageNum < 1 {
 		pageNum = 1
 	}
@@ -144,14 +356,32 @@ func (s *SysAdminService) JointGetAdminList(pageNum, pageSize, status int, usern
 		pageSize = 10
 	}
 
-	if status != 1 && status != 2 {
+	if status != 1 && status != 2 && status != 3 {
 		status = 1
 	}
 
-	sysAdminList, total, err := SysAdminDao.JointGetAdminList(pageNum, pageSize, status, username, beginTime, endTime)
+	sysAdminList, total, err := SysAdminDao.JointGetAdminList(scope, pageNum, pageSize, status, username, beginTime, endTime)
 	if err != nil {
 		return nil, response.ErrServerError
 	}
+	// 批量查询当前页用户的角色
+	if len(sysAdminList) > 0 {
+		adminIds := make([]uint, len(sysAdminList))
+		for i, admin := range sysAdminList {
+			adminIds[i] = admin.ID
+		}
+		adminRoles, err := SysAdminDao.GetAdminsRoles(adminIds)
+		if err != nil {
+			return nil, response.ErrServerError
+		}
+		rolesByAdmin := make(map[uint][]entity.AdminRoleVo)
+		for _, role := range adminRoles {
+			rolesByAdmin[role.AdminID] = append(rolesByAdmin[role.AdminID], role)
+		}
+		for i := range sysAdminList {
+			sysAdminList[i].Roles = rolesByAdmin[sysAdminList[i].ID]
+		}
+	}
 	adminListVo := &entity.AdminListVo{
 		Data: sysAdminList,
 		Pagination: response.PaginationMeta{
@@ -165,30 +395,39 @@ func (s *SysAdminService) JointGetAdminList(pageNum, pageSize, status int, usern
 }
 
 // 联表查询单个用户信息
-func (s *SysAdminService) JointGetAdminById(userId uint) (*entity.GetAdminByIdVo, error) {
-	sysAdmin, err := SysAdminDao.JointGetAdminById(userId)
+func (s *SysAdminService) JointGetAdminById(scope *entity.DataScope, userId uint) (*entity.GetAdminByIdVo, error) {
+	sysAdmin, err := SysAdminDao.JointGetAdminById(scope, userId)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, response.ErrAdminNotExists
 		}
 		return nil, response.ErrServerError
 	}
+	roles, err := SysAdminDao.GetAdminsRoles([]uint{userId})
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	sysAdmin.Roles = roles
 	return sysAdmin, nil
 }
 
 // 修改用户信息
-func (s *SysAdminService) UpdateSysAdmin(dto *entity.UpdateAdminDto) error {
+func (s *SysAdminService) UpdateSysAdmin(scope *entity.DataScope, operatorId uint, dto *entity.UpdateAdminDto) error {
 	// 获取当前用户
-	user, err := SysAdminDao.GetAdminById(dto.ID)
+	user, err := getScopedAdmin(scope, dto.ID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return response.ErrAdminNotExists
-		}
-		return response.ErrServerError
+		return err
+	}
+	if err := checkAdminManageable(operatorId, user); err != nil {
+		return err
 	}
 
 	// 逐个字段检查
 	if dto.Username != nil && *dto.Username != user.Username {
+		// 目录账号按用户名与目录关联
+		if user.LdapDN != "" {
+			return response.ErrLdapAccountManaged
+		}
 		// 检查新名字是否被占用
 		exists, _ := SysAdminDao.ExistsByName(*dto.Username)
 		if exists {
@@ -206,6 +445,10 @@ func (s *SysAdminService) UpdateSysAdmin(dto *entity.UpdateAdminDto) error {
 	}
 	// 检查新的部门、岗位的存在性，以及状态
 	if dto.DeptId != nil && *dto.DeptId != user.DeptID {
+		// 不能把用户调到数据权限范围外的部门
+		if !scope.ContainsDept(*dto.DeptId) {
+			return response.ErrDataScopeDenied
+		}
 		dept, err := SysDeptDao.GetDeptById(*dto.DeptId)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -237,71 +480,255 @@ func (s *SysAdminService) UpdateSysAdmin(dto *entity.UpdateAdminDto) error {
 	if dto.Email != nil && *dto.Email != user.Email {
 		user.Email = *dto.Email
 	}
+	// 状态或角色发生变化时，需要吊销用户已签发的令牌
+	needRevoke := false
 	if dto.Status != nil && *dto.Status != user.Status {
+		// 待激活的用户需要接受邀请后才能启用
+		if user.Status == 3 {
+			return response.ErrAdminPending
+		}
+		if *dto.Status == 2 {
+			if err := checkAdminRemovable(operatorId, user); err != nil {
+				return err
+			}
+		}
 		user.Status = *dto.Status
+		needRevoke = true
 	}
 	if dto.Note != nil {
 		user.Note = *dto.Note
 	}
+	if dto.PasswordLoginDisabled != nil && *dto.PasswordLoginDisabled != user.PasswordLoginDisabled {
+		// 只允许单点登录可能导致无法登录，与禁用账号同样处理
+		if *dto.PasswordLoginDisabled {
+			if err := checkAdminRemovable(operatorId, user); err != nil {
+				return err
+			}
+		}
+		user.PasswordLoginDisabled = *dto.PasswordLoginDisabled
+	}
+	// 写入前先检查角色，避免角色不可分配时用户信息已被修改
+	var oldRoleIds, newRoleIds []uint
+	if dto.RoleIds != nil {
+		oldRoleIds, err = SysAdminDao.GetAdminRoleIds(dto.ID)
+		if err != nil {
+			return response.ErrServerError
+		}
+		newRoleIds, err = checkAssignableRoles(operatorId, dto.RoleIds, oldRoleIds)
+		if err != nil {
+			return err
+		}
+	}
 	// 修改用户信息
 	if err := SysAdminDao.UpdateAdmin(user); err != nil {
 		return response.ErrServerError
 	}
 	// 修改角色信息
-	if err := SysAdminDao.UpdateAdminRole(dto.ID, *dto.RoleId); err != nil {
-		return response.ErrServerError
+	if dto.RoleIds != nil && !sameIds(oldRoleIds, newRoleIds) {
+		if err := SysAdminDao.UpdateAdminRoles(dto.ID, newRoleIds); err != nil {
+			return response.ErrServerError
+		}
+		if err := invalidateAdminPermissions(dto.ID); err != nil {
+			return err
+		}
+		needRevoke = true
+	}
+	if needRevoke {
+		return revokeAdminTokens(dto.ID)
 	}
 	return nil
 }
 
-// 删除用户
-func (s *SysAdminService) DeleteAdmin(userId uint) error {
-	// 先检查用户是否存在
-	_, err := SysAdminDao.GetAdminById(userId)
+// 检查要分配的角色都存在且已启用，新授予超级管理员角色或继承它的角色时操作人需要是超级管理员，返回去重后的角色id；
+// currentRoleIds 为用户已有的角色
+func checkAssignableRoles(operatorId uint, roleIds, currentRoleIds []uint) ([]uint, error) {
+	roleIds = uniqueIds(roleIds)
+	if len(roleIds) == 0 {
+		return roleIds, nil
+	}
+	roles, err := SysRoleDao.GetRolesByIds(roleIds)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if len(roles) != len(roleIds) {
+		return nil, response.ErrRoleNotExists
+	}
+	var addedRoleIds []uint
+	for _, role := range roles {
+		if role.RoleStatus == 2 {
+			return nil, response.ErrRoleDisabled
+		}
+		if !slices.Contains(currentRoleIds, role.ID) {
+			addedRoleIds = append(addedRoleIds, role.ID)
+		}
+	}
+	if err := checkSuperRolesGrantable(operatorId, addedRoleIds...); err != nil {
+		return nil, err
+	}
+	return roleIds, nil
+}
+
+// 检查自动创建用户使用的部门和岗位存在且未停用
+func checkProvisionDeptPost(deptId, postId uint) error {
+	dept, err := SysDeptDao.GetDeptById(deptId)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return response.ErrAdminNotExists
+			return response.ErrDeptNotExists
 		}
 		return response.ErrServerError
 	}
+	if dept.DeptStatus == 2 {
+		return response.ErrDeptDisabled
+	}
+	post, err := SysPostDao.GetSysPostById(postId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.ErrPostNotExists
+		}
+		return response.ErrServerError
+	}
+	if post.PostStatus == 2 {
+		return response.ErrPostDisabled
+	}
+	return nil
+}
+
+// id列表去重，保持原有顺序
+func uniqueIds(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// 判断两个不含重复元素的id列表是否相同，忽略顺序
+func sameIds(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[uint]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	for _, id := range b {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// 按外部系统的用户组合并角色：去掉 managedIds 中不在 wantedIds 里的角色，加上 wantedIds，
+// 其他手动分配的角色保持不变
+func mergeManagedRoles(oldRoleIds, managedIds, wantedIds []uint) []uint {
+	managed := make(map[uint]bool, len(managedIds))
+	for _, id := range managedIds {
+		managed[id] = true
+	}
+	newRoleIds := make([]uint, 0, len(oldRoleIds)+len(wantedIds))
+	for _, id := range oldRoleIds {
+		if !managed[id] {
+			newRoleIds = append(newRoleIds, id)
+		}
+	}
+	return uniqueIds(append(newRoleIds, wantedIds...))
+}
+
+// 自动创建用户的昵称：依次尝试候选昵称，都已被占用时使用带随机后缀的用户名
+func uniqueNickname(username string, candidates ...string) (string, error) {
+	for _, nickname := range append(candidates, username) {
+		if nickname == "" {
+			continue
+		}
+		exists, err := SysAdminDao.ExistsNickname(nickname)
+		if err != nil {
+			return "", response.ErrServerError
+		}
+		if !exists {
+			return nickname, nil
+		}
+	}
+	suffix, err := utils.RandomHex(3)
+	if err != nil {
+		return "", response.ErrServerError
+	}
+	return username + "_" + suffix, nil
+}
+
+// 删除用户
+func (s *SysAdminService) DeleteAdmin(scope *entity.DataScope, operatorId, userId uint) error {
+	// 先检查用户是否存在
+	user, err := getScopedAdmin(scope, userId)
+	if err != nil {
+		return err
+	}
+	if err := checkAdminRemovable(operatorId, user); err != nil {
+		return err
+	}
 	// 删除用户
 	if err := SysAdminDao.DeleteAdmin(userId); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	if err := invalidateAdminPermissions(userId); err != nil {
+		return err
+	}
+	return revokeAdminTokens(userId)
 }
 
 // 修改用户状态
-func (s *SysAdminService) UpdateAdminStatus(dto *entity.UpdateAdminStatusDto) error {
-	user, err := SysAdminDao.GetAdminById(dto.ID)
+func (s *SysAdminService) UpdateAdminStatus(scope *entity.DataScope, operatorId uint, dto *entity.UpdateAdminStatusDto) error {
+	user, err := getScopedAdmin(scope, dto.ID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return response.ErrAdminNotExists
+		return err
+	}
+	if user.Status == dto.NewStatus {
+		return nil
+	}
+	if user.Status == 3 {
+		return response.ErrAdminPending
+	}
+	if dto.NewStatus == 2 {
+		if err := checkAdminRemovable(operatorId, user); err != nil {
+			return err
 		}
-		return response.ErrServerError
 	}
 	user.Status = dto.NewStatus
 	if err := SysAdminDao.UpdateAdmin(user); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	return revokeAdminTokens(user.ID)
 }
 
 // 修改用户密码
-func (s *SysAdminService) ResetPassword(dto *entity.ResetPasswordDto) error {
-	user, err := SysAdminDao.GetAdminById(dto.ID)
+func (s *SysAdminService) ResetPassword(scope *entity.DataScope, operatorId uint, dto *entity.ResetPasswordDto) error {
+	user, err := getScopedAdmin(scope, dto.ID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return response.ErrAdminNotExists
-		}
+		return err
+	}
+	if err := checkAdminRemovable(operatorId, user); err != nil {
+		return err
+	}
+	// 目录账号使用目录中的密码登录
+	if user.LdapDN != "" {
+		return response.ErrLdapPasswordManaged
+	}
+	// 检查密码策略和密码历史
+	if err := checkNewPassword(user, dto.NewPassword); err != nil {
+		return err
+	}
+	if err := applyNewPassword(user, dto.NewPassword); err != nil {
 		return response.ErrServerError
 	}
-	newHashPassword, _ := encrypt.EncryptPassword(dto.NewPassword)
-	user.Password = newHashPassword
 	if err := SysAdminDao.UpdateAdmin(user); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	recordPasswordHistory(user)
+	return revokeAdminTokens(user.ID)
 }
 
 // 修改个人资料
@@ -313,6 +740,9 @@ func (s *SysAdminService) UpdatePersonal(adminId uint, dto *entity.UpdatePersona
 	}
 	// 修改用户信息
 	if dto.Username != nil && *dto.Username != admin.Username {
+		if admin.LdapDN != "" {
+			return response.ErrLdapAccountManaged
+		}
 		// 改名前先判断新名字是否被占用
 		nameExists, _ := SysAdminDao.ExistsByName(*dto.Username)
 		if nameExists {
@@ -352,6 +782,9 @@ func (s *SysAdminService) UpdatePassword(adminId uint, dto *entity.UpdatePasswor
 	if err != nil {
 		return response.ErrServerError
 	}
+	if admin.LdapDN != "" {
+		return response.ErrLdapPasswordManaged
+	}
 
 	// 验证旧密码
 	if !encrypt.VerifyPassword(admin.Password, dto.Password) {
@@ -363,11 +796,38 @@ func (s *SysAdminService) UpdatePassword(adminId uint, dto *entity.UpdatePasswor
 		return response.ErrPasswordInConsistent
 	}
 
+	// 检查密码策略和密码历史
+	if err := checkNewPassword(admin, dto.NewPassword); err != nil {
+		return err
+	}
+
 	// 修改新密码
-	hashNewPwd, _ := encrypt.EncryptPassword(dto.NewPassword)
-	admin.Password = hashNewPwd
+	if err := applyNewPassword(admin, dto.NewPassword); err != nil {
+		return response.ErrServerError
+	}
 	if err := SysAdminDao.UpdateAdmin(admin); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	recordPasswordHistory(admin)
+	return revokeAdminTokens(admin.ID)
+}
+
+// 获取当前登录用户带版本号的个人资料
+func (s *SysAdminService) GetMyProfile(adminId uint) (*entity.MyProfileVo, error) {
+	profile, err := SysAdminDao.GetProfile(adminId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrAdminNotExists
+		}
+		return nil, response.ErrServerError
+	}
+	roleNames, err := SysAdminDao.GetAdminRoleNames(adminId)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	profile.RoleNames = roleNames
+	return &entity.MyProfileVo{
+		Version: utils.DataVersion(profile),
+		Profile: profile,
+	}, nil
 }
@@ -0,0 +1,151 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateSysAdminRoles(t *testing.T) {
+	note := "changed"
+	tests := []struct {
+		name      string
+		operator  string
+		roleIds   []uint
+		err       error
+		wantNote  string
+		wantRoles []uint
+	}{
+		{"grant super role denied", "alice", []uint{1}, response.ErrSuperRoleDenied, "", []uint{}},
+		{"grant inherited super role denied", "alice", []uint{2, 3}, response.ErrSuperRoleDenied, "", []uint{}},
+		{"missing role", "alice", []uint{3, 9}, response.ErrRoleNotExists, "", []uint{}},
+		{"grant normal role", "alice", []uint{3}, nil, note, []uint{3}},
+		{"super operator grants super role", "root", []uint{1}, nil, note, []uint{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			seedSuperRoles(t)
+			carol := newTestAdmin(t, "carol", "")
+			mustCreate(t, carol)
+
+			dto := &entity.UpdateAdminDto{ID: carol.ID, Note: &note, RoleIds: tt.roleIds}
+			err := (&SysAdminService{}).UpdateSysAdmin(&entity.DataScope{All: true}, adminByName(t, tt.operator).ID, dto)
+			if err != tt.err {
+				t.Fatalf("UpdateSysAdmin() error = %v, want %v", err, tt.err)
+			}
+			// 角色检查失败时用户信息也不能被修改
+			if got := adminByName(t, "carol").Note; got != tt.wantNote {
+				t.Errorf("note = %q, want %q", got, tt.wantNote)
+			}
+			if roleIds := adminRoleIds(t, carol.ID); !reflect.DeepEqual(roleIds, tt.wantRoles) {
+				t.Errorf("roles = %v, want %v", roleIds, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	tests := []struct {
+		name string
+		// 返回用于刷新的令牌
+		prepare func(t *testing.T, user *entity.SysAdmin, pair *jwt.TokenPair) string
+		err     error
+	}{
+		{"valid refresh token", func(t *testing.T, _ *entity.SysAdmin, pair *jwt.TokenPair) string {
+			return pair.RefreshToken
+		}, nil},
+		{"access token", func(t *testing.T, _ *entity.SysAdmin, pair *jwt.TokenPair) string {
+			return pair.AccessToken
+		}, response.ErrTokenInvalid},
+		{"refresh token reused", func(t *testing.T, _ *entity.SysAdmin, pair *jwt.TokenPair) string {
+			if _, err := (&SysAdminService{}).RefreshToken(pair.RefreshToken); err != nil {
+				t.Fatal(err)
+			}
+			return pair.RefreshToken
+		}, response.ErrTokenRevoked},
+		{"admin tokens revoked", func(t *testing.T, user *entity.SysAdmin, pair *jwt.TokenPair) string {
+			if err := revokeAdminTokens(user.ID); err != nil {
+				t.Fatal(err)
+			}
+			return pair.RefreshToken
+		}, response.ErrTokenRevoked},
+		{"admin disabled", func(t *testing.T, user *entity.SysAdmin, pair *jwt.TokenPair) string {
+			global.DB.Model(user).Update("status", 2)
+			return pair.RefreshToken
+		}, response.ErrAdminDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			user := newTestAdmin(t, "alice", "")
+			mustCreate(t, user)
+			pair := loginTestAdmin(t, user, "session-1")
+
+			refreshed, err := (&SysAdminService{}).RefreshToken(tt.prepare(t, user, pair))
+			if err != tt.err {
+				t.Fatalf("RefreshToken() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			// 新的令牌对属于同一个会话，新的刷新令牌可以继续使用
+			claims, err := jwt.ParseToken(refreshed.AccessToken)
+			if err != nil || claims.SessionID != "session-1" || claims.TokenType != jwt.TokenTypeAccess {
+				t.Errorf("refreshed access token claims = %+v, %v", claims, err)
+			}
+			if _, err := (&SysAdminService{}).RefreshToken(refreshed.RefreshToken); err != nil {
+				t.Errorf("RefreshToken() with rotated token error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAdminChangesRevokeTokens(t *testing.T) {
+	admins := &SysAdminService{}
+	scope := &entity.DataScope{All: true}
+	tests := []struct {
+		name   string
+		change func(operatorId, adminId uint) error
+	}{
+		{"disable admin", func(operatorId, adminId uint) error {
+			return admins.UpdateAdminStatus(scope, operatorId, &entity.UpdateAdminStatusDto{ID: adminId, NewStatus: 2})
+		}},
+		{"reset password", func(operatorId, adminId uint) error {
+			return admins.ResetPassword(scope, operatorId, &entity.ResetPasswordDto{ID: adminId, NewPassword: "NewPass456"})
+		}},
+		{"change roles", func(operatorId, adminId uint) error {
+			return admins.UpdateSysAdmin(scope, operatorId, &entity.UpdateAdminDto{ID: adminId, RoleIds: []uint{3}})
+		}},
+		{"change password", func(_, adminId uint) error {
+			return admins.UpdatePassword(adminId, &entity.UpdatePasswordDto{Password: testOldPassword, NewPassword: "NewPass456", RePassword: "NewPass456"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			root, _, _ := seedSuperRoles(t)
+			carol := newTestAdmin(t, "carol", "")
+			mustCreate(t, carol)
+			pair := loginTestAdmin(t, carol, "session-1")
+
+			if err := tt.change(root.ID, carol.ID); err != nil {
+				t.Fatalf("change error = %v", err)
+			}
+			if version, _ := TokenDao.GetTokenVersion(carol.ID); version != 1 {
+				t.Errorf("token version = %d, want 1", version)
+			}
+			if session, _ := SessionDao.GetSession("session-1"); session != nil {
+				t.Error("login session was not deleted")
+			}
+			if _, err := admins.RefreshToken(pair.RefreshToken); err == nil {
+				t.Error("RefreshToken() succeeded after the tokens were revoked")
+			}
+		})
+	}
+}
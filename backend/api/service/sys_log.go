@@ -3,19 +3,20 @@ package service
 import (
 	"go-admin-server/api/entity"
 	"go-admin-server/common/response"
+	"go-admin-server/core/logwriter"
 )
 
 type SysLogService struct{}
 
 // 获取登录日志列表
-func (s *SysLogService) GetLoginLogList(pageNum, pageSize int, username, beginTime, endTime string, loginStaus uint) (*entity.LoginLogListVo, error) {
+func (s *SysLogService) GetLoginLogList(scope *entity.DataScope, pageNum, pageSize int, username, beginTime, endTime string, loginStaus uint, location entity.LoginLocationQuery) (*entity.LoginLogListVo, error) {
 	if pageNum < 1 {
 		pageNum = 1
 	}
 	if pageSize < 1 {
 		pageSize = 10
 	}
-	loginLogList, total, err := SysLogDao.GetLoginLogList(pageNum, pageSize, username, beginTime, endTime, loginStaus)
+	loginLogList, total, err := SysLogDao.GetLoginLogList(scope, pageNum, pageSize, username, beginTime, endTime, loginStaus, location)
 	if err != nil {
 		return nil, response.ErrServerError
 	}
@@ -32,30 +33,30 @@ func (s *SysLogService) GetLoginLogList(pageNum, pageSize int, username, beginTi
 }
 
 // 删除登录日志
-func (s *SysLogService) DeleteLoginLog(logId uint) error {
-	if err := SysLogDao.DeleteLoginLog(logId); err != nil {
+func (s *SysLogService) DeleteLoginLog(scope *entity.DataScope, logId uint) error {
+	if err := SysLogDao.DeleteLoginLog(scope, logId); err != nil {
 		return response.ErrServerError
 	}
 	return nil
 }
 
 // 批量删除登录日志
-func (s *SysLogService) BatchDeleteLoginLog(logIds []uint) error {
-	if err := SysLogDao.BatchDeleteLoginLog(logIds); err != nil {
+func (s *SysLogService) BatchDeleteLoginLog(scope *entity.DataScope, logIds []uint) error {
+	if err := SysLogDao.BatchDeleteLoginLog(scope, logIds); err != nil {
 		return response.ErrServerError
 	}
 	return nil
 }
 
 // 获取操作日志列表
-func (s *SysLogService) GetOperationLogList(pageNum, pageSize int, username, beginTime, endTime string) (*entity.OperationLogListVo, error) {
+func (s *SysLogService) GetOperationLogList(scope *entity.DataScope, pageNum, pageSize int, username, beginTime, endTime string) (*entity.OperationLogListVo, error) {
 	if pageNum < 1 {
 		pageNum = 1
 	}
 	if pageSize < 1 {
 		pageSize = 10
 	}
-	operationLogList, total, err := SysLogDao.GetOperationLogList(pageNum, pageSize, username, beginTime, endTime)
+	operationLogList, total, err := SysLogDao.GetOperationLogList(scope, pageNum, pageSize, username, beginTime, endTime)
 	if err != nil {
 		return nil, response.ErrServerError
 	}
@@ -72,17 +73,22 @@ func (s *SysLogService) GetOperationLogList(pageNum, pageSize int, username, beg
 }
 
 // 删除操作日志
-func (s *SysLogService) DeleteOpLog(logId uint) error {
-	if err := SysLogDao.DeleteOpLog(logId); err != nil {
+func (s *SysLogService) DeleteOpLog(scope *entity.DataScope, logId uint) error {
+	if err := SysLogDao.DeleteOpLog(scope, logId); err != nil {
 		return response.ErrServerError
 	}
 	return nil
 }
 
 // 批量删除操作日志
-func (s *SysLogService) BatchDeleteOpLog(logIds []uint) error {
-	if err := SysLogDao.BatchDeleteOpLog(logIds); err != nil {
+func (s *SysLogService) BatchDeleteOpLog(scope *entity.DataScope, logIds []uint) error {
+	if err := SysLogDao.BatchDeleteOpLog(scope, logIds); err != nil {
 		return response.ErrServerError
 	}
 	return nil
 }
+
+// 获取日志异步写入的运行指标
+func (s *SysLogService) GetLogWriterStats() logwriter.Stats {
+	return logwriter.GetStats()
+}
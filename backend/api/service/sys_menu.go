@@ -66,7 +66,8 @@ func (s *SysMenuService) CreateMenu(dto *entity.CreateMenuDto) error {
 	if err := SysMenuDao.CreateMenu(sysMenu); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	// 新菜单还没有分配给角色，只影响超级管理员账号
+	return invalidateMenuRolePermissions(nil)
 }
 
 // 获取菜单列表
@@ -157,7 +158,7 @@ func (s *SysMenuService) UpdateMenu(dto *entity.UpdateSysMenuDto) error {
 	if err := SysMenuDao.UpdateMenu(sysMenu); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	return invalidateMenuPermissions(sysMenu.ID)
 }
 
 // 删除单个菜单
@@ -169,10 +170,36 @@ func (s *SysMenuService) DeleteMenu(menuID uint) error {
 	if hasSubmenu {
 		return response.ErrHasSubmenu
 	}
+	// 删除前查询拥有该菜单的角色，删除后失效相关用户的权限缓存
+	roleIds, err := SysMenuDao.GetMenuRoleIds(menuID)
+	if err != nil {
+		return response.ErrServerError
+	}
 	if err := SysMenuDao.DeleteMenu(menuID); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	return invalidateMenuRolePermissions(roleIds)
+}
+
+// 菜单变化后删除拥有该菜单的角色下用户的权限缓存
+func invalidateMenuPermissions(menuID uint) error {
+	roleIds, err := SysMenuDao.GetMenuRoleIds(menuID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	return invalidateMenuRolePermissions(roleIds)
+}
+
+// 删除拥有这些角色的用户的权限缓存，超级管理员账号不按角色过滤菜单，菜单变化时同样需要删除
+func invalidateMenuRolePermissions(roleIds []uint) error {
+	if err := invalidateRolePermissions(roleIds...); err != nil {
+		return err
+	}
+	superIds, err := SysAdminDao.GetSuperAdminIds()
+	if err != nil {
+		return response.ErrServerError
+	}
+	return invalidateAdminPermissions(superIds...)
 }
 
 // 获取菜单下拉列表
@@ -201,3 +228,27 @@ func (s *SysMenuService) GetPermissionList(adminId uint) ([]entity.PermissionLis
 	}
 	return permissionList, nil
 }
+
+// 获取当前登录用户带版本号的左侧菜单列表
+func (s *SysMenuService) GetMyMenus(adminId uint) (*entity.MyMenusVo, error) {
+	menus, err := s.GetLeftMenuList(adminId)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.MyMenusVo{
+		Version: utils.DataVersion(menus),
+		Menus:   menus,
+	}, nil
+}
+
+// 获取当前登录用户带版本号的权限列表
+func (s *SysMenuService) GetMyPermissions(adminId uint) (*entity.MyPermissionsVo, error) {
+	permissions, err := s.GetPermissionList(adminId)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.MyPermissionsVo{
+		Version:     utils.DataVersion(permissions),
+		Permissions: permissions,
+	}, nil
+}
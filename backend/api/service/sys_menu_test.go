@@ -0,0 +1,45 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"reflect"
+	"testing"
+)
+
+func TestGetMyPermissionsOrder(t *testing.T) {
+	setupTestEnv(t)
+	// 按钮按权限值的逆序创建，权限列表仍按权限值排序
+	mustCreate(t,
+		&entity.SysMenu{ID: 1, MenuName: "用户删除", MenuType: 3, MenuStatus: 1, Value: "system:admin:remove"},
+		&entity.SysMenu{ID: 2, MenuName: "用户查询", MenuType: 3, MenuStatus: 1, Value: "system:admin:list"},
+		&entity.SysMenu{ID: 3, MenuName: "用户新增", MenuType: 3, MenuStatus: 1, Value: "system:admin:add"},
+		&entity.SysMenu{ID: 4, MenuName: "用户管理", MenuType: 2, MenuStatus: 1, Url: "/system/admin"},
+		&entity.SysRole{ID: 1, RoleName: "用户管理", RoleKey: "admin", RoleStatus: 1},
+		&entity.SysRoleMenu{RoleID: 1, MenuID: 1},
+		&entity.SysRoleMenu{RoleID: 1, MenuID: 2},
+		&entity.SysRoleMenu{RoleID: 1, MenuID: 3},
+	)
+	alice := newRoleAdmin(t, "alice", false, 1)
+
+	got, err := (&SysMenuService{}).GetMyPermissions(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []entity.PermissionListVo{{Value: "system:admin:add"}, {Value: "system:admin:list"}, {Value: "system:admin:remove"}}
+	if !reflect.DeepEqual(got.Permissions, want) {
+		t.Errorf("permissions = %v, want %v", got.Permissions, want)
+	}
+	// 权限未变化时版本号不变
+	if err := invalidateAdminPermissions(alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	again, err := (&SysMenuService{}).GetMyPermissions(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Version != got.Version {
+		t.Errorf("version changed from %s to %s", got.Version, again.Version)
+	}
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"errors"
+	"go-admin-server/api/dao"
 	"go-admin-server/api/entity"
 	"go-admin-server/common/response"
 	"go-admin-server/common/utils"
+	"go-admin-server/global"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,7 +15,7 @@ import (
 type SysRoleService struct{}
 
 // 创建角色
-func (s *SysRoleService) CreateRole(dto *entity.CreateRoleDto) error {
+func (s *SysRoleService) CreateRole(operatorId uint, dto *entity.CreateRoleDto) error {
 	// 检查名称存在性
 	nameExists, err := SysRoleDao.ExistsByName(dto.RoleName)
 	if err != nil {
@@ -22,6 +24,12 @@ func (s *SysRoleService) CreateRole(dto *entity.CreateRoleDto) error {
 	if nameExists {
 		return response.ErrRoleNameExists
 	}
+	// 只有超级管理员可以创建超级管理员角色
+	if dto.RoleKey == global.SuperRoleKey {
+		if err := checkSuperRoleGrantable(operatorId); err != nil {
+			return err
+		}
+	}
 	// 检查关键字存在性
 	keyExists, err := SysRoleDao.ExistsByKey(dto.RoleKey)
 	if err != nil {
@@ -30,12 +38,27 @@ func (s *SysRoleService) CreateRole(dto *entity.CreateRoleDto) error {
 	if keyExists {
 		return response.ErrRoleKeyExists
 	}
+	// 检查父角色
+	if dto.ParentID != 0 {
+		if err := checkRoleParent(operatorId, 0, dto.ParentID); err != nil {
+			return err
+		}
+	}
 	// 创建角色
 	sysRole := &entity.SysRole{
 		RoleName:    dto.RoleName,
 		RoleKey:     dto.RoleKey,
 		Description: dto.Description,
 		CreatedAt:   utils.HTime{Time: time.Now()},
+
+		RequireTwoFactor: dto.RequireTwoFactor,
+		DataScope:        dto.DataScope,
+	}
+	if sysRole.DataScope == 0 {
+		sysRole.DataScope = entity.DataScopeAll
+	}
+	if dto.ParentID != 0 {
+		sysRole.ParentID = &dto.ParentID
 	}
 	if dto.RoleStatus == 0 {
 		sysRole.RoleStatus = 1
@@ -88,12 +111,16 @@ func (s *SysRoleService) GetRoleByID(roleID uint) (*entity.SysRole, error) {
 }
 
 // 修改角色
-func (s *SysRoleService) UpdateRole(dto *entity.UpdateRoleDto) error {
+func (s *SysRoleService) UpdateRole(operatorId uint, dto *entity.UpdateRoleDto) error {
 	// 获取要修改的角色
 	sysRole, err := s.GetRoleByID(dto.ID)
 	if err != nil {
 		return err
 	}
+	// 超级管理员角色及继承它的角色只能由超级管理员修改
+	if err := checkSuperRolesGrantable(operatorId, sysRole.ID); err != nil {
+		return err
+	}
 	// 是否修改名称
 	if dto.RoleName != nil && *dto.RoleName != sysRole.RoleName {
 		// 检查名称存在性
@@ -105,6 +132,12 @@ func (s *SysRoleService) UpdateRole(dto *entity.UpdateRoleDto) error {
 	}
 	// 是否修改关键字
 	if dto.RoleKey != nil && *dto.RoleKey != sysRole.RoleKey {
+		// 修改为超级管理员角色关键字等同于授予超级管理员角色
+		if *dto.RoleKey == global.SuperRoleKey {
+			if err := checkSuperRoleGrantable(operatorId); err != nil {
+				return err
+			}
+		}
 		// 检查关键字存在性
 		keyExists, _ := SysRoleDao.ExistsByKey(*dto.RoleKey)
 		if keyExists {
@@ -120,29 +153,64 @@ func (s *SysRoleService) UpdateRole(dto *entity.UpdateRoleDto) error {
 	if dto.Description != nil {
 		sysRole.Description = *dto.Description
 	}
+	// 修改两步验证策略
+	if dto.RequireTwoFactor != nil {
+		sysRole.RequireTwoFactor = *dto.RequireTwoFactor
+	}
+	// 修改父角色，为0时取消继承
+	if dto.ParentID != nil {
+		if *dto.ParentID == 0 {
+			sysRole.ParentID = nil
+		} else if sysRole.ParentID == nil || *sysRole.ParentID != *dto.ParentID {
+			if err := checkRoleParent(operatorId, sysRole.ID, *dto.ParentID); err != nil {
+				return err
+			}
+			sysRole.ParentID = dto.ParentID
+		}
+	}
 	// 更新数据库
 	if err := SysRoleDao.UpdateRole(sysRole); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	return invalidateRolePermissions(sysRole.ID)
 }
 
 // 删除角色
-func (s *SysRoleService) DeleteRole(roleID uint) error {
+func (s *SysRoleService) DeleteRole(operatorId, roleID uint) error {
 	// 先检查角色是否存在
 	_, err := s.GetRoleByID(roleID)
 	if err != nil {
 		return err
 	}
+	// 超级管理员角色及继承它的角色只能由超级管理员删除
+	if err := checkSuperRolesGrantable(operatorId, roleID); err != nil {
+		return err
+	}
+	// 被其他角色继承时不能删除
+	hasSubRole, err := SysRoleDao.HasSubRole(roleID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if hasSubRole {
+		return response.ErrHasSubRole
+	}
+	// 删除前查询拥有该角色的用户，删除后失效他们的权限缓存
+	adminIds, err := SysRoleDao.GetRolesAdminIds([]uint{roleID})
+	if err != nil {
+		return response.ErrServerError
+	}
 	// 删除角色
 	if err := SysRoleDao.DeleteRole(roleID); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	if err := invalidateAdminPermissions(adminIds...); err != nil {
+		return err
+	}
+	return invalidateRolePermissions(roleID)
 }
 
 // 修改角色状态
-func (s *SysRoleService) UpdateRoleStatus(dto *entity.UpdateRoleStatusDto) error {
+func (s *SysRoleService) UpdateRoleStatus(operatorId uint, dto *entity.UpdateRoleStatusDto) error {
 	// 根据id获取角色
 	role, err := SysRoleDao.GetRoleByID(dto.ID)
 	if err != nil {
@@ -151,11 +219,15 @@ func (s *SysRoleService) UpdateRoleStatus(dto *entity.UpdateRoleStatusDto) error
 		}
 		return response.ErrServerError
 	}
+	// 超级管理员角色及继承它的角色只能由超级管理员启用或禁用
+	if err := checkSuperRolesGrantable(operatorId, role.ID); err != nil {
+		return err
+	}
 	role.RoleStatus = dto.NewStatus
 	if err := SysRoleDao.UpdateRole(role); err != nil {
 		return response.ErrServerError
 	}
-	return nil
+	return invalidateRolePermissions(role.ID)
 }
 
 // 获取角色下拉列表
@@ -191,11 +263,131 @@ func (s *SysRoleService) AssignRoleMenus(dto *entity.AssignRoleMenusDto) error {
 	if err := SysRoleDao.AssignRoleMenus(dto.ID, dto.MenuIDs); err != nil {
 		return response.ErrServerError
 	}
+	return invalidateRolePermissions(dto.ID)
+}
+
+// 检查父角色：父角色必须存在，且不能是角色自身或其下级角色，避免继承关系出现环；
+// 继承超级管理员角色等同于授予超级管理员角色，需要操作人是超级管理员。新建角色时 roleID 为0
+func checkRoleParent(operatorId, roleID, parentID uint) error {
+	tree, err := SysRoleDao.GetRoleTree()
+	if err != nil {
+		return response.ErrServerError
+	}
+	inheritsSuper, err := roleParentChain(tree, roleID, parentID)
+	if err != nil {
+		return err
+	}
+	if inheritsSuper {
+		return checkSuperRoleGrantable(operatorId)
+	}
+	return nil
+}
+
+// 沿新父角色的继承链向上查找：父角色不存在或遇到当前角色(出现环)时返回错误，
+// 同时返回继承链上是否有超级管理员角色
+func roleParentChain(tree map[uint]entity.SysRole, roleID, parentID uint) (bool, error) {
+	if _, ok := tree[parentID]; !ok {
+		return false, response.ErrInvalidRoleParentID
+	}
+	visited := make(map[uint]bool)
+	for id := &parentID; id != nil && !visited[*id]; id = tree[*id].ParentID {
+		if *id == roleID {
+			return false, response.ErrInvalidRoleParentID
+		}
+		visited[*id] = true
+	}
+	return dao.InheritsSuperRole(tree, parentID), nil
+}
+
+// 获取角色权限列表，区分直接分配的权限和从父角色继承的权限
+func (s *SysRoleService) GetRoleMenus(roleID uint) (*entity.RoleMenusVo, error) {
+	roleExists, err := SysRoleDao.ExistsByID(roleID)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if !roleExists {
+		return nil, response.ErrRoleNotExists
+	}
+	menuIds, err := SysRoleDao.GetRoleMenus([]uint{roleID})
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	vo := &entity.RoleMenusVo{MenuIDs: menuIds, InheritedMenuIDs: []uint{}}
+
+	tree, err := SysRoleDao.GetRoleTree()
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	ancestors := dao.RoleAncestors(tree, roleID)
+	if len(ancestors) == 0 {
+		return vo, nil
+	}
+	inheritedIds, err := SysRoleDao.GetRoleMenus(ancestors)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	direct := make(map[uint]bool, len(menuIds))
+	for _, id := range menuIds {
+		direct[id] = true
+	}
+	for _, id := range inheritedIds {
+		if !direct[id] {
+			vo.InheritedMenuIDs = append(vo.InheritedMenuIDs, id)
+		}
+	}
+	return vo, nil
+}
+
+// 获取角色数据权限
+func (s *SysRoleService) GetRoleDataScope(roleID uint) (*entity.RoleDataScopeVo, error) {
+	vo, err := DataScopeDao.GetRoleDataScope(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrRoleNotExists
+		}
+		return nil, response.ErrServerError
+	}
+	return vo, nil
+}
+
+// 分配角色数据权限，只有自定义部门时保存部门列表
+func (s *SysRoleService) AssignRoleDataScope(dto *entity.AssignRoleDataScopeDto) error {
+	roleExists, err := SysRoleDao.ExistsByID(dto.ID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if !roleExists {
+		return response.ErrRoleNotExists
+	}
+
+	var deptIds []uint
+	if dto.DataScope == entity.DataScopeCustom {
+		deptIds = dto.DeptIDs
+	}
+	if len(deptIds) > 0 {
+		deptAllExists, err := DataScopeDao.ExistsDeptIds(deptIds)
+		if err != nil {
+			return response.ErrServerError
+		}
+		if !deptAllExists {
+			return response.ErrDeptNotExists
+		}
+	}
+
+	if err := DataScopeDao.AssignRoleDataScope(dto.ID, dto.DataScope, deptIds); err != nil {
+		return response.ErrServerError
+	}
 	return nil
 }
 
-// 获取角色权限列表
-func (s *SysRoleService) GetRoleMenus(roleID uint) ([]uint, error) {
+// 查询拥有角色的用户列表
+func (s *SysRoleService) GetRoleAdminList(scope *entity.DataScope, roleID uint, pageNum, pageSize int, username string) (*entity.RoleAdminListVo, error) {
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
 	roleExists, err := SysRoleDao.ExistsByID(roleID)
 	if err != nil {
 		return nil, response.ErrServerError
@@ -203,9 +395,96 @@ func (s *SysRoleService) GetRoleMenus(roleID uint) ([]uint, error) {
 	if !roleExists {
 		return nil, response.ErrRoleNotExists
 	}
-	roleMenus, err := SysRoleDao.GetRoleMenus(roleID)
+	adminList, total, err := SysRoleDao.GetRoleAdminList(scope, roleID, pageNum, pageSize, username)
 	if err != nil {
 		return nil, response.ErrServerError
 	}
-	return roleMenus, nil
+	return &entity.RoleAdminListVo{
+		Data: adminList,
+		Pagination: response.PaginationMeta{
+			PageNum:    pageNum,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: (total + pageSize - 1) / pageSize,
+		},
+	}, nil
+}
+
+// 批量为角色添加用户
+func (s *SysRoleService) AddRoleAdmins(scope *entity.DataScope, operatorId uint, dto *entity.AddRoleAdminsDto) error {
+	// 检查授权有效期
+	if dto.ValidUntil != nil {
+		if !dto.ValidUntil.After(time.Now()) || (dto.ValidFrom != nil && !dto.ValidUntil.After(dto.ValidFrom.Time)) {
+			return response.ErrInvalidGrantPeriod
+		}
+	}
+	role, adminIds, err := checkRoleAdmins(scope, operatorId, dto.ID, dto.AdminIDs)
+	if err != nil {
+		return err
+	}
+	if role.RoleStatus == 2 {
+		return response.ErrRoleDisabled
+	}
+	grant := entity.SysAdminRole{
+		RoleID:     dto.ID,
+		ValidFrom:  dto.ValidFrom,
+		ValidUntil: dto.ValidUntil,
+		Reason:     dto.Reason,
+	}
+	if err := SysRoleDao.AddRoleAdmins(grant, adminIds); err != nil {
+		return response.ErrServerError
+	}
+	if err := invalidateAdminPermissions(adminIds...); err != nil {
+		return err
+	}
+	return revokeAdminsTokens(adminIds)
+}
+
+// 批量从角色中移除用户
+func (s *SysRoleService) RemoveRoleAdmins(scope *entity.DataScope, operatorId uint, dto *entity.RoleAdminsDto) error {
+	_, adminIds, err := checkRoleAdmins(scope, operatorId, dto.ID, dto.AdminIDs)
+	if err != nil {
+		return err
+	}
+	if err := SysRoleDao.RemoveRoleAdmins(dto.ID, adminIds); err != nil {
+		return response.ErrServerError
+	}
+	if err := invalidateAdminPermissions(adminIds...); err != nil {
+		return err
+	}
+	return revokeAdminsTokens(adminIds)
+}
+
+// 检查角色存在，用户都存在且在数据权限范围内，返回角色和去重后的用户id；
+// 只有超级管理员可以增减超级管理员角色及继承它的角色的用户
+func checkRoleAdmins(scope *entity.DataScope, operatorId, roleID uint, ids []uint) (*entity.SysRole, []uint, error) {
+	role, err := SysRoleDao.GetRoleByID(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, response.ErrRoleNotExists
+		}
+		return nil, nil, response.ErrServerError
+	}
+	if err := checkSuperRolesGrantable(operatorId, role.ID); err != nil {
+		return nil, nil, err
+	}
+	adminIds := uniqueIds(ids)
+	count, err := SysAdminDao.CountScopedAdmins(scope, adminIds)
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	if count != int64(len(adminIds)) {
+		return nil, nil, response.ErrAdminNotExists
+	}
+	return role, adminIds, nil
+}
+
+// 角色变化后吊销用户已签发的令牌
+func revokeAdminsTokens(adminIds []uint) error {
+	for _, adminId := range adminIds {
+		if err := revokeAdminTokens(adminId); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"testing"
+)
+
+// 角色树：1 <- 2 <- 3，4 单独存在，5 为超级管理员角色，6 继承 5
+func testRoleTree() map[uint]entity.SysRole {
+	parent := func(id uint) *uint { return &id }
+	roles := []entity.SysRole{
+		{ID: 1, RoleKey: "root", RoleStatus: 1},
+		{ID: 2, RoleKey: "manager", RoleStatus: 1, ParentID: parent(1)},
+		{ID: 3, RoleKey: "staff", RoleStatus: 1, ParentID: parent(2)},
+		{ID: 4, RoleKey: "guest", RoleStatus: 1},
+		{ID: 5, RoleKey: global.SuperRoleKey, RoleStatus: 1},
+		{ID: 6, RoleKey: "deputy", RoleStatus: 1, ParentID: parent(5)},
+	}
+	tree := make(map[uint]entity.SysRole, len(roles))
+	for _, role := range roles {
+		tree[role.ID] = role
+	}
+	return tree
+}
+
+func TestRoleParentChain(t *testing.T) {
+	tests := []struct {
+		name          string
+		roleID        uint
+		parentID      uint
+		wantErr       error
+		inheritsSuper bool
+	}{
+		{"new role under leaf", 0, 3, nil, false},
+		{"move leaf to another root", 3, 4, nil, false},
+		{"keep existing chain", 3, 2, nil, false},
+		{"self as parent", 2, 2, response.ErrInvalidRoleParentID, false},
+		{"direct child as parent", 1, 2, response.ErrInvalidRoleParentID, false},
+		{"grandchild as parent", 1, 3, response.ErrInvalidRoleParentID, false},
+		{"missing parent", 4, 99, response.ErrInvalidRoleParentID, false},
+		{"super role as parent", 4, 5, nil, true},
+		{"super role as ancestor", 0, 6, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inheritsSuper, err := roleParentChain(testRoleTree(), tt.roleID, tt.parentID)
+			if err != tt.wantErr {
+				t.Fatalf("roleParentChain(%d, %d) error = %v, want %v", tt.roleID, tt.parentID, err, tt.wantErr)
+			}
+			if inheritsSuper != tt.inheritsSuper {
+				t.Errorf("inheritsSuper = %v, want %v", inheritsSuper, tt.inheritsSuper)
+			}
+		})
+	}
+}
+
+func TestRoleParentChainExistingCycle(t *testing.T) {
+	// 数据库中已存在的环不能导致死循环
+	tree := testRoleTree()
+	role := tree[1]
+	role.ParentID = func(id uint) *uint { return &id }(3)
+	tree[1] = role
+	if _, err := roleParentChain(tree, 4, 2); err != nil {
+		t.Fatalf("roleParentChain() error = %v", err)
+	}
+}
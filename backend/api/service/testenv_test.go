@@ -0,0 +1,114 @@
+//go:build cgo
+
+package service
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/core/permcache"
+	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 使用内存中的 sqlite 和 miniredis 替换数据库和redis，测试结束后恢复
+func setupTestEnv(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	oldConfig, oldLogger, oldDB, oldRDB := global.Config, global.Logger, global.DB, global.RDB
+	t.Cleanup(func() {
+		global.Config, global.Logger, global.DB, global.RDB = oldConfig, oldLogger, oldDB, oldRDB
+	})
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存数据库只存在于一个连接中
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(
+		&entity.SysPost{},
+		&entity.SysDept{},
+		&entity.SysMenu{},
+		&entity.SysRole{},
+		&entity.SysRoleMenu{},
+		&entity.SysRoleDept{},
+		&entity.SysAdmin{},
+		&entity.SysAdminRole{},
+		&entity.SysPasswordHistory{},
+		&entity.SysApiToken{},
+		&entity.SysAdminCredential{},
+		&entity.SysLoginLog{},
+		&entity.SysRecoveryCode{},
+		&entity.SysAdminInvitation{},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	// 各测试的数据库相互独立，用户id会重复，不使用进程内的权限缓存
+	permcache.Start(config.PermissionCache{Disabled: true})
+	global.Config = &config.AppConfig{}
+	global.Logger = zap.NewNop()
+	global.DB = db
+	global.RDB = rdb
+	jwt.Setup(config.Jwt{Secret: "test-secret"})
+	return mr
+}
+
+// 创建测试数据，失败时结束测试
+func mustCreate(t *testing.T, values ...any) {
+	t.Helper()
+	for _, value := range values {
+		if err := global.DB.Create(value).Error; err != nil {
+			t.Fatalf("create %T: %v", value, err)
+		}
+	}
+}
+
+// 为用户创建登录会话并签发令牌对
+func loginTestAdmin(t *testing.T, user *entity.SysAdmin, sessionID string) *jwt.TokenPair {
+	t.Helper()
+	if err := SessionDao.SaveSession(&entity.SysSession{SessionID: sessionID, AdminID: user.ID, Username: user.Username}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	version, err := TokenDao.GetTokenVersion(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair, err := jwt.GenerateTokenPair(user, sessionID, "", version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pair
+}
+
+// 等待后台补全会话的登录地点，避免后台协程在测试环境恢复后访问redis
+func waitSessionLocation(t *testing.T, sessionID string) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		session, err := SessionDao.GetSession(sessionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if session == nil || session.Location != "" {
+			return
+		}
+	}
+	t.Fatalf("location of session %s was not filled", sessionID)
+}
@@ -0,0 +1,435 @@
+// 两步验证(TOTP)：绑定验证器、恢复码管理，以及登录时的第二步验证
+
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/jwt"
+	"go-admin-server/pkg/totp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	recoveryCodeCount = 10              // 每次生成的恢复码数量
+	totpStepTTL       = 2 * time.Minute // 已使用时间步的保留时长，需覆盖验证码的有效窗口
+)
+
+type TwoFactorService struct{}
+
+// 获取两步验证配置，未配置的项使用默认值
+func twoFactorConfig() config.TwoFactor {
+	cfg := global.Config.TwoFactor
+	if cfg.Issuer == "" {
+		cfg.Issuer = "go-admin"
+	}
+	if cfg.ChallengeTTL <= 0 {
+		cfg.ChallengeTTL = 5 * time.Minute
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	return cfg
+}
+
+func getAdmin(adminId uint) (*entity.SysAdmin, error) {
+	user, err := SysAdminDao.GetAdminById(adminId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrAdminNotExists
+		}
+		return nil, response.ErrServerError
+	}
+	return user, nil
+}
+
+// 创建登录挑战：用户已开启两步验证，或所属角色要求两步验证时返回挑战，否则返回nil。
+// 已注册通行密钥的用户可以使用通行密钥完成验证，所属角色要求两步验证时也不需要再绑定验证器
+func createLoginChallenge(user *entity.SysAdmin, loginMethod, ip, browser, Os, device string) (*entity.LoginChallengeVo, error) {
+	passkeyAvailable, err := hasPasskeys(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	setupRequired := false
+	if !user.TotpEnabled {
+		required, err := TwoFactorDao.IsTwoFactorRequired(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+		setupRequired = !passkeyAvailable
+	}
+	token, err := utils.RandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	cfg := twoFactorConfig()
+	challenge := &entity.LoginChallenge{
+		AdminID:  user.ID,
+		Username: user.Username,
+		Ip:       ip,
+		Browser:  browser,
+		Os:       Os,
+		Device:   device,
+
+		LoginMethod: loginMethod,
+	}
+	if err := TwoFactorDao.SaveChallenge(token, challenge, cfg.ChallengeTTL); err != nil {
+		return nil, err
+	}
+	return &entity.LoginChallengeVo{
+		TwoFactorRequired: true,
+		SetupRequired:     setupRequired,
+		ChallengeToken:    token,
+		ExpiresAt:         time.Now().Add(cfg.ChallengeTTL),
+		PasskeyAvailable:  passkeyAvailable,
+	}, nil
+}
+
+// 生成新的TOTP密钥并加密保存，开启前需要先验证一次验证码
+func setupTotpSecret(user *entity.SysAdmin) (*entity.TwoFactorSetupVo, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	encrypted, err := encrypt.EncryptString(secret)
+	if err != nil {
+		global.Logger.Error("Failed to encrypt totp secret", zap.Error(err))
+		return nil, response.ErrServerError
+	}
+	if err := TwoFactorDao.SaveTotpSecret(user.ID, encrypted); err != nil {
+		return nil, response.ErrServerError
+	}
+	uri := totp.KeyURI(twoFactorConfig().Issuer, user.Username, secret)
+	qrCode, err := totp.QRCode(uri)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	return &entity.TwoFactorSetupVo{
+		Secret:     secret,
+		OtpauthURL: uri,
+		QRCode:     qrCode,
+	}, nil
+}
+
+// 校验TOTP验证码，同一验证码只能使用一次
+func verifyTotp(user *entity.SysAdmin, code string) (bool, error) {
+	if user.TotpSecret == "" {
+		return false, response.ErrTwoFactorNotSetup
+	}
+	secret, err := encrypt.DecryptString(user.TotpSecret)
+	if err != nil {
+		global.Logger.Error("Failed to decrypt totp secret", zap.Uint("adminId", user.ID), zap.Error(err))
+		return false, response.ErrServerError
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	unused, err := TwoFactorDao.UseTotpStep(user.ID, step, totpStepTTL)
+	if err != nil {
+		return false, response.ErrServerError
+	}
+	return unused, nil
+}
+
+// 校验TOTP验证码或恢复码
+func verifyTwoFactorCode(user *entity.SysAdmin, code string) (bool, error) {
+	ok, err := verifyTotp(user, code)
+	if err != nil || ok {
+		return ok, err
+	}
+	ok, err = TwoFactorDao.UseRecoveryCode(user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return false, response.ErrServerError
+	}
+	return ok, nil
+}
+
+// 恢复码哈希，忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// 生成恢复码，返回恢复码明文和对应的哈希值
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		random, err := utils.RandomHex(5)
+		if err != nil {
+			return nil, nil, err
+		}
+		code := random[:5] + "-" + random[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// 开启两步验证，返回恢复码
+func enableTwoFactor(adminId uint) (*entity.RecoveryCodesVo, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if err := TwoFactorDao.EnableTwoFactor(adminId, hashes); err != nil {
+		return nil, response.ErrServerError
+	}
+	return &entity.RecoveryCodesVo{RecoveryCodes: codes}, nil
+}
+
+// 绑定验证器：生成密钥和二维码
+func (s *TwoFactorService) SetupTwoFactor(adminId uint) (*entity.TwoFactorSetupVo, error) {
+	user, err := getAdmin(adminId)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, response.ErrTwoFactorEnabled
+	}
+	return setupTotpSecret(user)
+}
+
+// 开启两步验证：验证码正确后开启，并返回恢复码
+func (s *TwoFactorService) EnableTwoFactor(adminId uint, dto *entity.EnableTwoFactorDto) (*entity.RecoveryCodesVo, error) {
+	user, err := getAdmin(adminId)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, response.ErrTwoFactorEnabled
+	}
+	ok, err := verifyTotp(user, dto.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, response.ErrTwoFactorCodeError
+	}
+	return enableTwoFactor(user.ID)
+}
+
+// 关闭两步验证：需要验证密码和验证码，所属角色要求两步验证时不能关闭
+func (s *TwoFactorService) DisableTwoFactor(adminId uint, dto *entity.DisableTwoFactorDto) error {
+	user, err := getAdmin(adminId)
+	if err != nil {
+		return err
+	}
+	if !user.TotpEnabled {
+		return response.ErrTwoFactorNotEnabled
+	}
+	required, err := TwoFactorDao.IsTwoFactorRequired(user.ID)
+	if err != nil {
+		return response.ErrServerError
+	}
+	if required {
+		return response.ErrTwoFactorRequired
+	}
+	if !encrypt.VerifyPassword(user.Password, dto.Password) {
+		return response.ErrPasswordError
+	}
+	ok, err := verifyTwoFactorCode(user, dto.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return response.ErrTwoFactorCodeError
+	}
+	if err := TwoFactorDao.DisableTwoFactor(user.ID); err != nil {
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 重新生成恢复码，原有的恢复码全部作废
+func (s *TwoFactorService) RegenerateRecoveryCodes(adminId uint, dto *entity.RegenerateRecoveryCodesDto) (*entity.RecoveryCodesVo, error) {
+	user, err := getAdmin(adminId)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TotpEnabled {
+		return nil, response.ErrTwoFactorNotEnabled
+	}
+	ok, err := verifyTotp(user, dto.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, response.ErrTwoFactorCodeError
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if err := TwoFactorDao.ReplaceRecoveryCodes(user.ID, hashes); err != nil {
+		return nil, response.ErrServerError
+	}
+	return &entity.RecoveryCodesVo{RecoveryCodes: codes}, nil
+}
+
+// 管理员重置用户的两步验证，用于用户丢失验证器且没有恢复码的情况
+func (s *TwoFactorService) ResetTwoFactor(scope *entity.DataScope, operatorId, id uint) error {
+	user, err := getScopedAdmin(scope, id)
+	if err != nil {
+		return err
+	}
+	if err := checkAdminRemovable(operatorId, user); err != nil {
+		return err
+	}
+	if err := TwoFactorDao.DisableTwoFactor(id); err != nil {
+		return response.ErrServerError
+	}
+	return nil
+}
+
+// 获取登录挑战，挑战不存在或已过期时返回错误
+func getLoginChallenge(token string) (*entity.LoginChallenge, error) {
+	challenge, err := TwoFactorDao.GetChallenge(token)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if challenge == nil {
+		return nil, response.ErrChallengeInvalid
+	}
+	return challenge, nil
+}
+
+// 登录时绑定验证器：所属角色要求两步验证但用户尚未绑定时使用
+func (s *TwoFactorService) LoginSetupTwoFactor(dto *entity.TwoFactorLoginSetupDto) (*entity.TwoFactorSetupVo, error) {
+	challenge, err := getLoginChallenge(dto.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := getAdmin(challenge.AdminID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, response.ErrTwoFactorEnabled
+	}
+	// 已注册通行密钥时需要使用通行密钥验证，不能在登录时绑定新的验证器
+	passkeyAvailable, err := hasPasskeys(user.ID)
+	if err != nil {
+		return nil, response.ErrServerError
+	}
+	if passkeyAvailable {
+		return nil, response.ErrTwoFactorEnabled
+	}
+	return setupTotpSecret(user)
+}
+
+// 获取登录挑战并计入一次验证次数，用户已停用时挑战作废
+func verifyingLoginChallenge(token string) (*entity.LoginChallenge, *entity.SysAdmin, error) {
+	challenge, err := getLoginChallenge(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 限制每个挑战的验证次数
+	attempts, err := TwoFactorDao.IncrChallengeAttempts(token)
+	if err != nil {
+		return nil, nil, response.ErrServerError
+	}
+	if attempts > int64(twoFactorConfig().MaxAttempts) {
+		_ = TwoFactorDao.DeleteChallenge(token)
+		return nil, nil, response.ErrChallengeInvalid
+	}
+	user, err := getAdmin(challenge.AdminID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Status != 1 {
+		_ = TwoFactorDao.DeleteChallenge(token)
+		SysLogDao.CreateLoginLog(user.Username, challenge.Ip, challenge.Browser, challenge.Os, "账号已停用", 2)
+		return nil, nil, response.ErrAdminDisabled
+	}
+	return challenge, user, nil
+}
+
+// 第二步验证失败：记录登录日志和失败次数，失败次数过多时锁定账号并作废挑战
+func loginChallengeFailed(token string, challenge *entity.LoginChallenge, user *entity.SysAdmin, msg string, verifyErr error) error {
+	ip, browser, Os := challenge.Ip, challenge.Browser, challenge.Os
+	SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, msg, 2)
+	if lockErr := recordLoginFailure(user.Username, ip); lockErr != nil {
+		_ = TwoFactorDao.DeleteChallenge(token)
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, lockErr.Error(), 2)
+		return lockErr
+	}
+	return verifyErr
+}
+
+// 第二步验证通过后签发令牌，调用前需要先作废挑战
+func completeLoginChallenge(challenge *entity.LoginChallenge, user *entity.SysAdmin) (*jwt.TokenPair, error) {
+	ip, browser, Os := challenge.Ip, challenge.Browser, challenge.Os
+	// 生成token
+	tokenPair, err := issueTokenPair(user, challenge.LoginMethod, ip, browser, Os, challenge.Device)
+	if err != nil {
+		SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "服务器故障", 2)
+		return nil, response.ErrServerError
+	}
+
+	// 登录成功
+	clearLoginFailures(user.Username)
+	SysLogDao.CreateLoginLog(user.Username, ip, browser, Os, "登录成功", 1)
+	return tokenPair, nil
+}
+
+// 两步验证登录：验证码通过后签发令牌；首次绑定时同时开启两步验证并返回恢复码
+func (s *TwoFactorService) LoginTwoFactor(dto *entity.TwoFactorLoginDto) (*entity.SysAdmin, *jwt.TokenPair, *entity.RecoveryCodesVo, error) {
+	challenge, user, err := verifyingLoginChallenge(dto.ChallengeToken)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var ok bool
+	if user.TotpEnabled {
+		ok, err = verifyTwoFactorCode(user, dto.Code)
+	} else {
+		// 已注册通行密钥时不需要绑定验证器，需要使用通行密钥验证
+		var passkeyAvailable bool
+		if passkeyAvailable, err = hasPasskeys(user.ID); err != nil {
+			return nil, nil, nil, response.ErrServerError
+		}
+		if passkeyAvailable {
+			return nil, nil, nil, response.ErrTwoFactorNotEnabled
+		}
+		// 首次绑定只接受验证器App中的验证码
+		ok, err = verifyTotp(user, dto.Code)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !ok {
+		return nil, nil, nil, loginChallengeFailed(dto.ChallengeToken, challenge, user, "两步验证码错误", response.ErrTwoFactorCodeError)
+	}
+	_ = TwoFactorDao.DeleteChallenge(dto.ChallengeToken)
+
+	var recoveryCodes *entity.RecoveryCodesVo
+	if !user.TotpEnabled {
+		if recoveryCodes, err = enableTwoFactor(user.ID); err != nil {
+			return nil, nil, nil, err
+		}
+		user.TotpEnabled = true
+	}
+
+	tokenPair, err := completeLoginChallenge(challenge, user)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return user, tokenPair, recoveryCodes, nil
+}
@@ -10,4 +10,18 @@ var (
 	SysRoleDao  = &dao.SysRoleDao{}
 	SysAdminDao = &dao.SysAdminDao{}
 	SysLogDao   = &dao.SysLogDao{}
+	TokenDao    = &dao.TokenDao{}
+	SessionDao  = &dao.SessionDao{}
+
+	LoginGuardDao = &dao.LoginGuardDao{}
+	TwoFactorDao  = &dao.TwoFactorDao{}
+
+	PasswordHistoryDao = &dao.PasswordHistoryDao{}
+	DataScopeDao       = &dao.DataScopeDao{}
+	ApiTokenDao        = &dao.ApiTokenDao{}
+	OidcDao            = &dao.OidcDao{}
+	ScimDao            = &dao.ScimDao{}
+	PasswordResetDao   = &dao.PasswordResetDao{}
+	AdminInvitationDao = &dao.AdminInvitationDao{}
+	PasskeyDao         = &dao.PasskeyDao{}
 )
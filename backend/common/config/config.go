@@ -2,13 +2,37 @@
 
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type AppConfig struct {
-	Server      `mapstructure:"server"`
-	Mysql       `mapstructure:"mysql"`
-	Redis       `mapstructure:"redis"`
-	Logger      `mapstructure:"logger"`
+	Server `mapstructure:"server"`
+	Mysql  `mapstructure:"mysql"`
+	Redis  `mapstructure:"redis"`
+	Logger `mapstructure:"logger"`
+	Jwt    `mapstructure:"jwt"`
+
+	LoginSecurity  `mapstructure:"login_security"`
+	TwoFactor      `mapstructure:"two_factor"`
+	PasswordPolicy `mapstructure:"password_policy"`
+	LogWriter      `mapstructure:"log_writer"`
+	GeoIP          `mapstructure:"geoip"`
+	RoleGrant      `mapstructure:"role_grant"`
+
+	PermissionCache `mapstructure:"permission_cache"`
+
+	Oidc `mapstructure:"oidc"`
+	Ldap `mapstructure:"ldap"`
+	Scim `mapstructure:"scim"`
+
+	Mail          `mapstructure:"mail"`
+	PasswordReset `mapstructure:"password_reset"`
+	Invitation    `mapstructure:"invitation"`
+
+	WebAuthn `mapstructure:"webauthn"`
 }
 
 type Server struct {
@@ -43,6 +67,199 @@ type Logger struct {
 	IsConsolePrint bool   `mapstructure:"is_console_print"`
 }
 
+type Jwt struct {
+	Issuer         string         `mapstructure:"issuer"`
+	AccessTTL      time.Duration  `mapstructure:"access_ttl"`       // 访问令牌有效期
+	RefreshTTL     time.Duration  `mapstructure:"refresh_ttl"`      // 刷新令牌有效期
+	Algorithm      string         `mapstructure:"algorithm"`        // 签名算法: HS256、RS256、EdDSA
+	Kid            string         `mapstructure:"kid"`              // 当前签名密钥的id，写入令牌头部
+	Secret         string         `mapstructure:"secret"`           // HS256 签名密钥
+	PrivateKeyFile string         `mapstructure:"private_key_file"` // RS256、EdDSA 私钥文件(PEM)
+	PublicKeyFile  string         `mapstructure:"public_key_file"`  // RS256、EdDSA 公钥文件(PEM)
+	VerifyKeys     []JwtVerifyKey `mapstructure:"verify_keys"`      // 已轮换下来的旧密钥，只用于验证令牌
+
+	ImpersonationTTL time.Duration `mapstructure:"impersonation_ttl"` // 模拟登录会话的最长有效期
+}
+
+type JwtVerifyKey struct {
+	Kid           string `mapstructure:"kid"`
+	Algorithm     string `mapstructure:"algorithm"`
+	Secret        string `mapstructure:"secret"`
+	PublicKeyFile string `mapstructure:"public_key_file"`
+}
+
+type LoginSecurity struct {
+	MaxUserFailures int           `mapstructure:"max_user_failures"` // 统计窗口内同一账号允许的最大登录失败次数
+	MaxIPFailures   int           `mapstructure:"max_ip_failures"`   // 统计窗口内同一IP允许的最大登录失败次数
+	FailureWindow   time.Duration `mapstructure:"failure_window"`    // 登录失败次数的统计窗口
+	LockDuration    time.Duration `mapstructure:"lock_duration"`     // 首次锁定时长，之后每次锁定时长翻倍
+	MaxLockDuration time.Duration `mapstructure:"max_lock_duration"` // 最长锁定时长
+}
+
+type TwoFactor struct {
+	Issuer       string        `mapstructure:"issuer"`        // 验证器App中显示的发行方名称
+	SecretKey    string        `mapstructure:"secret_key"`    // 加密存储TOTP密钥的密钥
+	ChallengeTTL time.Duration `mapstructure:"challenge_ttl"` // 两步验证挑战令牌有效期
+	MaxAttempts  int           `mapstructure:"max_attempts"`  // 每个挑战令牌允许的最大验证次数
+}
+
+type PasswordPolicy struct {
+	MinLength        int           `mapstructure:"min_length"`         // 最小长度
+	RequireLetter    bool          `mapstructure:"require_letter"`     // 必须包含字母
+	RequireMixedCase bool          `mapstructure:"require_mixed_case"` // 必须同时包含大写和小写字母
+	RequireDigit     bool          `mapstructure:"require_digit"`      // 必须包含数字
+	RequireSymbol    bool          `mapstructure:"require_symbol"`     // 必须包含特殊字符
+	BannedPasswords  []string      `mapstructure:"banned_passwords"`   // 禁止使用的密码，不区分大小写
+	HistoryCount     int           `mapstructure:"history_count"`      // 不能与最近使用过的 N 个密码相同，0 表示不限制
+	MaxAge           time.Duration `mapstructure:"max_age"`            // 密码最长使用时间，过期后必须修改密码，0 表示永不过期
+}
+
+type LogWriter struct {
+	BufferSize    int           `mapstructure:"buffer_size"`    // 日志队列容量，队列已满时丢弃新日志
+	BatchSize     int           `mapstructure:"batch_size"`     // 每批写入的最大条数
+	FlushInterval time.Duration `mapstructure:"flush_interval"` // 写入间隔，未达到批量大小时也按间隔写入
+}
+
+type GeoIP struct {
+	Provider    string        `mapstructure:"provider"`     // 查询方式：ip2region、mmdb、http，为空时不查询
+	DBFile      string        `mapstructure:"db_file"`      // 离线数据库文件：ip2region xdb 或 MaxMind City mmdb
+	ISPDBFile   string        `mapstructure:"isp_db_file"`  // MaxMind ISP 或 ASN mmdb，可选
+	CacheSize   int           `mapstructure:"cache_size"`   // 查询结果 LRU 缓存条数
+	HTTPTimeout time.Duration `mapstructure:"http_timeout"` // 在线查询超时时间
+}
+
+type RoleGrant struct {
+	SweepInterval time.Duration `mapstructure:"sweep_interval"` // 清理过期角色授权的间隔，0 表示不清理
+}
+
+type PermissionCache struct {
+	Disabled bool          `mapstructure:"disabled"`  // 关闭后每次都从数据库查询权限
+	TTL      time.Duration `mapstructure:"ttl"`       // redis缓存的过期时间
+	LocalTTL time.Duration `mapstructure:"local_ttl"` // 本地内存缓存的过期时间，不超过 ttl
+}
+
+type Oidc struct {
+	Enabled              bool             `mapstructure:"enabled"`                // 是否启用单点登录
+	ProviderName         string           `mapstructure:"provider_name"`          // 登录页显示的身份提供方名称
+	Issuer               string           `mapstructure:"issuer"`                 // 身份提供方地址，用于发现 /.well-known/openid-configuration
+	ClientID             string           `mapstructure:"client_id"`              // 客户端id
+	ClientSecret         string           `mapstructure:"client_secret"`          // 客户端密钥，公共客户端为空，只使用 PKCE
+	RedirectURL          string           `mapstructure:"redirect_url"`           // 前端回调页面地址，需要在身份提供方中登记
+	Scopes               []string         `mapstructure:"scopes"`                 // 申请的 scope，openid 会自动添加
+	UsernameClaim        string           `mapstructure:"username_claim"`         // 用户名所在的声明
+	EmailClaim           string           `mapstructure:"email_claim"`            // 邮箱所在的声明
+	NameClaim            string           `mapstructure:"name_claim"`             // 昵称所在的声明
+	GroupsClaim          string           `mapstructure:"groups_claim"`           // 用户组所在的声明
+	MatchBy              []string         `mapstructure:"match_by"`               // 首次登录时按顺序匹配已有用户：username、email
+	AllowUnverifiedEmail bool             `mapstructure:"allow_unverified_email"` // 允许使用未验证的邮箱匹配用户
+	StateTTL             time.Duration    `mapstructure:"state_ttl"`              // 发起登录到回调的最长时间
+	SkipTwoFactor        bool             `mapstructure:"skip_two_factor"`        // 身份提供方已完成多因素认证时，跳过本地两步验证
+	SyncGroupRoles       bool             `mapstructure:"sync_group_roles"`       // 每次登录时按用户组同步 group_roles 中的角色
+	GroupRoles           []OidcGroupRole  `mapstructure:"group_roles"`            // 用户组与角色关键字的映射
+	Provisioning         OidcProvisioning `mapstructure:"provisioning"`           // 自动创建用户
+}
+
+type OidcGroupRole struct {
+	Group   string `mapstructure:"group"`    // 身份提供方中的用户组
+	RoleKey string `mapstructure:"role_key"` // 角色关键字
+}
+
+type OidcProvisioning struct {
+	Enabled  bool     `mapstructure:"enabled"`   // 没有匹配的用户时自动创建
+	DeptID   uint     `mapstructure:"dept_id"`   // 默认部门
+	PostID   uint     `mapstructure:"post_id"`   // 默认岗位
+	RoleKeys []string `mapstructure:"role_keys"` // 默认角色关键字，与用户组映射的角色合并
+}
+
+type Ldap struct {
+	Enabled            bool              `mapstructure:"enabled"`              // 是否启用LDAP认证
+	URL                string            `mapstructure:"url"`                  // 服务器地址，ldap:// 或 ldaps://
+	StartTLS           bool              `mapstructure:"start_tls"`            // ldap:// 连接后升级为 TLS
+	InsecureSkipVerify bool              `mapstructure:"insecure_skip_verify"` // 不校验服务器证书，仅用于测试环境
+	CAFile             string            `mapstructure:"ca_file"`              // 自签名服务器证书的 CA 文件(PEM)
+	Timeout            time.Duration     `mapstructure:"timeout"`              // 连接和请求超时时间
+	BindDN             string            `mapstructure:"bind_dn"`              // 查询用户使用的服务账号
+	BindPassword       string            `mapstructure:"bind_password"`        // 服务账号密码
+	BaseDN             string            `mapstructure:"base_dn"`              // 查询用户的根节点
+	UserFilter         string            `mapstructure:"user_filter"`          // 登录时查询用户的过滤器，%s 替换为转义后的用户名
+	SyncFilter         string            `mapstructure:"sync_filter"`          // 同步时查询全部用户的过滤器
+	UsernameAttribute  string            `mapstructure:"username_attribute"`   // 用户名属性
+	NicknameAttribute  string            `mapstructure:"nickname_attribute"`   // 昵称属性
+	EmailAttribute     string            `mapstructure:"email_attribute"`      // 邮箱属性
+	PhoneAttribute     string            `mapstructure:"phone_attribute"`      // 电话属性
+	GroupAttribute     string            `mapstructure:"group_attribute"`      // 所属组属性，值为组的DN
+	ActiveDirectory    bool              `mapstructure:"active_directory"`     // 按 userAccountControl 判断 AD 账号是否已停用
+	FallbackLocal      bool              `mapstructure:"fallback_local"`       // LDAP服务器不可用时，目录用户可以使用本地密码登录
+	CreateOnLogin      bool              `mapstructure:"create_on_login"`      // 目录中存在但本地不存在的用户登录时自动创建
+	DefaultDeptID      uint              `mapstructure:"default_dept_id"`      // 没有匹配 dept_mappings 时的部门
+	DefaultPostID      uint              `mapstructure:"default_post_id"`      // 创建用户时的岗位
+	DeptMappings       []LdapDeptMapping `mapstructure:"dept_mappings"`        // OU或组与部门的映射，按顺序匹配第一个
+	RoleMappings       []LdapRoleMapping `mapstructure:"role_mappings"`        // 组与角色关键字的映射
+	Sync               LdapSync          `mapstructure:"sync"`                 // 定时同步
+}
+
+type LdapDeptMapping struct {
+	DN     string `mapstructure:"dn"`      // OU的DN(匹配用户DN的后缀)或组的DN
+	DeptID uint   `mapstructure:"dept_id"` // 部门id
+}
+
+type LdapRoleMapping struct {
+	Group   string `mapstructure:"group"`    // 组的DN
+	RoleKey string `mapstructure:"role_key"` // 角色关键字
+}
+
+type LdapSync struct {
+	Interval       time.Duration `mapstructure:"interval"`        // 同步间隔，0 表示不定时同步
+	CreateUsers    bool          `mapstructure:"create_users"`    // 创建目录中新增的用户
+	DisableMissing bool          `mapstructure:"disable_missing"` // 停用目录中已删除或已停用的用户
+}
+
+type Scim struct {
+	Enabled            bool   `mapstructure:"enabled"`              // 是否启用SCIM接口
+	Token              string `mapstructure:"token"`                // 身份提供方调用时携带的 Bearer 令牌
+	BaseURL            string `mapstructure:"base_url"`             // 资源地址的前缀，如 https://admin.example.com/scim/v2
+	DefaultDeptID      uint   `mapstructure:"default_dept_id"`      // 创建用户时的部门
+	DefaultPostID      uint   `mapstructure:"default_post_id"`      // 创建用户时的岗位
+	AllowPasswordLogin bool   `mapstructure:"allow_password_login"` // 创建的用户可以使用密码登录(如通过LDAP)，默认只能单点登录
+	MaxResults         int    `mapstructure:"max_results"`          // 每页最多返回的资源数
+}
+
+type Mail struct {
+	Enabled            bool          `mapstructure:"enabled"`              // 是否启用邮件发送
+	Host               string        `mapstructure:"host"`                 // SMTP 服务器地址
+	Port               int           `mapstructure:"port"`                 // SMTP 端口，通常为 587(STARTTLS)或 465(TLS)
+	Username           string        `mapstructure:"username"`             // 为空时不认证
+	Password           string        `mapstructure:"password"`             // 认证密码或授权码
+	From               string        `mapstructure:"from"`                 // 发件人地址
+	FromName           string        `mapstructure:"from_name"`            // 发件人名称
+	Encryption         string        `mapstructure:"encryption"`           // starttls、tls 或 none
+	InsecureSkipVerify bool          `mapstructure:"insecure_skip_verify"` // 不校验服务器证书，仅用于测试环境
+	Timeout            time.Duration `mapstructure:"timeout"`              // 连接和发送的超时时间
+	TemplateDir        string        `mapstructure:"template_dir"`         // 自定义邮件模板目录，为空时使用内置模板
+}
+
+type PasswordReset struct {
+	Enabled  bool          `mapstructure:"enabled"`   // 是否允许通过邮件自助重置密码，需要同时启用邮件发送
+	ResetURL string        `mapstructure:"reset_url"` // 前端重置密码页面的地址，邮件中的链接为该地址加上 token 参数
+	TokenTTL time.Duration `mapstructure:"token_ttl"` // 重置链接的有效期
+	Cooldown time.Duration `mapstructure:"cooldown"`  // 同一账号两次发送重置邮件的最小间隔
+}
+
+type Invitation struct {
+	AcceptURL string        `mapstructure:"accept_url"` // 前端接受邀请页面的地址，邮件中的链接为该地址加上 token 参数
+	TTL       time.Duration `mapstructure:"ttl"`        // 邀请链接的有效期，过期后需要管理员重新发送
+}
+
+type WebAuthn struct {
+	Enabled      bool          `mapstructure:"enabled"`      // 是否启用通行密钥
+	RPID         string        `mapstructure:"rp_id"`        // 依赖方id，为前端页面的域名，不含协议和端口
+	RPName       string        `mapstructure:"rp_name"`      // 注册通行密钥时显示的依赖方名称
+	Origins      []string      `mapstructure:"origins"`      // 允许的前端页面来源，如 https://admin.example.com
+	Timeout      time.Duration `mapstructure:"timeout"`      // 注册和验证的超时时间
+	Passwordless bool          `mapstructure:"passwordless"` // 允许使用通行密钥免密码登录，关闭时只能作为两步验证
+	MaxPasskeys  int           `mapstructure:"max_passkeys"` // 每个用户最多注册的通行密钥数量
+}
+
 func Init() *AppConfig {
 	v := viper.New()
 	v.SetConfigFile("./config.yaml")
@@ -17,6 +17,22 @@ var (
 		Name:  "admin",
 		Usage: "Crate a root account",
 	}
+	promoteFlag = &cli.BoolFlag{
+		Name:  "promote",
+		Usage: "Promote an account to super admin",
+	}
+	recoverFlag = &cli.BoolFlag{
+		Name:  "recover",
+		Usage: "Recover a super admin account: reset password and disable two factor",
+	}
+	ldapSyncFlag = &cli.BoolFlag{
+		Name:  "ldap-sync",
+		Usage: "Sync accounts from the LDAP directory",
+	}
+	ldapDryRunFlag = &cli.BoolFlag{
+		Name:  "ldap-dry-run",
+		Usage: "Print the LDAP directory sync report without changing any account",
+	}
 )
 
 func run(c *cli.Context) {
@@ -35,6 +51,21 @@ func run(c *cli.Context) {
 			global.Logger.Fatal("Failed to create root account", zap.Error(err))
 		}
 		global.Logger.Info("Successfully create a root account")
+	case c.Bool(promoteFlag.Name):
+		if err := PromoteSuperAdmin(); err != nil {
+			global.Logger.Fatal("Failed to promote super admin", zap.Error(err))
+		}
+		global.Logger.Info("Successfully promote a super admin")
+	case c.Bool(recoverFlag.Name):
+		if err := RecoverSuperAdmin(); err != nil {
+			global.Logger.Fatal("Failed to recover super admin", zap.Error(err))
+		}
+		global.Logger.Info("Successfully recover a super admin")
+	case c.Bool(ldapSyncFlag.Name), c.Bool(ldapDryRunFlag.Name):
+		if err := SyncLdapDirectory(c.Bool(ldapDryRunFlag.Name)); err != nil {
+			global.Logger.Fatal("Failed to sync ldap directory", zap.Error(err))
+		}
+		global.Logger.Info("Successfully sync ldap directory")
 	default:
 		global.Logger.Fatal("unknown command")
 	}
@@ -48,6 +79,10 @@ func InitFlag() {
 		app.Flags = []cli.Flag{
 			sqlFlag,
 			adminFlag,
+			promoteFlag,
+			recoverFlag,
+			ldapSyncFlag,
+			ldapDryRunFlag,
 		}
 		app.Action = run
 
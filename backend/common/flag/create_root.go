@@ -1,15 +1,20 @@
 package flag
 
 import (
+	"errors"
 	"fmt"
 	"go-admin-server/api/entity"
+	"go-admin-server/common/utils"
 	"go-admin-server/global"
 	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/validator"
 	"syscall"
+	"time"
 
 	"golang.org/x/term"
 )
 
+// 创建超级管理员账号，拥有全部权限，且不能被删除或禁用
 func CreateRootAccount() error {
 	var root entity.SysAdmin
 
@@ -23,6 +28,10 @@ func CreateRootAccount() error {
 		return err
 	}
 	pwd := string(pwdBytes)
+	// 检查密码策略
+	if err := validator.CheckPassword(pwd); err != nil {
+		return err
+	}
 
 	fmt.Println("请确认密码: ")
 	repwdBytes, err := term.ReadPassword(int(syscall.Stdin))
@@ -32,15 +41,28 @@ func CreateRootAccount() error {
 	repwd := string(repwdBytes)
 
 	if pwd != repwd {
-		return err
+		return errors.New("两次密码不一致")
 	}
 
-	hashPwd, _ := encrypt.EncryptPassword(pwd)
-
+	hashPwd, err := encrypt.EncryptPassword(pwd)
+	if err != nil {
+		return err
+	}
+	now := utils.HTime{Time: time.Now()}
 	root.Username = username
 	root.Password = hashPwd
+	root.CreatedAt = now
+	root.PasswordChangedAt = &now
+	root.IsSuper = true
 	if err := global.DB.Create(&root).Error; err != nil {
 		return err
 	}
+	// 记录密码历史
+	if validator.PasswordPolicy().HistoryCount > 0 {
+		history := &entity.SysPasswordHistory{AdminID: root.ID, Password: hashPwd, CreatedAt: now}
+		if err := global.DB.Create(history).Error; err != nil {
+			return err
+		}
+	}
 	return nil
 }
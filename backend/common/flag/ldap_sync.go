@@ -0,0 +1,21 @@
+package flag
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-admin-server/api/service"
+)
+
+// 按LDAP目录同步用户，dryRun 为 true 时只输出将要执行的变更
+func SyncLdapDirectory(dryRun bool) error {
+	report, err := service.SyncLdapDirectory(dryRun)
+	if err != nil {
+		return err
+	}
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+	return nil
+}
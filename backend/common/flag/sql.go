@@ -8,14 +8,20 @@ import (
 // 通过命令行执行模型迁移
 func SQL() error {
 	return global.DB.Set("table_options", "ENGINE=InnoDB").AutoMigrate(
-		&entity.SysPost{},         // 岗位表
-		&entity.SysDept{},         // 部门表
-		&entity.SysMenu{},         // 菜单表
-		&entity.SysRole{},         // 角色表
-		&entity.SysRoleMenu{},     // 角色-菜单关联表
-		&entity.SysAdmin{},        // 用户表
-		&entity.SysAdminRole{},    // 用户-角色关联表
-		&entity.SysLoginLog{},     // 登录日志表
-		&entity.SysOperationLog{}, // 操作日志表
+		&entity.SysPost{},            // 岗位表
+		&entity.SysDept{},            // 部门表
+		&entity.SysMenu{},            // 菜单表
+		&entity.SysRole{},            // 角色表
+		&entity.SysRoleMenu{},        // 角色-菜单关联表
+		&entity.SysRoleDept{},        // 角色-部门关联表
+		&entity.SysAdmin{},           // 用户表
+		&entity.SysAdminRole{},       // 用户-角色关联表
+		&entity.SysRecoveryCode{},    // 两步验证恢复码表
+		&entity.SysPasswordHistory{}, // 密码历史表
+		&entity.SysApiToken{},        // API令牌表
+		&entity.SysAdminInvitation{}, // 用户邀请表
+		&entity.SysAdminCredential{}, // 通行密钥表
+		&entity.SysLoginLog{},        // 登录日志表
+		&entity.SysOperationLog{},    // 操作日志表
 	)
 }
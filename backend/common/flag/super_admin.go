@@ -0,0 +1,42 @@
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"go-admin-server/api/service"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+var adminService = &service.SysAdminService{}
+
+// 将已有用户设置为超级管理员，并启用账号、解除登录锁定
+func PromoteSuperAdmin() error {
+	var username string
+	fmt.Println("请输入要设置为超级管理员的账号: ")
+	fmt.Scanln(&username)
+	return adminService.PromoteSuperAdmin(username)
+}
+
+// 找回超级管理员：在 PromoteSuperAdmin 的基础上重置密码、关闭两步验证，用于所有管理员都无法登录的情况
+func RecoverSuperAdmin() error {
+	var username string
+	fmt.Println("请输入要找回的账号: ")
+	fmt.Scanln(&username)
+
+	fmt.Println("请输入新密码: ")
+	pwdBytes, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+	fmt.Println("请确认新密码: ")
+	repwdBytes, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+	if string(pwdBytes) != string(repwdBytes) {
+		return errors.New("两次密码不一致")
+	}
+	return adminService.RecoverSuperAdmin(username, string(pwdBytes))
+}
@@ -1,5 +1,10 @@
 package response
 
+import (
+	"fmt"
+	"time"
+)
+
 // 业务状态码
 const (
 	CodeSuccess = 200 // 成功
@@ -35,6 +40,10 @@ const (
 	CodeRoleNotExists  = 1403 // 角色不存在
 	CodeRoleDisabled   = 1404 // 角色已被禁用
 
+	CodeInvalidRoleParentID = 1405 // 无效的父角色
+	CodeHasSubRole          = 1406 // 存在子角色
+	CodeInvalidGrantPeriod  = 1407 // 角色授权有效期无效
+
 	// 用户模块
 	CodeAdmiNameExists       = 1501 // 用户名称已存在
 	CodeAdminNicknameExists  = 1502 // 用户昵称已存在
@@ -44,15 +53,78 @@ const (
 	CodePasswordError        = 1506 // 旧密码错误
 	CodePasswordInConsistent = 1507 // 两次密码不一致
 	CodeAdminDisabled        = 1508 // 账号已停用
+	CodeSessionNotExists     = 1509 // 会话不存在
+	CodeAccountLocked        = 1510 // 账号已锁定
+	CodeIPLocked             = 1511 // IP已锁定
+	CodeTwoFactorCodeError   = 1512 // 两步验证码错误
+	CodeChallengeInvalid     = 1513 // 两步验证挑战无效或已过期
+	CodeTwoFactorNotSetup    = 1514 // 未绑定两步验证
+	CodeTwoFactorEnabled     = 1515 // 已开启两步验证
+	CodeTwoFactorNotEnabled  = 1516 // 未开启两步验证
+	CodeTwoFactorRequired    = 1517 // 角色要求开启两步验证
+	CodePasswordPolicy       = 1518 // 密码不符合密码策略
+	CodePasswordReused       = 1519 // 新密码与最近使用过的密码相同
+	CodeNotImpersonating     = 1520 // 当前未处于模拟登录状态
+	CodeServiceAccountLogin  = 1521 // 服务账号不能交互式登录
+
+	CodePasswordLoginDisabled = 1522 // 已禁用密码登录
+	CodePasswordResetDisabled = 1523 // 未启用邮件重置密码
+	CodePasswordResetInvalid  = 1524 // 重置密码链接无效或已过期
+	CodeInvitationDisabled    = 1525 // 未启用邀请
+	CodeInvitationInvalid     = 1526 // 邀请链接无效
+	CodeInvitationExpired     = 1527 // 邀请链接已过期
+	CodeInvitationMailFailed  = 1528 // 邀请邮件发送失败
+	CodeAdminPending          = 1529 // 账号尚未激活
+	CodeAdminNotPending       = 1530 // 账号不是待激活状态
+
+	CodePasskeyDisabled       = 1531 // 未启用通行密钥
+	CodePasskeySessionInvalid = 1532 // 通行密钥会话无效或已过期
+	CodePasskeyVerifyFailed   = 1533 // 通行密钥验证失败
+	CodePasskeyNotExists      = 1534 // 通行密钥不存在
+	CodePasskeyExists         = 1535 // 通行密钥已注册
+	CodePasskeyLimit          = 1536 // 通行密钥数量已达上限
 
 	CodeFileUploadFail = 1601 // 文件上传失败
 
+	// API令牌模块
+	CodeApiTokenNotExists     = 1701 // API令牌不存在
+	CodeInvalidApiTokenScope  = 1702 // 无效的令牌权限范围
+	CodeNotServiceAccount     = 1703 // 不是服务账号
+	CodeInvalidApiTokenExpiry = 1704 // 无效的令牌过期时间
+	CodeApiTokenDenied        = 1705 // 不能为该服务账号创建API令牌
+
+	// 单点登录模块
+	CodeSsoDisabled        = 1801 // 未启用单点登录
+	CodeSsoStateInvalid    = 1802 // 单点登录请求无效或已过期
+	CodeSsoAuthFailed      = 1803 // 身份提供方认证失败
+	CodeSsoAccountNotFound = 1804 // 没有匹配的用户
+	CodeSsoAccountConflict = 1805 // 账号已绑定其他身份
+	CodeSsoLinkDenied      = 1806 // 不能自动关联的账号
+
+	// 目录同步模块
+	CodeLdapDisabled        = 1901 // 未启用LDAP
+	CodeLdapUnavailable     = 1902 // LDAP服务器不可用
+	CodeLdapPasswordManaged = 1903 // 目录账号的密码由LDAP管理
+	CodeLdapSyncRunning     = 1904 // 目录同步正在进行
+	CodeLdapAccountManaged  = 1905 // 目录账号的用户名由LDAP管理
+
 	// 2000~3000 对应的HTTPStatus 为 Unauthorized
 	CodeUnauthorized     = 2000 // 未认证
 	CodeTokenFormatError = 2001 // token格式错误
 	CodeTokenInvalid     = 2002 // 无效token
+	CodeTokenRevoked     = 2003 // token已被吊销
+	CodeApiTokenInvalid  = 2004 // API令牌无效或已过期
 
 	// 3000~4000 对应的HTTPStatus 为 Forbidden
+	CodeForbidden       = 3000 // 无访问权限
+	CodePasswordExpired = 3001 // 密码已过期
+	CodeDataScopeDenied = 3002 // 超出数据权限范围
+
+	CodeSuperAdminProtected = 3003 // 超级管理员受保护
+	CodeOperateSelf         = 3004 // 不能对自己进行该操作
+	CodeImpersonating       = 3005 // 模拟登录期间禁止的操作
+	CodeImpersonateDenied   = 3006 // 不能模拟登录该用户
+	CodeSuperRoleDenied     = 3007 // 不能授予超级管理员角色
 
 	CodeNotFound = 4000 // 请求资源不存在
 
@@ -78,6 +150,21 @@ func NewBusinessError(code int, message string) *BusinessError {
 	}
 }
 
+// 账号因登录失败次数过多被锁定
+func ErrAccountLocked(until time.Time) *BusinessError {
+	return NewBusinessError(CodeAccountLocked, fmt.Sprintf("登录失败次数过多，账号已锁定至 %s", until.Format("2006-01-02 15:04:05")))
+}
+
+// IP因登录失败次数过多被锁定
+func ErrIPLocked(until time.Time) *BusinessError {
+	return NewBusinessError(CodeIPLocked, fmt.Sprintf("登录失败次数过多，当前IP已被限制登录至 %s", until.Format("2006-01-02 15:04:05")))
+}
+
+// 密码不符合密码策略
+func ErrPasswordPolicy(reason string) *BusinessError {
+	return NewBusinessError(CodePasswordPolicy, reason)
+}
+
 // 统一错误注册
 var (
 	ErrServerError   = NewBusinessError(CodeServerError, "服务器内部错误")
@@ -112,6 +199,10 @@ var (
 	ErrRoleNotExists  = NewBusinessError(CodeRoleNotExists, "角色不存在")
 	ErrRoleDisabled   = NewBusinessError(CodeRoleDisabled, "角色已被禁用")
 
+	ErrInvalidRoleParentID = NewBusinessError(CodeInvalidRoleParentID, "父角色不存在，或是当前角色自身及其下级角色")
+	ErrHasSubRole          = NewBusinessError(CodeHasSubRole, "存在继承该角色的子角色")
+	ErrInvalidGrantPeriod  = NewBusinessError(CodeInvalidGrantPeriod, "授权失效时间必须晚于生效时间和当前时间")
+
 	// 用户模块
 	ErrAdminNameExists      = NewBusinessError(CodeAdmiNameExists, "用户名称已存在")
 	ErrAdminNicknameExists  = NewBusinessError(CodeAdminNicknameExists, "用户昵称已存在")
@@ -121,10 +212,71 @@ var (
 	ErrPasswordError        = NewBusinessError(CodePasswordError, "旧密码错误")
 	ErrPasswordInConsistent = NewBusinessError(CodePasswordInConsistent, "两次新密码不一致")
 	ErrAdminDisabled        = NewBusinessError(CodeAdminDisabled, "账号已停用")
+	ErrSessionNotExists     = NewBusinessError(CodeSessionNotExists, "会话不存在或已失效")
+	ErrTwoFactorCodeError   = NewBusinessError(CodeTwoFactorCodeError, "两步验证码错误")
+	ErrChallengeInvalid     = NewBusinessError(CodeChallengeInvalid, "两步验证已失效，请重新登录")
+	ErrTwoFactorNotSetup    = NewBusinessError(CodeTwoFactorNotSetup, "请先绑定两步验证")
+	ErrTwoFactorEnabled     = NewBusinessError(CodeTwoFactorEnabled, "已开启两步验证")
+	ErrTwoFactorNotEnabled  = NewBusinessError(CodeTwoFactorNotEnabled, "未开启两步验证")
+	ErrTwoFactorRequired    = NewBusinessError(CodeTwoFactorRequired, "所属角色要求开启两步验证，不能关闭")
+	ErrPasswordReused       = NewBusinessError(CodePasswordReused, "新密码不能与最近使用过的密码相同")
+	ErrNotImpersonating     = NewBusinessError(CodeNotImpersonating, "当前未处于模拟登录状态")
+	ErrServiceAccountLogin  = NewBusinessError(CodeServiceAccountLogin, "服务账号不能登录，请使用API令牌调用接口")
+
+	ErrPasswordLoginDisabled = NewBusinessError(CodePasswordLoginDisabled, "该账号已禁用密码登录，请使用单点登录")
+	ErrPasswordResetDisabled = NewBusinessError(CodePasswordResetDisabled, "未开启找回密码，请联系管理员重置密码")
+	ErrPasswordResetInvalid  = NewBusinessError(CodePasswordResetInvalid, "重置密码链接无效或已过期，请重新申请")
+	ErrInvitationDisabled    = NewBusinessError(CodeInvitationDisabled, "未配置邮件发送或邀请页面地址，不能邀请用户")
+	ErrInvitationInvalid     = NewBusinessError(CodeInvitationInvalid, "邀请链接无效或已被使用")
+	ErrInvitationExpired     = NewBusinessError(CodeInvitationExpired, "邀请链接已过期，请联系管理员重新发送")
+	ErrInvitationMailFailed  = NewBusinessError(CodeInvitationMailFailed, "邀请已创建，但邮件发送失败，请稍后重新发送")
+	ErrAdminPending          = NewBusinessError(CodeAdminPending, "账号尚未激活，请通过邀请邮件设置密码")
+	ErrAdminNotPending       = NewBusinessError(CodeAdminNotPending, "该用户已激活，不存在待接受的邀请")
+
+	ErrPasskeyDisabled       = NewBusinessError(CodePasskeyDisabled, "未启用通行密钥登录")
+	ErrPasskeySessionInvalid = NewBusinessError(CodePasskeySessionInvalid, "通行密钥验证已失效，请重试")
+	ErrPasskeyVerifyFailed   = NewBusinessError(CodePasskeyVerifyFailed, "通行密钥验证失败")
+	ErrPasskeyNotExists      = NewBusinessError(CodePasskeyNotExists, "通行密钥不存在")
+	ErrPasskeyExists         = NewBusinessError(CodePasskeyExists, "该通行密钥已注册")
+	ErrPasskeyLimit          = NewBusinessError(CodePasskeyLimit, "通行密钥数量已达上限，请先删除不再使用的通行密钥")
 
 	ErrAdminUnauthorized = NewBusinessError(CodeUnauthorized, "用户未认证")
 	ErrTokenFormatError  = NewBusinessError(CodeTokenFormatError, "Token格式错误")
 	ErrTokenInvalid      = NewBusinessError(CodeTokenInvalid, "无效的Token")
+	ErrTokenRevoked      = NewBusinessError(CodeTokenRevoked, "Token已失效，请重新登录")
+	ErrApiTokenInvalid   = NewBusinessError(CodeApiTokenInvalid, "API令牌无效或已过期")
+
+	ErrForbidden       = NewBusinessError(CodeForbidden, "没有访问权限")
+	ErrPasswordExpired = NewBusinessError(CodePasswordExpired, "密码已过期，请先修改密码")
+	ErrDataScopeDenied = NewBusinessError(CodeDataScopeDenied, "没有该部门的数据权限")
+
+	ErrSuperAdminProtected = NewBusinessError(CodeSuperAdminProtected, "超级管理员账号不能被删除、禁用或降级，拥有超级管理员权限的用户只能由超级管理员管理")
+	ErrOperateSelf         = NewBusinessError(CodeOperateSelf, "不能对当前登录的账号进行该操作")
+	ErrImpersonating       = NewBusinessError(CodeImpersonating, "模拟登录期间不能进行该操作")
+	ErrImpersonateDenied   = NewBusinessError(CodeImpersonateDenied, "不能模拟登录自己、超级管理员或权限超出自己的用户")
+	ErrSuperRoleDenied     = NewBusinessError(CodeSuperRoleDenied, "只有超级管理员可以分配超级管理员角色")
 
 	ErrFileUploadFail = NewBusinessError(CodeFileUploadFail, "文件上传失败")
+
+	// API令牌模块
+	ErrApiTokenNotExists     = NewBusinessError(CodeApiTokenNotExists, "API令牌不存在")
+	ErrInvalidApiTokenScope  = NewBusinessError(CodeInvalidApiTokenScope, "令牌权限范围包含不存在的权限")
+	ErrNotServiceAccount     = NewBusinessError(CodeNotServiceAccount, "只能为服务账号创建API令牌，个人令牌请在个人中心创建")
+	ErrInvalidApiTokenExpiry = NewBusinessError(CodeInvalidApiTokenExpiry, "令牌过期时间必须晚于当前时间")
+	ErrApiTokenDenied        = NewBusinessError(CodeApiTokenDenied, "不能为超级管理员或权限超出自己的服务账号创建API令牌")
+
+	// 单点登录模块
+	ErrSsoDisabled        = NewBusinessError(CodeSsoDisabled, "未启用单点登录")
+	ErrSsoStateInvalid    = NewBusinessError(CodeSsoStateInvalid, "单点登录请求无效或已过期，请重新登录")
+	ErrSsoAuthFailed      = NewBusinessError(CodeSsoAuthFailed, "身份提供方认证失败，请重新登录")
+	ErrSsoAccountNotFound = NewBusinessError(CodeSsoAccountNotFound, "没有与该身份匹配的用户，请联系管理员开通账号")
+	ErrSsoAccountConflict = NewBusinessError(CodeSsoAccountConflict, "匹配的用户已绑定其他单点登录身份，请联系管理员")
+	ErrSsoLinkDenied      = NewBusinessError(CodeSsoLinkDenied, "超级管理员和服务账号不能通过单点登录自动关联")
+
+	// 目录同步模块
+	ErrLdapDisabled        = NewBusinessError(CodeLdapDisabled, "未启用LDAP")
+	ErrLdapUnavailable     = NewBusinessError(CodeLdapUnavailable, "LDAP服务器不可用，请稍后重试")
+	ErrLdapPasswordManaged = NewBusinessError(CodeLdapPasswordManaged, "目录账号的密码由LDAP管理，请在目录中修改")
+	ErrLdapSyncRunning     = NewBusinessError(CodeLdapSyncRunning, "目录同步正在进行中，请稍后再试")
+	ErrLdapAccountManaged  = NewBusinessError(CodeLdapAccountManaged, "目录账号的用户名由LDAP管理，不能修改")
 )
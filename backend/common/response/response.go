@@ -2,6 +2,8 @@ package response
 
 import (
 	"fmt"
+	"go-admin-server/global"
+	customValidator "go-admin-server/pkg/validator"
 	"net/http"
 	"strings"
 
@@ -49,6 +51,7 @@ func codeToHTTPStaus(bizCode int) int {
 }
 
 func result(c *gin.Context, httpCode, code int, message string, data any) {
+	setResult(c, code, message)
 	c.JSON(httpCode, Response{
 		Code:    code,
 		Message: message,
@@ -56,6 +59,12 @@ func result(c *gin.Context, httpCode, code int, message string, data any) {
 	})
 }
 
+// 记录业务状态码和消息，供操作日志使用
+func setResult(c *gin.Context, code int, message string) {
+	c.Set(global.ResponseCode, code)
+	c.Set(global.ResponseMessage, message)
+}
+
 func Success(c *gin.Context) {
 	result(c, http.StatusOK, CodeSuccess, "成功", nil)
 }
@@ -124,6 +133,12 @@ func ValidationError(c *gin.Context, err error) {
 				message = fmt.Sprintf("%s 必须与 %s 相同", e.Field(), e.Param())
 			case "nefield":
 				message = fmt.Sprintf("%s 不能与 %s 相同", e.Field(), e.Param())
+			case "password":
+				// 返回具体不符合密码策略的原因
+				message = fmt.Sprintf("%s 不符合密码策略", e.Field())
+				if err := customValidator.CheckPassword(fmt.Sprint(e.Value())); err != nil {
+					message = err.Error()
+				}
 			case "oneof":
 				message = fmt.Sprintf("%s 必须是以下值之一: %s", e.Field(), strings.Replace(e.Param(), " ", ", ", -1))
 			default:
@@ -131,6 +146,7 @@ func ValidationError(c *gin.Context, err error) {
 			}
 
 			// 返回单一错误消息
+			setResult(c, 1000, message)
 			c.JSON(http.StatusBadRequest, Response{
 				Code:    1000,
 				Message: message,
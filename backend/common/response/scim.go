@@ -0,0 +1,98 @@
+package response
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ScimContentType = "application/scim+json" // SCIM 协议的媒体类型
+
+	scimErrorSchema = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// SCIM 错误类型，见 RFC 7644 3.12
+const (
+	ScimTypeInvalidFilter = "invalidFilter"
+	ScimTypeTooMany       = "tooMany"
+	ScimTypeUniqueness    = "uniqueness"
+	ScimTypeMutability    = "mutability"
+	ScimTypeInvalidSyntax = "invalidSyntax"
+	ScimTypeInvalidPath   = "invalidPath"
+	ScimTypeNoTarget      = "noTarget"
+	ScimTypeInvalidValue  = "invalidValue"
+)
+
+// ScimError SCIM 接口的错误，按 SCIM 协议的格式返回
+type ScimError struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+// 实现error接口
+func (e *ScimError) Error() string {
+	return e.Detail
+}
+
+// 创建 SCIM 错误
+func NewScimError(status int, scimType, detail string) *ScimError {
+	return &ScimError{Status: status, ScimType: scimType, Detail: detail}
+}
+
+// SCIM 错误响应结构体
+type scimErrorBody struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// ScimSuccess 返回 SCIM 资源，data 为 nil 时只返回状态码
+func ScimSuccess(c *gin.Context, status int, data any) {
+	setResult(c, CodeSuccess, "成功")
+	if data == nil {
+		c.Status(status)
+		return
+	}
+	c.Header("Content-Type", ScimContentType)
+	c.JSON(status, data)
+}
+
+// ScimFail 按 SCIM 协议的格式返回错误，业务错误按业务状态码转换为 HTTP 状态码
+func ScimFail(c *gin.Context, err error) {
+	scimErr, ok := err.(*ScimError)
+	if !ok {
+		bizErr, ok := err.(*BusinessError)
+		if !ok {
+			bizErr = ErrServerError
+		}
+		scimErr = NewScimError(codeToHTTPStaus(bizErr.Code), "", bizErr.Message)
+	}
+	setResult(c, scimStatusCode(scimErr.Status), scimErr.Detail)
+	c.Header("Content-Type", ScimContentType)
+	c.JSON(scimErr.Status, scimErrorBody{
+		Schemas:  []string{scimErrorSchema},
+		Status:   strconv.Itoa(scimErr.Status),
+		ScimType: scimErr.ScimType,
+		Detail:   scimErr.Detail,
+	})
+}
+
+// HTTP 状态码对应的业务状态码，供操作日志使用
+func scimStatusCode(status int) int {
+	switch {
+	case status == http.StatusUnauthorized:
+		return CodeUnauthorized
+	case status == http.StatusForbidden:
+		return CodeForbidden
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status >= http.StatusInternalServerError:
+		return CodeServerError
+	default:
+		return CodeInvalidParams
+	}
+}
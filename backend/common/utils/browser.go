@@ -30,6 +30,26 @@ func GetBrowser(c *gin.Context) string {
 	return normalizeBrowser(browser)
 }
 
+// GetDevice 获取设备类型，能识别出设备型号时返回型号
+func GetDevice(c *gin.Context) string {
+	userAgentStr := c.Request.Header.Get("User-Agent")
+	if userAgentStr == "" {
+		return "Unknown"
+	}
+
+	ua := user_agent.New(userAgentStr)
+	switch {
+	case ua.Bot():
+		return "Bot"
+	case ua.Model() != "":
+		return ua.Model()
+	case ua.Mobile():
+		return "Mobile"
+	default:
+		return "Desktop"
+	}
+}
+
 // normalizeOS 标准化操作系统名称
 func normalizeOS(os string) string {
 	if os == "" {
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// DataVersion 根据数据内容计算版本号，内容不变时版本号不变
+func DataVersion(data any) string {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:8])
+}
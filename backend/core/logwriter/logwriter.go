@@ -0,0 +1,212 @@
+// 异步批量日志写入：登录日志、操作日志先写入有界队列，由后台协程按数量或时间间隔批量插入数据库，
+// 队列已满时直接丢弃并计数，保证记录日志不会阻塞请求，也不会导致请求失败
+
+package logwriter
+
+import (
+	"context"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/global"
+	"go-admin-server/pkg/geoip"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBufferSize    = 10000
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+)
+
+// 运行指标
+type Stats struct {
+	Enqueued uint64 `json:"enqueued"` // 成功进入队列的日志数
+	Written  uint64 `json:"written"`  // 成功写入数据库的日志数
+	Dropped  uint64 `json:"dropped"`  // 队列已满或已关闭时丢弃的日志数
+	Failed   uint64 `json:"failed"`   // 写入数据库失败的日志数
+	Pending  int    `json:"pending"`  // 队列中等待写入的日志数
+}
+
+type writer struct {
+	queue         chan any // *entity.SysLoginLog 或 *entity.SysOperationLog
+	batchSize     int
+	flushInterval time.Duration
+	done          chan struct{}
+
+	mu     sync.RWMutex // 保护 closed，避免向已关闭的队列写入
+	closed bool
+
+	enqueued atomic.Uint64
+	written  atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+}
+
+var std *writer
+
+// Start 启动后台写入协程
+func Start(cfg config.LogWriter) {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	std = &writer{
+		queue:         make(chan any, cfg.BufferSize),
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		done:          make(chan struct{}),
+	}
+	go std.run()
+}
+
+// Stop 停止接收新日志，等待队列中的日志全部写入或 ctx 超时
+func Stop(ctx context.Context) error {
+	if std == nil {
+		return nil
+	}
+	std.mu.Lock()
+	if !std.closed {
+		std.closed = true
+		close(std.queue)
+	}
+	std.mu.Unlock()
+
+	select {
+	case <-std.done:
+		stats := GetStats()
+		global.Logger.Info("Log writer stopped",
+			zap.Uint64("written", stats.Written),
+			zap.Uint64("dropped", stats.Dropped),
+			zap.Uint64("failed", stats.Failed))
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GetStats 获取运行指标
+func GetStats() Stats {
+	if std == nil {
+		return Stats{}
+	}
+	return Stats{
+		Enqueued: std.enqueued.Load(),
+		Written:  std.written.Load(),
+		Dropped:  std.dropped.Load(),
+		Failed:   std.failed.Load(),
+		Pending:  len(std.queue),
+	}
+}
+
+// WriteLoginLog 写入登录日志，登录地点为空时由后台协程根据IP补全
+func WriteLoginLog(log *entity.SysLoginLog) {
+	write(log)
+}
+
+// WriteOperationLog 写入操作日志
+func WriteOperationLog(log *entity.SysOperationLog) {
+	write(log)
+}
+
+func write(log any) {
+	if std == nil {
+		// 未启动后台协程(如命令行模式)时直接写入
+		if err := global.DB.Create(log).Error; err != nil {
+			global.Logger.Error("Failed to write log", zap.Error(err))
+		}
+		return
+	}
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	if std.closed {
+		std.drop()
+		return
+	}
+	select {
+	case std.queue <- log:
+		std.enqueued.Add(1)
+	default:
+		std.drop()
+	}
+}
+
+// 丢弃日志，每丢弃1000条输出一次警告，避免刷屏
+func (w *writer) drop() {
+	if dropped := w.dropped.Add(1); dropped%1000 == 1 {
+		global.Logger.Warn("Log queue is full, dropping logs", zap.Uint64("dropped", dropped))
+	}
+}
+
+func (w *writer) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	var loginLogs []*entity.SysLoginLog
+	var operationLogs []*entity.SysOperationLog
+	flush := func() {
+		if len(loginLogs) > 0 {
+			w.flushLoginLogs(loginLogs)
+			loginLogs = nil
+		}
+		if len(operationLogs) > 0 {
+			w.flushOperationLogs(operationLogs)
+			operationLogs = nil
+		}
+	}
+
+	for {
+		select {
+		case log, ok := <-w.queue:
+			if !ok {
+				flush()
+				return
+			}
+			switch l := log.(type) {
+			case *entity.SysLoginLog:
+				loginLogs = append(loginLogs, l)
+			case *entity.SysOperationLog:
+				operationLogs = append(operationLogs, l)
+			}
+			if len(loginLogs)+len(operationLogs) >= w.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (w *writer) flushLoginLogs(logs []*entity.SysLoginLog) {
+	for _, log := range logs {
+		if log.LoginLocation == "" {
+			location := geoip.Lookup(log.IpAddress)
+			log.LoginLocation = location.String()
+			log.Country, log.Region, log.City, log.Isp = location.Country, location.Region, location.City, location.ISP
+		}
+	}
+	if err := global.DB.CreateInBatches(logs, w.batchSize).Error; err != nil {
+		w.failed.Add(uint64(len(logs)))
+		global.Logger.Error("Failed to write login logs", zap.Int("count", len(logs)), zap.Error(err))
+		return
+	}
+	w.written.Add(uint64(len(logs)))
+}
+
+func (w *writer) flushOperationLogs(logs []*entity.SysOperationLog) {
+	if err := global.DB.CreateInBatches(logs, w.batchSize).Error; err != nil {
+		w.failed.Add(uint64(len(logs)))
+		global.Logger.Error("Failed to write operation logs", zap.Int("count", len(logs)), zap.Error(err))
+		return
+	}
+	w.written.Add(uint64(len(logs)))
+}
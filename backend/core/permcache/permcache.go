@@ -0,0 +1,332 @@
+// 用户权限缓存：本地内存 + redis 两级缓存，按用户id缓存，并按角色id建立索引。
+// 角色、菜单、用户角色变化时删除受影响用户的缓存，并通过 redis 发布订阅通知所有实例删除本地缓存。
+// 每个用户在 redis 中有一个失效版本，失效时递增，写入缓存时比较版本，避免任何实例把失效前加载的旧数据写回缓存
+
+package permcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/global"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTTL      = 30 * time.Minute
+	defaultLocalTTL = time.Minute
+)
+
+// 缓存失效通知
+type invalidation struct {
+	AdminIDs []uint `json:"adminIds,omitempty"`
+	RoleIDs  []uint `json:"roleIds,omitempty"`
+}
+
+type localEntry struct {
+	permission *entity.AdminPermission
+	expiresAt  time.Time
+}
+
+var (
+	ttl      = defaultTTL
+	localTTL = defaultLocalTTL
+	enabled  = true
+
+	mu    sync.RWMutex
+	local = make(map[uint]localEntry)
+
+	// 本实例每次失效本地缓存都会递增，写入本地缓存前比较，避免把失效前加载的旧数据写入本地缓存
+	generation atomic.Uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+)
+
+// Start 读取配置，并订阅缓存失效通知
+func Start(cfg config.PermissionCache) {
+	enabled = !cfg.Disabled
+	if cfg.TTL > 0 {
+		ttl = cfg.TTL
+	}
+	if cfg.LocalTTL > 0 {
+		localTTL = cfg.LocalTTL
+	}
+	if localTTL > ttl {
+		localTTL = ttl
+	}
+	if !enabled {
+		return
+	}
+
+	var ctx context.Context
+	ctx, cancel = context.WithCancel(context.Background())
+	done = make(chan struct{})
+	go subscribe(ctx)
+}
+
+// Stop 停止订阅
+func Stop() {
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// 版本一致时才写入缓存：比较用户的失效版本，写入权限缓存并加入角色索引
+var setScript = redis.NewScript(`
+if (redis.call('GET', KEYS[1]) or '0') ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+for i = 3, #KEYS do
+	redis.call('SADD', KEYS[i], ARGV[4])
+	redis.call('PEXPIRE', KEYS[i], ARGV[5])
+end
+return 1
+`)
+
+// Version 用户权限缓存的失效版本，从数据库加载权限前获取，写入缓存时传入
+type Version struct {
+	local  uint64 // 本实例的本地缓存失效版本
+	remote string // redis 中用户的失效版本
+	ok     bool   // 读取 redis 失败时不写入缓存
+}
+
+// CurrentVersion 返回用户当前的失效版本
+func CurrentVersion(adminId uint) Version {
+	version := Version{local: generation.Load()}
+	if !enabled {
+		return version
+	}
+	remote, err := global.RDB.Get(context.Background(), versionKey(adminId)).Result()
+	switch {
+	case err == nil:
+		version.remote, version.ok = remote, true
+	case errors.Is(err, redis.Nil):
+		version.remote, version.ok = "0", true
+	}
+	return version
+}
+
+// Get 获取用户权限缓存，先查本地缓存，再查 redis
+func Get(adminId uint) (*entity.AdminPermission, bool) {
+	if !enabled {
+		return nil, false
+	}
+	mu.RLock()
+	entry, ok := local[adminId]
+	mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.permission, true
+	}
+
+	gen := generation.Load()
+	data, err := global.RDB.Get(context.Background(), adminKey(adminId)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	var permission entity.AdminPermission
+	if err := json.Unmarshal(data, &permission); err != nil {
+		return nil, false
+	}
+	setLocal(adminId, &permission, localTTL, gen)
+	return &permission, true
+}
+
+// Set 写入用户权限缓存，maxTTL 用于让缓存在临时授权生效或失效时过期，
+// version 为加载权限前获取的失效版本，期间发生过失效(包括其他实例发起的失效)时不写入
+func Set(adminId uint, permission *entity.AdminPermission, maxTTL time.Duration, version Version) {
+	if !enabled || !version.ok || generation.Load() != version.local {
+		return
+	}
+	expiration := ttl
+	if maxTTL > 0 && maxTTL < expiration {
+		expiration = maxTTL
+	}
+	// 即将过期的缓存没有意义，redis 的过期时间也至少为1毫秒
+	if expiration < time.Millisecond {
+		return
+	}
+	data, err := json.Marshal(permission)
+	if err != nil {
+		return
+	}
+
+	keys := make([]string, 0, len(permission.RoleIDs)+2)
+	keys = append(keys, versionKey(adminId), adminKey(adminId))
+	for _, roleId := range permission.RoleIDs {
+		keys = append(keys, roleIndexKey(roleId))
+	}
+	written, err := setScript.Run(context.Background(), global.RDB, keys,
+		version.remote, data, expiration.Milliseconds(), adminId, ttl.Milliseconds()).Int()
+	if err != nil {
+		global.Logger.Warn("Failed to cache admin permission", zap.Uint("adminId", adminId), zap.Error(err))
+		return
+	}
+	if written == 0 {
+		return
+	}
+
+	localExpiration := localTTL
+	if expiration < localExpiration {
+		localExpiration = expiration
+	}
+	setLocal(adminId, permission, localExpiration, version.local)
+}
+
+// InvalidateAdmins 删除用户的权限缓存，用于用户角色发生变化
+func InvalidateAdmins(adminIds ...uint) error {
+	return Invalidate(adminIds, nil)
+}
+
+// InvalidateRoles 删除生效角色包含这些角色的用户的权限缓存，用于角色或菜单发生变化
+func InvalidateRoles(roleIds ...uint) error {
+	return Invalidate(nil, roleIds)
+}
+
+// Invalidate 删除指定用户、以及生效角色包含指定角色的用户的权限缓存，并通知所有实例
+func Invalidate(adminIds, roleIds []uint) error {
+	if !enabled || len(adminIds)+len(roleIds) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	msg := invalidation{AdminIDs: append([]uint(nil), adminIds...), RoleIDs: roleIds}
+	for _, roleId := range roleIds {
+		members, err := global.RDB.SMembers(ctx, roleIndexKey(roleId)).Result()
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			if id, err := strconv.ParseUint(member, 10, 64); err == nil {
+				msg.AdminIDs = append(msg.AdminIDs, uint(id))
+			}
+		}
+	}
+	return invalidate(msg)
+}
+
+// 递增用户的失效版本并删除 redis 缓存和本地缓存，再通知其他实例删除本地缓存。
+// 失效版本的有效期不短于缓存，过期后按0处理，此时失效前写入的缓存也已过期
+func invalidate(msg invalidation) error {
+	ctx := context.Background()
+	keys := make([]string, 0, len(msg.AdminIDs)+len(msg.RoleIDs))
+	pipe := global.RDB.TxPipeline()
+	for _, adminId := range msg.AdminIDs {
+		pipe.Incr(ctx, versionKey(adminId))
+		pipe.Expire(ctx, versionKey(adminId), ttl)
+		keys = append(keys, adminKey(adminId))
+	}
+	for _, roleId := range msg.RoleIDs {
+		keys = append(keys, roleIndexKey(roleId))
+	}
+	pipe.Del(ctx, keys...)
+	dropLocal(msg)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return global.RDB.Publish(ctx, global.PermCacheChannel, data).Err()
+}
+
+// 订阅其他实例发出的失效通知，连接断开期间可能错过通知，重新订阅成功后清空本地缓存
+func subscribe(ctx context.Context) {
+	defer close(done)
+	pubsub := global.RDB.Subscribe(ctx, global.PermCacheChannel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			global.Logger.Warn("Permission cache subscription interrupted", zap.Error(err))
+			dropAllLocal()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			dropAllLocal()
+		case *redis.Message:
+			var inv invalidation
+			if err := json.Unmarshal([]byte(m.Payload), &inv); err != nil {
+				global.Logger.Warn("Invalid permission cache invalidation", zap.String("payload", m.Payload))
+				continue
+			}
+			dropLocal(inv)
+		}
+	}
+}
+
+func setLocal(adminId uint, permission *entity.AdminPermission, expiration time.Duration, gen uint64) {
+	mu.Lock()
+	defer mu.Unlock()
+	// 在持有锁的情况下再次比较，保证不会覆盖并发失效的结果
+	if generation.Load() != gen {
+		return
+	}
+	local[adminId] = localEntry{permission: permission, expiresAt: time.Now().Add(expiration)}
+}
+
+// 删除指定用户、以及生效角色包含指定角色的用户的本地缓存
+func dropLocal(msg invalidation) {
+	mu.Lock()
+	defer mu.Unlock()
+	generation.Add(1)
+	for _, adminId := range msg.AdminIDs {
+		delete(local, adminId)
+	}
+	if len(msg.RoleIDs) == 0 {
+		return
+	}
+	roles := make(map[uint]bool, len(msg.RoleIDs))
+	for _, roleId := range msg.RoleIDs {
+		roles[roleId] = true
+	}
+	for adminId, entry := range local {
+		for _, roleId := range entry.permission.RoleIDs {
+			if roles[roleId] {
+				delete(local, adminId)
+				break
+			}
+		}
+	}
+}
+
+func dropAllLocal() {
+	mu.Lock()
+	defer mu.Unlock()
+	generation.Add(1)
+	local = make(map[uint]localEntry)
+}
+
+func adminKey(adminId uint) string {
+	return global.PermCacheAdminPrefix + strconv.FormatUint(uint64(adminId), 10)
+}
+
+func versionKey(adminId uint) string {
+	return global.PermCacheVersionPrefix + strconv.FormatUint(uint64(adminId), 10)
+}
+
+func roleIndexKey(roleId uint) string {
+	return global.PermCacheRoleAdminPrefix + strconv.FormatUint(uint64(roleId), 10)
+}
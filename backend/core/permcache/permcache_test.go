@@ -0,0 +1,145 @@
+package permcache
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/global"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 使用 miniredis 替换redis并清空本地缓存
+func setupTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	oldLogger, oldRDB := global.Logger, global.RDB
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	global.Logger, global.RDB = zap.NewNop(), rdb
+	dropAllLocal()
+	t.Cleanup(func() {
+		rdb.Close()
+		dropAllLocal()
+		global.Logger, global.RDB = oldLogger, oldRDB
+	})
+	return mr
+}
+
+func testPermission(roleIds ...uint) *entity.AdminPermission {
+	return &entity.AdminPermission{RoleIDs: roleIds}
+}
+
+func TestSetAndGet(t *testing.T) {
+	mr := setupTestRedis(t)
+
+	Set(1, testPermission(10, 11), 0, CurrentVersion(1))
+	if !mr.Exists(adminKey(1)) {
+		t.Fatal("permission was not cached in redis")
+	}
+	if ttl := mr.TTL(adminKey(1)); ttl <= 0 || ttl > defaultTTL {
+		t.Errorf("cache ttl = %v, want (0, %v]", ttl, defaultTTL)
+	}
+	for _, roleId := range []uint{10, 11} {
+		if ok, _ := mr.SIsMember(roleIndexKey(roleId), "1"); !ok {
+			t.Errorf("admin is not indexed by role %d", roleId)
+		}
+	}
+	if _, ok := Get(1); !ok {
+		t.Error("Get() missed cached permission")
+	}
+
+	// 本地缓存过期后从redis读取
+	dropAllLocal()
+	if permission, ok := Get(1); !ok || len(permission.RoleIDs) != 2 {
+		t.Errorf("Get() = %v, %v, want the cached permission", permission, ok)
+	}
+}
+
+func TestSetMaxTTL(t *testing.T) {
+	mr := setupTestRedis(t)
+
+	Set(1, testPermission(), 5*time.Second, CurrentVersion(1))
+	if ttl := mr.TTL(adminKey(1)); ttl <= 0 || ttl > 5*time.Second {
+		t.Errorf("cache ttl = %v, want (0, 5s]", ttl)
+	}
+	// 即将过期的缓存不写入
+	Set(2, testPermission(), time.Microsecond, CurrentVersion(2))
+	if mr.Exists(adminKey(2)) {
+		t.Error("permission expiring within 1ms should not be cached")
+	}
+}
+
+func TestSetAfterInvalidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		invalidate func(mr *miniredis.Miniredis) error
+	}{
+		{"local invalidation", func(*miniredis.Miniredis) error { return InvalidateAdmins(1) }},
+		// 其他实例递增了失效版本，本实例还没有收到通知
+		{"remote invalidation", func(mr *miniredis.Miniredis) error {
+			_, err := mr.Incr(versionKey(1), 1)
+			return err
+		}},
+		{"role invalidation", func(*miniredis.Miniredis) error {
+			// 用户通过角色索引被失效
+			Set(1, testPermission(10), 0, CurrentVersion(1))
+			return InvalidateRoles(10)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr := setupTestRedis(t)
+			version := CurrentVersion(1)
+			if err := tt.invalidate(mr); err != nil {
+				t.Fatal(err)
+			}
+			// 失效前加载的权限不能写回缓存
+			Set(1, testPermission(10), 0, version)
+			if mr.Exists(adminKey(1)) {
+				t.Error("stale permission was written to redis")
+			}
+			if _, ok := Get(1); ok {
+				t.Error("stale permission was cached")
+			}
+
+			// 失效后重新加载的权限可以写入
+			Set(1, testPermission(10), 0, CurrentVersion(1))
+			if _, ok := Get(1); !ok {
+				t.Error("fresh permission was not cached")
+			}
+		})
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	mr := setupTestRedis(t)
+	Set(1, testPermission(10), 0, CurrentVersion(1))
+	Set(2, testPermission(11), 0, CurrentVersion(2))
+	Set(3, testPermission(10, 11), 0, CurrentVersion(3))
+
+	if err := InvalidateRoles(10); err != nil {
+		t.Fatal(err)
+	}
+	for adminId, want := range map[uint]bool{1: false, 2: true, 3: false} {
+		if _, ok := Get(adminId); ok != want {
+			t.Errorf("admin %d cached = %v, want %v", adminId, ok, want)
+		}
+	}
+	if mr.Exists(roleIndexKey(10)) {
+		t.Error("role index was not deleted")
+	}
+	// 失效版本不短于缓存的有效期
+	if ttl := mr.TTL(versionKey(1)); ttl < defaultTTL {
+		t.Errorf("version ttl = %v, want at least %v", ttl, defaultTTL)
+	}
+}
+
+func TestReadVersionFailed(t *testing.T) {
+	mr := setupTestRedis(t)
+	mr.Close()
+	if version := CurrentVersion(1); version.ok {
+		t.Error("version should not be usable when redis is unavailable")
+	}
+}
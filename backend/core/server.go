@@ -3,6 +3,10 @@ package core
 import (
 	"context"
 	"fmt"
+	"go-admin-server/api/service"
+	"go-admin-server/core/logwriter"
+	"go-admin-server/core/permcache"
+	"go-admin-server/core/sweeper"
 	"go-admin-server/global"
 	"go-admin-server/router"
 	"net/http"
@@ -15,6 +19,21 @@ import (
 )
 
 func RunServer() {
+	// 启动日志异步写入
+	logwriter.Start(global.Config.LogWriter)
+	// 启动权限缓存，订阅其他实例的缓存失效通知
+	permcache.Start(global.Config.PermissionCache)
+	// 启动后台定时任务
+	sweeper.Start(sweeper.Job{
+		Name:     "role_grant",
+		Interval: global.Config.RoleGrant.SweepInterval,
+		Run:      service.SweepExpiredRoleGrants,
+	}, sweeper.Job{
+		Name:     "ldap_sync",
+		Interval: global.Config.Ldap.Sync.Interval,
+		Run:      service.SweepLdapDirectory,
+	})
+
 	router := router.SetupRouter()
 	address := fmt.Sprintf("%s:%d", global.Config.Server.Host, global.Config.Server.Port)
 
@@ -43,7 +62,13 @@ func RunServer() {
 	
 	// 优雅关闭
 	if err := srv.Shutdown(ctx); err != nil {
-		global.Logger.Fatal("Server forced to shutdown", zap.Error(err))
+		global.Logger.Error("Server forced to shutdown", zap.Error(err))
+	}
+	sweeper.Stop()
+	permcache.Stop()
+	// 请求全部处理完成后，将队列中剩余的日志写入数据库
+	if err := logwriter.Stop(ctx); err != nil {
+		global.Logger.Error("Failed to drain log queue", zap.Error(err))
 	}
 	global.Logger.Info("Server exit")
 }
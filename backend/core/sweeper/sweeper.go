@@ -0,0 +1,78 @@
+// 后台定时任务：按固定间隔执行清理类任务，多实例部署时通过 redis 锁保证同一时刻只有一个实例执行
+
+package sweeper
+
+import (
+	"context"
+	"go-admin-server/global"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+)
+
+// Job 定时任务
+type Job struct {
+	Name     string                    // 任务名称，同时作为 redis 锁的键名
+	Interval time.Duration             // 执行间隔，不大于0时不执行
+	Run      func(ctx context.Context) // 任务内容
+}
+
+// Start 启动定时任务
+func Start(jobs ...Job) {
+	var ctx context.Context
+	ctx, cancel = context.WithCancel(context.Background())
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			continue
+		}
+		wg.Add(1)
+		go run(ctx, job)
+	}
+}
+
+// Stop 停止定时任务，等待正在执行的任务结束
+func Stop() {
+	if cancel == nil {
+		return
+	}
+	cancel()
+	wg.Wait()
+}
+
+func run(ctx context.Context, job Job) {
+	defer wg.Done()
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runOnce(ctx, job)
+		}
+	}
+}
+
+// 获取到锁的实例执行任务，锁在任务执行间隔内自动过期
+func runOnce(ctx context.Context, job Job) {
+	locked, err := global.RDB.SetNX(ctx, global.SweeperLockPrefix+job.Name, 1, job.Interval).Result()
+	if err != nil {
+		global.Logger.Error("Failed to acquire sweeper lock", zap.String("job", job.Name), zap.Error(err))
+		return
+	}
+	if !locked {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			global.Logger.Error("Sweeper job panicked", zap.String("job", job.Name), zap.Any("panic", r))
+		}
+	}()
+	job.Run(ctx)
+}
@@ -105,6 +105,51 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/adminService/forceLogout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除指定用户的所有登录会话，用户需要重新登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "强制用户下线",
+                "parameters": [
+                    {
+                        "description": "强制下线请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ForceLogoutDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/api/adminService/getAdminById": {
             "post": {
                 "security": [
@@ -189,7 +234,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "状态",
+                        "description": "状态：1-\u003e启用,2-\u003e禁用,3-\u003e待激活",
                         "name": "status",
                         "in": "query"
                     },
@@ -222,14 +267,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/adminService/resetPassword": {
+        "/api/adminService/impersonate": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "重置用户密码",
+                "description": "以指定用户的身份登录，用于按该用户看到的内容排查问题。返回的令牌同时携带发起人和被模拟用户的身份，\n操作日志会记录双方；模拟期间不能修改密码、管理两步验证或再次模拟登录，不能模拟自己或超级管理员",
                 "consumes": [
                     "application/json"
                 ],
@@ -239,15 +284,15 @@ const docTemplate = `{
                 "tags": [
                     "用户管理"
                 ],
-                "summary": "重置用户密码",
+                "summary": "模拟登录",
                 "parameters": [
                     {
-                        "description": "重置密码请求",
+                        "description": "模拟登录请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.ResetPasswordDto"
+                            "$ref": "#/definitions/entity.ImpersonateDto"
                         }
                     }
                 ],
@@ -267,14 +312,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/adminService/updateAdmin": {
+        "/api/adminService/inviteAdmin": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改用户信息",
+                "description": "预先设置部门、岗位和角色，向被邀请人的邮箱发送激活链接，被邀请人设置密码后账号启用；接受邀请前用户为待激活状态",
                 "consumes": [
                     "application/json"
                 ],
@@ -284,15 +329,15 @@ const docTemplate = `{
                 "tags": [
                     "用户管理"
                 ],
-                "summary": "修改用户信息",
+                "summary": "邀请用户",
                 "parameters": [
                     {
-                        "description": "修改用户请求结构体",
+                        "description": "邀请用户请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdateAdminDto"
+                            "$ref": "#/definitions/entity.InviteAdminDto"
                         }
                     }
                 ],
@@ -312,14 +357,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/adminService/updateAdminStatus": {
+        "/api/adminService/resendInvitation": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改用户状态",
+                "description": "向待激活用户重新发送邀请邮件，之前发送的链接失效，有效期重新计算",
                 "consumes": [
                     "application/json"
                 ],
@@ -329,15 +374,15 @@ const docTemplate = `{
                 "tags": [
                     "用户管理"
                 ],
-                "summary": "修改用户状态",
+                "summary": "重新发送邀请",
                 "parameters": [
                     {
-                        "description": "修改用户状态请求",
+                        "description": "重新发送邀请请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdateAdminStatusDto"
+                            "$ref": "#/definitions/entity.AdminInvitationDto"
                         }
                     }
                 ],
@@ -357,14 +402,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/adminService/updatePassword": {
+        "/api/adminService/resetPassword": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改个人密码",
+                "description": "重置用户密码",
                 "consumes": [
                     "application/json"
                 ],
@@ -374,15 +419,15 @@ const docTemplate = `{
                 "tags": [
                     "用户管理"
                 ],
-                "summary": "修改个人密码",
+                "summary": "重置用户密码",
                 "parameters": [
                     {
-                        "description": "修改个人密码请求结构体",
+                        "description": "重置密码请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdatePasswordDto"
+                            "$ref": "#/definitions/entity.ResetPasswordDto"
                         }
                     }
                 ],
@@ -402,14 +447,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/adminService/updatePersonal": {
+        "/api/adminService/resetTwoFactor": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改个人资料",
+                "description": "关闭用户的两步验证并清除密钥和恢复码，用于用户丢失验证器的情况",
                 "consumes": [
                     "application/json"
                 ],
@@ -419,15 +464,15 @@ const docTemplate = `{
                 "tags": [
                     "用户管理"
                 ],
-                "summary": "修改个人资料",
+                "summary": "重置用户两步验证",
                 "parameters": [
                     {
-                        "description": "修改个人资料请求结构体",
+                        "description": "重置两步验证请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdatePersonalDto"
+                            "$ref": "#/definitions/entity.ResetTwoFactorDto"
                         }
                     }
                 ],
@@ -447,37 +492,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/captcha": {
-            "get": {
-                "description": "获取验证码",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "无需认证接口"
-                ],
-                "summary": "获取验证码",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/deptService/createDept": {
+        "/api/adminService/revokeInvitation": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "创建部门",
+                "description": "撤销尚未接受的邀请，同时删除待激活的用户",
                 "consumes": [
                     "application/json"
                 ],
@@ -485,17 +507,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "部门管理"
+                    "用户管理"
                 ],
-                "summary": "创建部门",
+                "summary": "撤销邀请",
                 "parameters": [
                     {
-                        "description": "创建部门请求结构体",
+                        "description": "撤销邀请请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.CreateDeptDto"
+                            "$ref": "#/definitions/entity.AdminInvitationDto"
                         }
                     }
                 ],
@@ -515,14 +537,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deptService/deleteDept": {
+        "/api/adminService/unlockAdmin": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据id删除部门",
+                "description": "清除用户因登录失败次数过多产生的锁定状态和失败次数",
                 "consumes": [
                     "application/json"
                 ],
@@ -530,17 +552,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "部门管理"
+                    "用户管理"
                 ],
-                "summary": "根据id删除部门",
+                "summary": "解除用户登录锁定",
                 "parameters": [
                     {
-                        "description": "删除部门请求",
+                        "description": "解除登录锁定请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.DeleteDeptDto"
+                            "$ref": "#/definitions/entity.UnlockAdminDto"
                         }
                     }
                 ],
@@ -560,14 +582,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deptService/getDeptById": {
+        "/api/adminService/updateAdmin": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据id查询部门",
+                "description": "修改用户信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -575,17 +597,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "部门管理"
+                    "用户管理"
                 ],
-                "summary": "根据id查询部门",
+                "summary": "修改用户信息",
                 "parameters": [
                     {
-                        "description": "根据id查询部门请求",
+                        "description": "修改用户请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.GetDeptByIdDto"
+                            "$ref": "#/definitions/entity.UpdateAdminDto"
                         }
                     }
                 ],
@@ -605,14 +627,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deptService/getDeptDropdown": {
-            "get": {
+        "/api/adminService/updateAdminStatus": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "部门下拉列表",
+                "description": "修改用户状态",
                 "consumes": [
                     "application/json"
                 ],
@@ -620,9 +642,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "部门管理"
+                    "用户管理"
+                ],
+                "summary": "修改用户状态",
+                "parameters": [
+                    {
+                        "description": "修改用户状态请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateAdminStatusDto"
+                        }
+                    }
                 ],
-                "summary": "部门下拉列表",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -639,14 +672,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deptService/getDeptList": {
-            "get": {
+        "/api/adminService/updatePassword": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "查询部门列表",
+                "description": "修改个人密码",
                 "consumes": [
                     "application/json"
                 ],
@@ -654,21 +687,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "部门管理"
+                    "用户管理"
                 ],
-                "summary": "查询部门列表",
+                "summary": "修改个人密码",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "部门名称",
-                        "name": "deptName",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "部门状态",
-                        "name": "deptStatus",
-                        "in": "query"
+                        "description": "修改个人密码请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdatePasswordDto"
+                        }
                     }
                 ],
                 "responses": {
@@ -687,14 +717,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deptService/updateDept": {
+        "/api/adminService/updatePersonal": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改部门信息",
+                "description": "修改个人资料",
                 "consumes": [
                     "application/json"
                 ],
@@ -702,17 +732,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "部门管理"
+                    "用户管理"
                 ],
-                "summary": "修改部门信息",
+                "summary": "修改个人资料",
                 "parameters": [
                     {
-                        "description": "修改部门请求结构体",
+                        "description": "修改个人资料请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdateSysDeptDto"
+                            "$ref": "#/definitions/entity.UpdatePersonalDto"
                         }
                     }
                 ],
@@ -732,14 +762,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/logService/batchDeleteLoginLog": {
+        "/api/apiTokenService/createApiToken": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "批量删除登录日志",
+                "description": "为数据权限范围内的服务账号创建API令牌，令牌明文只在响应中返回一次。\n服务账号拥有超级管理员权限或操作人没有的权限时不能创建。\n调用接口时放在 X-API-Key 请求头或 Authorization: Bearer 中，scopes 为空时与服务账号的权限一致",
                 "consumes": [
                     "application/json"
                 ],
@@ -747,17 +777,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "日志管理"
+                    "API令牌管理"
                 ],
-                "summary": "批量删除登录日志",
+                "summary": "创建服务账号的API令牌",
                 "parameters": [
                     {
-                        "description": "批量删除登录日志请求",
+                        "description": "创建API令牌请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.BatchDeleteLoginLogDto"
+                            "$ref": "#/definitions/entity.CreateApiTokenDto"
                         }
                     }
                 ],
@@ -765,7 +795,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.ApiTokenCreatedVo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -777,14 +819,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/logService/batchDeleteOpLog": {
-            "post": {
+        "/api/apiTokenService/getApiTokenList": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "批量操作登录日志",
+                "description": "查询数据权限范围内用户的API令牌，不返回令牌明文",
                 "consumes": [
                     "application/json"
                 ],
@@ -792,25 +834,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "日志管理"
+                    "API令牌管理"
                 ],
-                "summary": "批量删除操作日志",
+                "summary": "查询API令牌列表",
                 "parameters": [
                     {
-                        "description": "批量删除操作日志请求",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.BatchDeleteOpLogDto"
-                        }
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "pageNum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页大小",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "所属用户id",
+                        "name": "adminId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "令牌名称",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.ApiTokenListVo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -822,14 +891,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/logService/deleteLoginLog": {
+        "/api/apiTokenService/revokeApiToken": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "删除登录日志",
+                "description": "吊销数据权限范围内用户的API令牌，吊销后立即失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -837,17 +906,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "日志管理"
+                    "API令牌管理"
                 ],
-                "summary": "删除登录日志",
+                "summary": "吊销API令牌",
                 "parameters": [
                     {
-                        "description": "删除登录日志请求",
+                        "description": "吊销API令牌请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.DeleteLoginLogDto"
+                            "$ref": "#/definitions/entity.RevokeApiTokenDto"
                         }
                     }
                 ],
@@ -867,14 +936,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/logService/deleteOpLog": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "删除操作日志",
+        "/api/captcha": {
+            "get": {
+                "description": "获取验证码",
                 "consumes": [
                     "application/json"
                 ],
@@ -882,44 +946,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "日志管理"
-                ],
-                "summary": "删除操作日志",
-                "parameters": [
-                    {
-                        "description": "删除操作日志请求",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.DeleteOpLogDto"
-                        }
-                    }
+                    "无需认证接口"
                 ],
+                "summary": "获取验证码",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
                     }
                 }
             }
         },
-        "/api/logService/getLoginLogList": {
-            "get": {
+        "/api/deptService/createDept": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "查询登录日志列表",
+                "description": "创建部门",
                 "consumes": [
                     "application/json"
                 ],
@@ -927,45 +974,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "日志管理"
+                    "部门管理"
                 ],
-                "summary": "查询登录日志列表",
+                "summary": "创建部门",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "页码",
-                        "name": "pageNum",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "页大小",
-                        "name": "pageSize",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "用户名",
-                        "name": "username",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "登录状态",
-                        "name": "loginStatus",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "开始时间",
-                        "name": "beginTime",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "结束时间",
-                        "name": "endTime",
-                        "in": "query"
+                        "description": "创建部门请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateDeptDto"
+                        }
                     }
                 ],
                 "responses": {
@@ -984,14 +1004,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/logService/getOperationLogList": {
-            "get": {
+        "/api/deptService/deleteDept": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "查询操作日志列表",
+                "description": "根据id删除部门",
                 "consumes": [
                     "application/json"
                 ],
@@ -999,39 +1019,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "日志管理"
+                    "部门管理"
                 ],
-                "summary": "查询操作日志列表",
+                "summary": "根据id删除部门",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "页码",
-                        "name": "pageNum",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "页大小",
-                        "name": "pageSize",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "用户名",
-                        "name": "username",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "开始时间",
-                        "name": "beginTime",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "结束时间",
-                        "name": "endTime",
-                        "in": "query"
+                        "description": "删除部门请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeleteDeptDto"
+                        }
                     }
                 ],
                 "responses": {
@@ -1050,9 +1049,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/login": {
+        "/api/deptService/getDeptById": {
             "post": {
-                "description": "用户登录",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据id查询部门",
                 "consumes": [
                     "application/json"
                 ],
@@ -1060,17 +1064,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "无需认证接口"
+                    "部门管理"
                 ],
-                "summary": "用户登录",
+                "summary": "根据id查询部门",
                 "parameters": [
                     {
-                        "description": "登录请求结构体",
+                        "description": "根据id查询部门请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.LoginDto"
+                            "$ref": "#/definitions/entity.GetDeptByIdDto"
                         }
                     }
                 ],
@@ -1090,14 +1094,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/menuService/createMenu": {
-            "post": {
+        "/api/deptService/getDeptDropdown": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "创建菜单",
+                "description": "部门下拉列表",
                 "consumes": [
                     "application/json"
                 ],
@@ -1105,20 +1109,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
-                ],
-                "summary": "创建菜单",
-                "parameters": [
-                    {
-                        "description": "创建菜单请求结构体",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.CreateMenuDto"
-                        }
-                    }
+                    "部门管理"
                 ],
+                "summary": "部门下拉列表",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1135,14 +1128,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/menuService/deleteMenu": {
-            "post": {
+        "/api/deptService/getDeptList": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据id删除单个菜单",
+                "description": "查询部门列表",
                 "consumes": [
                     "application/json"
                 ],
@@ -1150,18 +1143,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "部门管理"
                 ],
-                "summary": "根据id删除单个菜单",
+                "summary": "查询部门列表",
                 "parameters": [
                     {
-                        "description": "删除菜单请求",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.DeleteMenuDto"
-                        }
+                        "type": "string",
+                        "description": "部门名称",
+                        "name": "deptName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "部门状态",
+                        "name": "deptStatus",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1180,14 +1176,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/menuService/getMenuById": {
+        "/api/deptService/updateDept": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据id查询菜单",
+                "description": "修改部门信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -1195,17 +1191,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "部门管理"
                 ],
-                "summary": "根据id查询菜单",
+                "summary": "修改部门信息",
                 "parameters": [
                     {
-                        "description": "根据id查询菜单请求",
+                        "description": "修改部门请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.GetMenuByIdDto"
+                            "$ref": "#/definitions/entity.UpdateSysDeptDto"
                         }
                     }
                 ],
@@ -1225,14 +1221,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/menuService/getMenuDropdown": {
-            "get": {
+        "/api/endImpersonation": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "菜单下拉列表",
+                "description": "删除模拟登录会话，回到发起人原来的登录会话，返回发起人的新令牌；原会话已失效时需要重新登录",
                 "consumes": [
                     "application/json"
                 ],
@@ -1240,9 +1236,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "当前用户"
                 ],
-                "summary": "菜单下拉列表",
+                "summary": "结束模拟登录",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1259,14 +1255,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/menuService/getMenuList": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "查询菜单列表",
+        "/api/invitation/accept": {
+            "post": {
+                "description": "设置密码并激活账号，新密码需符合密码策略；成功后使用用户名和新密码登录",
                 "consumes": [
                     "application/json"
                 ],
@@ -1274,21 +1265,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "无需认证接口"
                 ],
-                "summary": "查询菜单列表",
+                "summary": "接受邀请",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "菜单名称",
-                        "name": "menuName",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "菜单状态",
-                        "name": "menuStatus",
-                        "in": "query"
+                        "description": "接受邀请请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AcceptInvitationDto"
+                        }
                     }
                 ],
                 "responses": {
@@ -1307,14 +1295,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/menuService/updateMenu": {
+        "/api/invitation/info": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "修改菜单信息",
+                "description": "接受邀请页面使用邀请链接中的令牌查询被邀请的账号",
                 "consumes": [
                     "application/json"
                 ],
@@ -1322,17 +1305,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "无需认证接口"
                 ],
-                "summary": "修改菜单信息",
+                "summary": "查询邀请信息",
                 "parameters": [
                     {
-                        "description": "修改菜单请求结构体",
+                        "description": "查询邀请信息请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdateSysMenuDto"
+                            "$ref": "#/definitions/entity.InvitationTokenDto"
                         }
                     }
                 ],
@@ -1340,7 +1323,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.InvitationInfoVo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -1352,14 +1347,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/postService/batchDeletePosts": {
+        "/api/ldapService/syncDirectory": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "批量删除岗位",
+                "description": "按目录创建、更新和停用用户，并按 OU 和用户组映射部门和角色；dryRun 为 true 时只返回将要执行的变更",
                 "consumes": [
                     "application/json"
                 ],
@@ -1367,17 +1362,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "岗位管理"
+                    "目录同步"
                 ],
-                "summary": "批量删除岗位",
+                "summary": "同步LDAP目录",
                 "parameters": [
                     {
-                        "description": "要删除的岗位id列表",
+                        "description": "目录同步请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.BatchDeletePostsDto"
+                            "$ref": "#/definitions/entity.LdapSyncDto"
                         }
                     }
                 ],
@@ -1385,7 +1380,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.LdapSyncReport"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -1397,14 +1404,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/postService/createPost": {
+        "/api/logService/batchDeleteLoginLog": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "创建岗位",
+                "description": "批量删除登录日志",
                 "consumes": [
                     "application/json"
                 ],
@@ -1412,17 +1419,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "岗位管理"
+                    "日志管理"
                 ],
-                "summary": "创建岗位",
+                "summary": "批量删除登录日志",
                 "parameters": [
                     {
-                        "description": "创建岗位的请求参数",
+                        "description": "批量删除登录日志请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.CreateSysPostDto"
+                            "$ref": "#/definitions/entity.BatchDeleteLoginLogDto"
                         }
                     }
                 ],
@@ -1442,14 +1449,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/postService/deletePost": {
+        "/api/logService/batchDeleteOpLog": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "删除单个岗位",
+                "description": "批量操作登录日志",
                 "consumes": [
                     "application/json"
                 ],
@@ -1457,17 +1464,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "岗位管理"
+                    "日志管理"
                 ],
-                "summary": "删除单个岗位",
+                "summary": "批量删除操作日志",
                 "parameters": [
                     {
-                        "description": "删除单个岗位请求结构体",
+                        "description": "批量删除操作日志请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.DeletePostByIdDto"
+                            "$ref": "#/definitions/entity.BatchDeleteOpLogDto"
                         }
                     }
                 ],
@@ -1487,14 +1494,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/postService/getPostById": {
+        "/api/logService/deleteLoginLog": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根据id查询岗位信息",
+                "description": "删除登录日志",
                 "consumes": [
                     "application/json"
                 ],
@@ -1502,17 +1509,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "岗位管理"
+                    "日志管理"
                 ],
-                "summary": "根据id查询岗位信息",
+                "summary": "删除登录日志",
                 "parameters": [
                     {
-                        "description": "获取岗位信息请求结构体",
+                        "description": "删除登录日志请求",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.GetPostByIdDto"
+                            "$ref": "#/definitions/entity.DeleteLoginLogDto"
                         }
                     }
                 ],
@@ -1532,14 +1539,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/postService/getPostDropdown": {
-            "get": {
+        "/api/logService/deleteOpLog": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "岗位下拉列表",
+                "description": "删除操作日志",
                 "consumes": [
                     "application/json"
                 ],
@@ -1547,9 +1554,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "岗位管理"
+                    "日志管理"
+                ],
+                "summary": "删除操作日志",
+                "parameters": [
+                    {
+                        "description": "删除操作日志请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeleteOpLogDto"
+                        }
+                    }
                 ],
-                "summary": "岗位下拉列表",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1566,14 +1584,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/postService/getPostList": {
+        "/api/logService/getLogWriterStats": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "分页获取岗位列表",
+                "description": "查询登录日志、操作日志异步写入的运行指标，包括已写入、已丢弃、写入失败和等待写入的日志数",
                 "consumes": [
                     "application/json"
                 ],
@@ -1581,9 +1599,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "岗位管理"
+                    "日志管理"
                 ],
-                "summary": "获取岗位列表",
+                "summary": "查询日志写入指标",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/logwriter.Stats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/logService/getLoginLogList": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询登录日志列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "日志管理"
+                ],
+                "summary": "查询登录日志列表",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1593,20 +1657,20 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "每页数量",
+                        "description": "页大小",
                         "name": "pageSize",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "岗位状态：1-\u003e启用,2-\u003e禁用",
-                        "name": "postStatus",
+                        "type": "string",
+                        "description": "用户名",
+                        "name": "username",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "岗位名称",
-                        "name": "postName",
+                        "type": "integer",
+                        "description": "登录状态",
+                        "name": "loginStatus",
                         "in": "query"
                     },
                     {
@@ -1620,6 +1684,30 @@ const docTemplate = `{
                         "description": "结束时间",
                         "name": "endTime",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "国家",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "省份/地区",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "城市",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "运营商",
+                        "name": "isp",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1638,14 +1726,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/postService/updatePost": {
-            "post": {
+        "/api/logService/getOperationLogList": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改岗位信息",
+                "description": "查询操作日志列表",
                 "consumes": [
                     "application/json"
                 ],
@@ -1653,18 +1741,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "岗位管理"
+                    "日志管理"
                 ],
-                "summary": "修改岗位",
+                "summary": "查询操作日志列表",
                 "parameters": [
                     {
-                        "description": "修改岗位请求结构体",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.UpdateSysPostDto"
-                        }
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "pageNum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页大小",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "用户名",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间",
+                        "name": "beginTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间",
+                        "name": "endTime",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1683,14 +1792,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/postService/updatePostStatus": {
+        "/api/login": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "修改岗位状态",
+                "description": "用户登录，需要两步验证时返回挑战令牌，使用挑战令牌和验证码调用 /api/login/twoFactor 完成登录；\n密码已过期时返回 passwordExpired=true，此时令牌只能用于修改密码和退出登录",
                 "consumes": [
                     "application/json"
                 ],
@@ -1698,17 +1802,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "岗位管理"
+                    "无需认证接口"
                 ],
-                "summary": "修改岗位状态",
+                "summary": "用户登录",
                 "parameters": [
                     {
-                        "description": "修改岗位状态请求",
+                        "description": "登录请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdatePostStatusDto"
+                            "$ref": "#/definitions/entity.LoginDto"
                         }
                     }
                 ],
@@ -1728,14 +1832,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/assignRoleMenus": {
+        "/api/login/passkey": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "角色权限分配",
+                "description": "提交 navigator.credentials.get() 返回的凭据完成免密码登录，不需要再进行两步验证",
                 "consumes": [
                     "application/json"
                 ],
@@ -1743,17 +1842,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "无需认证接口"
                 ],
-                "summary": "角色权限分配",
+                "summary": "通行密钥登录",
                 "parameters": [
                     {
-                        "description": "权限菜单列表",
+                        "description": "通行密钥登录请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.AssignRoleMenusDto"
+                            "$ref": "#/definitions/entity.PasskeyLoginDto"
                         }
                     }
                 ],
@@ -1773,14 +1872,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/createRole": {
+        "/api/login/passkeyOptions": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                "description": "返回传给 navigator.credentials.get() 的参数，由认证器选择通行密钥，需要启用免密码登录",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "创建角色",
+                "tags": [
+                    "无需认证接口"
+                ],
+                "summary": "发起通行密钥登录",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.PasskeyOptionsVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/login/twoFactor": {
+            "post": {
+                "description": "使用登录返回的挑战令牌和验证码(或恢复码)完成登录；首次绑定验证器时同时返回恢复码",
                 "consumes": [
                     "application/json"
                 ],
@@ -1788,17 +1920,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "无需认证接口"
                 ],
-                "summary": "创建角色",
+                "summary": "两步验证登录",
                 "parameters": [
                     {
-                        "description": "创建角色请求结构体",
+                        "description": "两步验证登录请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.CreateRoleDto"
+                            "$ref": "#/definitions/entity.TwoFactorLoginDto"
                         }
                     }
                 ],
@@ -1818,14 +1950,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/deleteRole": {
+        "/api/login/twoFactorPasskey": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "删除角色",
+                "description": "使用通行密钥代替验证码完成两步验证",
                 "consumes": [
                     "application/json"
                 ],
@@ -1833,17 +1960,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "无需认证接口"
                 ],
-                "summary": "删除角色",
+                "summary": "通行密钥两步验证登录",
                 "parameters": [
                     {
-                        "description": "删除角色请求",
+                        "description": "通行密钥两步验证登录请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.DeleteRoleDto"
+                            "$ref": "#/definitions/entity.TwoFactorPasskeyLoginDto"
                         }
                     }
                 ],
@@ -1863,14 +1990,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/getRoleById": {
+        "/api/login/twoFactorPasskeyOptions": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "根据id查询角色",
+                "description": "登录返回 passkeyAvailable=true 时，使用挑战令牌获取传给 navigator.credentials.get() 的参数",
                 "consumes": [
                     "application/json"
                 ],
@@ -1878,17 +2000,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "无需认证接口"
                 ],
-                "summary": "根据id查询角色",
+                "summary": "发起通行密钥两步验证",
                 "parameters": [
                     {
-                        "description": "根据id查询角色请求",
+                        "description": "发起通行密钥两步验证请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.GetRoleByIdDto"
+                            "$ref": "#/definitions/entity.TwoFactorPasskeyOptionsDto"
                         }
                     }
                 ],
@@ -1896,7 +2018,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.PasskeyOptionsVo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -1908,14 +2042,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/getRoleDropdown": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "角色下拉列表",
+        "/api/login/twoFactorSetup": {
+            "post": {
+                "description": "所属角色要求两步验证但尚未绑定验证器时，使用挑战令牌获取密钥和二维码",
                 "consumes": [
                     "application/json"
                 ],
@@ -1923,14 +2052,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "无需认证接口"
+                ],
+                "summary": "登录时绑定验证器",
+                "parameters": [
+                    {
+                        "description": "登录时绑定验证器请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.TwoFactorLoginSetupDto"
+                        }
+                    }
                 ],
-                "summary": "角色下拉列表",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.TwoFactorSetupVo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -1942,14 +2094,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/getRoleList": {
-            "get": {
+        "/api/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "查询角色列表",
+                "description": "退出登录，删除当前登录会话，会话下签发的令牌立即失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -1957,52 +2109,117 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "当前用户"
                 ],
-                "summary": "查询角色列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "页码",
-                        "name": "pageNum",
-                        "in": "query"
+                "summary": "退出登录",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/apiTokens": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "页大小",
-                        "name": "pageSize",
-                        "in": "query"
-                    },
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询当前登录用户的个人API令牌，不返回令牌明文",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "查询我的API令牌",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "角色状态",
-                        "name": "roleStatus",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "角色名称",
-                        "name": "roleName",
+                        "description": "页码",
+                        "name": "pageNum",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "开始时间",
-                        "name": "startTime",
+                        "type": "integer",
+                        "description": "页大小",
+                        "name": "pageSize",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.ApiTokenListVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/beginPasskeyRegistration": {
+            "post": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "结束时间",
-                        "name": "endTiem",
-                        "in": "query"
+                        "BearerAuth": []
                     }
                 ],
+                "description": "返回传给 navigator.credentials.create() 的参数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "发起注册通行密钥",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.PasskeyOptionsVo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -2014,14 +2231,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/getRoleMenus": {
+        "/api/me/createApiToken": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "查询角色权限列表",
+                "description": "为当前登录用户创建API令牌，令牌明文只在响应中返回一次，scopes 为空时与本人的权限一致",
                 "consumes": [
                     "application/json"
                 ],
@@ -2029,17 +2246,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "当前用户"
                 ],
-                "summary": "查询角色权限列表",
+                "summary": "创建个人API令牌",
                 "parameters": [
                     {
-                        "description": "查询角色权限",
+                        "description": "创建API令牌请求，adminId 不需要传",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.GetRoleMenusDto"
+                            "$ref": "#/definitions/entity.CreateApiTokenDto"
                         }
                     }
                 ],
@@ -2047,7 +2264,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.ApiTokenCreatedVo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -2059,14 +2288,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/updateRole": {
+        "/api/me/deletePasskey": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改角色",
                 "consumes": [
                     "application/json"
                 ],
@@ -2074,17 +2302,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "当前用户"
                 ],
-                "summary": "修改角色",
+                "summary": "删除通行密钥",
                 "parameters": [
                     {
-                        "description": "修改角色请求结构体",
+                        "description": "删除通行密钥请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdateRoleDto"
+                            "$ref": "#/definitions/entity.DeletePasskeyDto"
                         }
                     }
                 ],
@@ -2104,14 +2332,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/roleService/updateRoleStatus": {
+        "/api/me/disableTwoFactor": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改角色状态",
+                "description": "验证密码和验证码(或恢复码)后关闭两步验证，所属角色要求两步验证时不能关闭",
                 "consumes": [
                     "application/json"
                 ],
@@ -2119,17 +2347,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "当前用户"
                 ],
-                "summary": "修改角色状态",
+                "summary": "关闭两步验证",
                 "parameters": [
                     {
-                        "description": "修改角色状态请求结构体",
+                        "description": "关闭两步验证请求结构体",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdateRoleStatusDto"
+                            "$ref": "#/definitions/entity.DisableTwoFactorDto"
                         }
                     }
                 ],
@@ -2149,14 +2377,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/upload": {
+        "/api/me/enableTwoFactor": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "单图片上传",
+                "description": "验证验证器App中的验证码后开启两步验证，返回只显示一次的恢复码",
                 "consumes": [
                     "application/json"
                 ],
@@ -2164,23 +2392,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "文件上传"
+                    "当前用户"
                 ],
-                "summary": "单图片上传",
+                "summary": "开启两步验证",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "图片",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
+                        "description": "开启两步验证请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.EnableTwoFactorDto"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.RecoveryCodesVo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -2191,61 +2433,3046 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.AssignRoleMenusDto": {
-            "type": "object",
-            "required": [
-                "id",
-                "menuIds"
-            ],
-            "properties": {
-                "id": {
-                    "description": "角色id",
-                    "type": "integer"
-                },
-                "menuIds": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
+        },
+        "/api/me/finishPasskeyRegistration": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "提交 navigator.credentials.create() 返回的凭据和通行密钥名称",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "完成注册通行密钥",
+                "parameters": [
+                    {
+                        "description": "完成注册通行密钥请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.FinishPasskeyRegistrationDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.SysAdminCredential"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "entity.BatchDeleteLoginLogDto": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
+        "/api/me/menus": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询当前登录用户的左侧菜单列表，返回数据附带版本号",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "查询左侧菜单",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.MyMenusVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "entity.BatchDeleteOpLogDto": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
+        "/api/me/passkeys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "查询个人通行密钥",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.SysAdminCredential"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "entity.BatchDeletePostsDto": {
-            "type": "object",
-            "required": [
-                "postIds"
-            ],
-            "properties": {
-                "postIds": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
+        "/api/me/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询当前登录用户的权限列表，返回数据附带版本号",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "查询权限列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.MyPermissionsVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询当前登录用户的个人资料，返回数据附带版本号",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "查询个人资料",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.MyProfileVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/regenerateRecoveryCodes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "验证验证码后重新生成恢复码，原有的恢复码全部作废",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "重新生成恢复码",
+                "parameters": [
+                    {
+                        "description": "重新生成恢复码请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RegenerateRecoveryCodesDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.RecoveryCodesVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/renamePasskey": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "修改通行密钥名称",
+                "parameters": [
+                    {
+                        "description": "修改通行密钥名称请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RenamePasskeyDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/revokeApiToken": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "吊销当前登录用户的个人API令牌",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "吊销我的API令牌",
+                "parameters": [
+                    {
+                        "description": "吊销API令牌请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RevokeApiTokenDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/revokeOtherSessions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "吊销当前用户除当前会话以外的所有登录会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "吊销其他登录会话",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/revokeSession": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "吊销当前用户的某个登录会话，该设备需要重新登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "吊销登录会话",
+                "parameters": [
+                    {
+                        "description": "吊销会话请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RevokeSessionDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询当前用户所有的登录会话(登录设备)，当前会话的current为true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "查询我的登录会话",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.SysSession"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/setupTwoFactor": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "生成TOTP密钥和二维码，使用验证器App扫码后调用开启两步验证接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "当前用户"
+                ],
+                "summary": "绑定验证器",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.TwoFactorSetupVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/menuService/createMenu": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建菜单",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "菜单管理"
+                ],
+                "summary": "创建菜单",
+                "parameters": [
+                    {
+                        "description": "创建菜单请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateMenuDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/menuService/deleteMenu": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据id删除单个菜单",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "菜单管理"
+                ],
+                "summary": "根据id删除单个菜单",
+                "parameters": [
+                    {
+                        "description": "删除菜单请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeleteMenuDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/menuService/getMenuById": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据id查询菜单",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "菜单管理"
+                ],
+                "summary": "根据id查询菜单",
+                "parameters": [
+                    {
+                        "description": "根据id查询菜单请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetMenuByIdDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/menuService/getMenuDropdown": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "菜单下拉列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "菜单管理"
+                ],
+                "summary": "菜单下拉列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/menuService/getMenuList": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询菜单列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "菜单管理"
+                ],
+                "summary": "查询菜单列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "菜单名称",
+                        "name": "menuName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "菜单状态",
+                        "name": "menuStatus",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/menuService/updateMenu": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改菜单信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "菜单管理"
+                ],
+                "summary": "修改菜单信息",
+                "parameters": [
+                    {
+                        "description": "修改菜单请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateSysMenuDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/oidc/authorize": {
+            "get": {
+                "description": "返回身份提供方的授权地址，前端保存 state 后跳转到 authUrl；\n身份提供方重定向回前端回调页面后，比对 state 并调用 /api/oidc/login 完成登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "无需认证接口"
+                ],
+                "summary": "发起单点登录",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.OidcAuthorizeVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/oidc/config": {
+            "get": {
+                "description": "登录页据此决定是否显示单点登录入口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "无需认证接口"
+                ],
+                "summary": "查询单点登录配置",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.OidcConfigVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/oidc/login": {
+            "post": {
+                "description": "使用身份提供方回调携带的 code 和 state 完成登录，响应与 /api/login 相同，需要两步验证时返回挑战令牌",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "无需认证接口"
+                ],
+                "summary": "单点登录",
+                "parameters": [
+                    {
+                        "description": "单点登录请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.OidcLoginDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/password/forgot": {
+            "post": {
+                "description": "按用户名或邮箱申请重置密码，账号存在且可以重置时向其邮箱发送一次性的重置链接；无论账号是否存在都返回成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "无需认证接口"
+                ],
+                "summary": "找回密码",
+                "parameters": [
+                    {
+                        "description": "找回密码请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ForgotPasswordDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/password/reset": {
+            "post": {
+                "description": "使用重置邮件中的令牌设置新密码，新密码需符合密码策略；成功后链接失效，已登录的会话全部退出",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "无需认证接口"
+                ],
+                "summary": "重置密码",
+                "parameters": [
+                    {
+                        "description": "重置密码请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PasswordResetDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postService/batchDeletePosts": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "批量删除岗位",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "岗位管理"
+                ],
+                "summary": "批量删除岗位",
+                "parameters": [
+                    {
+                        "description": "要删除的岗位id列表",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BatchDeletePostsDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postService/createPost": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建岗位",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "岗位管理"
+                ],
+                "summary": "创建岗位",
+                "parameters": [
+                    {
+                        "description": "创建岗位的请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateSysPostDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postService/deletePost": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除单个岗位",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "岗位管理"
+                ],
+                "summary": "删除单个岗位",
+                "parameters": [
+                    {
+                        "description": "删除单个岗位请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeletePostByIdDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postService/getPostById": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据id查询岗位信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "岗位管理"
+                ],
+                "summary": "根据id查询岗位信息",
+                "parameters": [
+                    {
+                        "description": "获取岗位信息请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetPostByIdDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postService/getPostDropdown": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "岗位下拉列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "岗位管理"
+                ],
+                "summary": "岗位下拉列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postService/getPostList": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页获取岗位列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "岗位管理"
+                ],
+                "summary": "获取岗位列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "pageNum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "岗位状态：1-\u003e启用,2-\u003e禁用",
+                        "name": "postStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "岗位名称",
+                        "name": "postName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间",
+                        "name": "beginTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间",
+                        "name": "endTime",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postService/updatePost": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改岗位信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "岗位管理"
+                ],
+                "summary": "修改岗位",
+                "parameters": [
+                    {
+                        "description": "修改岗位请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateSysPostDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postService/updatePostStatus": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改岗位状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "岗位管理"
+                ],
+                "summary": "修改岗位状态",
+                "parameters": [
+                    {
+                        "description": "修改岗位状态请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdatePostStatusDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/addRoleAdmins": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "批量为角色添加用户，可设置授权有效期用于临时授权，已拥有该角色的用户更新有效期和原因",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "批量为角色添加用户",
+                "parameters": [
+                    {
+                        "description": "添加角色用户请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AddRoleAdminsDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/assignRoleDataScope": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "设置角色的数据权限范围：1-\u003e全部数据,2-\u003e自定义部门,3-\u003e本部门,4-\u003e本部门及以下,5-\u003e仅本人",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "分配角色数据权限",
+                "parameters": [
+                    {
+                        "description": "分配角色数据权限",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AssignRoleDataScopeDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/assignRoleMenus": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "角色权限分配",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "角色权限分配",
+                "parameters": [
+                    {
+                        "description": "权限菜单列表",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AssignRoleMenusDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/createRole": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "创建角色",
+                "parameters": [
+                    {
+                        "description": "创建角色请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateRoleDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/deleteRole": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "删除角色",
+                "parameters": [
+                    {
+                        "description": "删除角色请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeleteRoleDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/getRoleAdminList": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询拥有该角色的用户列表，只返回数据权限范围内的用户",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "查询拥有角色的用户列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色id",
+                        "name": "roleId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "pageNum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页大小",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "用户名",
+                        "name": "username",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.RoleAdminListVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/getRoleById": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据id查询角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "根据id查询角色",
+                "parameters": [
+                    {
+                        "description": "根据id查询角色请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetRoleByIdDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/getRoleDataScope": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询角色的数据权限范围，以及自定义数据权限时可访问的部门",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "查询角色数据权限",
+                "parameters": [
+                    {
+                        "description": "查询角色数据权限",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetRoleDataScopeDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.RoleDataScopeVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/getRoleDropdown": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "角色下拉列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "角色下拉列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/getRoleList": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询角色列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "查询角色列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "pageNum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页大小",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "角色状态",
+                        "name": "roleStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "角色名称",
+                        "name": "roleName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间",
+                        "name": "startTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间",
+                        "name": "endTiem",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/getRoleMenus": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询角色权限列表，分别返回直接分配的权限和从父角色继承的权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "查询角色权限列表",
+                "parameters": [
+                    {
+                        "description": "查询角色权限",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetRoleMenusDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.RoleMenusVo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/removeRoleAdmins": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "批量从角色中移除用户",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "批量从角色中移除用户",
+                "parameters": [
+                    {
+                        "description": "角色用户请求",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RoleAdminsDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/updateRole": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "修改角色",
+                "parameters": [
+                    {
+                        "description": "修改角色请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateRoleDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roleService/updateRoleStatus": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改角色状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "修改角色状态",
+                "parameters": [
+                    {
+                        "description": "修改角色状态请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateRoleStatusDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/token/refresh": {
+            "post": {
+                "description": "使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌随即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "无需认证接口"
+                ],
+                "summary": "刷新令牌",
+                "parameters": [
+                    {
+                        "description": "刷新令牌请求结构体",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RefreshTokenDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/jwt.TokenPair"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/upload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "单图片上传",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "文件上传"
+                ],
+                "summary": "单图片上传",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "图片",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Groups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持 filter、startIndex、count 分页和排除 members 属性",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "查询SCIM组列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "过滤条件，如 displayName eq \\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "起始位置，从1开始",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排除的属性",
+                        "name": "excludedAttributes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/entity.ScimListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "Resources": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.ScimGroup"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建角色，菜单权限需要在角色管理中分配",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "创建SCIM组",
+                "parameters": [
+                    {
+                        "description": "组",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimGroup"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimGroup"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Groups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "查询SCIM组",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "角色id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimGroup"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "替换SCIM组",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "角色id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "组",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimGroup"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "删除SCIM组",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "角色id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持修改名称和增减成员",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "修改SCIM组",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "角色id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "修改操作",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/ResourceTypes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "查询SCIM资源类型",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/ServiceProviderConfig": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "查询SCIM服务提供方配置",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持 filter、startIndex、count 分页和排除 groups 属性",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "查询SCIM用户列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "过滤条件，如 userName eq \\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "起始位置，从1开始",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排除的属性",
+                        "name": "excludedAttributes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/entity.ScimListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "Resources": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.ScimUser"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "使用配置的默认部门和岗位创建用户，密码随机生成",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "创建SCIM用户",
+                "parameters": [
+                    {
+                        "description": "用户",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimUser"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "查询SCIM用户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimUser"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "active 为 false 时停用用户并吊销会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "替换SCIM用户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "用户",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "删除SCIM用户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持 add、replace、remove 操作，active 为 false 时停用用户并吊销会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "修改SCIM用户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "修改操作",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScimUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.AcceptInvitationDto": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "rePassword",
+                "token"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string"
+                },
+                "rePassword": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AddRoleAdminsDto": {
+            "type": "object",
+            "required": [
+                "adminIds",
+                "id"
+            ],
+            "properties": {
+                "adminIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "description": "角色id",
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "授权原因",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "validFrom": {
+                    "description": "生效时间，为空时立即生效",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/utils.HTime"
+                        }
+                    ]
+                },
+                "validUntil": {
+                    "description": "失效时间，为空时长期有效",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/utils.HTime"
+                        }
+                    ]
+                }
+            }
+        },
+        "entity.AdminInvitationDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "待激活用户的id",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ApiTokenCreatedVo": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "$ref": "#/definitions/utils.HTime"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "token": {
+                    "description": "令牌明文，请求时放在 X-API-Key 请求头或 Authorization: Bearer 中",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ApiTokenListVo": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ApiTokenVo"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/response.PaginationMeta"
+                }
+            }
+        },
+        "entity.ApiTokenVo": {
+            "type": "object",
+            "properties": {
+                "adminId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "$ref": "#/definitions/utils.HTime"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "expired": {
+                    "description": "是否已过期",
+                    "type": "boolean"
+                },
+                "expiresAt": {
+                    "$ref": "#/definitions/utils.HTime"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "$ref": "#/definitions/utils.HTime"
+                },
+                "lastUsedIp": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "权限范围",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tokenPrefix": {
+                    "type": "string"
+                },
+                "username": {
+                    "description": "所属用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AssignRoleDataScopeDto": {
+            "type": "object",
+            "required": [
+                "dataScope",
+                "id"
+            ],
+            "properties": {
+                "dataScope": {
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5
+                    ]
+                },
+                "deptIds": {
+                    "description": "数据权限为自定义部门时可访问的部门",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.AssignRoleMenusDto": {
+            "type": "object",
+            "required": [
+                "id",
+                "menuIds"
+            ],
+            "properties": {
+                "id": {
+                    "description": "角色id",
+                    "type": "integer"
+                },
+                "menuIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entity.BatchDeleteLoginLogDto": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entity.BatchDeleteOpLogDto": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entity.BatchDeletePostsDto": {
+            "type": "object",
+            "required": [
+                "postIds"
+            ],
+            "properties": {
+                "postIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
                     }
                 }
             }
@@ -2253,330 +5480,1461 @@ const docTemplate = `{
         "entity.CreateAdminDto": {
             "type": "object",
             "required": [
-                "deptID",
-                "email",
-                "nickname",
-                "password",
-                "phone",
-                "postID",
-                "roleID",
-                "staus",
-                "username"
+                "deptID",
+                "email",
+                "nickname",
+                "phone",
+                "postID",
+                "roleIds",
+                "staus",
+                "username"
+            ],
+            "properties": {
+                "deptID": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "服务账号不需要密码",
+                    "type": "string"
+                },
+                "passwordLoginDisabled": {
+                    "description": "禁用密码登录，只能使用单点登录",
+                    "type": "boolean"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "postID": {
+                    "type": "integer"
+                },
+                "roleIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "serviceAccount": {
+                    "description": "是否为服务账号，服务账号不能登录，只能使用API令牌",
+                    "type": "boolean"
+                },
+                "staus": {
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CreateApiTokenDto": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "adminId": {
+                    "description": "令牌所属的服务账号id，个人令牌不需要传",
+                    "type": "integer"
+                },
+                "expiresAt": {
+                    "description": "过期时间，为空时长期有效",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/utils.HTime"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "令牌名称",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "scopes": {
+                    "description": "权限范围，按钮权限值列表，为空时与所属用户的权限一致",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.CreateDeptDto": {
+            "type": "object",
+            "required": [
+                "deptName",
+                "deptType"
+            ],
+            "properties": {
+                "deptName": {
+                    "type": "string"
+                },
+                "deptType": {
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "parentID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.CreateMenuDto": {
+            "type": "object",
+            "required": [
+                "menuIcon",
+                "menuName",
+                "menuType"
+            ],
+            "properties": {
+                "menuIcon": {
+                    "type": "string"
+                },
+                "menuName": {
+                    "type": "string"
+                },
+                "menuType": {
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "menyStatus": {
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2
+                    ]
+                },
+                "parentID": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CreateRoleDto": {
+            "type": "object",
+            "required": [
+                "roleKey",
+                "roleName"
+            ],
+            "properties": {
+                "dataScope": {
+                    "description": "数据权限，默认全部数据，自定义部门通过分配数据权限设置",
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "父角色id，为0时不继承",
+                    "type": "integer"
+                },
+                "requireTwoFactor": {
+                    "description": "是否要求拥有该角色的用户开启两步验证",
+                    "type": "boolean"
+                },
+                "roleKey": {
+                    "type": "string"
+                },
+                "roleName": {
+                    "type": "string"
+                },
+                "roleStatus": {
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "entity.CreateSysPostDto": {
+            "type": "object",
+            "required": [
+                "postCode",
+                "postName"
+            ],
+            "properties": {
+                "postCode": {
+                    "type": "string"
+                },
+                "postName": {
+                    "type": "string"
+                },
+                "psotStatus": {
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2
+                    ]
+                },
+                "remark": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DeleteAdminDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DeleteDeptDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DeleteLoginLogDto": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DeleteMenuDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DeleteOpLogDto": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DeletePasskeyDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DeletePostByIdDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DeleteRoleDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DisableTwoFactorDto": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.EnableTwoFactorDto": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.FinishPasskeyRegistrationDto": {
+            "type": "object",
+            "required": [
+                "credential",
+                "name",
+                "sessionId"
+            ],
+            "properties": {
+                "credential": {
+                    "description": "navigator.credentials.create() 返回的凭据",
+                    "type": "object"
+                },
+                "name": {
+                    "description": "通行密钥名称，便于区分不同设备",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "sessionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.FirstLevelMenuVo": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SecondLevelMenuVo"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "menuIcon": {
+                    "type": "string"
+                },
+                "menuName": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ForceLogoutDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ForgotPasswordDto": {
+            "type": "object",
+            "required": [
+                "account",
+                "captchaId",
+                "captchaImage"
+            ],
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "captchaId": {
+                    "type": "string"
+                },
+                "captchaImage": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.GetAdminByIdDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.GetDeptByIdDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.GetMenuByIdDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.GetPostByIdDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.GetRoleByIdDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.GetRoleDataScopeDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.GetRoleMenusDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ImpersonateDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "被模拟的用户id",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.InvitationInfoVo": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "$ref": "#/definitions/utils.HTime"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.InvitationTokenDto": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.InviteAdminDto": {
+            "type": "object",
+            "required": [
+                "deptID",
+                "email",
+                "nickname",
+                "postID",
+                "roleIds",
+                "username"
+            ],
+            "properties": {
+                "deptID": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 11
+                },
+                "postID": {
+                    "type": "integer"
+                },
+                "roleIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.LdapSyncDto": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "description": "只生成同步报告，不修改数据",
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.LdapSyncItem": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "创建或更新的内容",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dn": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "跳过、停用或失败的原因",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.LdapSyncReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "创建的用户",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LdapSyncItem"
+                    }
+                },
+                "disabled": {
+                    "description": "停用的用户",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LdapSyncItem"
+                    }
+                },
+                "dryRun": {
+                    "description": "是否为试运行",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "同步失败的用户",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LdapSyncItem"
+                    }
+                },
+                "finishedAt": {
+                    "description": "结束时间",
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "跳过的用户",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LdapSyncItem"
+                    }
+                },
+                "startedAt": {
+                    "description": "开始时间",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "目录中的用户数",
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "更新的用户",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LdapSyncItem"
+                    }
+                },
+                "warnings": {
+                    "description": "警告",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.LoginDto": {
+            "type": "object",
+            "required": [
+                "captchaId",
+                "captchaImage",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "captchaId": {
+                    "type": "string"
+                },
+                "captchaImage": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MyMenusVo": {
+            "type": "object",
+            "properties": {
+                "menus": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FirstLevelMenuVo"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MyPermissionsVo": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PermissionListVo"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MyProfileVo": {
+            "type": "object",
+            "properties": {
+                "profile": {
+                    "$ref": "#/definitions/entity.ProfileVo"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OidcAuthorizeVo": {
+            "type": "object",
+            "properties": {
+                "authUrl": {
+                    "description": "跳转到身份提供方的授权地址",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "需要在该时间前完成登录",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "前端保存后与回调参数比对，防止登录CSRF",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OidcConfigVo": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "是否启用单点登录",
+                    "type": "boolean"
+                },
+                "providerName": {
+                    "description": "身份提供方名称",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OidcLoginDto": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PasskeyLoginDto": {
+            "type": "object",
+            "required": [
+                "credential",
+                "sessionId"
+            ],
+            "properties": {
+                "credential": {
+                    "description": "navigator.credentials.get() 返回的凭据",
+                    "type": "object"
+                },
+                "sessionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PasskeyOptionsVo": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "description": "需要在该时间前完成",
+                    "type": "string"
+                },
+                "options": {
+                    "description": "传给 navigator.credentials.create() 或 get() 的参数",
+                    "type": "object"
+                },
+                "sessionId": {
+                    "description": "会话id，完成时原样提交",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PasswordResetDto": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "rePassword",
+                "token"
             ],
             "properties": {
-                "deptID": {
+                "newPassword": {
+                    "type": "string"
+                },
+                "rePassword": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PermissionListVo": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "description": "权限",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ProfileVo": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "创建时间",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/utils.HTime"
+                        }
+                    ]
+                },
+                "deptId": {
+                    "description": "部门id",
                     "type": "integer"
                 },
+                "deptName": {
+                    "description": "部门名称",
+                    "type": "string"
+                },
                 "email": {
+                    "description": "邮箱",
                     "type": "string"
                 },
-                "nickname": {
+                "icon": {
+                    "description": "头像",
                     "type": "string"
                 },
-                "note": {
+                "id": {
+                    "description": "ID",
+                    "type": "integer"
+                },
+                "nickname": {
+                    "description": "昵称",
                     "type": "string"
                 },
-                "password": {
+                "note": {
+                    "description": "备注",
                     "type": "string"
                 },
                 "phone": {
+                    "description": "电话",
                     "type": "string"
                 },
-                "postID": {
+                "postId": {
+                    "description": "岗位id",
                     "type": "integer"
                 },
-                "roleID": {
-                    "type": "integer"
+                "postName": {
+                    "description": "岗位名称",
+                    "type": "string"
                 },
-                "staus": {
-                    "type": "integer",
-                    "enum": [
-                        1,
-                        2
-                    ]
+                "roleNames": {
+                    "description": "角色名称列表",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "totpEnabled": {
+                    "description": "是否开启两步验证",
+                    "type": "boolean"
                 },
                 "username": {
+                    "description": "用户名",
                     "type": "string"
                 }
             }
         },
-        "entity.CreateDeptDto": {
+        "entity.RecoveryCodesVo": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.RefreshTokenDto": {
             "type": "object",
             "required": [
-                "deptName",
-                "deptType"
+                "refreshToken"
             ],
             "properties": {
-                "deptName": {
+                "refreshToken": {
                     "type": "string"
-                },
-                "deptType": {
-                    "type": "integer",
-                    "enum": [
-                        1,
-                        2,
-                        3
-                    ]
-                },
-                "parentID": {
-                    "type": "integer"
                 }
             }
         },
-        "entity.CreateMenuDto": {
+        "entity.RegenerateRecoveryCodesDto": {
             "type": "object",
             "required": [
-                "menuIcon",
-                "menuName",
-                "menuType"
+                "code"
             ],
             "properties": {
-                "menuIcon": {
-                    "type": "string"
-                },
-                "menuName": {
+                "code": {
                     "type": "string"
-                },
-                "menuType": {
-                    "type": "integer",
-                    "enum": [
-                        1,
-                        2,
-                        3
-                    ]
-                },
-                "menyStatus": {
-                    "type": "integer",
-                    "enum": [
-                        1,
-                        2
-                    ]
-                },
-                "parentID": {
+                }
+            }
+        },
+        "entity.RenamePasskeyDto": {
+            "type": "object",
+            "required": [
+                "id",
+                "name"
+            ],
+            "properties": {
+                "id": {
                     "type": "integer"
                 },
-                "sort": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "entity.ResetPasswordDto": {
+            "type": "object",
+            "required": [
+                "id",
+                "newPassword"
+            ],
+            "properties": {
+                "id": {
                     "type": "integer"
                 },
-                "url": {
-                    "type": "string"
-                },
-                "value": {
+                "newPassword": {
                     "type": "string"
                 }
             }
         },
-        "entity.CreateRoleDto": {
+        "entity.ResetTwoFactorDto": {
             "type": "object",
             "required": [
-                "roleKey",
-                "roleName"
+                "id"
             ],
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "roleKey": {
-                    "type": "string"
-                },
-                "roleName": {
-                    "type": "string"
-                },
-                "roleStatus": {
-                    "type": "integer",
-                    "enum": [
-                        1,
-                        2
-                    ]
+                "id": {
+                    "type": "integer"
                 }
             }
         },
-        "entity.CreateSysPostDto": {
+        "entity.RevokeApiTokenDto": {
             "type": "object",
             "required": [
-                "postCode",
-                "postName"
+                "id"
             ],
             "properties": {
-                "postCode": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.RevokeSessionDto": {
+            "type": "object",
+            "required": [
+                "sessionId"
+            ],
+            "properties": {
+                "sessionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.RoleAdminListVo": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RoleAdminVo"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/response.PaginationMeta"
+                }
+            }
+        },
+        "entity.RoleAdminVo": {
+            "type": "object",
+            "properties": {
+                "deptName": {
+                    "description": "部门名称",
                     "type": "string"
                 },
-                "postName": {
+                "id": {
+                    "description": "用户id",
+                    "type": "integer"
+                },
+                "nickname": {
+                    "description": "昵称",
                     "type": "string"
                 },
-                "psotStatus": {
-                    "type": "integer",
-                    "enum": [
-                        1,
-                        2
-                    ]
+                "reason": {
+                    "description": "授权原因",
+                    "type": "string"
                 },
-                "remark": {
+                "status": {
+                    "description": "状态：1-\u003e启用,2-\u003e禁用",
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "用户名",
                     "type": "string"
+                },
+                "validFrom": {
+                    "description": "授权生效时间",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/utils.HTime"
+                        }
+                    ]
+                },
+                "validUntil": {
+                    "description": "授权失效时间",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/utils.HTime"
+                        }
+                    ]
                 }
             }
         },
-        "entity.DeleteAdminDto": {
+        "entity.RoleAdminsDto": {
             "type": "object",
             "required": [
+                "adminIds",
                 "id"
             ],
             "properties": {
+                "adminIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "id": {
+                    "description": "角色id",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.RoleDataScopeVo": {
+            "type": "object",
+            "properties": {
+                "dataScope": {
+                    "description": "数据权限",
                     "type": "integer"
+                },
+                "deptIds": {
+                    "description": "自定义部门",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entity.RoleMenusVo": {
+            "type": "object",
+            "properties": {
+                "inheritedMenuIds": {
+                    "description": "从父角色继承的权限",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "menuIds": {
+                    "description": "直接分配的权限",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "entity.DeleteDeptDto": {
+        "entity.ScimGroup": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "externalId": {
+                    "type": "string"
+                },
                 "id": {
-                    "type": "integer"
+                    "type": "string"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ScimMultiValue"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/entity.ScimMeta"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "entity.DeleteLoginLogDto": {
+        "entity.ScimListResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "Resources": {},
+                "itemsPerPage": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startIndex": {
+                    "type": "integer"
+                },
+                "totalResults": {
                     "type": "integer"
                 }
             }
         },
-        "entity.DeleteMenuDto": {
+        "entity.ScimMeta": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
-                "id": {
-                    "type": "integer"
+                "created": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "type": "string"
                 }
             }
         },
-        "entity.DeleteOpLogDto": {
+        "entity.ScimMultiValue": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "$ref": {
+                    "type": "string"
+                },
+                "display": {
+                    "type": "string"
+                },
+                "primary": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
-        "entity.DeletePostByIdDto": {
+        "entity.ScimName": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
-                "id": {
-                    "type": "integer"
+                "familyName": {
+                    "type": "string"
+                },
+                "formatted": {
+                    "type": "string"
+                },
+                "givenName": {
+                    "type": "string"
                 }
             }
         },
-        "entity.DeleteRoleDto": {
+        "entity.ScimPatchRequest": {
+            "type": "object"
+        },
+        "entity.ScimUser": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
+                "active": {
+                    "description": "请求中未设置时为启用",
+                    "type": "boolean"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ScimMultiValue"
+                    }
+                },
+                "externalId": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ScimMultiValue"
+                    }
+                },
                 "id": {
-                    "type": "integer"
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/entity.ScimMeta"
+                },
+                "name": {
+                    "$ref": "#/definitions/entity.ScimName"
+                },
+                "phoneNumbers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ScimMultiValue"
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userName": {
+                    "type": "string"
                 }
             }
         },
-        "entity.GetAdminByIdDto": {
+        "entity.SecondLevelMenuVo": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
-                "id": {
-                    "type": "integer"
+                "menuIcon": {
+                    "type": "string"
+                },
+                "menuName": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "entity.GetDeptByIdDto": {
+        "entity.SysAdminCredential": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
+                "aaguid": {
+                    "type": "string"
+                },
+                "adminId": {
+                    "type": "integer"
+                },
+                "backupEligible": {
+                    "type": "boolean"
+                },
+                "backupState": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "$ref": "#/definitions/utils.HTime"
+                },
                 "id": {
                     "type": "integer"
+                },
+                "lastUsedAt": {
+                    "$ref": "#/definitions/utils.HTime"
+                },
+                "lastUsedIp": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "transports": {
+                    "type": "string"
                 }
             }
         },
-        "entity.GetMenuByIdDto": {
+        "entity.SysSession": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
-                "id": {
+                "adminId": {
+                    "description": "用户id",
+                    "type": "integer"
+                },
+                "browser": {
+                    "description": "浏览器",
+                    "type": "string"
+                },
+                "current": {
+                    "description": "是否为当前请求所在的会话",
+                    "type": "boolean"
+                },
+                "device": {
+                    "description": "设备",
+                    "type": "string"
+                },
+                "impersonatorId": {
+                    "description": "模拟登录会话的发起人id",
                     "type": "integer"
+                },
+                "impersonatorName": {
+                    "description": "模拟登录会话的发起人用户名",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "登录ip",
+                    "type": "string"
+                },
+                "lastActiveAt": {
+                    "description": "最近一次刷新令牌的时间",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/utils.HTime"
+                        }
+                    ]
+                },
+                "location": {
+                    "description": "登录地点",
+                    "type": "string"
+                },
+                "loginAt": {
+                    "description": "登录时间",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/utils.HTime"
+                        }
+                    ]
+                },
+                "loginMethod": {
+                    "description": "登录方式：password、oidc、ldap、passkey，为空时为密码登录",
+                    "type": "string"
+                },
+                "os": {
+                    "description": "操作系统",
+                    "type": "string"
+                },
+                "sessionId": {
+                    "description": "会话id",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "用户名",
+                    "type": "string"
                 }
             }
         },
-        "entity.GetPostByIdDto": {
+        "entity.TwoFactorLoginDto": {
             "type": "object",
             "required": [
-                "id"
+                "challengeToken",
+                "code"
             ],
             "properties": {
-                "id": {
-                    "type": "integer"
+                "challengeToken": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
                 }
             }
         },
-        "entity.GetRoleByIdDto": {
+        "entity.TwoFactorLoginSetupDto": {
             "type": "object",
             "required": [
-                "id"
+                "challengeToken"
             ],
             "properties": {
-                "id": {
-                    "type": "integer"
+                "challengeToken": {
+                    "type": "string"
                 }
             }
         },
-        "entity.GetRoleMenusDto": {
+        "entity.TwoFactorPasskeyLoginDto": {
             "type": "object",
             "required": [
-                "id"
+                "challengeToken",
+                "credential",
+                "sessionId"
             ],
             "properties": {
-                "id": {
-                    "type": "integer"
+                "challengeToken": {
+                    "type": "string"
+                },
+                "credential": {
+                    "description": "navigator.credentials.get() 返回的凭据",
+                    "type": "object"
+                },
+                "sessionId": {
+                    "type": "string"
                 }
             }
         },
-        "entity.LoginDto": {
+        "entity.TwoFactorPasskeyOptionsDto": {
             "type": "object",
             "required": [
-                "captchaId",
-                "captchaImage",
-                "password",
-                "username"
+                "challengeToken"
             ],
             "properties": {
-                "captchaId": {
+                "challengeToken": {
                     "type": "string"
-                },
-                "captchaImage": {
+                }
+            }
+        },
+        "entity.TwoFactorSetupVo": {
+            "type": "object",
+            "properties": {
+                "otpauthUrl": {
+                    "description": "otpauth:// 地址",
                     "type": "string"
                 },
-                "password": {
+                "qrCode": {
+                    "description": "base64 编码的二维码图片",
                     "type": "string"
                 },
-                "username": {
+                "secret": {
+                    "description": "base32 编码的密钥，无法扫码时手动输入",
                     "type": "string"
                 }
             }
         },
-        "entity.ResetPasswordDto": {
+        "entity.UnlockAdminDto": {
             "type": "object",
             "required": [
                 "id"
@@ -2584,9 +6942,6 @@ const docTemplate = `{
             "properties": {
                 "id": {
                     "type": "integer"
-                },
-                "newPassword": {
-                    "type": "string"
                 }
             }
         },
@@ -2611,14 +6966,22 @@ const docTemplate = `{
                 "note": {
                     "type": "string"
                 },
+                "passwordLoginDisabled": {
+                    "description": "是否禁用密码登录，只能使用单点登录",
+                    "type": "boolean"
+                },
                 "phone": {
                     "type": "string"
                 },
                 "postId": {
                     "type": "integer"
                 },
-                "roleId": {
-                    "type": "integer"
+                "roleIds": {
+                    "description": "为null时不修改角色，为空数组时移除全部角色",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
                 "status": {
                     "type": "integer",
@@ -2722,6 +7085,13 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "parentId": {
+                    "description": "为0时取消继承",
+                    "type": "integer"
+                },
+                "requireTwoFactor": {
+                    "type": "boolean"
+                },
                 "roleKey": {
                     "type": "string"
                 },
@@ -2858,6 +7228,69 @@ const docTemplate = `{
                 }
             }
         },
+        "jwt.TokenPair": {
+            "type": "object",
+            "properties": {
+                "accessExpiresAt": {
+                    "type": "string"
+                },
+                "accessToken": {
+                    "type": "string"
+                },
+                "refreshExpiresAt": {
+                    "type": "string"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "令牌权限范围，为空表示不受限",
+                    "type": "string"
+                }
+            }
+        },
+        "logwriter.Stats": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "description": "队列已满或已关闭时丢弃的日志数",
+                    "type": "integer"
+                },
+                "enqueued": {
+                    "description": "成功进入队列的日志数",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "写入数据库失败的日志数",
+                    "type": "integer"
+                },
+                "pending": {
+                    "description": "队列中等待写入的日志数",
+                    "type": "integer"
+                },
+                "written": {
+                    "description": "成功写入数据库的日志数",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.PaginationMeta": {
+            "type": "object",
+            "properties": {
+                "pageNum": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
         "response.Response": {
             "type": "object",
             "properties": {
@@ -2869,6 +7302,14 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "utils.HTime": {
+            "type": "object",
+            "properties": {
+                "time.Time": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
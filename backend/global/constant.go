@@ -1,6 +1,41 @@
 package global
 
 const (
-	LoggedUser  = "loginUser"     // 当前登录用户的信息
-	CaptchaPrex = "captcha_code:" // redis存储验证码的前缀
+	LoggedUser   = "loginUser"     // 当前登录用户的信息
+	LoggedClaims = "loginClaims"   // 当前登录用户的令牌声明
+	CaptchaPrex  = "captcha_code:" // redis存储验证码的前缀
+
+	LoggedApiToken = "loginApiToken" // 当前请求使用的API令牌，使用登录令牌时不存在
+
+	LogModule       = "logModule"       // 操作日志的业务模块名称
+	LogAction       = "logAction"       // 操作日志的业务操作名称
+	ResponseCode    = "responseCode"    // 响应的业务状态码
+	ResponseMessage = "responseMessage" // 响应的业务消息
+
+	TokenBlacklistPrefix = "token_blacklist:"  // redis存储已吊销令牌jti的前缀
+	TokenVersionPrefix   = "token_version:"    // redis存储用户令牌版本号的前缀
+	SessionPrefix        = "session:"          // redis存储登录会话的前缀
+	AdminSessionsPrefix  = "admin_sessions:"   // redis存储用户所有会话id集合的前缀
+	LoginFailPrefix      = "login_fail:"       // redis存储登录失败次数的前缀
+	LoginLockPrefix      = "login_lock:"       // redis存储登录锁定截止时间的前缀
+	LoginLockLevelPrefix = "login_lock_level:" // redis存储登录锁定次数的前缀，用于计算指数退避的锁定时长
+	LoginChallengePrefix = "login_challenge:"  // redis存储两步验证挑战的前缀
+	TotpUsedPrefix       = "totp_used:"        // redis存储已使用的TOTP时间步的前缀，防止验证码重放
+	SweeperLockPrefix    = "sweeper_lock:"     // redis存储后台定时任务执行锁的前缀
+
+	PermCacheAdminPrefix     = "perm_cache:admin:"       // redis存储用户权限缓存的前缀
+	PermCacheRoleAdminPrefix = "perm_cache:role_admins:" // redis存储缓存了某角色权限的用户id集合的前缀
+	PermCacheVersionPrefix   = "perm_cache:version:"     // redis存储用户权限缓存失效版本的前缀
+	PermCacheChannel         = "perm_cache:invalidate"   // redis发布权限缓存失效通知的频道
+
+	OidcStatePrefix = "oidc_state:"    // redis存储单点登录授权请求的前缀
+	LdapSyncLock    = "ldap_sync_lock" // redis存储目录同步执行锁的键，防止定时同步与手动同步同时执行
+
+	PasswordResetPrefix         = "password_reset:"          // redis存储密码重置令牌摘要的前缀
+	PasswordResetAdminPrefix    = "password_reset_admin:"    // redis存储用户当前有效的密码重置令牌摘要的前缀，重新申请时旧链接失效
+	PasswordResetCooldownPrefix = "password_reset_cooldown:" // redis存储重置邮件发送冷却的前缀
+
+	PasskeySessionPrefix = "passkey_session:" // redis存储通行密钥注册和验证会话的前缀
+
+	SuperRoleKey = "admin" // 超级管理员角色关键字，拥有全部权限
 )
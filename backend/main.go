@@ -1,12 +1,22 @@
 package main
 
 import (
+	"fmt"
 	"go-admin-server/common/config"
 	"go-admin-server/common/flag"
 	"go-admin-server/core"
 	_ "go-admin-server/docs"
 	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"go-admin-server/pkg/geoip"
+	"go-admin-server/pkg/jwt"
+	"go-admin-server/pkg/ldap"
+	"go-admin-server/pkg/mailer"
+	"go-admin-server/pkg/oidc"
+	"go-admin-server/pkg/passkey"
 	"go-admin-server/pkg/validator"
+
+	"go.uber.org/zap"
 )
 
 // @title go-admin 后台管理系统
@@ -25,7 +35,35 @@ func main() {
 	global.DB = core.InitDB()         // MySQL
 	global.RDB = core.InitRDB()       // Redis
 
-	flag.InitFlag()            // 注册命令行工具cli
-	validator.SetupValidator() // 验证器 Validator
+	// 密码策略，命令行创建账号时也需要使用
+	validator.SetupPasswordPolicy(global.Config.PasswordPolicy)
+	// LDAP，命令行同步目录时也需要使用
+	if err := ldap.Setup(global.Config.Ldap); err != nil {
+		panic(fmt.Errorf("failed to setup ldap: %w", err))
+	}
+
+	flag.InitFlag()              // 注册命令行工具cli
+	validator.SetupValidator()   // 验证器 Validator
+	jwt.Setup(global.Config.Jwt) // JWT 签名密钥
+	// 数据加密密钥
+	if err := encrypt.SetupSecretKey(global.Config.TwoFactor.SecretKey); err != nil {
+		panic(fmt.Errorf("failed to setup two factor secret key: %w", err))
+	}
+	// 单点登录，身份提供方的元数据在首次登录时获取
+	if err := oidc.Setup(global.Config.Oidc); err != nil {
+		panic(fmt.Errorf("failed to setup oidc: %w", err))
+	}
+	// 通行密钥
+	if err := passkey.Setup(global.Config.WebAuthn); err != nil {
+		panic(fmt.Errorf("failed to setup webauthn: %w", err))
+	}
+	// 邮件发送，用于找回密码等通知
+	if err := mailer.Setup(global.Config.Mail); err != nil {
+		panic(fmt.Errorf("failed to setup mailer: %w", err))
+	}
+	// IP地理位置查询，数据库不可用时不影响启动，只是不再记录登录地点
+	if err := geoip.Setup(global.Config.GeoIP); err != nil {
+		global.Logger.Warn("Failed to setup geoip, login locations will not be resolved", zap.Error(err))
+	}
 	core.RunServer()
 }
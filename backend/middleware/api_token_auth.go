@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"go-admin-server/api/dao"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+
+	apiTokenTouchInterval = time.Minute // 最近使用时间的更新间隔，避免每次请求都写数据库
+)
+
+var (
+	apiTokenDao = dao.ApiTokenDao{}
+	adminDao    = dao.SysAdminDao{}
+)
+
+// 使用API令牌认证：令牌存在、未过期，且所属用户未被停用。认证通过后以所属用户的身份访问接口，
+// 令牌设置了权限范围时由 Permission 中间件进一步限制，并且不能访问没有声明按钮权限的接口(个人中心、上传等)
+func apiTokenAuth(c *gin.Context, token string) {
+	apiToken, err := apiTokenDao.GetApiTokenByHash(encrypt.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, response.ErrApiTokenInvalid)
+		} else {
+			global.Logger.Error("Failed to query api token", zap.Error(err))
+			response.Error(c, response.ErrServerError)
+		}
+		c.Abort()
+		return
+	}
+	now := time.Now()
+	if apiToken.ExpiresAt != nil && !apiToken.ExpiresAt.After(now) {
+		response.Error(c, response.ErrApiTokenInvalid)
+		c.Abort()
+		return
+	}
+	admin, err := adminDao.GetAdminById(apiToken.AdminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, response.ErrApiTokenInvalid)
+		} else {
+			global.Logger.Error("Failed to query api token owner", zap.Error(err))
+			response.Error(c, response.ErrServerError)
+		}
+		c.Abort()
+		return
+	}
+	if admin.Status != 1 {
+		response.Error(c, response.ErrAdminDisabled)
+		c.Abort()
+		return
+	}
+	if sensitiveRoutes[c.FullPath()] {
+		response.Error(c, response.ErrForbidden)
+		c.Abort()
+		return
+	}
+	// 登录即可访问的接口不受权限范围约束，设置了权限范围的令牌一律不能访问
+	if _, ok := routePermission(c); apiToken.Scopes != "" && !ok {
+		response.Error(c, response.ErrForbidden)
+		c.Abort()
+		return
+	}
+
+	if apiToken.LastUsedAt == nil || now.Sub(apiToken.LastUsedAt.Time) >= apiTokenTouchInterval {
+		if err := apiTokenDao.TouchApiToken(apiToken.ID, c.ClientIP(), now); err != nil {
+			global.Logger.Warn("Failed to update api token last used time", zap.Uint("tokenId", apiToken.ID), zap.Error(err))
+		}
+	}
+
+	c.Set(global.LoggedUser, entity.JwtAdmin{
+		ID:       admin.ID,
+		Username: admin.Username,
+		Nickname: admin.Nickname,
+		Icon:     admin.Icon,
+		Email:    admin.Email,
+		Phone:    admin.Phone,
+		Note:     admin.Note,
+	})
+	c.Set(global.LoggedApiToken, apiToken)
+	c.Next()
+}
@@ -0,0 +1,139 @@
+//go:build cgo
+
+package middleware
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/common/utils"
+	"go-admin-server/global"
+	"go-admin-server/pkg/encrypt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 创建API令牌，返回令牌明文
+func newTestApiToken(t *testing.T, adminId uint, plain, scopes string) string {
+	t.Helper()
+	mustCreate(t, &entity.SysApiToken{
+		AdminID:     adminId,
+		Name:        plain,
+		TokenHash:   encrypt.HashToken(plain),
+		TokenPrefix: plain[:len(entity.ApiTokenPrefix)+6],
+		Scopes:      scopes,
+	})
+	return plain
+}
+
+func TestApiTokenScopes(t *testing.T) {
+	setupTestEnv(t)
+	// 服务账号拥有 system:post:list 和 system:post:add 权限
+	mustCreate(t,
+		&entity.SysMenu{ID: 1, MenuName: "岗位查询", MenuType: 3, MenuStatus: 1, Value: "system:post:list"},
+		&entity.SysMenu{ID: 2, MenuName: "岗位新增", MenuType: 3, MenuStatus: 1, Value: "system:post:add"},
+		&entity.SysRole{ID: 1, RoleName: "岗位管理", RoleKey: "post", RoleStatus: 1},
+		&entity.SysRoleMenu{RoleID: 1, MenuID: 1},
+		&entity.SysRoleMenu{RoleID: 1, MenuID: 2},
+		&entity.SysAdmin{ID: 1, Username: "robot", Nickname: "robot", Password: "x", Status: 1, IsServiceAccount: true},
+		&entity.SysAdminRole{AdminID: 1, RoleID: 1},
+	)
+	unscoped := newTestApiToken(t, 1, entity.ApiTokenPrefix+"unscoped0", "")
+	listOnly := newTestApiToken(t, 1, entity.ApiTokenPrefix+"listonly0", "system:post:list")
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := gin.New()
+	private := router.Group("/api", JWTAuth())
+	private.GET("/me/profile", ok)
+	private.GET("/postService/getPostList", Permission("system:post:list"), ok)
+	private.POST("/postService/createPost", Permission("system:post:add"), ok)
+	// 路由声明的权限没有登记时拒绝访问
+	private.POST("/postService/unregistered", Permission("system:post:add"), ok)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"unscoped token on login only route", http.MethodGet, "/api/me/profile", unscoped, http.StatusOK},
+		{"scoped token on login only route", http.MethodGet, "/api/me/profile", listOnly, http.StatusForbidden},
+		{"unscoped token on permission route", http.MethodPost, "/api/postService/createPost", unscoped, http.StatusOK},
+		{"scoped token within scope", http.MethodGet, "/api/postService/getPostList", listOnly, http.StatusOK},
+		{"scoped token out of scope", http.MethodPost, "/api/postService/createPost", listOnly, http.StatusForbidden},
+		{"unregistered route permission", http.MethodPost, "/api/postService/unregistered", unscoped, http.StatusInternalServerError},
+		{"unknown token", http.MethodGet, "/api/me/profile", entity.ApiTokenPrefix + "unknown", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{apiKeyHeader: {tt.token}}
+			if got := serve(router, tt.method, tt.path, header); got != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApiTokenAuth(t *testing.T) {
+	expired := utils.HTime{Time: time.Now().Add(-time.Minute)}
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, token *entity.SysApiToken, owner *entity.SysAdmin)
+		header  func(token string) http.Header
+		path    string
+		want    int
+	}{
+		{"x-api-key header", nil, func(token string) http.Header { return http.Header{apiKeyHeader: {token}} }, "/api/me/profile", response.CodeSuccess},
+		{"bearer token", nil, bearer, "/api/me/profile", response.CodeSuccess},
+		{"expired token", func(t *testing.T, token *entity.SysApiToken, _ *entity.SysAdmin) {
+			global.DB.Model(token).Update("expires_at", &expired)
+		}, bearer, "/api/me/profile", response.CodeApiTokenInvalid},
+		{"revoked token", func(t *testing.T, token *entity.SysApiToken, _ *entity.SysAdmin) {
+			global.DB.Delete(token)
+		}, bearer, "/api/me/profile", response.CodeApiTokenInvalid},
+		{"disabled owner", func(t *testing.T, _ *entity.SysApiToken, owner *entity.SysAdmin) {
+			global.DB.Model(owner).Update("status", 2)
+		}, bearer, "/api/me/profile", response.CodeAdminDisabled},
+		{"sensitive route", nil, bearer, "/api/adminService/updatePassword", response.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			owner := &entity.SysAdmin{ID: 1, Username: "robot", Nickname: "robot", Password: "x", Status: 1, IsServiceAccount: true}
+			mustCreate(t, owner)
+			plain := newTestApiToken(t, owner.ID, entity.ApiTokenPrefix+"robot0000", "")
+			var token entity.SysApiToken
+			if err := global.DB.Take(&token).Error; err != nil {
+				t.Fatal(err)
+			}
+			if tt.prepare != nil {
+				tt.prepare(t, &token, owner)
+			}
+
+			router := gin.New()
+			private := router.Group("/api", JWTAuth(), OperationLog())
+			private.GET("/me/profile", response.Success)
+			private.GET("/adminService/updatePassword", response.Success)
+			if got := serveCode(t, router, http.MethodGet, tt.path, tt.header(plain)); got != tt.want {
+				t.Fatalf("code = %d, want %d", got, tt.want)
+			}
+			if tt.want != response.CodeSuccess {
+				return
+			}
+			// 操作日志记录调用使用的令牌，并更新令牌的最近使用时间
+			var log entity.SysOperationLog
+			if err := global.DB.Take(&log).Error; err != nil {
+				t.Fatal(err)
+			}
+			if log.AdminID != owner.ID || log.ApiTokenID != token.ID || log.ApiTokenName != token.Name {
+				t.Errorf("operation log admin, token = %d, %d %q", log.AdminID, log.ApiTokenID, log.ApiTokenName)
+			}
+			if err := global.DB.Take(&token).Error; err != nil || token.LastUsedAt == nil {
+				t.Errorf("token last used at = %v, %v", token.LastUsedAt, err)
+			}
+		})
+	}
+}
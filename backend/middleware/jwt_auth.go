@@ -1,16 +1,55 @@
 package middleware
 
 import (
+	"go-admin-server/api/dao"
+	"go-admin-server/api/entity"
 	"go-admin-server/common/response"
 	"go-admin-server/global"
 	"go-admin-server/pkg/jwt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
+var (
+	tokenDao   = dao.TokenDao{}
+	sessionDao = dao.SessionDao{}
+)
+
+// 密码已过期时令牌可以访问的接口
+var passwordChangeRoutes = map[string]bool{
+	"/api/adminService/updatePassword": true,
+	"/api/logout":                      true,
+}
+
+// 模拟登录期间以及使用API令牌时禁止访问的敏感接口：修改个人资料(邮箱可用于找回密码)、修改密码、两步验证、通行密钥、吊销会话、重置他人凭据、模拟登录以及创建API令牌
+var sensitiveRoutes = map[string]bool{
+	"/api/adminService/updatePassword":    true,
+	"/api/adminService/updatePersonal":    true,
+	"/api/adminService/resetPassword":     true,
+	"/api/adminService/resetTwoFactor":    true,
+	"/api/adminService/impersonate":       true,
+	"/api/me/revokeSession":               true,
+	"/api/me/revokeOtherSessions":         true,
+	"/api/me/setupTwoFactor":              true,
+	"/api/me/enableTwoFactor":             true,
+	"/api/me/disableTwoFactor":            true,
+	"/api/me/regenerateRecoveryCodes":     true,
+	"/api/me/beginPasskeyRegistration":    true,
+	"/api/me/finishPasskeyRegistration":   true,
+	"/api/me/deletePasskey":               true,
+	"/api/me/createApiToken":              true,
+	"/api/apiTokenService/createApiToken": true,
+}
+
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// API令牌可以放在 X-API-Key 请求头中
+		if apiKey := c.GetHeader(apiKeyHeader); apiKey != "" {
+			apiTokenAuth(c, apiKey)
+			return
+		}
 		// 获取Authorization请求头
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -26,16 +65,78 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 		token := parts[1]
+		// 也可以通过 Bearer 方式传递API令牌
+		if strings.HasPrefix(token, entity.ApiTokenPrefix) {
+			apiTokenAuth(c, token)
+			return
+		}
 
-		// 解析token
+		// 解析token，只有访问令牌可以用于请求接口
 		claims, err := jwt.ParseToken(token)
-		if err != nil {
+		if err != nil || claims.TokenType != jwt.TokenTypeAccess {
 			response.Error(c, response.ErrTokenInvalid)
 			c.Abort()
 			return
 		}
+		// 检查token是否已被吊销
+		revoked, err := isTokenRevoked(claims)
+		if err != nil {
+			global.Logger.Error("Failed to check token revocation", zap.Error(err))
+			response.Error(c, response.ErrServerError)
+			c.Abort()
+			return
+		}
+		if revoked {
+			response.Error(c, response.ErrTokenRevoked)
+			c.Abort()
+			return
+		}
+		// 受限令牌只能访问指定的接口
+		if claims.Scope == jwt.ScopePasswordChange && !passwordChangeRoutes[c.FullPath()] {
+			response.Error(c, response.ErrPasswordExpired)
+			c.Abort()
+			return
+		}
+		// 模拟登录期间不能访问敏感接口
+		if claims.Impersonator != nil && sensitiveRoutes[c.FullPath()] {
+			response.Error(c, response.ErrImpersonating)
+			c.Abort()
+			return
+		}
 		// 将当前登录用户的信息，设置到上下文中
 		c.Set(global.LoggedUser, claims.JwtAdmin)
+		c.Set(global.LoggedClaims, claims)
 		c.Next()
 	}
 }
+
+// 令牌被单独吊销、用户的令牌版本号已变化(修改密码、停用账号等)，
+// 或者令牌所属的登录会话已被删除(退出登录、被强制下线)，都视为已吊销
+func isTokenRevoked(claims *jwt.CustomClaims) (bool, error) {
+	revoked, err := tokenDao.IsTokenRevoked(claims.RegisteredClaims.ID)
+	if err != nil || revoked {
+		return revoked, err
+	}
+	version, err := tokenDao.GetTokenVersion(claims.JwtAdmin.ID)
+	if err != nil {
+		return false, err
+	}
+	if claims.TokenVersion != version {
+		return true, nil
+	}
+	// 模拟登录时，发起人的令牌被整体吊销(修改密码、停用账号等)后模拟令牌同时失效
+	if claims.Impersonator != nil {
+		version, err := tokenDao.GetTokenVersion(claims.Impersonator.ID)
+		if err != nil {
+			return false, err
+		}
+		if claims.Impersonator.TokenVersion != version {
+			return true, nil
+		}
+	}
+	exists, err := sessionDao.ExistsSession(claims.SessionID)
+	if err != nil {
+		return false, err
+	}
+	return !exists, nil
+}
@@ -0,0 +1,169 @@
+//go:build cgo
+
+package middleware
+
+import (
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 只挂载 JWTAuth 的测试路由，通过认证时返回成功
+func newJWTRouter() *gin.Engine {
+	router := gin.New()
+	router.GET("/api/me/profile", JWTAuth(), response.Success)
+	return router
+}
+
+func TestTokenRevocation(t *testing.T) {
+	tests := []struct {
+		name string
+		// 返回请求使用的 Authorization 请求头
+		header func(t *testing.T, user *entity.SysAdmin, pair *jwt.TokenPair) http.Header
+		want   int
+	}{
+		{"valid access token", func(t *testing.T, _ *entity.SysAdmin, pair *jwt.TokenPair) http.Header {
+			return bearer(pair.AccessToken)
+		}, response.CodeSuccess},
+		{"refresh token", func(t *testing.T, _ *entity.SysAdmin, pair *jwt.TokenPair) http.Header {
+			return bearer(pair.RefreshToken)
+		}, response.CodeTokenInvalid},
+		{"tampered token", func(t *testing.T, _ *entity.SysAdmin, pair *jwt.TokenPair) http.Header {
+			return bearer(pair.AccessToken + "x")
+		}, response.CodeTokenInvalid},
+		{"not bearer", func(t *testing.T, _ *entity.SysAdmin, pair *jwt.TokenPair) http.Header {
+			return http.Header{"Authorization": {pair.AccessToken}}
+		}, response.CodeTokenFormatError},
+		{"no token", func(*testing.T, *entity.SysAdmin, *jwt.TokenPair) http.Header {
+			return nil
+		}, response.CodeUnauthorized},
+		{"revoked token", func(t *testing.T, _ *entity.SysAdmin, pair *jwt.TokenPair) http.Header {
+			claims, err := jwt.ParseToken(pair.AccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tokenDao.RevokeToken(claims.RegisteredClaims.ID, claims.ExpiresAt.Time); err != nil {
+				t.Fatal(err)
+			}
+			return bearer(pair.AccessToken)
+		}, response.CodeTokenRevoked},
+		{"admin tokens revoked", func(t *testing.T, user *entity.SysAdmin, pair *jwt.TokenPair) http.Header {
+			if err := tokenDao.RevokeAdminTokens(user.ID); err != nil {
+				t.Fatal(err)
+			}
+			return bearer(pair.AccessToken)
+		}, response.CodeTokenRevoked},
+		{"new token after admin tokens revoked", func(t *testing.T, user *entity.SysAdmin, _ *jwt.TokenPair) http.Header {
+			if err := tokenDao.RevokeAdminTokens(user.ID); err != nil {
+				t.Fatal(err)
+			}
+			return bearer(loginTestAdmin(t, user, "session-2").AccessToken)
+		}, response.CodeSuccess},
+		{"other admin tokens revoked", func(t *testing.T, user *entity.SysAdmin, pair *jwt.TokenPair) http.Header {
+			if err := tokenDao.RevokeAdminTokens(user.ID + 1); err != nil {
+				t.Fatal(err)
+			}
+			return bearer(pair.AccessToken)
+		}, response.CodeSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			user := &entity.SysAdmin{ID: 1, Username: "alice", Nickname: "alice", Password: "x", Status: 1}
+			mustCreate(t, user)
+			pair := loginTestAdmin(t, user, "session-1")
+
+			header := tt.header(t, user, pair)
+			if got := serveCode(t, newJWTRouter(), http.MethodGet, "/api/me/profile", header); got != tt.want {
+				t.Errorf("code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRevocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		deleted string // 被删除的会话
+		want    int
+	}{
+		{"current session deleted", "session-1", response.CodeTokenRevoked},
+		{"other session deleted", "session-2", response.CodeSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			user := &entity.SysAdmin{ID: 1, Username: "alice", Nickname: "alice", Password: "x", Status: 1}
+			mustCreate(t, user)
+			pair := loginTestAdmin(t, user, "session-1")
+			loginTestAdmin(t, user, "session-2")
+
+			if err := sessionDao.DeleteSession(user.ID, tt.deleted); err != nil {
+				t.Fatal(err)
+			}
+			if got := serveCode(t, newJWTRouter(), http.MethodGet, "/api/me/profile", bearer(pair.AccessToken)); got != tt.want {
+				t.Errorf("code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImpersonationToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		revoke string // 令牌被整体吊销的用户
+		want   int
+		logged bool // 是否记录了同时包含双方身份的操作日志
+	}{
+		{"normal route", http.MethodGet, "/api/me/profile", "", response.CodeSuccess, true},
+		{"sensitive route", http.MethodPost, "/api/adminService/updatePassword", "", response.CodeImpersonating, false},
+		{"impersonator tokens revoked", http.MethodGet, "/api/me/profile", "alice", response.CodeTokenRevoked, false},
+		{"target tokens revoked", http.MethodGet, "/api/me/profile", "bob", response.CodeTokenRevoked, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestEnv(t)
+			alice := &entity.SysAdmin{ID: 1, Username: "alice", Nickname: "alice", Password: "x", Status: 1}
+			bob := &entity.SysAdmin{ID: 2, Username: "bob", Nickname: "bob", Password: "x", Status: 1}
+			mustCreate(t, alice, bob)
+			loginTestAdmin(t, alice, "alice-1")
+			// alice 模拟 bob 登录
+			if err := sessionDao.SaveSession(&entity.SysSession{SessionID: "bob-1", AdminID: bob.ID, ImpersonatorID: alice.ID}, time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			pair, err := jwt.GenerateImpersonationTokenPair(bob, "bob-1", 0, &jwt.Impersonator{ID: alice.ID, Username: alice.Username, SessionID: "alice-1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.revoke != "" {
+				id := map[string]uint{"alice": alice.ID, "bob": bob.ID}[tt.revoke]
+				if err := tokenDao.RevokeAdminTokens(id); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			router := gin.New()
+			private := router.Group("/api", JWTAuth(), OperationLog())
+			private.GET("/me/profile", response.Success)
+			private.POST("/adminService/updatePassword", response.Success)
+			if got := serveCode(t, router, tt.method, tt.path, bearer(pair.AccessToken)); got != tt.want {
+				t.Errorf("code = %d, want %d", got, tt.want)
+			}
+			var count int64
+			global.DB.Model(&entity.SysOperationLog{}).
+				Where("admin_id = ? AND impersonator_id = ? AND impersonator_name = ?", bob.ID, alice.ID, alice.Username).
+				Count(&count)
+			if logged := count == 1; logged != tt.logged {
+				t.Errorf("operation logged = %v, want %v", logged, tt.logged)
+			}
+		})
+	}
+}
@@ -1,19 +1,48 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
 	"go-admin-server/api/dao"
 	"go-admin-server/api/entity"
 	"go-admin-server/common/response"
 	"go-admin-server/common/utils"
 	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+	"io"
+	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	maxLogBodySize  = 4096 // 请求体最多记录的字节数
+	maxLogQuerySize = 1000 // 查询参数最多记录的字节数
+	maskedValue     = "******"
+)
+
 var logDao = dao.SysLogDao{}
 
+// 声明路由组的业务模块名称，记录到操作日志中
+func LogModule(module string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(global.LogModule, module)
+		c.Next()
+	}
+}
+
+// 声明路由的业务操作名称，记录到操作日志中
+func LogAction(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(global.LogAction, action)
+		c.Next()
+	}
+}
+
+// 记录操作日志：在请求处理完成后记录请求参数、处理结果和耗时
 func OperationLog() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		method := strings.ToLower(c.Request.Method)
@@ -31,21 +60,144 @@ func OperationLog() gin.HandlerFunc {
 			return
 		}
 
+		start := time.Now()
+		body := readRequestBody(c)
+
+		c.Next()
+
 		operationLog := &entity.SysOperationLog{
 			AdminID:   loggedUser.ID,
 			Username:  loggedUser.Username,
 			Method:    method,
 			Ip:        c.ClientIP(),
 			Url:       c.Request.URL.Path,
-			CreatedAt: utils.HTime{Time: time.Now()},
+			CreatedAt: utils.HTime{Time: start},
+			Module:    c.GetString(global.LogModule),
+			Action:    c.GetString(global.LogAction),
+			Query:     truncate(sanitizeQuery(c.Request.URL.RawQuery), maxLogQuerySize),
+			Body:      body,
+			Status:    c.Writer.Status(),
+			Code:      c.GetInt(global.ResponseCode),
+			Latency:   time.Since(start).Milliseconds(),
+			UserAgent: truncate(c.Request.UserAgent(), 500),
+		}
+		// 使用API令牌调用时记录令牌
+		if apiToken, ok := c.Get(global.LoggedApiToken); ok {
+			if apiToken, ok := apiToken.(*entity.SysApiToken); ok {
+				operationLog.ApiTokenID = apiToken.ID
+				operationLog.ApiTokenName = apiToken.Name
+			}
+		}
+		// 模拟登录时同时记录发起人
+		if claims, ok := c.Get(global.LoggedClaims); ok {
+			if claims, ok := claims.(*jwt.CustomClaims); ok && claims.Impersonator != nil {
+				operationLog.ImpersonatorID = claims.Impersonator.ID
+				operationLog.ImpersonatorName = claims.Impersonator.Username
+			}
+		}
+		if operationLog.Code != response.CodeSuccess {
+			operationLog.ErrorMsg = truncate(c.GetString(global.ResponseMessage), 500)
 		}
+		// 异步写入，不影响请求
+		logDao.CreateOperationLog(operationLog)
+	}
+}
 
-		if err := logDao.CreateOperationLog(operationLog); err != nil {
-			response.Error(c, response.ErrServerError)
-			c.Abort()
-			return
+// 读取请求体并脱敏，读取后重新放回请求中供后续处理使用
+func readRequestBody(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	contentType := c.ContentType()
+	// 文件上传等请求不记录请求体
+	if contentType == gin.MIMEMultipartPOSTForm {
+		return "[multipart/form-data]"
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+	if len(data) == 0 {
+		return ""
+	}
+
+	var body string
+	switch contentType {
+	case gin.MIMEJSON, response.ScimContentType:
+		body = sanitizeJSON(data)
+	case gin.MIMEPOSTForm:
+		body = sanitizeQuery(string(data))
+	default:
+		body = string(data)
+	}
+	return truncate(body, maxLogBodySize)
+}
+
+// 敏感字段：密码、密钥、令牌、验证码
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") ||
+		strings.Contains(key, "secret") ||
+		strings.Contains(key, "token") ||
+		key == "code"
+}
+
+// JSON 请求体脱敏，无法解析时原样返回
+func sanitizeJSON(data []byte) string {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+	sanitized, err := json.Marshal(maskValue(value))
+	if err != nil {
+		return string(data)
+	}
+	return string(sanitized)
+}
+
+func maskValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				v[key] = maskedValue
+			} else {
+				v[key] = maskValue(item)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = maskValue(item)
 		}
+	}
+	return value
+}
 
-		c.Next()
+// 查询参数、表单脱敏
+func sanitizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	for key := range values {
+		if isSensitiveKey(key) {
+			values[key] = []string{maskedValue}
+		}
+	}
+	return values.Encode()
+}
+
+// 按字节截断，避免截断多字节字符
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
 	}
+	return s[:max]
 }
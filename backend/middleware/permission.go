@@ -0,0 +1,127 @@
+// 接口权限校验中间件
+
+package middleware
+
+import (
+	"go-admin-server/api/dao"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/response"
+	"go-admin-server/global"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+var menuDao = dao.SysMenuDao{}
+
+// 声明了按钮权限的接口及其权限值，与路由中 Permission 的参数保持一致；
+// 设置了权限范围的API令牌只能访问这里登记的接口，未登记的接口视为登录即可访问
+var routePermissions = map[string]string{
+	"/api/postService/createPost":          "system:post:add",
+	"/api/postService/getPostList":         "system:post:list",
+	"/api/postService/getPostById":         "system:post:query",
+	"/api/postService/updatePost":          "system:post:edit",
+	"/api/postService/deletePost":          "system:post:remove",
+	"/api/postService/batchDeletePosts":    "system:post:remove",
+	"/api/postService/updatePostStatus":    "system:post:edit",
+	"/api/deptService/createDept":          "system:dept:add",
+	"/api/deptService/getDeptList":         "system:dept:list",
+	"/api/deptService/getDeptById":         "system:dept:query",
+	"/api/deptService/updateDept":          "system:dept:edit",
+	"/api/deptService/deleteDept":          "system:dept:remove",
+	"/api/menuService/createMenu":          "system:menu:add",
+	"/api/menuService/getMenuList":         "system:menu:list",
+	"/api/menuService/getMenuById":         "system:menu:query",
+	"/api/menuService/updateMenu":          "system:menu:edit",
+	"/api/menuService/deleteMenu":          "system:menu:remove",
+	"/api/roleService/createRole":          "system:role:add",
+	"/api/roleService/getRoleList":         "system:role:list",
+	"/api/roleService/getRoleById":         "system:role:query",
+	"/api/roleService/updateRole":          "system:role:edit",
+	"/api/roleService/deleteRole":          "system:role:remove",
+	"/api/roleService/updateRoleStatus":    "system:role:edit",
+	"/api/roleService/getRoleMenus":        "system:role:query",
+	"/api/roleService/assignRoleMenus":     "system:role:assign",
+	"/api/roleService/getRoleDataScope":    "system:role:query",
+	"/api/roleService/assignRoleDataScope": "system:role:assign",
+	"/api/roleService/getRoleAdminList":    "system:role:query",
+	"/api/roleService/addRoleAdmins":       "system:role:assign",
+	"/api/roleService/removeRoleAdmins":    "system:role:assign",
+	"/api/adminService/createAdmin":        "system:admin:add",
+	"/api/adminService/inviteAdmin":        "system:admin:invite",
+	"/api/adminService/resendInvitation":   "system:admin:invite",
+	"/api/adminService/revokeInvitation":   "system:admin:invite",
+	"/api/adminService/getAdminList":       "system:admin:list",
+	"/api/adminService/getAdminById":       "system:admin:query",
+	"/api/adminService/updateAdmin":        "system:admin:edit",
+	"/api/adminService/deleteAdmin":        "system:admin:remove",
+	"/api/adminService/updateAdminStatus":  "system:admin:edit",
+	"/api/adminService/resetPassword":      "system:admin:resetPwd",
+	"/api/adminService/forceLogout":        "system:admin:forceLogout",
+	"/api/adminService/unlockAdmin":        "system:admin:unlock",
+	"/api/adminService/resetTwoFactor":     "system:admin:reset2fa",
+	"/api/adminService/impersonate":        "system:admin:impersonate",
+	"/api/apiTokenService/createApiToken":  "system:apiToken:add",
+	"/api/apiTokenService/getApiTokenList": "system:apiToken:list",
+	"/api/apiTokenService/revokeApiToken":  "system:apiToken:remove",
+	"/api/ldapService/syncDirectory":       "system:ldap:sync",
+	"/api/logService/getLoginLogList":      "monitor:loginLog:list",
+	"/api/logService/deleteLoginLog":       "monitor:loginLog:remove",
+	"/api/logService/batchDeleteLoginLog":  "monitor:loginLog:remove",
+	"/api/logService/getOpLogList":         "monitor:opLog:list",
+	"/api/logService/deleteOpLog":          "monitor:opLog:remove",
+	"/api/logService/batchDeleteOpLog":     "monitor:opLog:remove",
+	"/api/logService/getLogWriterStats":    "monitor:opLog:list",
+}
+
+// Permission 校验当前登录用户是否拥有访问该路由的权限
+// value 对应 sys_menu 表中按钮类型(menu_type=3)菜单的权限值，
+// 通过 sys_admin_role -> sys_role -> sys_role_menu 判断用户的角色是否被分配了该按钮
+func Permission(value string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userInfo, exists := c.Get(global.LoggedUser)
+		if !exists {
+			response.Error(c, response.ErrAdminUnauthorized)
+			c.Abort()
+			return
+		}
+		loggedUser, ok := userInfo.(entity.JwtAdmin)
+		if !ok {
+			global.Logger.Error("Failed to assert logged user")
+			response.Error(c, response.ErrServerError)
+			c.Abort()
+			return
+		}
+		// 路由声明的权限需要登记在 routePermissions 中，否则API令牌的权限范围校验会与路由不一致
+		if registered, _ := routePermission(c); registered != value {
+			global.Logger.Error("Route permission is not registered", zap.String("path", c.FullPath()), zap.String("permission", value))
+			response.Error(c, response.ErrServerError)
+			c.Abort()
+			return
+		}
+
+		hasPermission, err := menuDao.HasPermission(loggedUser.ID, value)
+		if err != nil {
+			global.Logger.Error("Failed to check permission", zap.String("permission", value), zap.Error(err))
+			response.Error(c, response.ErrServerError)
+			c.Abort()
+			return
+		}
+		// 使用API令牌时还需要在令牌的权限范围内
+		if apiToken, ok := c.Get(global.LoggedApiToken); ok && hasPermission {
+			hasPermission = apiToken.(*entity.SysApiToken).AllowPermission(value)
+		}
+		if !hasPermission {
+			response.Error(c, response.ErrForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// 当前接口声明的按钮权限，登录即可访问的接口返回 false
+func routePermission(c *gin.Context) (string, bool) {
+	value, ok := routePermissions[c.FullPath()]
+	return value, ok
+}
@@ -0,0 +1,71 @@
+//go:build cgo
+
+package middleware
+
+import (
+	"go-admin-server/api/entity"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPermission(t *testing.T) {
+	setupTestEnv(t)
+	// 角色 2 继承角色 1 的 system:post:list 权限，角色 3 已停用
+	parent := uint(1)
+	mustCreate(t,
+		&entity.SysMenu{ID: 1, MenuName: "岗位查询", MenuType: 3, MenuStatus: 1, Value: "system:post:list"},
+		&entity.SysMenu{ID: 2, MenuName: "岗位删除", MenuType: 3, MenuStatus: 1, Value: "system:post:remove"},
+		&entity.SysRole{ID: 1, RoleName: "岗位查询", RoleKey: "postReader", RoleStatus: 1},
+		&entity.SysRole{ID: 2, RoleName: "岗位管理", RoleKey: "postAdmin", RoleStatus: 1, ParentID: &parent},
+		&entity.SysRole{ID: 3, RoleName: "已停用", RoleKey: "disabled", RoleStatus: 2},
+		&entity.SysRoleMenu{RoleID: 1, MenuID: 1},
+		&entity.SysRoleMenu{RoleID: 2, MenuID: 2},
+		&entity.SysRoleMenu{RoleID: 3, MenuID: 1},
+	)
+	admins := map[string]*entity.SysAdmin{}
+	for i, name := range []string{"reader", "manager", "disabled", "nobody", "root"} {
+		admins[name] = &entity.SysAdmin{ID: uint(i + 1), Username: name, Nickname: name, Password: "x", Status: 1, IsSuper: name == "root"}
+		mustCreate(t, admins[name])
+	}
+	mustCreate(t,
+		&entity.SysAdminRole{AdminID: admins["reader"].ID, RoleID: 1},
+		&entity.SysAdminRole{AdminID: admins["manager"].ID, RoleID: 2},
+		&entity.SysAdminRole{AdminID: admins["disabled"].ID, RoleID: 3},
+	)
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := gin.New()
+	private := router.Group("/api", JWTAuth())
+	private.GET("/postService/getPostList", Permission("system:post:list"), ok)
+	private.DELETE("/postService/deletePost", Permission("system:post:remove"), ok)
+
+	tests := []struct {
+		name   string
+		admin  string // 为空表示未登录
+		method string
+		path   string
+		want   int
+	}{
+		{"granted", "reader", http.MethodGet, "/api/postService/getPostList", http.StatusOK},
+		{"not granted", "reader", http.MethodDelete, "/api/postService/deletePost", http.StatusForbidden},
+		{"own role", "manager", http.MethodDelete, "/api/postService/deletePost", http.StatusOK},
+		{"inherited from parent role", "manager", http.MethodGet, "/api/postService/getPostList", http.StatusOK},
+		{"disabled role", "disabled", http.MethodGet, "/api/postService/getPostList", http.StatusForbidden},
+		{"no role", "nobody", http.MethodGet, "/api/postService/getPostList", http.StatusForbidden},
+		{"super admin", "root", http.MethodDelete, "/api/postService/deletePost", http.StatusOK},
+		{"not logged in", "", http.MethodGet, "/api/postService/getPostList", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			if tt.admin != "" {
+				header = bearer(loginTestAdmin(t, admins[tt.admin], "session-"+tt.admin).AccessToken)
+			}
+			if got := serve(router, tt.method, tt.path, header); got != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
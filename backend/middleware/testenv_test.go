@@ -0,0 +1,130 @@
+//go:build cgo
+
+package middleware
+
+import (
+	"encoding/json"
+	"go-admin-server/api/entity"
+	"go-admin-server/common/config"
+	"go-admin-server/common/response"
+	"go-admin-server/core/permcache"
+	"go-admin-server/global"
+	"go-admin-server/pkg/jwt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 使用内存中的 sqlite 和 miniredis 替换数据库和redis，测试结束后恢复
+func setupTestEnv(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	oldConfig, oldLogger, oldDB, oldRDB := global.Config, global.Logger, global.DB, global.RDB
+	t.Cleanup(func() {
+		global.Config, global.Logger, global.DB, global.RDB = oldConfig, oldLogger, oldDB, oldRDB
+	})
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存数据库只存在于一个连接中
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(
+		&entity.SysMenu{},
+		&entity.SysRole{},
+		&entity.SysRoleMenu{},
+		&entity.SysAdmin{},
+		&entity.SysAdminRole{},
+		&entity.SysApiToken{},
+		&entity.SysOperationLog{},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	// 各测试的数据库相互独立，用户id会重复，不使用进程内的权限缓存
+	permcache.Start(config.PermissionCache{Disabled: true})
+	global.Config = &config.AppConfig{}
+	global.Logger = zap.NewNop()
+	global.DB = db
+	global.RDB = rdb
+	jwt.Setup(config.Jwt{Secret: "test-secret"})
+	gin.SetMode(gin.TestMode)
+	return mr
+}
+
+// 创建测试数据，失败时结束测试
+func mustCreate(t *testing.T, values ...any) {
+	t.Helper()
+	for _, value := range values {
+		if err := global.DB.Create(value).Error; err != nil {
+			t.Fatalf("create %T: %v", value, err)
+		}
+	}
+}
+
+// 发送请求，返回 HTTP 状态码
+func serve(router *gin.Engine, method, path string, header http.Header) int {
+	return record(router, method, path, header).Code
+}
+
+// 发送请求，返回响应中的业务状态码
+func serveCode(t *testing.T, router *gin.Engine, method, path string, header http.Header) int {
+	t.Helper()
+	var result response.Response
+	if err := json.Unmarshal(record(router, method, path, header).Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return result.Code
+}
+
+func record(router *gin.Engine, method, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// 为用户创建登录会话并签发令牌对
+func loginTestAdmin(t *testing.T, user *entity.SysAdmin, sessionID string) *jwt.TokenPair {
+	t.Helper()
+	if err := sessionDao.SaveSession(&entity.SysSession{SessionID: sessionID, AdminID: user.ID, Username: user.Username}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	version, err := tokenDao.GetTokenVersion(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair, err := jwt.GenerateTokenPair(user, sessionID, "", version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pair
+}
+
+// 使用访问令牌的请求头
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
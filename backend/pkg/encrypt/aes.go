@@ -0,0 +1,59 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var aead cipher.AEAD
+
+// 设置数据加密密钥，使用 SHA-256 将任意长度的密钥派生为 AES-256 密钥
+func SetupSecretKey(secretKey string) error {
+	if secretKey == "" {
+		return errors.New("secret key is empty")
+	}
+	sum := sha256.Sum256([]byte(secretKey))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return err
+	}
+	aead, err = cipher.NewGCM(block)
+	return err
+}
+
+// 使用 AES-GCM 加密字符串，返回 base64 编码的 nonce+密文
+func EncryptString(plaintext string) (string, error) {
+	if aead == nil {
+		return "", errors.New("secret key is not configured")
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// 解密 EncryptString 加密的字符串
+func DecryptString(ciphertext string) (string, error) {
+	if aead == nil {
+		return "", errors.New("secret key is not configured")
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
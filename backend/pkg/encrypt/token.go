@@ -0,0 +1,13 @@
+package encrypt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// 计算一次性令牌(API令牌、重置密码链接、邀请链接等)的哈希值，数据库和 redis 中只保存哈希值。
+// 令牌是足够长的随机数，使用 SHA-256 即可
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+// IP地理位置查询，支持 ip2region(xdb)、MaxMind(mmdb) 离线数据库，以及可选的在线 HTTP 查询
+
+package geoip
+
+import (
+	"fmt"
+	"go-admin-server/common/config"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderIP2Region = "ip2region" // ip2region xdb 离线数据库
+	ProviderMMDB      = "mmdb"      // MaxMind GeoIP2/GeoLite2 离线数据库
+	ProviderHTTP      = "http"      // 在线查询 whois.pconline.com.cn，会把用户IP发送给第三方
+
+	defaultCacheSize   = 10000
+	defaultHTTPTimeout = 3 * time.Second
+)
+
+// Location 地理位置
+type Location struct {
+	Country string `json:"country"` // 国家
+	Region  string `json:"region"`  // 省份/地区
+	City    string `json:"city"`    // 城市
+	ISP     string `json:"isp"`     // 运营商
+}
+
+// String 拼接为便于展示的地址，如 "中国 广东省 深圳市 电信"
+func (l Location) String() string {
+	var parts []string
+	for _, part := range []string{l.Country, l.Region, l.City, l.ISP} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "未知地址"
+	}
+	return strings.Join(parts, " ")
+}
+
+// GeoIPResolver IP地理位置查询接口
+type GeoIPResolver interface {
+	Resolve(ip net.IP) (Location, error)
+}
+
+var (
+	resolver GeoIPResolver = nopResolver{}
+	cache                  = newLRUCache(defaultCacheSize)
+)
+
+// 未配置查询方式时不查询
+type nopResolver struct{}
+
+func (nopResolver) Resolve(net.IP) (Location, error) {
+	return Location{}, nil
+}
+
+// Setup 根据配置创建查询实现，配置错误时返回错误，此时不查询地理位置
+func Setup(cfg config.GeoIP) error {
+	cacheSize := cfg.CacheSize
+	if cacheSize <= 0 {
+		cacheSize = defaultCacheSize
+	}
+	cache = newLRUCache(cacheSize)
+
+	var err error
+	switch cfg.Provider {
+	case "":
+		resolver = nopResolver{}
+	case ProviderIP2Region:
+		resolver, err = newIP2RegionResolver(cfg.DBFile)
+	case ProviderMMDB:
+		resolver, err = newMMDBResolver(cfg.DBFile, cfg.ISPDBFile)
+	case ProviderHTTP:
+		timeout := cfg.HTTPTimeout
+		if timeout <= 0 {
+			timeout = defaultHTTPTimeout
+		}
+		resolver = newHTTPResolver(timeout)
+	default:
+		err = fmt.Errorf("unknown geoip provider %q", cfg.Provider)
+	}
+	if err != nil {
+		resolver = nopResolver{}
+	}
+	return err
+}
+
+// SetResolver 替换查询实现，用于接入其他数据源
+func SetResolver(r GeoIPResolver) {
+	resolver = r
+	cache.Purge()
+}
+
+// Lookup 查询IP地址的地理位置，内网地址、局域网地址不查询，查询结果会被缓存
+func Lookup(ipStr string) Location {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return Location{Country: "无效IP地址"}
+	}
+	if isLocalIP(ip) {
+		return Location{Country: "内网地址"}
+	}
+	if isLANIP(ip) {
+		return Location{Country: "局域网"}
+	}
+
+	key := ip.String()
+	if location, ok := cache.Get(key); ok {
+		return location
+	}
+	location, err := resolver.Resolve(ip)
+	if err != nil {
+		// 查询失败时不缓存，下次重新查询
+		return Location{}
+	}
+	cache.Add(key, location)
+	return location
+}
+
+// isLocalIP 判断是否为本地IP
+func isLocalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalMulticast() || ip.IsLinkLocalUnicast()
+}
+
+// isLANIP 判断是否为局域网IP
+func isLANIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		// IPv4 局域网段
+		switch {
+		case ip4[0] == 10:
+			return true
+		case ip4[0] == 172 && ip4[1] >= 16 && ip4[1] <= 31:
+			return true
+		case ip4[0] == 192 && ip4[1] == 168:
+			return true
+		case ip4[0] == 169 && ip4[1] == 254: // APIPA
+			return true
+		}
+	} else if ip6 := ip.To16(); ip6 != nil {
+		// IPv6 局域网段
+		switch {
+		case ip[0] == 0xfe && ip[1] == 0x80: // Link-local
+			return true
+		case ip[0] == 0xfc || ip[0] == 0xfd: // Unique Local Address
+			return true
+		}
+	}
+	return false
+}
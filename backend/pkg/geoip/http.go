@@ -0,0 +1,61 @@
+package geoip
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// 在线查询 whois.pconline.com.cn，会把用户IP发送给第三方，离线部署环境下不可用
+type httpResolver struct {
+	client *http.Client
+}
+
+// 查询结果，接口返回 GBK 编码
+type pconlineResult struct {
+	Pro  string `json:"pro"`  // 省份
+	City string `json:"city"` // 城市
+	Addr string `json:"addr"` // 完整地址，如 "广东省深圳市 电信"
+	Err  string `json:"err"`
+}
+
+func newHTTPResolver(timeout time.Duration) *httpResolver {
+	return &httpResolver{client: &http.Client{Timeout: timeout}}
+}
+
+func (r *httpResolver) Resolve(ip net.IP) (Location, error) {
+	url := fmt.Sprintf("https://whois.pconline.com.cn/ipJson.jsp?json=true&ip=%s", ip.String())
+	resp, err := r.client.Get(url)
+	if err != nil {
+		return Location{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Location{}, fmt.Errorf("geoip http status %d", resp.StatusCode)
+	}
+
+	var result pconlineResult
+	decoder := json.NewDecoder(simplifiedchinese.GBK.NewDecoder().Reader(resp.Body))
+	if err := decoder.Decode(&result); err != nil {
+		return Location{}, err
+	}
+
+	location := Location{Region: result.Pro, City: result.City}
+	addr := strings.TrimSpace(result.Addr)
+	if i := strings.LastIndex(addr, " "); i >= 0 {
+		location.ISP = strings.TrimSpace(addr[i+1:])
+		addr = strings.TrimSpace(addr[:i])
+	}
+	// 国内地址返回省份，国外地址只返回国家名称
+	if result.Pro != "" {
+		location.Country = "中国"
+	} else {
+		location.Country = addr
+	}
+	return location, nil
+}
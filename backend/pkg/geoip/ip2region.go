@@ -0,0 +1,96 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// ip2region xdb 文件结构：256字节头部 + 256*256 个向量索引(每个8字节) + 二分查找的段索引(每个14字节) + 地区数据
+const (
+	xdbHeaderLength      = 256
+	xdbVectorIndexCols   = 256
+	xdbVectorIndexSize   = 8
+	xdbSegmentIndexSize  = 14
+	xdbVectorIndexLength = 256 * xdbVectorIndexCols * xdbVectorIndexSize
+)
+
+// ip2region 离线查询，整个 xdb 文件加载到内存中，只支持 IPv4
+type ip2RegionResolver struct {
+	content []byte
+}
+
+func newIP2RegionResolver(dbFile string) (*ip2RegionResolver, error) {
+	content, err := os.ReadFile(dbFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < xdbHeaderLength+xdbVectorIndexLength {
+		return nil, fmt.Errorf("invalid ip2region xdb file %q", dbFile)
+	}
+	return &ip2RegionResolver{content: content}, nil
+}
+
+func (r *ip2RegionResolver) Resolve(ip net.IP) (Location, error) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return Location{}, errors.New("ip2region only supports ipv4")
+	}
+	region, err := r.search(binary.BigEndian.Uint32(ip4))
+	if err != nil {
+		return Location{}, err
+	}
+	return parseIP2Region(region), nil
+}
+
+// 根据向量索引确定段索引范围，再二分查找所在的段
+func (r *ip2RegionResolver) search(ip uint32) (string, error) {
+	il0, il1 := ip>>24&0xFF, ip>>16&0xFF
+	idx := xdbHeaderLength + int(il0)*xdbVectorIndexCols*xdbVectorIndexSize + int(il1)*xdbVectorIndexSize
+	sPtr := binary.LittleEndian.Uint32(r.content[idx:])
+	ePtr := binary.LittleEndian.Uint32(r.content[idx+4:])
+
+	low, high := 0, int((ePtr-sPtr)/xdbSegmentIndexSize)
+	for low <= high {
+		mid := (low + high) >> 1
+		p := int(sPtr) + mid*xdbSegmentIndexSize
+		if p+xdbSegmentIndexSize > len(r.content) {
+			return "", errors.New("corrupted ip2region xdb file")
+		}
+		sip := binary.LittleEndian.Uint32(r.content[p:])
+		eip := binary.LittleEndian.Uint32(r.content[p+4:])
+		switch {
+		case ip < sip:
+			high = mid - 1
+		case ip > eip:
+			low = mid + 1
+		default:
+			dataLen := int(binary.LittleEndian.Uint16(r.content[p+8:]))
+			dataPtr := int(binary.LittleEndian.Uint32(r.content[p+10:]))
+			if dataPtr+dataLen > len(r.content) {
+				return "", errors.New("corrupted ip2region xdb file")
+			}
+			return string(r.content[dataPtr : dataPtr+dataLen]), nil
+		}
+	}
+	return "", nil
+}
+
+// 解析地区数据，格式为 "国家|区域|省份|城市|ISP"，未知的字段为 "0"
+func parseIP2Region(region string) Location {
+	fields := strings.Split(region, "|")
+	field := func(i int) string {
+		if i >= len(fields) || fields[i] == "0" {
+			return ""
+		}
+		return fields[i]
+	}
+	// 新版数据去掉了区域字段，格式为 "国家|省份|城市|ISP"
+	if len(fields) == 4 {
+		return Location{Country: field(0), Region: field(1), City: field(2), ISP: field(3)}
+	}
+	return Location{Country: field(0), Region: field(2), City: field(3), ISP: field(4)}
+}
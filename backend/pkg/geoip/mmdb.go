@@ -0,0 +1,70 @@
+package geoip
+
+import (
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// MaxMind 离线查询：city 数据库查询国家、省份、城市，可选的 ISP 或 ASN 数据库查询运营商
+type mmdbResolver struct {
+	city *geoip2.Reader
+	isp  *geoip2.Reader
+}
+
+func newMMDBResolver(dbFile, ispDBFile string) (*mmdbResolver, error) {
+	city, err := geoip2.Open(dbFile)
+	if err != nil {
+		return nil, err
+	}
+	r := &mmdbResolver{city: city}
+	if ispDBFile != "" {
+		if r.isp, err = geoip2.Open(ispDBFile); err != nil {
+			city.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *mmdbResolver) Resolve(ip net.IP) (Location, error) {
+	record, err := r.city.City(ip)
+	if err != nil {
+		return Location{}, err
+	}
+	location := Location{
+		Country: localizedName(record.Country.Names),
+		City:    localizedName(record.City.Names),
+	}
+	if len(record.Subdivisions) > 0 {
+		location.Region = localizedName(record.Subdivisions[0].Names)
+	}
+	if r.isp != nil {
+		location.ISP = r.lookupISP(ip)
+	}
+	return location, nil
+}
+
+// 运营商数据库可以是 GeoIP2-ISP 或 GeoLite2-ASN
+func (r *mmdbResolver) lookupISP(ip net.IP) string {
+	if r.isp.Metadata().DatabaseType == "GeoLite2-ASN" {
+		record, err := r.isp.ASN(ip)
+		if err != nil {
+			return ""
+		}
+		return record.AutonomousSystemOrganization
+	}
+	record, err := r.isp.ISP(ip)
+	if err != nil {
+		return ""
+	}
+	return record.ISP
+}
+
+// 优先使用中文名称
+func localizedName(names map[string]string) string {
+	if name, ok := names["zh-CN"]; ok {
+		return name
+	}
+	return names["en"]
+}
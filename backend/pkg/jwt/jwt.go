@@ -3,14 +3,21 @@ package jwt
 import (
 	"errors"
 	"go-admin-server/api/entity"
+	"go-admin-server/common/utils"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// 令牌类型
 const (
-	TokenExpireDuration = 24 * time.Hour
-	TokenSecret         = "this-is-go-admin-server"
+	TokenTypeAccess  = "access"  // 访问令牌，用于请求接口
+	TokenTypeRefresh = "refresh" // 刷新令牌，只能用于换取新的令牌对
+)
+
+// 令牌权限范围，为空表示不受限
+const (
+	ScopePasswordChange = "password_change" // 密码已过期，只能用于修改密码
 )
 
 var (
@@ -20,10 +27,40 @@ var (
 
 type CustomClaims struct {
 	entity.JwtAdmin
+	TokenType    string `json:"tokenType"`       // 令牌类型
+	TokenVersion int64  `json:"tokenVersion"`    // 用户令牌版本号，版本号变化后旧令牌全部失效
+	SessionID    string `json:"sid"`             // 登录会话id，会话被删除后令牌失效
+	Scope        string `json:"scope,omitempty"` // 令牌权限范围
+
+	Impersonator *Impersonator `json:"impersonator,omitempty"` // 模拟登录的发起人，此时 JwtAdmin 为被模拟的用户
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(user *entity.SysAdmin) (string, error) {
+// 模拟登录的发起人
+type Impersonator struct {
+	ID           uint   `json:"id"`
+	Username     string `json:"username"`
+	SessionID    string `json:"sid"`          // 发起人原来的登录会话，结束模拟后回到该会话
+	TokenVersion int64  `json:"tokenVersion"` // 发起人的令牌版本号，发起人的令牌被整体吊销时模拟令牌同时失效
+}
+
+// 访问令牌与刷新令牌
+type TokenPair struct {
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+	AccessExpiresAt  time.Time `json:"accessExpiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+	Scope            string    `json:"scope,omitempty"` // 令牌权限范围，为空表示不受限
+}
+
+func generateToken(user *entity.SysAdmin, tokenType, sessionID, scope string, version int64, impersonator *Impersonator, expireDuration time.Duration) (string, time.Time, error) {
+	// 令牌的唯一标识 jti
+	jti, err := utils.RandomHex(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	now := time.Now()
+	expiresAt := now.Add(expireDuration)
 	claims := &CustomClaims{
 		JwtAdmin: entity.JwtAdmin{
 			ID:       user.ID,
@@ -34,20 +71,59 @@ func GenerateToken(user *entity.SysAdmin) (string, error) {
 			Phone:    user.Phone,
 			Note:     user.Note,
 		},
+		TokenType:    tokenType,
+		TokenVersion: version,
+		SessionID:    sessionID,
+		Scope:        scope,
+		Impersonator: impersonator,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpireDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "go-admin",
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    issuer,
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(TokenSecret))
+	token := jwt.NewWithClaims(signingKey.method, claims)
+	if signingKey.kid != "" {
+		token.Header["kid"] = signingKey.kid
+	}
+	tokenString, err := token.SignedString(signingKey.signKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return tokenString, expiresAt, nil
+}
+
+// GenerateTokenPair 为登录会话生成访问令牌与刷新令牌，scope 为空表示令牌不受限
+func GenerateTokenPair(user *entity.SysAdmin, sessionID, scope string, version int64) (*TokenPair, error) {
+	return generateTokenPair(user, sessionID, scope, version, nil)
+}
+
+// GenerateImpersonationTokenPair 为模拟登录会话生成令牌对，令牌同时携带被模拟的用户和发起人的身份
+func GenerateImpersonationTokenPair(user *entity.SysAdmin, sessionID string, version int64, impersonator *Impersonator) (*TokenPair, error) {
+	return generateTokenPair(user, sessionID, "", version, impersonator)
+}
+
+func generateTokenPair(user *entity.SysAdmin, sessionID, scope string, version int64, impersonator *Impersonator) (*TokenPair, error) {
+	accessToken, accessExpiresAt, err := generateToken(user, TokenTypeAccess, sessionID, scope, version, impersonator, accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, refreshExpiresAt, err := generateToken(user, TokenTypeRefresh, sessionID, scope, version, impersonator, refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+		Scope:            scope,
+	}, nil
 }
 
 func ParseToken(tokenString string) (*CustomClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(t *jwt.Token) (any, error) {
-		return []byte(TokenSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, keyFunc, jwt.WithIssuer(issuer))
 	if err != nil {
 		return nil, ErrInvalid
 	}
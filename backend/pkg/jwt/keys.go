@@ -0,0 +1,194 @@
+// 签名密钥管理，支持 HS256、RS256、EdDSA，通过 kid 选择验证密钥实现密钥轮换
+
+package jwt
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"go-admin-server/common/config"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultIssuer     = "go-admin"
+	defaultAccessTTL  = 2 * time.Hour      // 访问令牌默认有效期
+	defaultRefreshTTL = 7 * 24 * time.Hour // 刷新令牌默认有效期
+
+	defaultImpersonationTTL = time.Hour // 模拟登录会话默认最长有效期
+)
+
+type key struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   any // 签名密钥，只有当前密钥才有
+	verifyKey any // 验证密钥
+}
+
+var (
+	issuer     = defaultIssuer
+	accessTTL  = defaultAccessTTL
+	refreshTTL = defaultRefreshTTL
+	signingKey *key            // 当前用于签名的密钥
+	verifyKeys map[string]*key // 所有可用于验证的密钥，key 为 kid
+
+	impersonationTTL = defaultImpersonationTTL
+)
+
+// Setup 根据配置加载签名密钥和验证密钥，配置错误时直接 panic
+func Setup(cfg config.Jwt) {
+	if cfg.Issuer != "" {
+		issuer = cfg.Issuer
+	}
+	if cfg.AccessTTL > 0 {
+		accessTTL = cfg.AccessTTL
+	}
+	if cfg.RefreshTTL > 0 {
+		refreshTTL = cfg.RefreshTTL
+	}
+	if cfg.ImpersonationTTL > 0 {
+		impersonationTTL = cfg.ImpersonationTTL
+	}
+
+	current, err := loadSigningKey(cfg)
+	if err != nil {
+		panic(fmt.Errorf("failed to load jwt signing key: %w", err))
+	}
+	keys := map[string]*key{current.kid: current}
+	for _, vk := range cfg.VerifyKeys {
+		if _, exists := keys[vk.Kid]; exists {
+			panic(fmt.Errorf("duplicate jwt kid %q", vk.Kid))
+		}
+		k, err := loadVerifyKey(vk)
+		if err != nil {
+			panic(fmt.Errorf("failed to load jwt verify key %q: %w", vk.Kid, err))
+		}
+		keys[k.kid] = k
+	}
+	signingKey = current
+	verifyKeys = keys
+}
+
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case "", "HS256":
+		return jwt.SigningMethodHS256, nil
+	case "RS256":
+		return jwt.SigningMethodRS256, nil
+	case "EdDSA":
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+}
+
+// 加载当前签名密钥
+func loadSigningKey(cfg config.Jwt) (*key, error) {
+	method, err := signingMethod(cfg.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	k := &key{kid: cfg.Kid, method: method}
+	if method == jwt.SigningMethodHS256 {
+		if cfg.Secret == "" {
+			return nil, errors.New("jwt.secret is required for HS256")
+		}
+		k.signKey = []byte(cfg.Secret)
+		k.verifyKey = []byte(cfg.Secret)
+		return k, nil
+	}
+
+	if cfg.PrivateKeyFile == "" {
+		return nil, errors.New("jwt.private_key_file is required for " + method.Alg())
+	}
+	pemBytes, err := os.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	var signer crypto.Signer
+	if method == jwt.SigningMethodRS256 {
+		signer, err = jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+	} else {
+		var privateKey crypto.PrivateKey
+		privateKey, err = jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err == nil {
+			signer = privateKey.(crypto.Signer)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	k.signKey = signer
+
+	// 未配置公钥文件时，由私钥推导公钥
+	if cfg.PublicKeyFile == "" {
+		k.verifyKey = signer.Public()
+		return k, nil
+	}
+	k.verifyKey, err = loadPublicKey(method, cfg.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// 加载只用于验证的旧密钥
+func loadVerifyKey(cfg config.JwtVerifyKey) (*key, error) {
+	method, err := signingMethod(cfg.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	k := &key{kid: cfg.Kid, method: method}
+	if method == jwt.SigningMethodHS256 {
+		if cfg.Secret == "" {
+			return nil, errors.New("secret is required for HS256")
+		}
+		k.verifyKey = []byte(cfg.Secret)
+		return k, nil
+	}
+	if cfg.PublicKeyFile == "" {
+		return nil, errors.New("public_key_file is required for " + method.Alg())
+	}
+	k.verifyKey, err = loadPublicKey(method, cfg.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func loadPublicKey(method jwt.SigningMethod, filename string) (any, error) {
+	pemBytes, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if method == jwt.SigningMethodRS256 {
+		return jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+	}
+	return jwt.ParseEdPublicKeyFromPEM(pemBytes)
+}
+
+// RefreshTTL 刷新令牌有效期，登录会话的有效期与之一致
+func RefreshTTL() time.Duration {
+	return refreshTTL
+}
+
+// ImpersonationTTL 模拟登录会话从开始模拟起的最长有效期，刷新令牌不会延长
+func ImpersonationTTL() time.Duration {
+	return impersonationTTL
+}
+
+// 根据令牌头部的 kid 选择验证密钥，并校验令牌的签名算法与密钥一致
+func keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	k, ok := verifyKeys[kid]
+	if !ok {
+		return nil, ErrInvalid
+	}
+	if t.Method.Alg() != k.method.Alg() {
+		return nil, ErrInvalid
+	}
+	return k.verifyKey, nil
+}
@@ -0,0 +1,269 @@
+// LDAP / Active Directory 客户端：使用服务账号查询用户后以用户DN绑定验证密码，以及分页查询全部用户用于目录同步
+
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"go-admin-server/common/config"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+const (
+	defaultTimeout           = 10 * time.Second
+	defaultUserFilter        = "(uid=%s)"
+	defaultSyncFilter        = "(objectClass=person)"
+	defaultUsernameAttribute = "uid"
+	defaultNicknameAttribute = "cn"
+	defaultEmailAttribute    = "mail"
+	defaultPhoneAttribute    = "telephoneNumber"
+	defaultGroupAttribute    = "memberOf"
+
+	searchPageSize = 500
+
+	adAccountDisable = 0x2 // userAccountControl 中的 ACCOUNTDISABLE 标志位
+)
+
+var (
+	ErrDisabled           = errors.New("ldap is disabled")
+	ErrUserNotFound       = errors.New("ldap user not found")
+	ErrInvalidCredentials = errors.New("ldap invalid credentials")
+)
+
+// Entry 目录中的用户
+type Entry struct {
+	DN       string   `json:"dn"`
+	Username string   `json:"username"`
+	Nickname string   `json:"nickname"`
+	Email    string   `json:"email"`
+	Phone    string   `json:"phone"`
+	Groups   []string `json:"groups"`   // 所属组的DN
+	Disabled bool     `json:"disabled"` // AD 中已停用
+}
+
+var (
+	mu        sync.RWMutex
+	cfg       config.Ldap
+	tlsConfig *tls.Config
+)
+
+// Setup 检查配置、加载CA证书并填充默认值，不连接服务器
+func Setup(c config.Ldap) error {
+	if c.Enabled && (c.URL == "" || c.BaseDN == "") {
+		return errors.New("ldap url and base_dn are required")
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.UserFilter == "" {
+		c.UserFilter = defaultUserFilter
+	}
+	if !strings.Contains(c.UserFilter, "%s") {
+		return errors.New("ldap user_filter must contain %s")
+	}
+	if c.SyncFilter == "" {
+		c.SyncFilter = defaultSyncFilter
+	}
+	if c.UsernameAttribute == "" {
+		c.UsernameAttribute = defaultUsernameAttribute
+	}
+	if c.NicknameAttribute == "" {
+		c.NicknameAttribute = defaultNicknameAttribute
+	}
+	if c.EmailAttribute == "" {
+		c.EmailAttribute = defaultEmailAttribute
+	}
+	if c.PhoneAttribute == "" {
+		c.PhoneAttribute = defaultPhoneAttribute
+	}
+	if c.GroupAttribute == "" {
+		c.GroupAttribute = defaultGroupAttribute
+	}
+
+	tc := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return fmt.Errorf("read ldap ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("no certificate found in ldap ca file")
+		}
+		tc.RootCAs = pool
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	cfg = c
+	tlsConfig = tc
+	return nil
+}
+
+// Config 返回填充了默认值的配置
+func Config() config.Ldap {
+	mu.RLock()
+	defer mu.RUnlock()
+	return cfg
+}
+
+// Enabled 是否启用了LDAP认证
+func Enabled() bool {
+	return Config().Enabled
+}
+
+// 连接服务器并使用服务账号绑定
+func connect(c config.Ldap) (*goldap.Conn, error) {
+	mu.RLock()
+	tc := tlsConfig.Clone()
+	mu.RUnlock()
+	// StartTLS 需要显式指定校验证书使用的主机名
+	if u, err := url.Parse(c.URL); err == nil && tc.ServerName == "" {
+		tc.ServerName = u.Hostname()
+	}
+
+	conn, err := goldap.DialURL(c.URL,
+		goldap.DialWithDialer(&net.Dialer{Timeout: c.Timeout}),
+		goldap.DialWithTLSConfig(tc))
+	if err != nil {
+		return nil, fmt.Errorf("dial ldap: %w", err)
+	}
+	conn.SetTimeout(c.Timeout)
+	if c.StartTLS {
+		if err := conn.StartTLS(tc); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ldap start tls: %w", err)
+		}
+	}
+	if c.BindDN != "" {
+		err = conn.Bind(c.BindDN, c.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ldap service bind: %w", err)
+	}
+	return conn, nil
+}
+
+// 需要读取的属性
+func attributes(c config.Ldap) []string {
+	attrs := []string{c.UsernameAttribute, c.NicknameAttribute, c.EmailAttribute, c.PhoneAttribute, c.GroupAttribute}
+	if c.ActiveDirectory {
+		attrs = append(attrs, "userAccountControl")
+	}
+	return attrs
+}
+
+func toEntry(c config.Ldap, e *goldap.Entry) Entry {
+	entry := Entry{
+		DN:       e.DN,
+		Username: e.GetAttributeValue(c.UsernameAttribute),
+		Nickname: e.GetAttributeValue(c.NicknameAttribute),
+		Email:    e.GetAttributeValue(c.EmailAttribute),
+		Phone:    e.GetAttributeValue(c.PhoneAttribute),
+		Groups:   e.GetAttributeValues(c.GroupAttribute),
+	}
+	if c.ActiveDirectory {
+		uac, _ := strconv.ParseInt(e.GetAttributeValue("userAccountControl"), 10, 64)
+		entry.Disabled = uac&adAccountDisable != 0
+	}
+	return entry
+}
+
+// Authenticate 使用用户名和密码认证：查询用户DN后以该DN绑定。用户不存在时返回 ErrUserNotFound，
+// 密码错误时返回 ErrInvalidCredentials，其他错误表示服务器不可用或配置错误
+func Authenticate(username, password string) (*Entry, error) {
+	c := Config()
+	if !c.Enabled {
+		return nil, ErrDisabled
+	}
+	// 空密码会被服务器当作匿名绑定而成功
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	conn, err := connect(c)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	filter := strings.ReplaceAll(c.UserFilter, "%s", goldap.EscapeFilter(username))
+	request := goldap.NewSearchRequest(c.BaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		2, int(c.Timeout.Seconds()), false, filter, attributes(c), nil)
+	result, err := conn.Search(request)
+	if err != nil {
+		return nil, fmt.Errorf("search ldap user: %w", err)
+	}
+	if len(result.Entries) == 0 {
+		return nil, ErrUserNotFound
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("ldap user filter matched %d entries for %q", len(result.Entries), username)
+	}
+	entry := toEntry(c, result.Entries[0])
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ldap user bind: %w", err)
+	}
+	return &entry, nil
+}
+
+// SearchUsers 按同步过滤器分页查询目录中的全部用户，忽略没有用户名属性的条目
+func SearchUsers() ([]Entry, error) {
+	c := Config()
+	if !c.Enabled {
+		return nil, ErrDisabled
+	}
+	conn, err := connect(c)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	request := goldap.NewSearchRequest(c.BaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		0, 0, false, c.SyncFilter, attributes(c), nil)
+	result, err := conn.SearchWithPaging(request, searchPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("search ldap users: %w", err)
+	}
+	entries := make([]Entry, 0, len(result.Entries))
+	for _, e := range result.Entries {
+		entry := toEntry(c, e)
+		if entry.Username != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// InDN 判断 dn 是否等于 base 或位于 base 之下，忽略大小写和逗号两侧的空格
+func InDN(dn, base string) bool {
+	dn, base = normalizeDN(dn), normalizeDN(base)
+	if base == "" {
+		return false
+	}
+	return dn == base || strings.HasSuffix(dn, ","+base)
+}
+
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return strings.ToLower(strings.Join(parts, ","))
+}
@@ -0,0 +1,220 @@
+package ldap
+
+import (
+	"errors"
+	"go-admin-server/common/config"
+	"go-admin-server/pkg/ldap/ldaptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	testBaseDN       = "dc=example,dc=com"
+	testBindDN       = "cn=reader,dc=example,dc=com"
+	testBindPassword = "reader-secret"
+)
+
+func testEntries() []ldaptest.Entry {
+	return []ldaptest.Entry{
+		{
+			DN:       "uid=alice,ou=dev,dc=example,dc=com",
+			Password: "alice-secret",
+			Attributes: map[string][]string{
+				"objectClass":     {"person"},
+				"uid":             {"alice"},
+				"cn":              {"Alice"},
+				"mail":            {"alice@example.com"},
+				"telephoneNumber": {"138-0000-0000"},
+				"memberOf":        {"cn=devs,ou=groups,dc=example,dc=com", "cn=ops,ou=groups,dc=example,dc=com"},
+			},
+		},
+		{
+			DN:       "uid=bob,ou=sales,dc=example,dc=com",
+			Password: "bob-secret",
+			Attributes: map[string][]string{
+				"objectClass":        {"person"},
+				"uid":                {"bob"},
+				"cn":                 {"Bob"},
+				"userAccountControl": {"514"},
+			},
+		},
+		{
+			DN: "uid=carol,ou=sales,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"carol"},
+			},
+		},
+		// 没有用户名属性的条目在同步时忽略
+		{
+			DN:         "cn=printer,ou=devices,dc=example,dc=com",
+			Attributes: map[string][]string{"objectClass": {"person"}, "cn": {"printer"}},
+		},
+		// 不在 baseDN 之下
+		{
+			DN:         "uid=dave,dc=other,dc=com",
+			Password:   "dave-secret",
+			Attributes: map[string][]string{"objectClass": {"person"}, "uid": {"dave"}},
+		},
+	}
+}
+
+// 启动测试目录服务并按服务地址配置客户端
+func setupTestServer(t *testing.T, c config.Ldap) *ldaptest.Server {
+	t.Helper()
+	server, err := ldaptest.NewServer(testBindDN, testBindPassword, testEntries()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	c.Enabled = true
+	c.URL = server.URL
+	c.BaseDN = testBaseDN
+	c.BindDN = testBindDN
+	c.BindPassword = testBindPassword
+	c.Timeout = 5 * time.Second
+	if err := Setup(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Setup(config.Ldap{}) })
+	return server
+}
+
+func TestAuthenticate(t *testing.T) {
+	setupTestServer(t, config.Ldap{})
+
+	entry, err := Authenticate("alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	want := &Entry{
+		DN:       "uid=alice,ou=dev,dc=example,dc=com",
+		Username: "alice",
+		Nickname: "Alice",
+		Email:    "alice@example.com",
+		Phone:    "138-0000-0000",
+		Groups:   []string{"cn=devs,ou=groups,dc=example,dc=com", "cn=ops,ou=groups,dc=example,dc=com"},
+	}
+	if !reflect.DeepEqual(entry, want) {
+		t.Errorf("Authenticate() = %+v, want %+v", entry, want)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		want     error
+	}{
+		{"wrong password", "alice", "wrong", ErrInvalidCredentials},
+		{"empty password", "alice", "", ErrInvalidCredentials},
+		{"empty username", "", "alice-secret", ErrInvalidCredentials},
+		{"unknown user", "nobody", "secret", ErrUserNotFound},
+		{"outside base dn", "dave", "dave-secret", ErrUserNotFound},
+		{"filter injection is escaped", "*", "alice-secret", ErrUserNotFound},
+		{"entry without password", "carol", "anything", ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Authenticate(tt.username, tt.password); !errors.Is(err, tt.want) {
+				t.Errorf("Authenticate(%q) error = %v, want %v", tt.username, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateActiveDirectory(t *testing.T) {
+	setupTestServer(t, config.Ldap{ActiveDirectory: true})
+
+	entry, err := Authenticate("bob", "bob-secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if !entry.Disabled {
+		t.Error("userAccountControl 514 should be disabled")
+	}
+	entry, err = Authenticate("alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if entry.Disabled {
+		t.Error("entry without userAccountControl should not be disabled")
+	}
+}
+
+func TestAuthenticateAmbiguousFilter(t *testing.T) {
+	setupTestServer(t, config.Ldap{UserFilter: "(|(uid=%s)(objectClass=person))"})
+
+	_, err := Authenticate("alice", "alice-secret")
+	if err == nil || errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate() error = %v, want a configuration error", err)
+	}
+}
+
+func TestAuthenticateServiceBindFailed(t *testing.T) {
+	server := setupTestServer(t, config.Ldap{})
+	c := Config()
+	c.BindPassword = "wrong"
+	if err := Setup(c); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Authenticate("alice", "alice-secret")
+	if err == nil || errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate() error = %v, want a service bind error", err)
+	}
+	if server.Searches() != 0 {
+		t.Errorf("searches = %d, want 0", server.Searches())
+	}
+}
+
+func TestAuthenticateDisabled(t *testing.T) {
+	if err := Setup(config.Ldap{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Authenticate("alice", "alice-secret"); !errors.Is(err, ErrDisabled) {
+		t.Errorf("Authenticate() error = %v, want %v", err, ErrDisabled)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	server := setupTestServer(t, config.Ldap{})
+	// 每页2条，目录中的4个条目需要分两页查询
+	server.PageSize = 2
+
+	entries, err := SearchUsers()
+	if err != nil {
+		t.Fatalf("SearchUsers() error = %v", err)
+	}
+	var usernames []string
+	for _, entry := range entries {
+		usernames = append(usernames, entry.Username)
+	}
+	if want := []string{"alice", "bob", "carol"}; !reflect.DeepEqual(usernames, want) {
+		t.Errorf("SearchUsers() = %v, want %v", usernames, want)
+	}
+	if server.Searches() != 2 {
+		t.Errorf("searches = %d, want 2", server.Searches())
+	}
+}
+
+func TestInDN(t *testing.T) {
+	tests := []struct {
+		dn   string
+		base string
+		want bool
+	}{
+		{"uid=alice,ou=dev,dc=example,dc=com", "ou=dev,dc=example,dc=com", true},
+		{"uid=alice,ou=dev,dc=example,dc=com", "OU=Dev, DC=Example, DC=Com", true},
+		{"ou=dev,dc=example,dc=com", "ou=dev,dc=example,dc=com", true},
+		{"uid=alice,ou=devops,dc=example,dc=com", "ou=dev,dc=example,dc=com", false},
+		{"uid=alice,ou=mydev,dc=example,dc=com", "dev,dc=example,dc=com", false},
+		{"uid=alice,dc=example,dc=com", "", false},
+	}
+	for _, tt := range tests {
+		if got := InDN(tt.dn, tt.base); got != tt.want {
+			t.Errorf("InDN(%q, %q) = %v, want %v", tt.dn, tt.base, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+// 内存中的LDAP目录服务，用于测试目录认证和同步：只支持简单绑定、查询(含分页控制)和解绑，
+// 过滤器支持 and、or、not、等值和存在判断，属性名和值都忽略大小写
+
+package ldaptest
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// Entry 目录中的条目，Password 为空时不能以该条目绑定
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server 测试用的目录服务，服务账号绑定后才能查询
+type Server struct {
+	URL      string
+	PageSize int // 分页查询时每页最多返回的条目数，0 表示按客户端请求的大小
+
+	bindDN       string
+	bindPassword string
+	listener     net.Listener
+	wg           sync.WaitGroup
+
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	entries  []Entry
+	searches int
+}
+
+// NewServer 在本机随机端口启动目录服务
+func NewServer(bindDN, bindPassword string, entries ...Entry) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		URL:          "ldap://" + listener.Addr().String(),
+		bindDN:       bindDN,
+		bindPassword: bindPassword,
+		listener:     listener,
+		conns:        make(map[net.Conn]struct{}),
+		entries:      entries,
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close 停止服务，断开全部连接
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// SetEntries 替换目录中的全部条目
+func (s *Server) SetEntries(entries ...Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = entries
+}
+
+// Searches 收到的查询请求数，分页查询的每一页各算一次
+func (s *Server) Searches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.searches
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// 处理一个连接上的请求，收到解绑请求或连接断开时退出
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	var boundDN string
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		var responses []*ber.Packet
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			var code uint16
+			boundDN, code = s.bind(op)
+			responses = append(responses, envelope(messageID, result(goldap.ApplicationBindResponse, code), nil))
+		case goldap.ApplicationSearchRequest:
+			responses = s.search(messageID, boundDN, op, packet)
+		case goldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = append(responses, envelope(messageID, result(goldap.ApplicationExtendedResponse, goldap.LDAPResultUnwillingToPerform), nil))
+		}
+		for _, response := range responses {
+			if _, err := conn.Write(response.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// 简单绑定：DN 为空表示匿名绑定，返回绑定后的DN和结果码
+func (s *Server) bind(op *ber.Packet) (string, uint16) {
+	if len(op.Children) < 3 {
+		return "", goldap.LDAPResultProtocolError
+	}
+	dn, _ := op.Children[1].Value.(string)
+	password := op.Children[2].Data.String()
+	if dn == "" {
+		return "", goldap.LDAPResultSuccess
+	}
+	if strings.EqualFold(dn, s.bindDN) {
+		if password != s.bindPassword {
+			return "", goldap.LDAPResultInvalidCredentials
+		}
+		return dn, goldap.LDAPResultSuccess
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) && entry.Password != "" && entry.Password == password {
+			return entry.DN, goldap.LDAPResultSuccess
+		}
+	}
+	return "", goldap.LDAPResultInvalidCredentials
+}
+
+// 查询：只有服务账号可以查询，匹配 baseDN 之下的条目。带分页控制时 cookie 为下一页的起始位置
+func (s *Server) search(messageID int64, boundDN string, op, packet *ber.Packet) []*ber.Packet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searches++
+
+	if boundDN == "" || !strings.EqualFold(boundDN, s.bindDN) {
+		return []*ber.Packet{envelope(messageID, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultInsufficientAccessRights), nil)}
+	}
+	if len(op.Children) < 8 {
+		return []*ber.Packet{envelope(messageID, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError), nil)}
+	}
+	baseDN, _ := op.Children[0].Value.(string)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var attributes []string
+	for _, attr := range op.Children[7].Children {
+		if name, ok := attr.Value.(string); ok {
+			attributes = append(attributes, name)
+		}
+	}
+
+	var matched []Entry
+	for _, entry := range s.entries {
+		if inDN(entry.DN, baseDN) && match(filter, entry) {
+			matched = append(matched, entry)
+		}
+	}
+
+	code := uint16(goldap.LDAPResultSuccess)
+	var controls []*ber.Packet
+	if paging := pagingControl(packet); paging != nil {
+		offset, _ := strconv.Atoi(string(paging.Cookie))
+		size := int(paging.PagingSize)
+		if s.PageSize > 0 && s.PageSize < size {
+			size = s.PageSize
+		}
+		// 页大小为0表示放弃分页查询
+		total := len(matched)
+		offset = min(offset, total)
+		end := min(offset+size, total)
+		next := goldap.NewControlPaging(paging.PagingSize)
+		if size > 0 && end < total {
+			next.SetCookie([]byte(strconv.Itoa(end)))
+		}
+		matched = matched[offset:end]
+		controls = append(controls, next.Encode())
+	} else if sizeLimit > 0 && len(matched) > int(sizeLimit) {
+		matched = matched[:sizeLimit]
+		code = goldap.LDAPResultSizeLimitExceeded
+	}
+
+	responses := make([]*ber.Packet, 0, len(matched)+1)
+	for _, entry := range matched {
+		responses = append(responses, envelope(messageID, searchEntry(entry, attributes), nil))
+	}
+	return append(responses, envelope(messageID, result(goldap.ApplicationSearchResultDone, code), controls))
+}
+
+// 请求中的分页控制
+func pagingControl(packet *ber.Packet) *goldap.ControlPaging {
+	if len(packet.Children) < 3 {
+		return nil
+	}
+	for _, child := range packet.Children[2].Children {
+		control, err := goldap.DecodeControl(child)
+		if err != nil {
+			continue
+		}
+		if paging, ok := control.(*goldap.ControlPaging); ok {
+			return paging
+		}
+	}
+	return nil
+}
+
+// 判断条目是否匹配过滤器，不支持的过滤器类型不匹配
+func match(filter *ber.Packet, entry Entry) bool {
+	switch filter.Tag {
+	case goldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !match(child, entry) {
+				return false
+			}
+		}
+		return true
+	case goldap.FilterOr:
+		for _, child := range filter.Children {
+			if match(child, entry) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterNot:
+		return len(filter.Children) == 1 && !match(filter.Children[0], entry)
+	case goldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name, _ := filter.Children[0].Value.(string)
+		value, _ := filter.Children[1].Value.(string)
+		for _, v := range values(entry, name) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterPresent:
+		return len(values(entry, filter.Data.String())) > 0
+	}
+	return false
+}
+
+// 条目的属性值，属性名忽略大小写
+func values(entry Entry, name string) []string {
+	for attr, vals := range entry.Attributes {
+		if strings.EqualFold(attr, name) {
+			return vals
+		}
+	}
+	return nil
+}
+
+// 判断 dn 是否等于 base 或位于 base 之下
+func inDN(dn, base string) bool {
+	dn, base = strings.ToLower(dn), strings.ToLower(base)
+	return base == "" || dn == base || strings.HasSuffix(dn, ","+base)
+}
+
+func envelope(messageID int64, op *ber.Packet, controls []*ber.Packet) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	packet.AppendChild(op)
+	if len(controls) > 0 {
+		// 子节点的编码在添加时写入父节点，需要先组装好再添加
+		packet.AppendChild(controlsPacket(controls))
+	}
+	return packet
+}
+
+func controlsPacket(controls []*ber.Packet) *ber.Packet {
+	packet := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+	for _, control := range controls {
+		packet.AppendChild(control)
+	}
+	return packet
+}
+
+func result(tag ber.Tag, code uint16) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, goldap.ApplicationMap[uint8(tag)])
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, goldap.LDAPResultCodeMap[code], "Diagnostic Message"))
+	return packet
+}
+
+// 查询结果中的条目，attributes 为空时返回全部属性
+func searchEntry(entry Entry, attributes []string) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "Object Name"))
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, vals := range entry.Attributes {
+		if len(attributes) > 0 && !containsFold(attributes, name) {
+			continue
+		}
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range vals {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(set)
+		attrs.AppendChild(attr)
+	}
+	packet.AppendChild(attrs)
+	return packet
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+// 邮件发送：Mailer 接口可以替换为其他实现(如第三方邮件服务的API)，默认使用 SMTP 发送，模板使用 html/template 渲染
+
+package mailer
+
+import (
+	"errors"
+	"go-admin-server/common/config"
+	"sync"
+	"time"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var ErrDisabled = errors.New("mail is disabled")
+
+// Message 邮件内容，HTML 和 Text 至少有一个
+type Message struct {
+	To      []string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Mailer 邮件发送方式
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+var (
+	mu      sync.RWMutex
+	current Mailer
+	tmpl    *templates
+)
+
+// Setup 根据配置创建 SMTP 发送方式并加载邮件模板，不连接服务器
+func Setup(c config.Mail) error {
+	var m Mailer
+	if c.Enabled {
+		if c.Host == "" || c.Port == 0 || c.From == "" {
+			return errors.New("mail host, port and from are required")
+		}
+		switch c.Encryption {
+		case "":
+			c.Encryption = EncryptionStartTLS
+		case EncryptionStartTLS, EncryptionTLS, EncryptionNone:
+		default:
+			return errors.New("mail encryption must be starttls, tls or none")
+		}
+		if c.Timeout <= 0 {
+			c.Timeout = defaultTimeout
+		}
+		m = NewSMTPMailer(c)
+	}
+	t, err := loadTemplates(c.TemplateDir)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = m
+	tmpl = t
+	return nil
+}
+
+// SetMailer 替换发送方式，传入 nil 表示不发送邮件
+func SetMailer(m Mailer) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = m
+}
+
+// Enabled 是否可以发送邮件
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current != nil
+}
+
+// Send 发送邮件
+func Send(msg *Message) error {
+	mu.RLock()
+	m := current
+	mu.RUnlock()
+	if m == nil {
+		return ErrDisabled
+	}
+	if len(msg.To) == 0 {
+		return errors.New("mail has no recipient")
+	}
+	return m.Send(msg)
+}
+
+// SendTemplate 使用模板渲染邮件正文后发送，模板 name 对应模板目录中的 name.html 和 name.txt
+func SendTemplate(to []string, subject, name string, data any) error {
+	mu.RLock()
+	t := tmpl
+	mu.RUnlock()
+	if t == nil {
+		return errors.New("mail templates are not loaded")
+	}
+	html, text, err := t.render(name, data)
+	if err != nil {
+		return err
+	}
+	return Send(&Message{To: to, Subject: subject, HTML: html, Text: text})
+}
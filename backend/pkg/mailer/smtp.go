@@ -0,0 +1,182 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"go-admin-server/common/config"
+	"go-admin-server/common/utils"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP 连接的加密方式
+const (
+	EncryptionStartTLS = "starttls" // 明文连接后升级为 TLS，服务器不支持时拒绝发送
+	EncryptionTLS      = "tls"      // 直接使用 TLS 连接
+	EncryptionNone     = "none"     // 不加密，仅用于本地或测试环境
+)
+
+// SMTPMailer 通过 SMTP 服务器发送邮件
+type SMTPMailer struct {
+	cfg config.Mail
+}
+
+func NewSMTPMailer(c config.Mail) *SMTPMailer {
+	return &SMTPMailer{cfg: c}
+}
+
+// Send 每封邮件使用一个连接，连接和发送共用超时时间
+func (m *SMTPMailer) Send(msg *Message) error {
+	c := m.cfg
+	recipients := make([]string, 0, len(msg.To))
+	for _, to := range msg.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("invalid mail recipient %q: %w", to, err)
+		}
+		recipients = append(recipients, addr.Address)
+	}
+	data, err := m.build(msg, recipients)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	dialer := &net.Dialer{Timeout: c.Timeout}
+	tlsConfig := &tls.Config{ServerName: c.Host, InsecureSkipVerify: c.InsecureSkipVerify}
+	var conn net.Conn
+	if c.Encryption == EncryptionTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("dial smtp: %w", err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(c.Timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, c.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if c.Encryption == EncryptionStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp start tls: %w", err)
+		}
+	}
+	if c.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.Username, c.Password, c.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(c.From); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	for _, to := range recipients {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp rcpt to %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
+
+// 生成 MIME 邮件，同时有 HTML 和纯文本时使用 multipart/alternative
+func (m *SMTPMailer) build(msg *Message, recipients []string) ([]byte, error) {
+	var buf bytes.Buffer
+	from := (&mail.Address{Name: m.cfg.FromName, Address: m.cfg.From}).String()
+	messageID, err := utils.RandomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("From", from)
+	header.Set("To", strings.Join(recipients, ", "))
+	header.Set("Subject", mime.BEncoding.Encode("utf-8", msg.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	domain := m.cfg.From[strings.LastIndex(m.cfg.From, "@")+1:]
+	header.Set("Message-ID", fmt.Sprintf("<%s@%s>", messageID, domain))
+	header.Set("MIME-Version", "1.0")
+
+	switch {
+	case msg.HTML != "" && msg.Text != "":
+		mw := multipart.NewWriter(&buf)
+		header.Set("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+		writeHeader(&buf, header)
+		for _, part := range []struct{ contentType, body string }{
+			{"text/plain; charset=utf-8", msg.Text},
+			{"text/html; charset=utf-8", msg.HTML},
+		} {
+			w, err := mw.CreatePart(textproto.MIMEHeader{
+				"Content-Type":              {part.contentType},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := writeQuotedPrintable(w, part.body); err != nil {
+				return nil, err
+			}
+		}
+		if err := mw.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		contentType, body := "text/plain; charset=utf-8", msg.Text
+		if msg.HTML != "" {
+			contentType, body = "text/html; charset=utf-8", msg.HTML
+		}
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+		if err := writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var embedded embed.FS
+
+// 邮件模板：name.html 为 HTML 正文，name.txt 为纯文本正文，自定义目录中的模板优先于内置模板
+type templates struct {
+	html []*htmltemplate.Template
+	text []*texttemplate.Template
+}
+
+func loadTemplates(dir string) (*templates, error) {
+	builtin, err := fs.Sub(embedded, "templates")
+	if err != nil {
+		return nil, err
+	}
+	fsyses := []fs.FS{builtin}
+	if dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("mail template dir: %w", err)
+		}
+		fsyses = []fs.FS{os.DirFS(dir), builtin}
+	}
+
+	t := &templates{}
+	for _, fsys := range fsyses {
+		html := htmltemplate.New("")
+		if matches, _ := fs.Glob(fsys, "*.html"); len(matches) > 0 {
+			if html, err = html.ParseFS(fsys, "*.html"); err != nil {
+				return nil, fmt.Errorf("parse mail templates: %w", err)
+			}
+		}
+		text := texttemplate.New("")
+		if matches, _ := fs.Glob(fsys, "*.txt"); len(matches) > 0 {
+			if text, err = text.ParseFS(fsys, "*.txt"); err != nil {
+				return nil, fmt.Errorf("parse mail templates: %w", err)
+			}
+		}
+		t.html = append(t.html, html)
+		t.text = append(t.text, text)
+	}
+	return t, nil
+}
+
+// 渲染模板，没有对应的 HTML 或纯文本模板时该部分为空，两者都没有时返回错误
+func (t *templates) render(name string, data any) (string, string, error) {
+	var html, text bytes.Buffer
+	for _, set := range t.html {
+		if tpl := set.Lookup(name + ".html"); tpl != nil {
+			if err := tpl.Execute(&html, data); err != nil {
+				return "", "", fmt.Errorf("render mail template %s.html: %w", name, err)
+			}
+			break
+		}
+	}
+	for _, set := range t.text {
+		if tpl := set.Lookup(name + ".txt"); tpl != nil {
+			if err := tpl.Execute(&text, data); err != nil {
+				return "", "", fmt.Errorf("render mail template %s.txt: %w", name, err)
+			}
+			break
+		}
+	}
+	if html.Len() == 0 && text.Len() == 0 {
+		return "", "", fmt.Errorf("mail template %s not found", name)
+	}
+	return html.String(), text.String(), nil
+}
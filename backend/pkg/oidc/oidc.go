@@ -0,0 +1,226 @@
+// OIDC 单点登录客户端，使用授权码 + PKCE 流程，身份提供方的元数据在首次使用时发现并缓存
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-admin-server/common/config"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultProviderName  = "SSO"
+	defaultUsernameClaim = "preferred_username"
+	defaultEmailClaim    = "email"
+	defaultNameClaim     = "name"
+	defaultGroupsClaim   = "groups"
+	defaultStateTTL      = 10 * time.Minute
+
+	httpTimeout = 10 * time.Second // 请求身份提供方的超时时间
+)
+
+var ErrDisabled = errors.New("oidc is disabled")
+
+// Identity 身份提供方返回的用户身份，来自 ID Token 中的声明
+type Identity struct {
+	Issuer        string   // 签发方
+	Subject       string   // 用户在身份提供方中的唯一标识
+	Username      string   // 用户名
+	Email         string   // 邮箱
+	EmailVerified bool     // 邮箱是否已验证
+	Name          string   // 显示名称
+	Groups        []string // 所属用户组
+}
+
+// 发现身份提供方元数据后创建的客户端
+type client struct {
+	oauth2   oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+var (
+	cfg        config.Oidc
+	httpClient = &http.Client{Timeout: httpTimeout}
+
+	mu      sync.Mutex
+	current *client // 尚未成功发现元数据时为nil，下次使用时重试
+)
+
+// Setup 检查配置并填充默认值，不访问身份提供方，启动时身份提供方不可用不影响服务启动
+func Setup(c config.Oidc) error {
+	if c.Enabled {
+		if c.Issuer == "" || c.ClientID == "" || c.RedirectURL == "" {
+			return errors.New("oidc issuer, client_id and redirect_url are required")
+		}
+		for _, field := range c.MatchBy {
+			if field != "username" && field != "email" {
+				return fmt.Errorf("invalid oidc match_by %q", field)
+			}
+		}
+	}
+	if c.ProviderName == "" {
+		c.ProviderName = defaultProviderName
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = defaultUsernameClaim
+	}
+	if c.EmailClaim == "" {
+		c.EmailClaim = defaultEmailClaim
+	}
+	if c.NameClaim == "" {
+		c.NameClaim = defaultNameClaim
+	}
+	if c.GroupsClaim == "" {
+		c.GroupsClaim = defaultGroupsClaim
+	}
+	if c.StateTTL <= 0 {
+		c.StateTTL = defaultStateTTL
+	}
+	scopes := []string{gooidc.ScopeOpenID}
+	for _, scope := range c.Scopes {
+		if scope != gooidc.ScopeOpenID {
+			scopes = append(scopes, scope)
+		}
+	}
+	c.Scopes = scopes
+
+	mu.Lock()
+	defer mu.Unlock()
+	cfg = c
+	current = nil
+	return nil
+}
+
+// Config 返回填充了默认值的配置
+func Config() config.Oidc {
+	mu.Lock()
+	defer mu.Unlock()
+	return cfg
+}
+
+// Enabled 是否启用了单点登录
+func Enabled() bool {
+	return Config().Enabled
+}
+
+// 获取客户端，首次使用时发现身份提供方元数据
+func getClient(ctx context.Context) (*client, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !cfg.Enabled {
+		return nil, ErrDisabled
+	}
+	if current != nil {
+		return current, nil
+	}
+	ctx = gooidc.ClientContext(ctx, httpClient)
+	provider, err := gooidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discover oidc provider: %w", err)
+	}
+	current = &client{
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       cfg.Scopes,
+		},
+		verifier: provider.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+	}
+	return current, nil
+}
+
+// AuthCodeURL 生成跳转到身份提供方的授权地址，verifier 为 PKCE 校验码
+func AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	c, err := getClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	return c.oauth2.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// GenerateVerifier 生成 PKCE 校验码
+func GenerateVerifier() string {
+	return oauth2.GenerateVerifier()
+}
+
+// Exchange 使用授权码换取令牌，校验 ID Token 的签名、受众、有效期和 nonce 后返回用户身份
+func Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	c, err := getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = gooidc.ClientContext(ctx, httpClient)
+	token, err := c.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+	idToken, err := c.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("decode id_token claims: %w", err)
+	}
+	return identityFromClaims(idToken.Issuer, idToken.Subject, claims), nil
+}
+
+// 按配置的声明名称提取用户身份
+func identityFromClaims(issuer, subject string, claims map[string]any) *Identity {
+	c := Config()
+	identity := &Identity{
+		Issuer:   issuer,
+		Subject:  subject,
+		Username: stringClaim(claims, c.UsernameClaim),
+		Email:    stringClaim(claims, c.EmailClaim),
+		Name:     stringClaim(claims, c.NameClaim),
+		Groups:   stringsClaim(claims, c.GroupsClaim),
+	}
+	// 部分身份提供方把 email_verified 返回为字符串
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+	return identity
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	value, _ := claims[name].(string)
+	return strings.TrimSpace(value)
+}
+
+// 用户组声明可能是字符串数组，也可能是单个字符串
+func stringsClaim(claims map[string]any, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
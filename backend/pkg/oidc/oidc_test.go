@@ -0,0 +1,106 @@
+package oidc
+
+import (
+	"go-admin-server/common/config"
+	"reflect"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	valid := config.Oidc{Enabled: true, Issuer: "https://idp.example.com", ClientID: "admin", RedirectURL: "https://admin.example.com/sso"}
+	tests := []struct {
+		name    string
+		modify  func(c *config.Oidc)
+		wantErr bool
+	}{
+		{"valid", nil, false},
+		{"disabled without issuer", func(c *config.Oidc) { *c = config.Oidc{} }, false},
+		{"missing issuer", func(c *config.Oidc) { c.Issuer = "" }, true},
+		{"missing client id", func(c *config.Oidc) { c.ClientID = "" }, true},
+		{"missing redirect url", func(c *config.Oidc) { c.RedirectURL = "" }, true},
+		{"match by username and email", func(c *config.Oidc) { c.MatchBy = []string{"username", "email"} }, false},
+		{"invalid match by", func(c *config.Oidc) { c.MatchBy = []string{"phone"} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			if tt.modify != nil {
+				tt.modify(&c)
+			}
+			if err := Setup(c); (err != nil) != tt.wantErr {
+				t.Errorf("Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	t.Cleanup(func() { _ = Setup(config.Oidc{}) })
+}
+
+func TestSetupDefaults(t *testing.T) {
+	if err := Setup(config.Oidc{Scopes: []string{"profile", "openid", "email"}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Setup(config.Oidc{}) })
+	c := Config()
+	if c.ProviderName != defaultProviderName || c.UsernameClaim != defaultUsernameClaim || c.GroupsClaim != defaultGroupsClaim {
+		t.Errorf("defaults not applied: %+v", c)
+	}
+	// openid 只出现一次且在最前
+	if want := []string{"openid", "profile", "email"}; !reflect.DeepEqual(c.Scopes, want) {
+		t.Errorf("Scopes = %v, want %v", c.Scopes, want)
+	}
+}
+
+func TestIdentityFromClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.Oidc
+		claims map[string]any
+		want   Identity
+	}{
+		{
+			name: "default claims",
+			claims: map[string]any{
+				"preferred_username": " alice ",
+				"email":              "alice@example.com",
+				"email_verified":     true,
+				"name":               "Alice",
+				"groups":             []any{"devs", "ops", 1},
+			},
+			want: Identity{Username: "alice", Email: "alice@example.com", EmailVerified: true, Name: "Alice", Groups: []string{"devs", "ops"}},
+		},
+		{
+			name:   "custom claims",
+			config: config.Oidc{UsernameClaim: "upn", GroupsClaim: "roles"},
+			claims: map[string]any{"upn": "alice@corp", "preferred_username": "ignored", "roles": "admins"},
+			want:   Identity{Username: "alice@corp", Groups: []string{"admins"}},
+		},
+		{
+			name:   "email verified as string",
+			claims: map[string]any{"email": "alice@example.com", "email_verified": "true"},
+			want:   Identity{Email: "alice@example.com", EmailVerified: true},
+		},
+		{
+			name:   "email not verified",
+			claims: map[string]any{"email": "alice@example.com", "email_verified": "false"},
+			want:   Identity{Email: "alice@example.com"},
+		},
+		{
+			name:   "wrong claim types",
+			claims: map[string]any{"preferred_username": 1, "groups": map[string]any{"a": "b"}},
+			want:   Identity{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Setup(tt.config); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = Setup(config.Oidc{}) })
+			tt.want.Issuer, tt.want.Subject = "https://idp.example.com", "sub-1"
+			got := identityFromClaims("https://idp.example.com", "sub-1", tt.claims)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("identityFromClaims() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
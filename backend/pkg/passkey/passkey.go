@@ -0,0 +1,191 @@
+// 通行密钥(WebAuthn)：依赖方配置以及注册、验证流程的封装，流程中的会话数据由调用方保存
+
+package passkey
+
+import (
+	"bytes"
+	"errors"
+	"go-admin-server/common/config"
+	"strconv"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	defaultRPName      = "go-admin"
+	defaultTimeout     = 5 * time.Minute
+	defaultMaxPasskeys = 10
+)
+
+var ErrDisabled = errors.New("webauthn is disabled")
+
+// User 注册和验证时的用户，用户句柄为用户id，不包含用户名等个人信息
+type User struct {
+	ID          uint
+	Name        string
+	DisplayName string
+	Credentials []webauthn.Credential
+}
+
+func (u *User) WebAuthnID() []byte {
+	return UserHandle(u.ID)
+}
+
+func (u *User) WebAuthnName() string {
+	return u.Name
+}
+
+func (u *User) WebAuthnDisplayName() string {
+	return u.DisplayName
+}
+
+func (u *User) WebAuthnCredentials() []webauthn.Credential {
+	return u.Credentials
+}
+
+var (
+	cfg     config.WebAuthn
+	current *webauthn.WebAuthn // 未启用时为nil
+)
+
+// Setup 检查配置并填充默认值
+func Setup(c config.WebAuthn) error {
+	if c.RPName == "" {
+		c.RPName = defaultRPName
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.MaxPasskeys <= 0 {
+		c.MaxPasskeys = defaultMaxPasskeys
+	}
+	cfg, current = c, nil
+	if !c.Enabled {
+		return nil
+	}
+	if c.RPID == "" || len(c.Origins) == 0 {
+		return errors.New("webauthn rp_id and origins are required")
+	}
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: c.Timeout, TimeoutUVD: c.Timeout}
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          c.RPID,
+		RPDisplayName: c.RPName,
+		RPOrigins:     c.Origins,
+		// 通行密钥保存在认证器中(可发现凭据)，免密码登录时由认证器提供用户句柄
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationPreferred,
+		},
+		AttestationPreference: protocol.PreferNoAttestation,
+		Timeouts:              webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return err
+	}
+	current = w
+	return nil
+}
+
+// Config 填充默认值后的配置
+func Config() config.WebAuthn {
+	return cfg
+}
+
+// Enabled 是否已启用通行密钥
+func Enabled() bool {
+	return current != nil
+}
+
+// UserHandle 用户id对应的用户句柄
+func UserHandle(adminId uint) []byte {
+	return []byte(strconv.FormatUint(uint64(adminId), 10))
+}
+
+// ParseUserHandle 从用户句柄中解析用户id
+func ParseUserHandle(handle []byte) (uint, error) {
+	id, err := strconv.ParseUint(string(handle), 10, 64)
+	if err != nil || id == 0 || !bytes.Equal(handle, UserHandle(uint(id))) {
+		return 0, errors.New("invalid user handle")
+	}
+	return uint(id), nil
+}
+
+// BeginRegistration 开始注册，排除用户已注册的凭据，避免同一认证器重复注册
+func BeginRegistration(user *User) (*protocol.CredentialCreation, *webauthn.SessionData, error) {
+	if current == nil {
+		return nil, nil, ErrDisabled
+	}
+	exclusions := webauthn.Credentials(user.Credentials).CredentialDescriptors()
+	return current.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+}
+
+// FinishRegistration 校验认证器返回的注册数据，返回新的凭据
+func FinishRegistration(user *User, session webauthn.SessionData, body []byte) (*webauthn.Credential, error) {
+	if current == nil {
+		return nil, ErrDisabled
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(body)
+	if err != nil {
+		return nil, err
+	}
+	return current.CreateCredential(user, session, parsed)
+}
+
+// BeginLogin 开始验证已知用户，用于两步验证，只允许使用用户已注册的凭据
+func BeginLogin(user *User) (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	if current == nil {
+		return nil, nil, ErrDisabled
+	}
+	return current.BeginLogin(user)
+}
+
+// FinishLogin 校验已知用户的验证数据，返回使用的凭据，签名计数已更新
+func FinishLogin(user *User, session webauthn.SessionData, body []byte) (*webauthn.Credential, error) {
+	if current == nil {
+		return nil, ErrDisabled
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(body)
+	if err != nil {
+		return nil, err
+	}
+	return current.ValidateLogin(user, session, parsed)
+}
+
+// BeginDiscoverableLogin 开始免密码登录，不指定用户，要求认证器验证用户(PIN、指纹等)
+func BeginDiscoverableLogin() (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	if current == nil {
+		return nil, nil, ErrDisabled
+	}
+	return current.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+}
+
+// FinishDiscoverableLogin 校验免密码登录的验证数据，findUser 根据用户id查询用户及其凭据
+func FinishDiscoverableLogin(session webauthn.SessionData, body []byte, findUser func(adminId uint) (*User, error)) (*User, *webauthn.Credential, error) {
+	if current == nil {
+		return nil, nil, ErrDisabled
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	var found *User
+	handler := func(_, userHandle []byte) (webauthn.User, error) {
+		adminId, err := ParseUserHandle(userHandle)
+		if err != nil {
+			return nil, err
+		}
+		if found, err = findUser(adminId); err != nil {
+			return nil, err
+		}
+		return found, nil
+	}
+	// 验证失败时也返回已找到的用户，便于记录登录日志
+	_, credential, err := current.ValidatePasskeyLogin(handler, session, parsed)
+	if err != nil {
+		return found, nil, err
+	}
+	return found, credential, nil
+}
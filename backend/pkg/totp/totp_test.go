@@ -0,0 +1,96 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录B 的 SHA1 测试密钥
+const rfcKey = "12345678901234567890"
+
+// RFC 6238 附录B 的 SHA1 测试向量，验证码为8位，这里取后6位
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestGenerateRFC6238(t *testing.T) {
+	for _, tt := range rfcVectors {
+		if got := generate([]byte(rfcKey), tt.unix/period); got != tt.code {
+			t.Errorf("generate(T=%d) = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := b32.EncodeToString([]byte(rfcKey))
+	for _, tt := range rfcVectors {
+		step, ok := Validate(secret, tt.code, time.Unix(tt.unix, 0))
+		if !ok || step != tt.unix/period {
+			t.Errorf("Validate(T=%d, %s) = (%d, %v), want (%d, true)", tt.unix, tt.code, step, ok, tt.unix/period)
+		}
+	}
+
+	// 59秒对应第1个时间步，验证码 287082
+	now := time.Unix(59, 0)
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		t      time.Time
+		want   bool
+	}{
+		{"valid", secret, "287082", now, true},
+		{"lowercase secret", strings.ToLower(secret), "287082", now, true},
+		{"surrounding spaces", secret, " 287082 ", now, true},
+		{"previous step", secret, "287082", now.Add(period * time.Second), true},
+		{"next step", secret, "287082", now.Add(-period * time.Second), true},
+		{"outside skew", secret, "287082", now.Add(2 * period * time.Second), false},
+		{"wrong code", secret, "287083", now, false},
+		{"too short", secret, "28708", now, false},
+		{"too long", secret, "2870820", now, false},
+		{"invalid secret", "not base32!", "287082", now, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Validate(tt.secret, tt.code, tt.t); ok != tt.want {
+				t.Errorf("Validate() = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := b32.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	if len(key) != secretSize {
+		t.Errorf("secret size = %d, want %d", len(key), secretSize)
+	}
+	code := generate(key, time.Now().Unix()/period)
+	if _, ok := Validate(secret, code, time.Now()); !ok {
+		t.Error("generated secret does not validate its own code")
+	}
+}
+
+func TestKeyURI(t *testing.T) {
+	uri := KeyURI("go-admin", "alice", "JBSWY3DPEHPK3PXP")
+	for _, want := range []string{"otpauth://totp/go-admin:alice?", "secret=JBSWY3DPEHPK3PXP", "issuer=go-admin", "digits=6", "period=30"} {
+		if !strings.Contains(uri, want) {
+			t.Errorf("KeyURI() = %s, missing %s", uri, want)
+		}
+	}
+}
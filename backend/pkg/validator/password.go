@@ -0,0 +1,70 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"go-admin-server/common/config"
+	"strings"
+	"unicode"
+)
+
+const defaultPasswordMinLength = 8
+
+// 当前生效的密码策略，未调用 SetupPasswordPolicy 时为至少8位且包含字母和数字
+var passwordPolicy = config.PasswordPolicy{
+	MinLength:     defaultPasswordMinLength,
+	RequireLetter: true,
+	RequireDigit:  true,
+}
+
+// SetupPasswordPolicy 设置密码策略
+func SetupPasswordPolicy(cfg config.PasswordPolicy) {
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = defaultPasswordMinLength
+	}
+	passwordPolicy = cfg
+}
+
+// PasswordPolicy 获取当前生效的密码策略
+func PasswordPolicy() config.PasswordPolicy {
+	return passwordPolicy
+}
+
+// CheckPassword 检查密码是否符合密码策略，不符合时返回原因
+func CheckPassword(password string) error {
+	policy := passwordPolicy
+	if len([]rune(password)) < policy.MinLength {
+		return fmt.Errorf("密码长度不能少于 %d 个字符", policy.MinLength)
+	}
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSymbol = true
+		}
+	}
+	if policy.RequireLetter && !hasLower && !hasUpper {
+		return errors.New("密码必须包含字母")
+	}
+	if policy.RequireMixedCase && (!hasLower || !hasUpper) {
+		return errors.New("密码必须同时包含大写和小写字母")
+	}
+	if policy.RequireDigit && !hasDigit {
+		return errors.New("密码必须包含数字")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		return errors.New("密码必须包含特殊字符")
+	}
+	for _, banned := range policy.BannedPasswords {
+		if strings.EqualFold(password, banned) {
+			return errors.New("密码过于简单，请更换")
+		}
+	}
+	return nil
+}
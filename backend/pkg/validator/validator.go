@@ -36,23 +36,8 @@ func registerCustomValidations(v *validator.Validate) {
 		return len(phone) == 11 && strings.HasPrefix(phone, "1")
 	})
 
-	// 自定义密码强度验证
+	// 自定义密码强度验证，规则由密码策略配置决定
 	v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
-		password := fl.Field().String()
-		if len(password) < 8 {
-			return false
-		}
-		// 至少包含字母和数字
-		hasLetter := false
-		hasDigit := false
-		for _, char := range password {
-			if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') {
-				hasLetter = true
-			}
-			if char >= '0' && char <= '9' {
-				hasDigit = true
-			}
-		}
-		return hasLetter && hasDigit
+		return CheckPassword(fl.Field().String()) == nil
 	})
 }
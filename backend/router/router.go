@@ -20,87 +20,176 @@ func SetupRouter() *gin.Engine {
 	router.StaticFS("/uploads", http.Dir("./uploads"))
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFile.Handler))
 
-	router.GET("/api/captcha", controller.Captcha) // 生成验证码
-	router.POST("/api/login", controller.Login)    // 用户登录
+	router.GET("/api/captcha", controller.Captcha)                           // 生成验证码
+	router.POST("/api/login", controller.Login)                              // 用户登录
+	router.POST("/api/token/refresh", controller.RefreshToken)               // 刷新令牌
+	router.POST("/api/login/twoFactor", controller.LoginTwoFactor)           // 两步验证登录
+	router.POST("/api/login/twoFactorSetup", controller.LoginSetupTwoFactor) // 登录时绑定验证器
+	router.GET("/api/oidc/config", controller.GetOidcConfig)                 // 单点登录配置
+	router.GET("/api/oidc/authorize", controller.OidcAuthorize)              // 发起单点登录
+	router.POST("/api/oidc/login", controller.OidcLogin)                     // 单点登录回调
+	router.POST("/api/password/forgot", controller.ForgotPassword)           // 找回密码
+	router.POST("/api/password/reset", controller.ResetPasswordByMail)       // 通过邮件链接重置密码
+	router.POST("/api/invitation/info", controller.GetInvitationInfo)        // 查询邀请信息
+	router.POST("/api/invitation/accept", controller.AcceptInvitation)       // 接受邀请并激活账号
+
+	// 通行密钥登录，两步验证时使用登录返回的挑战令牌
+	router.POST("/api/login/passkeyOptions", controller.BeginPasskeyLogin)              // 发起通行密钥登录
+	router.POST("/api/login/passkey", controller.PasskeyLogin)                          // 通行密钥登录
+	router.POST("/api/login/twoFactorPasskeyOptions", controller.BeginTwoFactorPasskey) // 发起通行密钥两步验证
+	router.POST("/api/login/twoFactorPasskey", controller.LoginTwoFactorPasskey)        // 通行密钥两步验证登录
 
 	// 私有路由（需要认证）
+	// 管理类接口需通过 middleware.Permission 校验按钮权限(同时登记到 middleware/permission.go 的 routePermissions 中)，下拉列表、上传、个人资料等接口登录即可访问
+	// 路由组通过 middleware.LogModule 声明业务模块，路由通过 middleware.LogAction 声明业务操作，记录到操作日志中
 	private := router.Group("/api")
 	private.Use(middleware.JWTAuth(), middleware.OperationLog())
 	{
-		private.POST("/upload", controller.Upload) // 单图片上传
+		private.POST("/upload", middleware.LogAction("上传图片"), controller.Upload)
+		private.POST("/logout", middleware.LogAction("退出登录"), controller.Logout)
+		private.POST("/endImpersonation", middleware.LogAction("结束模拟登录"), controller.EndImpersonation)
+		// 当前登录用户
+		meGroup := private.Group("/me", middleware.LogModule("个人中心"))
+		{
+			meGroup.GET("/profile", middleware.LogAction("查询个人资料"), controller.GetMyProfile)
+			meGroup.GET("/menus", middleware.LogAction("查询左侧菜单"), controller.GetMyMenus)
+			meGroup.GET("/permissions", middleware.LogAction("查询权限列表"), controller.GetMyPermissions)
+			meGroup.GET("/sessions", middleware.LogAction("查询登录会话"), controller.GetMySessions)
+			meGroup.POST("/revokeSession", middleware.LogAction("吊销登录会话"), controller.RevokeSession)
+			meGroup.POST("/revokeOtherSessions", middleware.LogAction("吊销其他登录会话"), controller.RevokeOtherSessions)
+			meGroup.POST("/setupTwoFactor", middleware.LogAction("绑定验证器"), controller.SetupTwoFactor)
+			meGroup.POST("/enableTwoFactor", middleware.LogAction("开启两步验证"), controller.EnableTwoFactor)
+			meGroup.POST("/disableTwoFactor", middleware.LogAction("关闭两步验证"), controller.DisableTwoFactor)
+			meGroup.POST("/regenerateRecoveryCodes", middleware.LogAction("重新生成恢复码"), controller.RegenerateRecoveryCodes)
+			meGroup.GET("/apiTokens", middleware.LogAction("查询个人API令牌"), controller.GetMyApiTokenList)
+			meGroup.POST("/createApiToken", middleware.LogAction("创建个人API令牌"), controller.CreateMyApiToken)
+			meGroup.POST("/revokeApiToken", middleware.LogAction("吊销个人API令牌"), controller.RevokeMyApiToken)
+			meGroup.GET("/passkeys", middleware.LogAction("查询个人通行密钥"), controller.GetMyPasskeys)
+			meGroup.POST("/beginPasskeyRegistration", middleware.LogAction("发起注册通行密钥"), controller.BeginPasskeyRegistration)
+			meGroup.POST("/finishPasskeyRegistration", middleware.LogAction("注册通行密钥"), controller.FinishPasskeyRegistration)
+			meGroup.POST("/renamePasskey", middleware.LogAction("修改通行密钥名称"), controller.RenamePasskey)
+			meGroup.POST("/deletePasskey", middleware.LogAction("删除通行密钥"), controller.DeletePasskey)
+		}
+
 		// 岗位管理
-		postGroup := private.Group("/postService")
+		postGroup := private.Group("/postService", middleware.LogModule("岗位管理"))
 		{
-			postGroup.POST("/createPost", controller.CreatePost)             // 创建岗位
-			postGroup.GET("/getPostList", controller.GetPostList)            // 查询岗位列表
-			postGroup.POST("/getPostById", controller.GetPostById)           // 根据id查询岗位信息
-			postGroup.POST("/updatePost", controller.UpdatePost)             // 修改岗位信息
-			postGroup.POST("/deletePost", controller.DeletePost)             // 根据id删除岗位
-			postGroup.POST("/batchDeletePosts", controller.BatchDeletePosts) // 批量删除岗位
-			postGroup.POST("/updatePostStatus", controller.UpdatePostStatus) // 修改岗位状态
-			postGroup.GET("/getPostDropdown", controller.GetPostDropdown)    // 岗位下拉列表
+			postGroup.POST("/createPost", middleware.LogAction("创建岗位"), middleware.Permission("system:post:add"), controller.CreatePost)
+			postGroup.GET("/getPostList", middleware.LogAction("查询岗位列表"), middleware.Permission("system:post:list"), controller.GetPostList)
+			postGroup.POST("/getPostById", middleware.LogAction("查询岗位详情"), middleware.Permission("system:post:query"), controller.GetPostById)
+			postGroup.POST("/updatePost", middleware.LogAction("修改岗位信息"), middleware.Permission("system:post:edit"), controller.UpdatePost)
+			postGroup.POST("/deletePost", middleware.LogAction("删除岗位"), middleware.Permission("system:post:remove"), controller.DeletePost)
+			postGroup.POST("/batchDeletePosts", middleware.LogAction("批量删除岗位"), middleware.Permission("system:post:remove"), controller.BatchDeletePosts)
+			postGroup.POST("/updatePostStatus", middleware.LogAction("修改岗位状态"), middleware.Permission("system:post:edit"), controller.UpdatePostStatus)
+			postGroup.GET("/getPostDropdown", middleware.LogAction("岗位下拉列表"), controller.GetPostDropdown)
 		}
 
 		// 部门管理
-		deptGroup := private.Group("/deptService")
+		deptGroup := private.Group("/deptService", middleware.LogModule("部门管理"))
 		{
-			deptGroup.POST("/createDept", controller.CreateDept)          // 创建部门
-			deptGroup.GET("/getDeptList", controller.GetDeptList)         // 查询部门列表
-			deptGroup.POST("/getDeptById", controller.GetDeptById)        // 根据id查询部门
-			deptGroup.POST("/updateDept", controller.UpdateDept)          // 修改部门信息
-			deptGroup.POST("/deleteDept", controller.DeleteDept)          // 根据id删除单个部门
-			deptGroup.GET("/getDeptDropdown", controller.GetDeptDropdown) // 部门下拉列表
+			deptGroup.POST("/createDept", middleware.LogAction("创建部门"), middleware.Permission("system:dept:add"), controller.CreateDept)
+			deptGroup.GET("/getDeptList", middleware.LogAction("查询部门列表"), middleware.Permission("system:dept:list"), controller.GetDeptList)
+			deptGroup.POST("/getDeptById", middleware.LogAction("查询部门详情"), middleware.Permission("system:dept:query"), controller.GetDeptById)
+			deptGroup.POST("/updateDept", middleware.LogAction("修改部门信息"), middleware.Permission("system:dept:edit"), controller.UpdateDept)
+			deptGroup.POST("/deleteDept", middleware.LogAction("删除部门"), middleware.Permission("system:dept:remove"), controller.DeleteDept)
+			deptGroup.GET("/getDeptDropdown", middleware.LogAction("部门下拉列表"), controller.GetDeptDropdown)
 		}
 
 		// 菜单管理
-		menuGroup := private.Group("/menuService")
+		menuGroup := private.Group("/menuService", middleware.LogModule("菜单管理"))
 		{
-			menuGroup.POST("/createMenu", controller.CreateMenu)          // 创建菜单
-			menuGroup.GET("/getMenuList", controller.GetMenuList)         // 查询菜单列表
-			menuGroup.POST("/getMenuById", controller.GetMenuById)        // 根据id查询菜单
-			menuGroup.POST("/updateMenu", controller.UpdateMenu)          // 修改菜单信息
-			menuGroup.POST("/deleteMenu", controller.DeleteMenu)          // 根据id删除单个菜单
-			menuGroup.GET("/getMenuDropdown", controller.GetMenuDropdown) // 菜单下拉列表
+			menuGroup.POST("/createMenu", middleware.LogAction("创建菜单"), middleware.Permission("system:menu:add"), controller.CreateMenu)
+			menuGroup.GET("/getMenuList", middleware.LogAction("查询菜单列表"), middleware.Permission("system:menu:list"), controller.GetMenuList)
+			menuGroup.POST("/getMenuById", middleware.LogAction("查询菜单详情"), middleware.Permission("system:menu:query"), controller.GetMenuById)
+			menuGroup.POST("/updateMenu", middleware.LogAction("修改菜单信息"), middleware.Permission("system:menu:edit"), controller.UpdateMenu)
+			menuGroup.POST("/deleteMenu", middleware.LogAction("删除菜单"), middleware.Permission("system:menu:remove"), controller.DeleteMenu)
+			menuGroup.GET("/getMenuDropdown", middleware.LogAction("菜单下拉列表"), controller.GetMenuDropdown)
 		}
 
 		// 角色管理
-		roleGroup := private.Group("/roleService")
+		roleGroup := private.Group("/roleService", middleware.LogModule("角色管理"))
 		{
-			roleGroup.POST("/createRole", controller.CreateRole)             // 创建角色
-			roleGroup.GET("/getRoleList", controller.GetRoleList)            // 查询角色列表
-			roleGroup.POST("/getRoleById", controller.GetRoleById)           // 根据id查询角色
-			roleGroup.POST("/updateRole", controller.UpdateRole)             // 修改角色信息
-			roleGroup.POST("/deleteRole", controller.DeleteRole)             // 删除角色
-			roleGroup.POST("/updateRoleStatus", controller.UpdateRoleStatus) // 修改角色状态
-			roleGroup.GET("/getRoleDropdown", controller.GetRoleDropdown)    // 角色下拉列表
-			roleGroup.POST("/getRoleMenus", controller.GetRoleMenus)         // 查询角色的权限列表
-			roleGroup.POST("/assignRoleMenus", controller.AssignRoleMenus)   // 分配角色权限
+			roleGroup.POST("/createRole", middleware.LogAction("创建角色"), middleware.Permission("system:role:add"), controller.CreateRole)
+			roleGroup.GET("/getRoleList", middleware.LogAction("查询角色列表"), middleware.Permission("system:role:list"), controller.GetRoleList)
+			roleGroup.POST("/getRoleById", middleware.LogAction("查询角色详情"), middleware.Permission("system:role:query"), controller.GetRoleById)
+			roleGroup.POST("/updateRole", middleware.LogAction("修改角色信息"), middleware.Permission("system:role:edit"), controller.UpdateRole)
+			roleGroup.POST("/deleteRole", middleware.LogAction("删除角色"), middleware.Permission("system:role:remove"), controller.DeleteRole)
+			roleGroup.POST("/updateRoleStatus", middleware.LogAction("修改角色状态"), middleware.Permission("system:role:edit"), controller.UpdateRoleStatus)
+			roleGroup.GET("/getRoleDropdown", middleware.LogAction("角色下拉列表"), controller.GetRoleDropdown)
+			roleGroup.POST("/getRoleMenus", middleware.LogAction("查询角色的权限列表"), middleware.Permission("system:role:query"), controller.GetRoleMenus)
+			roleGroup.POST("/assignRoleMenus", middleware.LogAction("分配角色权限"), middleware.Permission("system:role:assign"), controller.AssignRoleMenus)
+			roleGroup.POST("/getRoleDataScope", middleware.LogAction("查询角色数据权限"), middleware.Permission("system:role:query"), controller.GetRoleDataScope)
+			roleGroup.POST("/assignRoleDataScope", middleware.LogAction("分配角色数据权限"), middleware.Permission("system:role:assign"), controller.AssignRoleDataScope)
+			roleGroup.GET("/getRoleAdminList", middleware.LogAction("查询角色用户列表"), middleware.Permission("system:role:query"), controller.GetRoleAdminList)
+			roleGroup.POST("/addRoleAdmins", middleware.LogAction("批量添加角色用户"), middleware.Permission("system:role:assign"), controller.AddRoleAdmins)
+			roleGroup.POST("/removeRoleAdmins", middleware.LogAction("批量移除角色用户"), middleware.Permission("system:role:assign"), controller.RemoveRoleAdmins)
 		}
 
 		// 用户管理
-		adminGroup := private.Group("/adminService")
+		adminGroup := private.Group("/adminService", middleware.LogModule("用户管理"))
+		{
+			adminGroup.POST("/createAdmin", middleware.LogAction("创建用户"), middleware.Permission("system:admin:add"), controller.CreateAdmin)
+			adminGroup.POST("/inviteAdmin", middleware.LogAction("邀请用户"), middleware.Permission("system:admin:invite"), controller.InviteAdmin)
+			adminGroup.POST("/resendInvitation", middleware.LogAction("重新发送邀请"), middleware.Permission("system:admin:invite"), controller.ResendInvitation)
+			adminGroup.POST("/revokeInvitation", middleware.LogAction("撤销邀请"), middleware.Permission("system:admin:invite"), controller.RevokeInvitation)
+			adminGroup.GET("/getAdminList", middleware.LogAction("查询用户列表"), middleware.Permission("system:admin:list"), controller.GetAdminList)
+			adminGroup.POST("/getAdminById", middleware.LogAction("查询用户详情"), middleware.Permission("system:admin:query"), controller.GetAdminById)
+			adminGroup.POST("/updateAdmin", middleware.LogAction("修改用户信息"), middleware.Permission("system:admin:edit"), controller.UpdateAdmin)
+			adminGroup.POST("/deleteAdmin", middleware.LogAction("删除用户"), middleware.Permission("system:admin:remove"), controller.DeleteAdmin)
+			adminGroup.POST("/updateAdminStatus", middleware.LogAction("修改用户状态"), middleware.Permission("system:admin:edit"), controller.UpdateAdminStatus)
+			adminGroup.POST("/resetPassword", middleware.LogAction("重置密码"), middleware.Permission("system:admin:resetPwd"), controller.ResetPassword)
+			adminGroup.POST("/forceLogout", middleware.LogAction("强制用户下线"), middleware.Permission("system:admin:forceLogout"), controller.ForceLogout)
+			adminGroup.POST("/unlockAdmin", middleware.LogAction("解除登录锁定"), middleware.Permission("system:admin:unlock"), controller.UnlockAdmin)
+			adminGroup.POST("/resetTwoFactor", middleware.LogAction("重置两步验证"), middleware.Permission("system:admin:reset2fa"), controller.ResetTwoFactor)
+			adminGroup.POST("/impersonate", middleware.LogAction("模拟登录"), middleware.Permission("system:admin:impersonate"), controller.Impersonate)
+			adminGroup.POST("/updatePersonal", middleware.LogAction("修改个人资料"), controller.UpdatePersonal)
+			adminGroup.POST("/updatePassword", middleware.LogAction("修改个人密码"), controller.UpdatePassword)
+		}
+
+		// API令牌管理
+		apiTokenGroup := private.Group("/apiTokenService", middleware.LogModule("API令牌管理"))
 		{
-			adminGroup.POST("/createAdmin", controller.CreateAdmin)             // 创建用户
-			adminGroup.GET("/getAdminList", controller.GetAdminList)            // 查询用户列表
-			adminGroup.POST("/getAdminById", controller.GetAdminById)           // 根据id查询用户
-			adminGroup.POST("/updateAdmin", controller.UpdateAdmin)             // 修改用户信息
-			adminGroup.POST("/deleteAdmin", controller.DeleteAdmin)             // 删除用户
-			adminGroup.POST("/updateAdminStatus", controller.UpdateAdminStatus) // 修改用户状态
-			adminGroup.POST("/resetPassword", controller.ResetPassword)         // 重置密码
-			adminGroup.POST("/updatePersonal", controller.UpdatePersonal)       // 修改个人资料
-			adminGroup.POST("/updatePassword", controller.UpdatePassword)       // 修改个人密码
+			apiTokenGroup.POST("/createApiToken", middleware.LogAction("创建API令牌"), middleware.Permission("system:apiToken:add"), controller.CreateApiToken)
+			apiTokenGroup.GET("/getApiTokenList", middleware.LogAction("查询API令牌列表"), middleware.Permission("system:apiToken:list"), controller.GetApiTokenList)
+			apiTokenGroup.POST("/revokeApiToken", middleware.LogAction("吊销API令牌"), middleware.Permission("system:apiToken:remove"), controller.RevokeApiToken)
+		}
+
+		// 目录同步
+		ldapGroup := private.Group("/ldapService", middleware.LogModule("目录同步"))
+		{
+			ldapGroup.POST("/syncDirectory", middleware.LogAction("同步LDAP目录"), middleware.Permission("system:ldap:sync"), controller.SyncLdapDirectory)
 		}
 
 		// 日志管理
-		logGroup := private.Group("/logService")
+		logGroup := private.Group("/logService", middleware.LogModule("日志管理"))
 		{
-			logGroup.GET("/getLoginLogList", controller.GetLoginLogList)          // 查询登录日志列表
-			logGroup.POST("/deleteLoginLog", controller.DeleteLoginLog)           // 删除登录日志
-			logGroup.POST("/batchDeleteLoginLog", controller.BatchDeleteLoginLog) // 批量删除登录日志
-			logGroup.GET("/getOpLogList", controller.GetOpLogList)                // 查询操作日志列表
-			logGroup.POST("/deleteOpLog", controller.DeleteOpLog)                 // 删除操作日志
-			logGroup.POST("/batchDeleteOpLog", controller.BatchDeleteOpLog)       // 批量删除操作日志
+			logGroup.GET("/getLoginLogList", middleware.LogAction("查询登录日志列表"), middleware.Permission("monitor:loginLog:list"), controller.GetLoginLogList)
+			logGroup.POST("/deleteLoginLog", middleware.LogAction("删除登录日志"), middleware.Permission("monitor:loginLog:remove"), controller.DeleteLoginLog)
+			logGroup.POST("/batchDeleteLoginLog", middleware.LogAction("批量删除登录日志"), middleware.Permission("monitor:loginLog:remove"), controller.BatchDeleteLoginLog)
+			logGroup.GET("/getOpLogList", middleware.LogAction("查询操作日志列表"), middleware.Permission("monitor:opLog:list"), controller.GetOpLogList)
+			logGroup.POST("/deleteOpLog", middleware.LogAction("删除操作日志"), middleware.Permission("monitor:opLog:remove"), controller.DeleteOpLog)
+			logGroup.POST("/batchDeleteOpLog", middleware.LogAction("批量删除操作日志"), middleware.Permission("monitor:opLog:remove"), controller.BatchDeleteOpLog)
+			logGroup.GET("/getLogWriterStats", middleware.LogAction("查询日志写入指标"), middleware.Permission("monitor:opLog:list"), controller.GetLogWriterStats)
 		}
 	}
+
+	// SCIM 2.0 用户和组的自动配置，使用专用令牌认证
+	scim := router.Group("/scim/v2", middleware.ScimAuth(), middleware.OperationLog(), middleware.LogModule("SCIM"))
+	{
+		scim.GET("/ServiceProviderConfig", middleware.LogAction("查询SCIM服务配置"), controller.ScimServiceProviderConfig)
+		scim.GET("/ResourceTypes", middleware.LogAction("查询SCIM资源类型"), controller.ScimResourceTypes)
+		scim.GET("/Users", middleware.LogAction("查询SCIM用户列表"), controller.ScimListUsers)
+		scim.POST("/Users", middleware.LogAction("创建SCIM用户"), controller.ScimCreateUser)
+		scim.GET("/Users/:id", middleware.LogAction("查询SCIM用户"), controller.ScimGetUser)
+		scim.PUT("/Users/:id", middleware.LogAction("替换SCIM用户"), controller.ScimReplaceUser)
+		scim.PATCH("/Users/:id", middleware.LogAction("修改SCIM用户"), controller.ScimPatchUser)
+		scim.DELETE("/Users/:id", middleware.LogAction("删除SCIM用户"), controller.ScimDeleteUser)
+		scim.GET("/Groups", middleware.LogAction("查询SCIM组列表"), controller.ScimListGroups)
+		scim.POST("/Groups", middleware.LogAction("创建SCIM组"), controller.ScimCreateGroup)
+		scim.GET("/Groups/:id", middleware.LogAction("查询SCIM组"), controller.ScimGetGroup)
+		scim.PUT("/Groups/:id", middleware.LogAction("替换SCIM组"), controller.ScimReplaceGroup)
+		scim.PATCH("/Groups/:id", middleware.LogAction("修改SCIM组"), controller.ScimPatchGroup)
+		scim.DELETE("/Groups/:id", middleware.LogAction("删除SCIM组"), controller.ScimDeleteGroup)
+	}
 	return router
 }